@@ -0,0 +1,37 @@
+package v1alpha1
+
+const (
+	DriftPolicyReconcile = "Reconcile"
+	DriftPolicyReport    = "Report"
+	DriftPolicyIgnore    = "Ignore"
+)
+
+// DriftDetectionSpec defines how changes made to a data-plane resource directly through the Typesense API are handled
+type DriftDetectionSpec struct {
+	// Policy decides whether drift is reverted to the spec, only reported in the Drifted condition, or not checked at all
+	// +optional
+	// +kubebuilder:default=Reconcile
+	// +kubebuilder:validation:Enum=Reconcile;Report;Ignore
+	Policy string `json:"policy,omitempty"`
+
+	// +optional
+	// +kubebuilder:default=300
+	// +kubebuilder:validation:Minimum=30
+	IntervalInSeconds int `json:"intervalInSeconds,omitempty"`
+}
+
+func (s *DriftDetectionSpec) GetPolicy() string {
+	if s == nil || s.Policy == "" {
+		return DriftPolicyReconcile
+	}
+
+	return s.Policy
+}
+
+func (s *DriftDetectionSpec) GetIntervalInSeconds() int {
+	if s == nil || s.IntervalInSeconds < 1 {
+		return 300
+	}
+
+	return s.IntervalInSeconds
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TypesenseAnalyticsRuleSpec defines the desired state of TypesenseAnalyticsRule
+type TypesenseAnalyticsRuleSpec struct {
+	// +kubebuilder:validation:Required
+	ClusterRef corev1.LocalObjectReference `json:"clusterRef"`
+
+	// Name of the rule in Typesense, defaults to the name of the resource
+	// +optional
+	// +kubebuilder:validation:Pattern:=`^[a-zA-Z0-9_\-]+$`
+	Name *string `json:"name,omitempty"`
+
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Enum=popular_queries;nohits_queries;counter;log
+	Type string `json:"type"`
+
+	// +kubebuilder:validation:Required
+	Source AnalyticsRuleSourceSpec `json:"source"`
+
+	// Destination is required by every rule type but log
+	// +optional
+	Destination *AnalyticsRuleDestinationSpec `json:"destination,omitempty"`
+
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	Limit *int `json:"limit,omitempty"`
+
+	// +optional
+	ExpandQuery *bool `json:"expandQuery,omitempty"`
+
+	// DriftDetection periodically compares the server state with the spec, by default drift is reverted every 5 minutes
+	// +optional
+	DriftDetection *DriftDetectionSpec `json:"driftDetection,omitempty"`
+}
+
+type AnalyticsRuleSourceSpec struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	Collections []string `json:"collections"`
+
+	// Events are required by counter and log rules
+	// +optional
+	Events []AnalyticsRuleEventSpec `json:"events,omitempty"`
+}
+
+type AnalyticsRuleEventSpec struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Enum=click;conversion;visit;custom;search
+	Type string `json:"type"`
+
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// +optional
+	Weight *int `json:"weight,omitempty"`
+}
+
+type AnalyticsRuleDestinationSpec struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Collection string `json:"collection"`
+
+	// CounterField is required by counter rules
+	// +optional
+	CounterField *string `json:"counterField,omitempty"`
+}
+
+// TypesenseAnalyticsRuleStatus defines the observed state of TypesenseAnalyticsRule
+type TypesenseAnalyticsRuleStatus struct {
+
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status,xDescriptors={"urn:alm:descriptor:io.kubernetes.conditions"}
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
+
+	// +optional
+	RuleName string `json:"ruleName,omitempty"`
+
+	// +optional
+	// +kubebuilder:validation:Enum=Healthy;Degraded;Unknown
+	Health string `json:"health,omitempty"`
+
+	// +optional
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+
+// TypesenseAnalyticsRule is the Schema for the typesenseanalyticsrules API
+// +kubebuilder:printcolumn:name="Cluster",type=string,JSONPath=`.spec.clusterRef.name`
+// +kubebuilder:printcolumn:name="Type",type=string,JSONPath=`.spec.type`
+// +kubebuilder:printcolumn:name="Health",type=string,JSONPath=`.status.health`
+// +kubebuilder:printcolumn:name="Last Sync",type=date,JSONPath=`.status.lastSyncTime`
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
+type TypesenseAnalyticsRule struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TypesenseAnalyticsRuleSpec   `json:"spec,omitempty"`
+	Status TypesenseAnalyticsRuleStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// TypesenseAnalyticsRuleList contains a list of TypesenseAnalyticsRule
+type TypesenseAnalyticsRuleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []TypesenseAnalyticsRule `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&TypesenseAnalyticsRule{}, &TypesenseAnalyticsRuleList{})
+}
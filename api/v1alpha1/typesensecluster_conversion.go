@@ -0,0 +1,150 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"encoding/json"
+	"errors"
+
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/akyriako/typesense-operator/api/v1beta1"
+)
+
+// ConvertTo converts this TypesenseCluster to the Hub version (v1beta1).
+func (src *TypesenseCluster) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1beta1.TypesenseCluster)
+	dst.ObjectMeta = src.ObjectMeta
+
+	var errs []error
+	convert := func(in any, out any) {
+		errs = append(errs, convertByJSON(in, out))
+	}
+
+	dst.Spec.Replicas = src.Spec.Replicas
+
+	dst.Spec.Server = v1beta1.ServerSpec{
+		Image:                   src.Spec.Image,
+		ApiPort:                 src.Spec.ApiPort,
+		AdditionalConfiguration: src.Spec.AdditionalServerConfiguration,
+		Resources:               src.Spec.Resources,
+		SecurityContext:         src.Spec.SecurityContext,
+	}
+	convert(src.Spec.AdminApiKey, &dst.Spec.Server.AdminApiKey)
+	convert(src.Spec.AdminApiKeyRotation, &dst.Spec.Server.AdminApiKeyRotation)
+	convert(src.Spec.HealthyLagThresholds, &dst.Spec.Server.HealthyLagThresholds)
+	if src.Spec.EnableCors || src.Spec.CorsDomains != nil {
+		dst.Spec.Server.Cors = &v1beta1.CorsSpec{
+			Enabled: src.Spec.EnableCors,
+			Domains: src.Spec.CorsDomains,
+		}
+	}
+
+	dst.Spec.Peering = v1beta1.PeeringSpec{
+		Port:                      src.Spec.PeeringPort,
+		ResetPeersOnError:         src.Spec.ResetPeersOnError,
+		IncrementalQuorumRecovery: src.Spec.IncrementalQuorumRecovery,
+	}
+
+	convert(src.Spec.Metrics, &dst.Spec.Sidecars.MetricsExporter)
+	convert(src.Spec.HealthCheck, &dst.Spec.Sidecars.HealthCheck)
+
+	convert(src.Spec.Ingress, &dst.Spec.Networking.Ingress)
+	convert(src.Spec.TLS, &dst.Spec.Networking.TLS)
+	convert(src.Spec.NetworkPolicy, &dst.Spec.Networking.NetworkPolicy)
+	convert(src.Spec.Gateway, &dst.Spec.Networking.Gateway)
+
+	convert(src.Spec.Storage, &dst.Spec.Storage)
+
+	dst.Spec.Scheduling = v1beta1.SchedulingSpec{
+		Affinity:                  src.Spec.Affinity,
+		NodeSelector:              src.Spec.NodeSelector,
+		Tolerations:               src.Spec.Tolerations,
+		TopologySpreadConstraints: src.Spec.TopologySpreadConstraints,
+	}
+	dst.Spec.PodSecurityContext = src.Spec.PodSecurityContext
+
+	convert(src.Spec.Scrapers, &dst.Spec.Scrapers)
+	convert(src.Spec.Seed, &dst.Spec.Seed)
+
+	convert(src.Status, &dst.Status)
+
+	return errors.Join(errs...)
+}
+
+// ConvertFrom converts from the Hub version (v1beta1) to this version.
+func (dst *TypesenseCluster) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1beta1.TypesenseCluster)
+	dst.ObjectMeta = src.ObjectMeta
+
+	var errs []error
+	convert := func(in any, out any) {
+		errs = append(errs, convertByJSON(in, out))
+	}
+
+	dst.Spec.Replicas = src.Spec.Replicas
+
+	dst.Spec.Image = src.Spec.Server.Image
+	dst.Spec.ApiPort = src.Spec.Server.ApiPort
+	dst.Spec.AdditionalServerConfiguration = src.Spec.Server.AdditionalConfiguration
+	dst.Spec.Resources = src.Spec.Server.Resources
+	dst.Spec.SecurityContext = src.Spec.Server.SecurityContext
+	convert(src.Spec.Server.AdminApiKey, &dst.Spec.AdminApiKey)
+	convert(src.Spec.Server.AdminApiKeyRotation, &dst.Spec.AdminApiKeyRotation)
+	convert(src.Spec.Server.HealthyLagThresholds, &dst.Spec.HealthyLagThresholds)
+	if src.Spec.Server.Cors != nil {
+		dst.Spec.EnableCors = src.Spec.Server.Cors.Enabled
+		dst.Spec.CorsDomains = src.Spec.Server.Cors.Domains
+	}
+
+	dst.Spec.PeeringPort = src.Spec.Peering.Port
+	dst.Spec.ResetPeersOnError = src.Spec.Peering.ResetPeersOnError
+	dst.Spec.IncrementalQuorumRecovery = src.Spec.Peering.IncrementalQuorumRecovery
+
+	convert(src.Spec.Sidecars.MetricsExporter, &dst.Spec.Metrics)
+	convert(src.Spec.Sidecars.HealthCheck, &dst.Spec.HealthCheck)
+
+	convert(src.Spec.Networking.Ingress, &dst.Spec.Ingress)
+	convert(src.Spec.Networking.TLS, &dst.Spec.TLS)
+	convert(src.Spec.Networking.NetworkPolicy, &dst.Spec.NetworkPolicy)
+	convert(src.Spec.Networking.Gateway, &dst.Spec.Gateway)
+
+	convert(src.Spec.Storage, &dst.Spec.Storage)
+
+	dst.Spec.Affinity = src.Spec.Scheduling.Affinity
+	dst.Spec.NodeSelector = src.Spec.Scheduling.NodeSelector
+	dst.Spec.Tolerations = src.Spec.Scheduling.Tolerations
+	dst.Spec.TopologySpreadConstraints = src.Spec.Scheduling.TopologySpreadConstraints
+	dst.Spec.PodSecurityContext = src.Spec.PodSecurityContext
+
+	convert(src.Spec.Scrapers, &dst.Spec.Scrapers)
+	convert(src.Spec.Seed, &dst.Spec.Seed)
+
+	convert(src.Status, &dst.Status)
+
+	return errors.Join(errs...)
+}
+
+// convertByJSON converts between the types that are identical in both versions
+func convertByJSON(in any, out any) error {
+	b, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(b, out)
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/akyriako/typesense-operator/api/v1beta1"
+)
+
+var _ = Describe("TypesenseCluster Conversion", func() {
+	var cluster *TypesenseCluster
+
+	BeforeEach(func() {
+		cluster = &TypesenseCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-1",
+				Namespace: "default",
+				Labels:    map[string]string{"team": "search"},
+			},
+			Spec: TypesenseClusterSpec{
+				Image:                         "typesense/typesense:27.1",
+				AdminApiKey:                   &AdminApiKeySpec{Name: "admin-key", Key: "api-key"},
+				AdminApiKeyRotation:           &AdminApiKeyRotationSpec{OverlapInSeconds: 600},
+				Replicas:                      3,
+				ApiPort:                       8108,
+				PeeringPort:                   8107,
+				ResetPeersOnError:             true,
+				EnableCors:                    true,
+				CorsDomains:                   ptr.To("https://example.com"),
+				AdditionalServerConfiguration: &corev1.LocalObjectReference{Name: "server-config"},
+				NodeSelector:                  map[string]string{"disktype": "ssd"},
+				Tolerations:                   []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpExists}},
+				PodSecurityContext:            &corev1.PodSecurityContext{RunAsUser: ptr.To(int64(10000))},
+				SecurityContext:               &corev1.SecurityContext{ReadOnlyRootFilesystem: ptr.To(true)},
+				Storage: &StorageSpec{
+					Size:             resource.MustParse("1Gi"),
+					StorageClassName: "standard",
+				},
+				Ingress: &IngressSpec{
+					Host:             "search.example.com",
+					IngressClassName: "nginx",
+					Path:             "/",
+					PathType:         ptr.To(networkingv1.PathTypePrefix),
+				},
+				Scrapers:                  []DocSearchScraperSpec{{Name: "docs", Image: "scraper:latest", Config: "{}", Schedule: "@daily"}},
+				Metrics:                   &MetricsExporterSpec{Release: "prometheus", IntervalInSeconds: 15},
+				HealthCheck:               &HealthCheckSpec{Image: "healthcheck:latest"},
+				IncrementalQuorumRecovery: true,
+				HealthyLagThresholds:      &HealthyLagThresholdsSpec{Read: ptr.To(500), Write: ptr.To(250)},
+				Seed: &SeedSpec{
+					Collections: []SeedCollectionSpec{{
+						Name:   "books",
+						Fields: []CollectionFieldSpec{{Name: "title", Type: "string"}},
+					}},
+				},
+				TLS:           &TLSSpec{SecretName: ptr.To("typesense-tls")},
+				NetworkPolicy: &NetworkPolicySpec{PrometheusNamespace: "observability"},
+				Gateway: &GatewaySpec{
+					ParentRef: GatewayParentReference{Name: "public", Namespace: "gateways"},
+					Paths:     []GatewayPathMatch{{Type: "PathPrefix", Value: "/"}},
+				},
+			},
+			Status: TypesenseClusterStatus{
+				Phase:       "QuorumReady",
+				AdminApiKey: &AdminApiKeyStatus{SecretName: "admin-key", Fingerprint: "abc"},
+			},
+		}
+	})
+
+	Context("When converting TypesenseCluster to the hub version", func() {
+		It("Should group the server, peering, sidecars and networking settings", func() {
+			hub := &v1beta1.TypesenseCluster{}
+			Expect(cluster.ConvertTo(hub)).To(Succeed())
+
+			Expect(hub.Name).To(Equal(cluster.Name))
+			Expect(hub.Spec.Server.Image).To(Equal(cluster.Spec.Image))
+			Expect(hub.Spec.Server.ApiPort).To(Equal(8108))
+			Expect(hub.Spec.Server.Cors).To(Equal(&v1beta1.CorsSpec{Enabled: true, Domains: ptr.To("https://example.com")}))
+			Expect(*hub.Spec.Server.HealthyLagThresholds.Write).To(Equal(250))
+			Expect(hub.Spec.Peering.Port).To(Equal(8107))
+			Expect(hub.Spec.Peering.IncrementalQuorumRecovery).To(BeTrue())
+			Expect(hub.Spec.Sidecars.MetricsExporter.Release).To(Equal("prometheus"))
+			Expect(hub.Spec.Networking.Ingress.Host).To(Equal("search.example.com"))
+			Expect(*hub.Spec.Networking.TLS.SecretName).To(Equal("typesense-tls"))
+			Expect(hub.Spec.Scheduling.NodeSelector).To(HaveKeyWithValue("disktype", "ssd"))
+			Expect(hub.Status.AdminApiKey.Fingerprint).To(Equal("abc"))
+		})
+
+		It("Should round-trip without losing any field", func() {
+			hub := &v1beta1.TypesenseCluster{}
+			Expect(cluster.ConvertTo(hub)).To(Succeed())
+
+			restored := &TypesenseCluster{}
+			Expect(restored.ConvertFrom(hub)).To(Succeed())
+			Expect(equality.Semantic.DeepEqual(restored, cluster)).To(BeTrue())
+		})
+
+		It("Should leave cors unset when it is not configured", func() {
+			cluster.Spec.EnableCors = false
+			cluster.Spec.CorsDomains = nil
+
+			hub := &v1beta1.TypesenseCluster{}
+			Expect(cluster.ConvertTo(hub)).To(Succeed())
+			Expect(hub.Spec.Server.Cors).To(BeNil())
+		})
+	})
+
+	Context("When converting TypesenseCluster from the hub version", func() {
+		It("Should round-trip without losing any field", func() {
+			hub := &v1beta1.TypesenseCluster{}
+			Expect(cluster.ConvertTo(hub)).To(Succeed())
+
+			spoke := &TypesenseCluster{}
+			Expect(spoke.ConvertFrom(hub)).To(Succeed())
+
+			restored := &v1beta1.TypesenseCluster{}
+			Expect(spoke.ConvertTo(restored)).To(Succeed())
+			Expect(equality.Semantic.DeepEqual(restored, hub)).To(BeTrue())
+		})
+	})
+})
@@ -21,6 +21,7 @@ import (
 	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
@@ -30,7 +31,14 @@ import (
 type TypesenseClusterSpec struct {
 	Image string `json:"image"`
 
-	AdminApiKey *corev1.SecretReference `json:"adminApiKey,omitempty"`
+	// AdminApiKey references the Secret holding the admin api key, the operator generates one if it is not set
+	// +optional
+	AdminApiKey *AdminApiKeySpec `json:"adminApiKey,omitempty"`
+
+	// AdminApiKeyRotation configures the rotation of the admin api key, which is requested by pointing adminApiKey
+	// to another Secret or by changing the ts.opentelekomcloud.com/rotate-admin-api-key annotation
+	// +optional
+	AdminApiKeyRotation *AdminApiKeyRotationSpec `json:"adminApiKeyRotation,omitempty"`
 
 	// +optional
 	// +kubebuilder:default=3
@@ -85,6 +93,16 @@ type TypesenseClusterSpec struct {
 	// +kubebuilder:validation:Optional
 	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
 
+	// PodSecurityContext of the pods of the cluster, it defaults to running as user 10000, group 3000 and fsGroup 2000
+	// with the RuntimeDefault seccomp profile; clear runAsUser, runAsGroup and fsGroup where the platform assigns them
+	// +kubebuilder:validation:Optional
+	PodSecurityContext *corev1.PodSecurityContext `json:"podSecurityContext,omitempty"`
+
+	// SecurityContext of the typesense container, it defaults to the restricted Pod Security Standard
+	// with a read-only root filesystem
+	// +kubebuilder:validation:Optional
+	SecurityContext *corev1.SecurityContext `json:"securityContext,omitempty"`
+
 	// +kubebuilder:validation:Optional
 	AdditionalServerConfiguration *corev1.LocalObjectReference `json:"additionalServerConfiguration,omitempty"`
 
@@ -105,6 +123,200 @@ type TypesenseClusterSpec struct {
 	// +kubebuilder:default=false
 	// +kubebuilder:validation:Type=boolean
 	IncrementalQuorumRecovery bool `json:"incrementalQuorumRecovery,omitempty"`
+
+	// HealthyLagThresholds override TYPESENSE_HEALTHY_READ_LAG and TYPESENSE_HEALTHY_WRITE_LAG
+	// of additionalServerConfiguration
+	// +kubebuilder:validation:Optional
+	HealthyLagThresholds *HealthyLagThresholdsSpec `json:"healthyLagThresholds,omitempty"`
+
+	// Seed imports collections and documents when the cluster is bootstrapped, it is ignored when added later on
+	// +kubebuilder:validation:Optional
+	Seed *SeedSpec `json:"seed,omitempty"`
+
+	// TLS serves the api over https, every client of the operator verifies it against the CA of the certificate
+	// +kubebuilder:validation:Optional
+	TLS *TLSSpec `json:"tls,omitempty"`
+
+	// NetworkPolicy isolates the raft peering of the nodes and restricts access to the api
+	// +kubebuilder:validation:Optional
+	NetworkPolicy *NetworkPolicySpec `json:"networkPolicy,omitempty"`
+
+	// Gateway exposes the api through a Gateway API HTTPRoute, as an alternative or in addition to the ingress
+	// +kubebuilder:validation:Optional
+	Gateway *GatewaySpec `json:"gateway,omitempty"`
+}
+
+// GatewaySpec exposes the api through an existing Gateway of the Gateway API, with a generated HTTPRoute
+type GatewaySpec struct {
+	// ParentRef is the Gateway, or one of its listeners, the HTTPRoute attaches to
+	// +kubebuilder:validation:Required
+	ParentRef GatewayParentReference `json:"parentRef"`
+
+	// Hostnames the HTTPRoute matches, those of the listener when empty
+	// +optional
+	Hostnames []string `json:"hostnames,omitempty"`
+
+	// Paths the HTTPRoute matches, every path when empty
+	// +optional
+	Paths []GatewayPathMatch `json:"paths,omitempty"`
+
+	// Headers every request has to carry
+	// +optional
+	Headers []GatewayHeaderMatch `json:"headers,omitempty"`
+
+	// Referer lets only requests through whose Referer header is of this host
+	// +optional
+	Referer *string `json:"referer,omitempty"`
+}
+
+type GatewayParentReference struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Namespace of the Gateway, the namespace of the cluster when empty
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// SectionName is the name of the listener of the Gateway
+	// +optional
+	SectionName string `json:"sectionName,omitempty"`
+}
+
+type GatewayPathMatch struct {
+	// +optional
+	// +kubebuilder:default=PathPrefix
+	// +kubebuilder:validation:Enum=Exact;PathPrefix;RegularExpression
+	Type string `json:"type,omitempty"`
+
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Value string `json:"value"`
+}
+
+type GatewayHeaderMatch struct {
+	// +optional
+	// +kubebuilder:default=Exact
+	// +kubebuilder:validation:Enum=Exact;RegularExpression
+	Type string `json:"type,omitempty"`
+
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// +kubebuilder:validation:Required
+	Value string `json:"value"`
+}
+
+// NetworkPolicySpec admits to the api, besides the operator, the reverse proxy and the jobs of the operator,
+// the peers listed in apiClients; the peering port is only open between the nodes of the cluster
+type NetworkPolicySpec struct {
+	// ApiClients are additional namespace and pod selectors allowed to call the api
+	// +optional
+	ApiClients []networkingv1.NetworkPolicyPeer `json:"apiClients,omitempty"`
+
+	// PrometheusNamespace is the namespace Prometheus scrapes the metrics port from
+	// +optional
+	// +kubebuilder:default=monitoring
+	// +kubebuilder:validation:Type=string
+	PrometheusNamespace string `json:"prometheusNamespace,omitempty"`
+}
+
+// HealthyLagThresholdsSpec are the replication lags, in number of operations, above which a node stops
+// accepting reads or writes and reports itself unhealthy
+type HealthyLagThresholdsSpec struct {
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	Read *int `json:"read,omitempty"`
+
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	Write *int `json:"write,omitempty"`
+}
+
+// AdminApiKeySpec is the source of the admin api key, a Secret in the namespace of the cluster or a file projected
+// by a secrets store CSI driver that syncs it to that Secret
+type AdminApiKeySpec struct {
+	// Name of the Secret holding the admin api key
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Namespace of the Secret, it has to be the namespace of the cluster
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Key of the admin api key in the Secret
+	// +optional
+	// +kubebuilder:default=typesense-api-key
+	// +kubebuilder:validation:MinLength=1
+	Key string `json:"key,omitempty"`
+
+	// CSI mounts a secrets store CSI volume in the pods of the cluster, which has to sync the admin api key to the
+	// Secret with the secretObjects of its SecretProviderClass, since the operator reads the key from the Secret
+	// +optional
+	CSI *AdminApiKeyCSISpec `json:"csi,omitempty"`
+}
+
+type AdminApiKeyCSISpec struct {
+	// Driver of the CSI volume
+	// +optional
+	// +kubebuilder:default=secrets-store.csi.k8s.io
+	Driver string `json:"driver,omitempty"`
+
+	// VolumeAttributes of the CSI volume, e.g. the secretProviderClass
+	// +kubebuilder:validation:Required
+	VolumeAttributes map[string]string `json:"volumeAttributes"`
+}
+
+type AdminApiKeyRotationSpec struct {
+	// OverlapInSeconds is how long the previous admin api key stays valid after a rotation,
+	// it has to outlast the rolling restart of the cluster
+	// +optional
+	// +kubebuilder:default=900
+	// +kubebuilder:validation:Minimum=60
+	// +kubebuilder:validation:Type=integer
+	OverlapInSeconds int `json:"overlapInSeconds,omitempty"`
+}
+
+// TLSSpec is the certificate of the api, exactly one of certManager or secretName has to be set
+type TLSSpec struct {
+	// CertManager issues the certificate with cert-manager, which has to be installed in the cluster
+	// +optional
+	CertManager *TLSCertManagerSpec `json:"certManager,omitempty"`
+
+	// SecretName of an existing kubernetes.io/tls Secret, its certificate has to cover the names of the
+	// resolver and the headless service of the cluster
+	// +optional
+	SecretName *string `json:"secretName,omitempty"`
+
+	// CAKey is the key of the Secret holding the CA that signed the certificate
+	// +optional
+	// +kubebuilder:default:="ca.crt"
+	CAKey string `json:"caKey,omitempty"`
+}
+
+type TLSCertManagerSpec struct {
+	IssuerRef TLSIssuerReference `json:"issuerRef"`
+
+	// +optional
+	Duration *metav1.Duration `json:"duration,omitempty"`
+
+	// +optional
+	RenewBefore *metav1.Duration `json:"renewBefore,omitempty"`
+}
+
+type TLSIssuerReference struct {
+	Name string `json:"name"`
+
+	// +optional
+	// +kubebuilder:default:="Issuer"
+	// +kubebuilder:validation:Enum=Issuer;ClusterIssuer
+	Kind string `json:"kind,omitempty"`
+
+	// +optional
+	// +kubebuilder:default:="cert-manager.io"
+	Group string `json:"group,omitempty"`
 }
 
 type StorageSpec struct {
@@ -116,7 +328,20 @@ type StorageSpec struct {
 	StorageClassName string `json:"storageClassName"`
 }
 
+type IngressMode string
+
+const (
+	IngressModeReverseProxy IngressMode = "ReverseProxy"
+	IngressModeDirect       IngressMode = "Direct"
+)
+
 type IngressSpec struct {
+	// Mode of the ingress, ReverseProxy routes through an nginx Deployment and Direct routes straight to the api
+	// +optional
+	// +kubebuilder:default:="ReverseProxy"
+	// +kubebuilder:validation:Enum=ReverseProxy;Direct
+	Mode IngressMode `json:"mode,omitempty"`
+
 	// +optional
 	// +kubebuilder:validation:Pattern:=`^([a-zA-Z0-9]|[a-zA-Z0-9][a-zA-Z0-9\-]{0,61}[a-zA-Z0-9])(\.([a-zA-Z0-9]|[a-zA-Z0-9][a-zA-Z0-9\-]{0,61}[a-zA-Z0-9]))*$`
 	Referer *string `json:"referer,omitempty"`
@@ -149,6 +374,23 @@ type IngressSpec struct {
 	// +optional
 	ReadOnlyRootFilesystem *ReadOnlyRootFilesystemSpec `json:"readOnlyRootFilesystem,omitempty"`
 
+	// ReverseProxy configures the replicas, scheduling, disruption budget and service of the reverse proxy
+	// +optional
+	ReverseProxy *ReverseProxySpec `json:"reverseProxy,omitempty"`
+
+	// RateLimit throttles the requests of every client ip, and optionally of every api key, in the reverse proxy
+	// +optional
+	RateLimit *RateLimitSpec `json:"rateLimit,omitempty"`
+
+	// Cache keeps the responses of searches in the reverse proxy, until the ttl expires or a collection of the operator changes
+	// +optional
+	Cache *CacheSpec `json:"cache,omitempty"`
+
+	// Auth verifies the bearer tokens of the requests in a sidecar of the reverse proxy and replaces them with
+	// a scoped api key derived from their claims, requests without a valid token are rejected
+	// +optional
+	Auth *AuthSpec `json:"auth,omitempty"`
+
 	// +optional
 	// +kubebuilder:default:="/"
 	Path string `json:"path,omitempty"`
@@ -159,6 +401,195 @@ type IngressSpec struct {
 	PathType *networkingv1.PathType `json:"pathType,omitempty"`
 }
 
+type ReverseProxySpec struct {
+	// Replicas of the reverse proxy, ignored when autoscaling is set
+	// +optional
+	// +kubebuilder:default:=3
+	// +kubebuilder:validation:Minimum=1
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// Autoscaling scales the reverse proxy on its cpu utilization with a HorizontalPodAutoscaler
+	// +optional
+	Autoscaling *ReverseProxyAutoscalingSpec `json:"autoscaling,omitempty"`
+
+	// ReadinessProbe of the nginx container, a tcp check of the http port by default
+	// +optional
+	ReadinessProbe *corev1.Probe `json:"readinessProbe,omitempty"`
+
+	// LivenessProbe of the nginx container, a tcp check of the http port by default
+	// +optional
+	LivenessProbe *corev1.Probe `json:"livenessProbe,omitempty"`
+
+	// +optional
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// TopologySpreadConstraints of the reverse proxy, the pods of the reverse proxy are selected if no labelSelector is set
+	// +optional
+	TopologySpreadConstraints []corev1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+
+	// PodDisruptionBudget of the reverse proxy, at most one pod is unavailable by default
+	// +optional
+	PodDisruptionBudget *ReverseProxyPodDisruptionBudgetSpec `json:"podDisruptionBudget,omitempty"`
+
+	// +optional
+	// +kubebuilder:default:="NodePort"
+	// +kubebuilder:validation:Enum=ClusterIP;NodePort;LoadBalancer
+	ServiceType corev1.ServiceType `json:"serviceType,omitempty"`
+}
+
+type ReverseProxyAutoscalingSpec struct {
+	// +optional
+	// +kubebuilder:default:=1
+	// +kubebuilder:validation:Minimum=1
+	MinReplicas int32 `json:"minReplicas,omitempty"`
+
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Minimum=1
+	MaxReplicas int32 `json:"maxReplicas"`
+
+	// +optional
+	// +kubebuilder:default:=80
+	// +kubebuilder:validation:Minimum=1
+	TargetCPUUtilizationPercentage int32 `json:"targetCPUUtilizationPercentage,omitempty"`
+}
+
+type ReverseProxyPodDisruptionBudgetSpec struct {
+	// +optional
+	MinAvailable *intstr.IntOrString `json:"minAvailable,omitempty"`
+
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+}
+
+type RateLimitSpec struct {
+	// RequestsPerSecond every client ip may send
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Minimum=1
+	RequestsPerSecond int32 `json:"requestsPerSecond"`
+
+	// Burst of requests above the rate a client ip may send before being rejected
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	Burst int32 `json:"burst,omitempty"`
+
+	// ApiKey limits the requests of every X-TYPESENSE-API-KEY header too, on top of the limit of the client ip
+	// +optional
+	ApiKey *ApiKeyRateLimitSpec `json:"apiKey,omitempty"`
+
+	// ConnectionsPerClient limits the concurrent connections of every client ip
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	ConnectionsPerClient *int32 `json:"connectionsPerClient,omitempty"`
+
+	// TrustedProxies are the CIDRs of the proxies in front of the reverse proxy, such as the ingress controller,
+	// whose X-Forwarded-For header carries the client ip; the private ranges by default
+	// +optional
+	TrustedProxies []string `json:"trustedProxies,omitempty"`
+
+	// ResponseBody of a request rejected with 429 Too Many Requests
+	// +optional
+	ResponseBody *string `json:"responseBody,omitempty"`
+}
+
+type ApiKeyRateLimitSpec struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Minimum=1
+	RequestsPerSecond int32 `json:"requestsPerSecond"`
+
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	Burst int32 `json:"burst,omitempty"`
+}
+
+type CacheSpec struct {
+	// TTL of a cached search response, which is served even after the documents, the schema or the alias of its
+	// collection changed until it expires
+	// +optional
+	// +kubebuilder:default:="1m"
+	TTL *metav1.Duration `json:"ttl,omitempty"`
+
+	// MaxSize of the cache of every reverse proxy pod, the size limit of the emptyDir it is kept in
+	// +optional
+	// +kubebuilder:default:="256Mi"
+	MaxSize *resource.Quantity `json:"maxSize,omitempty"`
+}
+
+type AuthSpec struct {
+	// Image of the auth sidecar, it has to provide the auth binary shipped in the image of the operator, which
+	// runs it when left empty
+	// +kubebuilder:validation:Optional
+	Image string `json:"image,omitempty"`
+
+	// JWKS the signatures of the bearer tokens are verified against
+	// +kubebuilder:validation:Required
+	JWKS JWKSSpec `json:"jwks"`
+
+	// Issuer the iss claim of the tokens has to match
+	// +optional
+	Issuer *string `json:"issuer,omitempty"`
+
+	// Audiences of which the aud claim of the tokens has to contain at least one
+	// +optional
+	Audiences []string `json:"audiences,omitempty"`
+
+	// ParentKeySecretRef points to the search-only api key the scoped keys are derived from
+	// +kubebuilder:validation:Required
+	ParentKeySecretRef corev1.SecretKeySelector `json:"parentKeySecretRef"`
+
+	// Claims restrict the scoped key of a token to the documents whose field matches the value of a claim,
+	// tokens lacking one of the claims are rejected
+	// +optional
+	Claims []ClaimMappingSpec `json:"claims,omitempty"`
+
+	// FilterBy is added to the filter of every scoped key
+	// +optional
+	FilterBy *string `json:"filterBy,omitempty"`
+
+	// LimitMultiSearches caps the number of searches of a multi search request of every scoped key
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	LimitMultiSearches *int `json:"limitMultiSearches,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// SecurityContext of the sidecar container, it defaults to the restricted Pod Security Standard
+	// with a read-only root filesystem
+	// +kubebuilder:validation:Optional
+	SecurityContext *corev1.SecurityContext `json:"securityContext,omitempty"`
+}
+
+type JWKSSpec struct {
+	// SecretKeyRef points to a JSON Web Key Set kept in a Secret
+	// +optional
+	SecretKeyRef *corev1.SecretKeySelector `json:"secretKeyRef,omitempty"`
+
+	// URL of a JSON Web Key Set, such as the jwks_uri of an OpenID Connect provider
+	// +optional
+	// +kubebuilder:validation:Pattern:=`^https?://`
+	URL *string `json:"url,omitempty"`
+
+	// RefreshInterval of the keys, they are reloaded earlier when a token is signed with an unknown key
+	// +optional
+	// +kubebuilder:default:="10m"
+	RefreshInterval *metav1.Duration `json:"refreshInterval,omitempty"`
+}
+
+type ClaimMappingSpec struct {
+	// Claim of the token, a string, a number, a boolean or an array of them
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Claim string `json:"claim"`
+
+	// Field of the documents the value of the claim is matched against
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Field string `json:"field"`
+}
+
 type ReadOnlyRootFilesystemSpec struct {
 	// +optional
 	SecurityContext *corev1.SecurityContext `json:"securityContext,omitempty"`
@@ -201,6 +632,11 @@ type MetricsExporterSpec struct {
 
 	// +kubebuilder:validation:Optional
 	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// SecurityContext of the sidecar container, it defaults to the restricted Pod Security Standard
+	// with a read-only root filesystem
+	// +kubebuilder:validation:Optional
+	SecurityContext *corev1.SecurityContext `json:"securityContext,omitempty"`
 }
 
 type HealthCheckSpec struct {
@@ -210,6 +646,103 @@ type HealthCheckSpec struct {
 
 	// +kubebuilder:validation:Optional
 	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// SecurityContext of the sidecar container, it defaults to the restricted Pod Security Standard
+	// with a read-only root filesystem
+	// +kubebuilder:validation:Optional
+	SecurityContext *corev1.SecurityContext `json:"securityContext,omitempty"`
+}
+
+type SeedSpec struct {
+	// +kubebuilder:validation:MinItems=1
+	Collections []SeedCollectionSpec `json:"collections"`
+
+	// +optional
+	// +kubebuilder:default=100
+	// +kubebuilder:validation:Minimum=1
+	BatchSize int `json:"batchSize,omitempty"`
+
+	// MaxRetries of the documents that failed to import
+	// +optional
+	// +kubebuilder:default=3
+	// +kubebuilder:validation:Minimum=0
+	MaxRetries int `json:"maxRetries,omitempty"`
+
+	// Image of the Job importing the documents, it has to provide sh, curl, grep, split and awk, defaults to
+	// the job image of the operator which ships them
+	// +kubebuilder:validation:Optional
+	Image string `json:"image,omitempty"`
+}
+
+type SeedCollectionSpec struct {
+	// +kubebuilder:validation:Pattern:=`^[a-zA-Z0-9_\-]+$`
+	Name string `json:"name"`
+
+	// +kubebuilder:validation:MinItems=1
+	Fields []CollectionFieldSpec `json:"fields"`
+
+	// +optional
+	DefaultSortingField *string `json:"defaultSortingField,omitempty"`
+
+	// +optional
+	Documents []SeedDocumentsSpec `json:"documents,omitempty"`
+}
+
+// SeedDocumentsSpec is a JSONL source of documents, exactly one of its fields has to be set
+type SeedDocumentsSpec struct {
+	// +optional
+	ConfigMapRef *corev1.ConfigMapKeySelector `json:"configMapRef,omitempty"`
+
+	// +optional
+	PersistentVolumeClaim *SeedPersistentVolumeClaimSpec `json:"persistentVolumeClaim,omitempty"`
+
+	// +optional
+	// +kubebuilder:validation:Pattern:=`^https?://.+$`
+	Url *string `json:"url,omitempty"`
+}
+
+type SeedPersistentVolumeClaimSpec struct {
+	ClaimName string `json:"claimName"`
+
+	// Path of the JSONL file, relative to the root of the volume
+	// +kubebuilder:validation:Pattern:=`^[a-zA-Z0-9_\-\./]+$`
+	Path string `json:"path"`
+}
+
+type SeedStatus struct {
+	// +kubebuilder:validation:Enum=Pending;Seeding;Completed
+	Phase string `json:"phase"`
+
+	// +optional
+	Documents int64 `json:"documents,omitempty"`
+
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+type AdminApiKeyStatus struct {
+	// SecretName of the admin api key the cluster is running with
+	SecretName string `json:"secretName"`
+
+	// Fingerprint is the sha256 of the admin api key the cluster is running with
+	Fingerprint string `json:"fingerprint"`
+
+	// +optional
+	LastRotationTime *metav1.Time `json:"lastRotationTime,omitempty"`
+
+	// RotationRequest is the last value of the rotation annotation that was handled
+	// +optional
+	RotationRequest string `json:"rotationRequest,omitempty"`
+
+	// OverlapKeyIDs are the api keys keeping both the previous and the new admin api key valid during a rotation
+	// +optional
+	OverlapKeyIDs []int64 `json:"overlapKeyIds,omitempty"`
+
+	// +optional
+	OverlapExpirationTime *metav1.Time `json:"overlapExpirationTime,omitempty"`
 }
 
 // TypesenseClusterStatus defines the observed state of TypesenseCluster
@@ -221,6 +754,14 @@ type TypesenseClusterStatus struct {
 
 	// +optional
 	Phase string `json:"phase,omitempty"`
+
+	// Seed records the import of spec.seed, which is armed when the cluster is created and runs only once after the
+	// quorum is first ready
+	// +optional
+	Seed *SeedStatus `json:"seed,omitempty"`
+
+	// +optional
+	AdminApiKey *AdminApiKeyStatus `json:"adminApiKey,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -1,9 +1,12 @@
 package v1alpha1
 
 import (
+	"time"
+
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 )
 
 func (s *TypesenseClusterSpec) GetResources() corev1.ResourceRequirements {
@@ -139,6 +142,168 @@ func (s *TypesenseClusterSpec) GetHealthCheckSidecarResources() corev1.ResourceR
 	}
 }
 
+func (s *TypesenseClusterSpec) GetPodSecurityContext() *corev1.PodSecurityContext {
+	if s.PodSecurityContext != nil {
+		return s.PodSecurityContext
+	}
+
+	return &corev1.PodSecurityContext{
+		RunAsUser:    ptr.To[int64](10000),
+		FSGroup:      ptr.To[int64](2000),
+		RunAsGroup:   ptr.To[int64](3000),
+		RunAsNonRoot: ptr.To(true),
+		SeccompProfile: &corev1.SeccompProfile{
+			Type: corev1.SeccompProfileTypeRuntimeDefault,
+		},
+	}
+}
+
+func (s *TypesenseClusterSpec) GetSecurityContext() *corev1.SecurityContext {
+	if s.SecurityContext != nil {
+		return s.SecurityContext
+	}
+
+	return getRestrictedSecurityContext()
+}
+
+func (s *TypesenseClusterSpec) GetMetricsExporterSecurityContext() *corev1.SecurityContext {
+	if s.Metrics != nil && s.Metrics.SecurityContext != nil {
+		return s.Metrics.SecurityContext
+	}
+
+	return getRestrictedSecurityContext()
+}
+
+func (s *TypesenseClusterSpec) GetHealthCheckSidecarSecurityContext() *corev1.SecurityContext {
+	if s.HealthCheck != nil && s.HealthCheck.SecurityContext != nil {
+		return s.HealthCheck.SecurityContext
+	}
+
+	return getRestrictedSecurityContext()
+}
+
+// getRestrictedSecurityContext satisfies the restricted Pod Security Standard
+func getRestrictedSecurityContext() *corev1.SecurityContext {
+	return &corev1.SecurityContext{
+		AllowPrivilegeEscalation: ptr.To(false),
+		ReadOnlyRootFilesystem:   ptr.To(true),
+		RunAsNonRoot:             ptr.To(true),
+		Capabilities: &corev1.Capabilities{
+			Drop: []corev1.Capability{"ALL"},
+		},
+		SeccompProfile: &corev1.SeccompProfile{
+			Type: corev1.SeccompProfileTypeRuntimeDefault,
+		},
+	}
+}
+
+func (s *IngressSpec) IsDirect() bool {
+	return s.Mode == IngressModeDirect
+}
+
+func (s *IngressSpec) GetReverseProxySpec() ReverseProxySpec {
+	if s.ReverseProxy != nil {
+		return *s.ReverseProxy
+	}
+
+	return ReverseProxySpec{}
+}
+
+func (s *IngressSpec) GetReverseProxyReplicas() int32 {
+	if s.ReverseProxy != nil && s.ReverseProxy.Replicas != nil {
+		return *s.ReverseProxy.Replicas
+	}
+
+	return 3
+}
+
+func (s *IngressSpec) GetReverseProxyServiceType() corev1.ServiceType {
+	if s.ReverseProxy != nil && s.ReverseProxy.ServiceType != "" {
+		return s.ReverseProxy.ServiceType
+	}
+
+	return corev1.ServiceTypeNodePort
+}
+
+func (s *IngressSpec) GetReverseProxyTopologySpreadConstraints(labels map[string]string) []corev1.TopologySpreadConstraint {
+	tscs := make([]corev1.TopologySpreadConstraint, 0)
+
+	for _, tsc := range s.GetReverseProxySpec().TopologySpreadConstraints {
+		if tsc.LabelSelector == nil {
+			tsc.LabelSelector = &metav1.LabelSelector{
+				MatchLabels: labels,
+			}
+		}
+		tscs = append(tscs, tsc)
+	}
+	return tscs
+}
+
+func (s *RateLimitSpec) GetTrustedProxies() []string {
+	if len(s.TrustedProxies) > 0 {
+		return s.TrustedProxies
+	}
+
+	return []string{"10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16"}
+}
+
+func (s *RateLimitSpec) GetResponseBody() string {
+	if s.ResponseBody != nil {
+		return *s.ResponseBody
+	}
+
+	return `{"message": "Too many requests."}`
+}
+
+func (s *CacheSpec) GetTTL() time.Duration {
+	if s.TTL != nil && s.TTL.Duration > 0 {
+		return s.TTL.Duration
+	}
+
+	return time.Minute
+}
+
+func (s *CacheSpec) GetMaxSize() resource.Quantity {
+	if s.MaxSize != nil && !s.MaxSize.IsZero() {
+		return *s.MaxSize
+	}
+
+	return resource.MustParse("256Mi")
+}
+
+func (s *AuthSpec) GetResources() corev1.ResourceRequirements {
+	if s.Resources != nil {
+		return *s.Resources
+	}
+
+	return corev1.ResourceRequirements{
+		Limits: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("100m"),
+			corev1.ResourceMemory: resource.MustParse("64Mi"),
+		},
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("50m"),
+			corev1.ResourceMemory: resource.MustParse("32Mi"),
+		},
+	}
+}
+
+func (s *AuthSpec) GetSecurityContext() *corev1.SecurityContext {
+	if s.SecurityContext != nil {
+		return s.SecurityContext
+	}
+
+	return getRestrictedSecurityContext()
+}
+
+func (s *JWKSSpec) GetRefreshInterval() time.Duration {
+	if s.RefreshInterval != nil && s.RefreshInterval.Duration > 0 {
+		return s.RefreshInterval.Duration
+	}
+
+	return 10 * time.Minute
+}
+
 func (s *IngressSpec) GetReverseProxyResources() corev1.ResourceRequirements {
 	if s.Resources != nil {
 		return *s.Resources
@@ -155,3 +320,37 @@ func (s *IngressSpec) GetReverseProxyResources() corev1.ResourceRequirements {
 		},
 	}
 }
+
+func (s *TypesenseClusterSpec) IsTLSEnabled() bool {
+	return s.TLS != nil
+}
+
+func (s *TLSSpec) GetCAKey() string {
+	if s.CAKey == "" {
+		return "ca.crt"
+	}
+	return s.CAKey
+}
+
+func (s *TypesenseClusterSpec) GetAdminApiKeySecretKey() string {
+	if s.AdminApiKey != nil && s.AdminApiKey.Key != "" {
+		return s.AdminApiKey.Key
+	}
+
+	return "typesense-api-key"
+}
+
+func (s *TypesenseClusterSpec) GetAdminApiKeyRotationOverlap() time.Duration {
+	if s.AdminApiKeyRotation != nil && s.AdminApiKeyRotation.OverlapInSeconds > 0 {
+		return time.Duration(s.AdminApiKeyRotation.OverlapInSeconds) * time.Second
+	}
+
+	return 15 * time.Minute
+}
+
+func (s *NetworkPolicySpec) GetPrometheusNamespace() string {
+	if s.PrometheusNamespace == "" {
+		return "monitoring"
+	}
+	return s.PrometheusNamespace
+}
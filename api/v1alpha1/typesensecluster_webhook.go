@@ -0,0 +1,231 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+	"net"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// NodeNameLenLimit is the longest node name raft accepts, the node names are derived from the name of the cluster
+const NodeNameLenLimit = 64
+
+// log is for logging in this package.
+var typesenseclusterlog = logf.Log.WithName("typesensecluster-resource")
+
+// SetupWebhookWithManager will setup the manager to manage the webhooks
+func (r *TypesenseCluster) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/mutate-ts-opentelekomcloud-com-v1alpha1-typesensecluster,mutating=true,failurePolicy=fail,sideEffects=None,groups=ts.opentelekomcloud.com,resources=typesenseclusters,verbs=create;update,versions=v1alpha1,name=mtypesensecluster.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Defaulter = &TypesenseCluster{}
+
+// Default implements webhook.Defaulter so a webhook will be registered for the type
+func (r *TypesenseCluster) Default() {
+	typesenseclusterlog.Info("default", "name", r.Name)
+
+	if r.Spec.Replicas == 0 {
+		r.Spec.Replicas = 3
+	}
+	if r.Spec.ApiPort == 0 {
+		r.Spec.ApiPort = 8108
+	}
+	if r.Spec.PeeringPort == 0 {
+		r.Spec.PeeringPort = 8107
+	}
+
+	if r.Spec.Storage != nil && r.Spec.Storage.Size.IsZero() {
+		r.Spec.Storage.Size = resource.MustParse("100Mi")
+	}
+
+	if r.Spec.Ingress != nil {
+		if r.Spec.Ingress.Path == "" {
+			r.Spec.Ingress.Path = "/"
+		}
+		if r.Spec.Ingress.PathType == nil {
+			r.Spec.Ingress.PathType = ptr.To(networkingv1.PathTypeImplementationSpecific)
+		}
+		if r.Spec.Ingress.Mode == "" {
+			r.Spec.Ingress.Mode = IngressModeReverseProxy
+		}
+		if r.Spec.Ingress.Image == "" {
+			r.Spec.Ingress.Image = "nginx:alpine"
+		}
+	}
+}
+
+// NOTE: The 'path' attribute must follow a specific pattern and should not be modified directly here.
+// Modifying the path for an invalid path can cause API server errors; failing to locate the webhook.
+// +kubebuilder:webhook:path=/validate-ts-opentelekomcloud-com-v1alpha1-typesensecluster,mutating=false,failurePolicy=fail,sideEffects=None,groups=ts.opentelekomcloud.com,resources=typesenseclusters,verbs=create;update,versions=v1alpha1,name=vtypesensecluster.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Validator = &TypesenseCluster{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *TypesenseCluster) ValidateCreate() (admission.Warnings, error) {
+	typesenseclusterlog.Info("validate create", "name", r.Name)
+
+	return nil, r.validateCluster(nil)
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *TypesenseCluster) ValidateUpdate(old runtime.Object) (admission.Warnings, error) {
+	typesenseclusterlog.Info("validate update", "name", r.Name)
+
+	var warnings admission.Warnings
+	oldCluster := old.(*TypesenseCluster)
+	if r.Spec.Seed != nil && oldCluster.Spec.Seed == nil && oldCluster.Status.Seed == nil {
+		warnings = append(warnings, "spec.seed is imported only when the cluster is bootstrapped, it has no effect on an existing cluster")
+	}
+
+	return warnings, r.validateCluster(oldCluster)
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *TypesenseCluster) ValidateDelete() (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (r *TypesenseCluster) validateCluster(old *TypesenseCluster) error {
+	var allErrs field.ErrorList
+	specPath := field.NewPath("spec")
+
+	// the node of the highest ordinal has the longest name
+	nodeName := fmt.Sprintf("%s-sts-%d.%s-sts-svc", r.Name, max(r.Spec.Replicas-1, 0), r.Name)
+	if len(nodeName) > NodeNameLenLimit {
+		maxNameLen := (NodeNameLenLimit - (len(nodeName) - 2*len(r.Name))) / 2
+		allErrs = append(allErrs, field.TooLong(field.NewPath("metadata", "name"), r.Name, maxNameLen))
+	}
+
+	if r.Spec.ApiPort == r.Spec.PeeringPort {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("peeringPort"), r.Spec.PeeringPort, "peeringPort must differ from apiPort"))
+	}
+
+	if r.Spec.Storage == nil {
+		allErrs = append(allErrs, field.Required(specPath.Child("storage"), "storage is required"))
+	} else if old != nil && old.Spec.Storage != nil && r.Spec.Storage.StorageClassName != old.Spec.Storage.StorageClassName {
+		allErrs = append(allErrs, field.Forbidden(specPath.Child("storage", "storageClassName"), "field is immutable"))
+	}
+
+	if r.Spec.AdminApiKey != nil && r.Spec.AdminApiKey.Namespace != "" && r.Spec.AdminApiKey.Namespace != r.Namespace {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("adminApiKey", "namespace"), r.Spec.AdminApiKey.Namespace, "the Secret must be in the namespace of the cluster"))
+	}
+
+	if r.Spec.AdminApiKey != nil && r.Spec.AdminApiKey.CSI != nil {
+		csi := r.Spec.AdminApiKey.CSI
+		if (csi.Driver == "" || csi.Driver == "secrets-store.csi.k8s.io") && csi.VolumeAttributes["secretProviderClass"] == "" {
+			allErrs = append(allErrs, field.Required(specPath.Child("adminApiKey", "csi", "volumeAttributes").Key("secretProviderClass"), "the SecretProviderClass syncing the admin api key to the Secret is required"))
+		}
+	}
+
+	if r.Spec.Ingress != nil {
+		ingressPath := specPath.Child("ingress")
+
+		if r.Spec.Ingress.Host == "" {
+			detail := "host is required"
+			if r.Spec.Ingress.Referer != nil {
+				detail = "host is required when referer is set"
+			}
+			allErrs = append(allErrs, field.Required(ingressPath.Child("host"), detail))
+		}
+		if reverseProxy := r.Spec.Ingress.ReverseProxy; reverseProxy != nil {
+			reverseProxyPath := ingressPath.Child("reverseProxy")
+
+			if reverseProxy.Autoscaling != nil && reverseProxy.Autoscaling.MinReplicas > reverseProxy.Autoscaling.MaxReplicas {
+				allErrs = append(allErrs, field.Invalid(reverseProxyPath.Child("autoscaling", "maxReplicas"), reverseProxy.Autoscaling.MaxReplicas, "maxReplicas must not be less than minReplicas"))
+			}
+			if pdb := reverseProxy.PodDisruptionBudget; pdb != nil && pdb.MinAvailable != nil && pdb.MaxUnavailable != nil {
+				allErrs = append(allErrs, field.Forbidden(reverseProxyPath.Child("podDisruptionBudget", "maxUnavailable"), "maxUnavailable cannot be set together with minAvailable"))
+			}
+		}
+		if rateLimit := r.Spec.Ingress.RateLimit; rateLimit != nil {
+			for i, cidr := range rateLimit.TrustedProxies {
+				if _, _, err := net.ParseCIDR(cidr); err != nil {
+					allErrs = append(allErrs, field.Invalid(ingressPath.Child("rateLimit", "trustedProxies").Index(i), cidr, "must be a CIDR"))
+				}
+			}
+		}
+		if auth := r.Spec.Ingress.Auth; auth != nil {
+			jwksPath := ingressPath.Child("auth", "jwks")
+
+			if auth.JWKS.SecretKeyRef == nil && auth.JWKS.URL == nil {
+				allErrs = append(allErrs, field.Required(jwksPath, "one of secretKeyRef or url must be set"))
+			}
+			if auth.JWKS.SecretKeyRef != nil && auth.JWKS.URL != nil {
+				allErrs = append(allErrs, field.Forbidden(jwksPath.Child("url"), "url cannot be set together with secretKeyRef"))
+			}
+		}
+		if r.Spec.Ingress.IsDirect() {
+			directives := []struct {
+				name  string
+				value *string
+			}{
+				{"httpDirectives", r.Spec.Ingress.HttpDirectives},
+				{"serverDirectives", r.Spec.Ingress.ServerDirectives},
+				{"locationDirectives", r.Spec.Ingress.LocationDirectives},
+			}
+			for _, directive := range directives {
+				if directive.value != nil {
+					allErrs = append(allErrs, field.Forbidden(ingressPath.Child(directive.name), "nginx directives need mode ReverseProxy"))
+				}
+			}
+			if r.Spec.Ingress.Referer != nil {
+				allErrs = append(allErrs, field.Forbidden(ingressPath.Child("referer"), "referer needs mode ReverseProxy"))
+			}
+			if r.Spec.Ingress.RateLimit != nil {
+				allErrs = append(allErrs, field.Forbidden(ingressPath.Child("rateLimit"), "rateLimit needs mode ReverseProxy"))
+			}
+			if r.Spec.Ingress.Cache != nil {
+				allErrs = append(allErrs, field.Forbidden(ingressPath.Child("cache"), "cache needs mode ReverseProxy"))
+			}
+			if r.Spec.Ingress.Auth != nil {
+				allErrs = append(allErrs, field.Forbidden(ingressPath.Child("auth"), "auth needs mode ReverseProxy"))
+			}
+		}
+	}
+
+	if r.Spec.TLS != nil {
+		tlsPath := specPath.Child("tls")
+
+		if r.Spec.TLS.CertManager == nil && r.Spec.TLS.SecretName == nil {
+			allErrs = append(allErrs, field.Required(tlsPath, "one of certManager or secretName must be set"))
+		}
+		if r.Spec.TLS.CertManager != nil && r.Spec.TLS.SecretName != nil {
+			allErrs = append(allErrs, field.Forbidden(tlsPath.Child("secretName"), "secretName cannot be set together with certManager"))
+		}
+	}
+
+	if len(allErrs) == 0 {
+		return nil
+	}
+
+	return apierrors.NewInvalid(GroupVersion.WithKind("TypesenseCluster").GroupKind(), r.Name, allErrs)
+}
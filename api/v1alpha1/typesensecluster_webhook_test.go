@@ -0,0 +1,241 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("TypesenseCluster Webhook", func() {
+	var cluster *TypesenseCluster
+
+	BeforeEach(func() {
+		cluster = &TypesenseCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-" + rand.String(5),
+				Namespace: "default",
+			},
+			Spec: TypesenseClusterSpec{
+				Image:   "typesense/typesense:27.1",
+				Storage: &StorageSpec{StorageClassName: "standard"},
+			},
+		}
+	})
+
+	Context("When creating TypesenseCluster under Defaulting Webhook", func() {
+		It("Should fill in the ports, replicas and ingress path", func() {
+			cluster.Spec.Ingress = &IngressSpec{Host: "search.example.com", IngressClassName: "nginx"}
+			cluster.Default()
+
+			Expect(cluster.Spec.Replicas).To(Equal(int32(3)))
+			Expect(cluster.Spec.ApiPort).To(Equal(8108))
+			Expect(cluster.Spec.PeeringPort).To(Equal(8107))
+			Expect(cluster.Spec.Storage.Size.String()).To(Equal("100Mi"))
+			Expect(cluster.Spec.Ingress.Path).To(Equal("/"))
+			Expect(cluster.Spec.Ingress.PathType).To(Equal(ptr.To(networkingv1.PathTypeImplementationSpecific)))
+			Expect(cluster.Spec.Ingress.Mode).To(Equal(IngressModeReverseProxy))
+		})
+
+		It("Should be defaulted by the api server", func() {
+			Expect(k8sClient.Create(ctx, cluster)).To(Succeed())
+			defer func() {
+				Expect(k8sClient.Delete(ctx, cluster)).To(Succeed())
+			}()
+
+			created := &TypesenseCluster{}
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(cluster), created)).To(Succeed())
+			Expect(created.Spec.ApiPort).To(Equal(8108))
+			Expect(created.Spec.PeeringPort).To(Equal(8107))
+		})
+	})
+
+	Context("When creating TypesenseCluster under Validating Webhook", func() {
+		BeforeEach(func() {
+			cluster.Default()
+		})
+
+		It("Should admit a well formed cluster", func() {
+			_, err := cluster.ValidateCreate()
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should deny a name that makes the node names exceed the raft limit", func() {
+			cluster.Name = strings.Repeat("c", 25)
+
+			_, err := cluster.ValidateCreate()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("metadata.name"))
+			Expect(err.Error()).To(ContainSubstring("at most 24 bytes"))
+		})
+
+		It("Should deny equal api and peering ports", func() {
+			cluster.Spec.PeeringPort = cluster.Spec.ApiPort
+
+			_, err := cluster.ValidateCreate()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.peeringPort"))
+		})
+
+		It("Should deny an admin api key Secret in another namespace", func() {
+			cluster.Spec.AdminApiKey = &AdminApiKeySpec{Name: "admin-key", Namespace: "other"}
+
+			_, err := cluster.ValidateCreate()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.adminApiKey.namespace"))
+		})
+
+		It("Should deny a secrets store CSI volume without a SecretProviderClass", func() {
+			cluster.Spec.AdminApiKey = &AdminApiKeySpec{Name: "admin-key", CSI: &AdminApiKeyCSISpec{VolumeAttributes: map[string]string{}}}
+
+			_, err := cluster.ValidateCreate()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.adminApiKey.csi.volumeAttributes[secretProviderClass]"))
+
+			cluster.Spec.AdminApiKey.CSI.VolumeAttributes["secretProviderClass"] = "typesense"
+			_, err = cluster.ValidateCreate()
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should deny a referer without a host", func() {
+			cluster.Spec.Ingress = &IngressSpec{Referer: ptr.To("www.example.com"), IngressClassName: "nginx"}
+
+			_, err := cluster.ValidateCreate()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("host is required when referer is set"))
+		})
+
+		It("Should deny nginx directives, a referer, rate limits and caching on a Direct ingress", func() {
+			cluster.Spec.Ingress = &IngressSpec{
+				Mode:             IngressModeDirect,
+				Host:             "search.example.com",
+				Referer:          ptr.To("app.example.com"),
+				PathType:         ptr.To(networkingv1.PathTypePrefix),
+				ServerDirectives: ptr.To("client_max_body_size 10m;"),
+				RateLimit:        &RateLimitSpec{RequestsPerSecond: 10, TrustedProxies: []string{"10.0.0.0/8", "10.0.0.1"}},
+				Cache:            &CacheSpec{},
+			}
+
+			_, err := cluster.ValidateCreate()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.ingress.serverDirectives"))
+			Expect(err.Error()).To(ContainSubstring("spec.ingress.referer: Forbidden"))
+			Expect(err.Error()).To(ContainSubstring("spec.ingress.rateLimit: Forbidden"))
+			Expect(err.Error()).To(ContainSubstring("spec.ingress.rateLimit.trustedProxies[1]"))
+			Expect(err.Error()).To(ContainSubstring("spec.ingress.cache: Forbidden"))
+
+			cluster.Spec.Ingress.RateLimit.TrustedProxies = nil
+			cluster.Spec.Ingress.Cache = nil
+
+			cluster.Spec.Ingress.Mode = IngressModeReverseProxy
+			_, err = cluster.ValidateCreate()
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should deny an auth sidecar without exactly one jwks source or on a Direct ingress", func() {
+			cluster.Spec.Ingress = &IngressSpec{
+				Mode:     IngressModeDirect,
+				Host:     "search.example.com",
+				PathType: ptr.To(networkingv1.PathTypePrefix),
+				Auth: &AuthSpec{
+					ParentKeySecretRef: corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "search-key"}, Key: "key"},
+				},
+			}
+
+			_, err := cluster.ValidateCreate()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.ingress.auth: Forbidden"))
+			Expect(err.Error()).To(ContainSubstring("spec.ingress.auth.jwks: Required"))
+
+			cluster.Spec.Ingress.Mode = IngressModeReverseProxy
+			cluster.Spec.Ingress.Auth.JWKS = JWKSSpec{
+				SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "jwks"}, Key: "jwks.json"},
+				URL:          ptr.To("https://idp.example.com/.well-known/jwks.json"),
+			}
+			_, err = cluster.ValidateCreate()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.ingress.auth.jwks.url: Forbidden"))
+
+			cluster.Spec.Ingress.Auth.JWKS.SecretKeyRef = nil
+			_, err = cluster.ValidateCreate()
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should deny inverted autoscaling bounds and a disruption budget with both limits", func() {
+			cluster.Spec.Ingress = &IngressSpec{
+				Host:     "search.example.com",
+				PathType: ptr.To(networkingv1.PathTypePrefix),
+				ReverseProxy: &ReverseProxySpec{
+					Autoscaling: &ReverseProxyAutoscalingSpec{MinReplicas: 4, MaxReplicas: 2},
+					PodDisruptionBudget: &ReverseProxyPodDisruptionBudgetSpec{
+						MinAvailable:   ptr.To(intstr.FromInt32(1)),
+						MaxUnavailable: ptr.To(intstr.FromInt32(1)),
+					},
+				},
+			}
+
+			_, err := cluster.ValidateCreate()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.ingress.reverseProxy.autoscaling.maxReplicas"))
+			Expect(err.Error()).To(ContainSubstring("spec.ingress.reverseProxy.podDisruptionBudget.maxUnavailable"))
+		})
+
+		It("Should be rejected by the api server", func() {
+			cluster.Spec.PeeringPort = cluster.Spec.ApiPort
+
+			err := k8sClient.Create(ctx, cluster)
+			Expect(apierrors.IsInvalid(err) || apierrors.IsForbidden(err)).To(BeTrue())
+		})
+	})
+
+	Context("When updating TypesenseCluster under Validating Webhook", func() {
+		It("Should deny changing the storage class", func() {
+			cluster.Default()
+			old := cluster.DeepCopy()
+			cluster.Spec.Storage.StorageClassName = "premium"
+
+			_, err := cluster.ValidateUpdate(old)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.storage.storageClassName"))
+		})
+
+		It("Should warn that a seed added to an existing cluster is ignored", func() {
+			cluster.Default()
+			old := cluster.DeepCopy()
+			cluster.Spec.Seed = &SeedSpec{Collections: []SeedCollectionSpec{{Name: "products"}}}
+
+			warnings, err := cluster.ValidateUpdate(old)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ConsistOf(ContainSubstring("spec.seed")))
+
+			old.Status.Seed = &SeedStatus{Phase: "Pending"}
+			warnings, err = cluster.ValidateUpdate(old)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,229 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TypesenseCollectionSpec defines the desired state of TypesenseCollection
+type TypesenseCollectionSpec struct {
+	// +kubebuilder:validation:Required
+	ClusterRef corev1.LocalObjectReference `json:"clusterRef"`
+
+	// Name of the alias clients search through, defaults to the name of the resource.
+	// Documents live in the physical collection <name>_v<revision> the alias points to
+	// +optional
+	// +kubebuilder:validation:Pattern:=`^[a-zA-Z0-9_\-]+$`
+	Name *string `json:"name,omitempty"`
+
+	// Revision of the schema. Non-breaking changes (adding or dropping fields) are applied in place,
+	// bumping the revision marks a change as breaking and migrates the documents to a new physical collection
+	// +optional
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=1
+	Revision int `json:"revision,omitempty"`
+
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	Fields []CollectionFieldSpec `json:"fields"`
+
+	// +optional
+	DefaultSortingField *string `json:"defaultSortingField,omitempty"`
+
+	// +optional
+	TokenSeparators []string `json:"tokenSeparators,omitempty"`
+
+	// +optional
+	SymbolsToIndex []string `json:"symbolsToIndex,omitempty"`
+
+	// +optional
+	EnableNestedFields *bool `json:"enableNestedFields,omitempty"`
+
+	// +optional
+	Migration *CollectionMigrationSpec `json:"migration,omitempty"`
+
+	// DeletionPolicy decides whether the alias and its collection are dropped along with the resource
+	// +optional
+	// +kubebuilder:default=Retain
+	// +kubebuilder:validation:Enum=Retain;Delete
+	DeletionPolicy string `json:"deletionPolicy,omitempty"`
+
+	// DriftDetection periodically compares the server state with the spec, by default drift is reverted every 5 minutes
+	// +optional
+	DriftDetection *DriftDetectionSpec `json:"driftDetection,omitempty"`
+}
+
+type CollectionFieldSpec struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Type string `json:"type"`
+
+	// +optional
+	Facet *bool `json:"facet,omitempty"`
+
+	// +optional
+	Optional *bool `json:"optional,omitempty"`
+
+	// +optional
+	Index *bool `json:"index,omitempty"`
+
+	// +optional
+	Sort *bool `json:"sort,omitempty"`
+
+	// +optional
+	Infix *bool `json:"infix,omitempty"`
+
+	// +optional
+	Locale *string `json:"locale,omitempty"`
+
+	// +optional
+	Stem *bool `json:"stem,omitempty"`
+
+	// +optional
+	StemDictionary *string `json:"stemDictionary,omitempty"`
+
+	// +optional
+	Reference *string `json:"reference,omitempty"`
+
+	// +optional
+	NumDim *int `json:"numDim,omitempty"`
+}
+
+type CollectionMigrationSpec struct {
+	// Transform is a jq filter applied to every exported document before it is imported in the new collection,
+	// it has to emit exactly one document per input document for the counts to verify
+	// +optional
+	Transform *string `json:"transform,omitempty"`
+
+	// Image of the copy Job, it has to provide sh, curl and jq, defaults to the job image of the operator which ships them
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// +optional
+	// +kubebuilder:default=1000
+	// +kubebuilder:validation:Minimum=1
+	BatchSize int `json:"batchSize,omitempty"`
+
+	// RetainPrevious keeps the previous physical collection instead of retiring it after the alias swap
+	// +optional
+	RetainPrevious bool `json:"retainPrevious,omitempty"`
+}
+
+type CollectionMigrationPhase string
+
+const (
+	CollectionMigrationPhaseCreating  CollectionMigrationPhase = "Creating"
+	CollectionMigrationPhaseCopying   CollectionMigrationPhase = "Copying"
+	CollectionMigrationPhaseVerifying CollectionMigrationPhase = "Verifying"
+	CollectionMigrationPhaseSwapping  CollectionMigrationPhase = "Swapping"
+	CollectionMigrationPhaseRetiring  CollectionMigrationPhase = "Retiring"
+	CollectionMigrationPhaseCompleted CollectionMigrationPhase = "Completed"
+	CollectionMigrationPhaseFailed    CollectionMigrationPhase = "Failed"
+)
+
+// CollectionMigrationStatus is the checkpoint of a migration, every phase is idempotent
+// so an interrupted migration resumes from the last recorded phase
+type CollectionMigrationStatus struct {
+	// +kubebuilder:validation:Enum=Creating;Copying;Verifying;Swapping;Retiring;Completed;Failed
+	Phase CollectionMigrationPhase `json:"phase"`
+
+	Source string `json:"source"`
+
+	Target string `json:"target"`
+
+	Revision int `json:"revision"`
+
+	// ObservedGeneration is the generation the migration was started or last retried at,
+	// a failed migration is retried once the resource changes again
+	ObservedGeneration int64 `json:"observedGeneration"`
+
+	// +optional
+	Job string `json:"job,omitempty"`
+
+	// SourceDocuments and TargetDocuments report the progress of the copy Job, and are compared once it completed
+	// +optional
+	SourceDocuments int64 `json:"sourceDocuments,omitempty"`
+
+	// +optional
+	TargetDocuments int64 `json:"targetDocuments,omitempty"`
+
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// TypesenseCollectionStatus defines the observed state of TypesenseCollection
+type TypesenseCollectionStatus struct {
+
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status,xDescriptors={"urn:alm:descriptor:io.kubernetes.conditions"}
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
+
+	// Collection is the physical collection the alias currently points to
+	// +optional
+	Collection string `json:"collection,omitempty"`
+
+	// +optional
+	Revision int `json:"revision,omitempty"`
+
+	// +optional
+	Migration *CollectionMigrationStatus `json:"migration,omitempty"`
+
+	// +optional
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+
+// TypesenseCollection is the Schema for the typesensecollections API
+// +kubebuilder:printcolumn:name="Cluster",type=string,JSONPath=`.spec.clusterRef.name`
+// +kubebuilder:printcolumn:name="Collection",type=string,JSONPath=`.status.collection`
+// +kubebuilder:printcolumn:name="Revision",type=integer,JSONPath=`.status.revision`
+// +kubebuilder:printcolumn:name="Migration",type=string,JSONPath=`.status.migration.phase`
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
+type TypesenseCollection struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TypesenseCollectionSpec   `json:"spec,omitempty"`
+	Status TypesenseCollectionStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// TypesenseCollectionList contains a list of TypesenseCollection
+type TypesenseCollectionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []TypesenseCollection `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&TypesenseCollection{}, &TypesenseCollectionList{})
+}
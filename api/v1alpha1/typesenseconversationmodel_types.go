@@ -0,0 +1,118 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TypesenseConversationModelSpec defines the desired state of TypesenseConversationModel
+type TypesenseConversationModelSpec struct {
+	// +kubebuilder:validation:Required
+	ClusterRef corev1.LocalObjectReference `json:"clusterRef"`
+
+	// Name is the id of the model in Typesense, defaults to the name of the resource
+	// +optional
+	// +kubebuilder:validation:Pattern:=`^[a-zA-Z0-9_\-]+$`
+	Name *string `json:"name,omitempty"`
+
+	// ModelName is the LLM to converse with, prefixed with its provider e.g. openai/gpt-4o-mini or vllm/<model>
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern:=`^[a-zA-Z0-9_\-]+/.+$`
+	ModelName string `json:"modelName"`
+
+	// ApiKeySecretRef points to the Secret key holding the API key of the LLM provider
+	// +optional
+	ApiKeySecretRef *corev1.SecretKeySelector `json:"apiKeySecretRef,omitempty"`
+
+	// BaseUrl of a vLLM or OpenAI compatible server, when not using the provider defaults
+	// +optional
+	// +kubebuilder:validation:Pattern:=`^https?://.+$`
+	BaseUrl *string `json:"baseUrl,omitempty"`
+
+	// +optional
+	SystemPrompt *string `json:"systemPrompt,omitempty"`
+
+	// MaxBytes is the maximum number of bytes of context sent to the LLM
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Minimum=1
+	MaxBytes int `json:"maxBytes"`
+
+	// HistoryCollection stores the conversations, it is created if missing
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	HistoryCollection string `json:"historyCollection"`
+
+	// Ttl in seconds of the conversations kept in the history collection
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	Ttl *int `json:"ttl,omitempty"`
+
+	// DriftDetection periodically compares the server state with the spec, by default drift is reverted every 5 minutes
+	// +optional
+	DriftDetection *DriftDetectionSpec `json:"driftDetection,omitempty"`
+}
+
+// TypesenseConversationModelStatus defines the observed state of TypesenseConversationModel
+type TypesenseConversationModelStatus struct {
+
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status,xDescriptors={"urn:alm:descriptor:io.kubernetes.conditions"}
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
+
+	// +optional
+	ModelID string `json:"modelId,omitempty"`
+
+	// ConfigurationHash fingerprints the registered configuration including the API key,
+	// so the model is only registered again when either of them changes
+	// +optional
+	ConfigurationHash string `json:"configurationHash,omitempty"`
+
+	// +optional
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+
+// TypesenseConversationModel is the Schema for the typesenseconversationmodels API
+// +kubebuilder:printcolumn:name="Cluster",type=string,JSONPath=`.spec.clusterRef.name`
+// +kubebuilder:printcolumn:name="Model",type=string,JSONPath=`.spec.modelName`
+// +kubebuilder:printcolumn:name="History",type=string,JSONPath=`.spec.historyCollection`
+// +kubebuilder:printcolumn:name="Last Sync",type=date,JSONPath=`.status.lastSyncTime`
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
+type TypesenseConversationModel struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TypesenseConversationModelSpec   `json:"spec,omitempty"`
+	Status TypesenseConversationModelStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// TypesenseConversationModelList contains a list of TypesenseConversationModel
+type TypesenseConversationModelList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []TypesenseConversationModel `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&TypesenseConversationModel{}, &TypesenseConversationModelList{})
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TypesenseCurationSpec defines the desired state of TypesenseCuration
+type TypesenseCurationSpec struct {
+	// +kubebuilder:validation:Required
+	ClusterRef corev1.LocalObjectReference `json:"clusterRef"`
+
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Collection string `json:"collection"`
+
+	// +optional
+	Rules []CurationRuleSpec `json:"rules,omitempty"`
+
+	// KeepUnmanagedRules leaves alone the overrides of the collection that were not created from the rules of this
+	// curation, by default every override missing from the rules is deleted and reported as drift
+	// +optional
+	KeepUnmanagedRules bool `json:"keepUnmanagedRules,omitempty"`
+
+	// DriftDetection periodically compares the server state with the spec, by default drift is reverted every 5 minutes
+	// +optional
+	DriftDetection *DriftDetectionSpec `json:"driftDetection,omitempty"`
+}
+
+type CurationRuleSpec struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern:=`^[a-zA-Z0-9_\-]+$`
+	Name string `json:"name"`
+
+	// +kubebuilder:validation:Required
+	Rule CurationRuleMatchSpec `json:"rule"`
+
+	// +optional
+	Includes []CurationIncludeSpec `json:"includes,omitempty"`
+
+	// +optional
+	Excludes []CurationExcludeSpec `json:"excludes,omitempty"`
+
+	// +optional
+	FilterBy *string `json:"filterBy,omitempty"`
+
+	// +optional
+	SortBy *string `json:"sortBy,omitempty"`
+
+	// +optional
+	ReplaceQuery *string `json:"replaceQuery,omitempty"`
+
+	// +optional
+	RemoveMatchedTokens *bool `json:"removeMatchedTokens,omitempty"`
+
+	// +optional
+	FilterCuratedHits *bool `json:"filterCuratedHits,omitempty"`
+
+	// +optional
+	StopProcessing *bool `json:"stopProcessing,omitempty"`
+
+	// +optional
+	EffectiveFrom *metav1.Time `json:"effectiveFrom,omitempty"`
+
+	// +optional
+	EffectiveTo *metav1.Time `json:"effectiveTo,omitempty"`
+}
+
+type CurationRuleMatchSpec struct {
+	// +optional
+	Query *string `json:"query,omitempty"`
+
+	// +optional
+	// +kubebuilder:validation:Enum=exact;contains
+	Match *string `json:"match,omitempty"`
+
+	// +optional
+	FilterBy *string `json:"filterBy,omitempty"`
+
+	// +optional
+	Tags []string `json:"tags,omitempty"`
+}
+
+type CurationIncludeSpec struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	ID string `json:"id"`
+
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Minimum=1
+	Position int `json:"position"`
+}
+
+type CurationExcludeSpec struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	ID string `json:"id"`
+}
+
+// TypesenseCurationStatus defines the observed state of TypesenseCuration
+type TypesenseCurationStatus struct {
+
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status,xDescriptors={"urn:alm:descriptor:io.kubernetes.conditions"}
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
+
+	// Rules are the overrides synced by the curation, only these are deleted from the server once they leave the spec
+	// +optional
+	Rules []string `json:"rules,omitempty"`
+
+	// +optional
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+
+// TypesenseCuration is the Schema for the typesensecurations API
+// +kubebuilder:printcolumn:name="Cluster",type=string,JSONPath=`.spec.clusterRef.name`
+// +kubebuilder:printcolumn:name="Collection",type=string,JSONPath=`.spec.collection`
+// +kubebuilder:printcolumn:name="Last Sync",type=date,JSONPath=`.status.lastSyncTime`
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
+type TypesenseCuration struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TypesenseCurationSpec   `json:"spec,omitempty"`
+	Status TypesenseCurationStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// TypesenseCurationList contains a list of TypesenseCuration
+type TypesenseCurationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []TypesenseCuration `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&TypesenseCuration{}, &TypesenseCurationList{})
+}
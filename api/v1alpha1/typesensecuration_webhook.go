@@ -0,0 +1,152 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// log is for logging in this package.
+var typesensecurationlog = logf.Log.WithName("typesensecuration-resource")
+
+// SetupWebhookWithManager will setup the manager to manage the webhooks
+func (r *TypesenseCuration) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+// NOTE: The 'path' attribute must follow a specific pattern and should not be modified directly here.
+// Modifying the path for an invalid path can cause API server errors; failing to locate the webhook.
+// +kubebuilder:webhook:path=/validate-ts-opentelekomcloud-com-v1alpha1-typesensecuration,mutating=false,failurePolicy=fail,sideEffects=None,groups=ts.opentelekomcloud.com,resources=typesensecurations,verbs=create;update,versions=v1alpha1,name=vtypesensecuration.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Validator = &TypesenseCuration{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *TypesenseCuration) ValidateCreate() (admission.Warnings, error) {
+	typesensecurationlog.Info("validate create", "name", r.Name)
+
+	return nil, r.validateCuration(nil)
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *TypesenseCuration) ValidateUpdate(old runtime.Object) (admission.Warnings, error) {
+	typesensecurationlog.Info("validate update", "name", r.Name)
+
+	return nil, r.validateCuration(old.(*TypesenseCuration))
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *TypesenseCuration) ValidateDelete() (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (r *TypesenseCuration) validateCuration(old *TypesenseCuration) error {
+	var allErrs field.ErrorList
+	specPath := field.NewPath("spec")
+
+	if old != nil {
+		if r.Spec.ClusterRef.Name != old.Spec.ClusterRef.Name {
+			allErrs = append(allErrs, field.Forbidden(specPath.Child("clusterRef"), "field is immutable"))
+		}
+		if r.Spec.Collection != old.Spec.Collection {
+			allErrs = append(allErrs, field.Forbidden(specPath.Child("collection"), "field is immutable"))
+		}
+	}
+
+	names := map[string]bool{}
+	for i, rule := range r.Spec.Rules {
+		rulePath := specPath.Child("rules").Index(i)
+
+		if names[rule.Name] {
+			allErrs = append(allErrs, field.Duplicate(rulePath.Child("name"), rule.Name))
+		}
+		names[rule.Name] = true
+
+		allErrs = append(allErrs, validateCurationRule(rule, rulePath)...)
+	}
+
+	if len(allErrs) == 0 {
+		return nil
+	}
+
+	return apierrors.NewInvalid(GroupVersion.WithKind("TypesenseCuration").GroupKind(), r.Name, allErrs)
+}
+
+func validateCurationRule(rule CurationRuleSpec, rulePath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	matchPath := rulePath.Child("rule")
+
+	hasQuery := rule.Rule.Query != nil && *rule.Rule.Query != ""
+	hasMatch := rule.Rule.Match != nil && *rule.Rule.Match != ""
+	hasFilterBy := rule.Rule.FilterBy != nil && *rule.Rule.FilterBy != ""
+
+	if !hasQuery && !hasFilterBy && len(rule.Rule.Tags) == 0 {
+		allErrs = append(allErrs, field.Required(matchPath, "one of query, filterBy or tags must be set"))
+	}
+	if hasQuery && !hasMatch {
+		allErrs = append(allErrs, field.Required(matchPath.Child("match"), "match is required when query is set"))
+	}
+	if hasMatch && !hasQuery {
+		allErrs = append(allErrs, field.Required(matchPath.Child("query"), "query is required when match is set"))
+	}
+
+	if len(rule.Includes) == 0 && len(rule.Excludes) == 0 && rule.FilterBy == nil && rule.SortBy == nil && rule.ReplaceQuery == nil {
+		allErrs = append(allErrs, field.Required(rulePath, "at least one of includes, excludes, filterBy, sortBy or replaceQuery must be set"))
+	}
+
+	included := map[string]bool{}
+	positions := map[int]bool{}
+	for i, include := range rule.Includes {
+		includePath := rulePath.Child("includes").Index(i)
+		if included[include.ID] {
+			allErrs = append(allErrs, field.Duplicate(includePath.Child("id"), include.ID))
+		}
+		if positions[include.Position] {
+			allErrs = append(allErrs, field.Duplicate(includePath.Child("position"), include.Position))
+		}
+		if include.Position < 1 {
+			allErrs = append(allErrs, field.Invalid(includePath.Child("position"), include.Position, "position must be greater than 0"))
+		}
+		included[include.ID] = true
+		positions[include.Position] = true
+	}
+
+	excluded := map[string]bool{}
+	for i, exclude := range rule.Excludes {
+		excludePath := rulePath.Child("excludes").Index(i)
+		if excluded[exclude.ID] {
+			allErrs = append(allErrs, field.Duplicate(excludePath.Child("id"), exclude.ID))
+		}
+		if included[exclude.ID] {
+			allErrs = append(allErrs, field.Invalid(excludePath.Child("id"), exclude.ID, "document cannot be pinned and hidden by the same rule"))
+		}
+		excluded[exclude.ID] = true
+	}
+
+	if rule.EffectiveFrom != nil && rule.EffectiveTo != nil && !rule.EffectiveFrom.Before(rule.EffectiveTo) {
+		allErrs = append(allErrs, field.Invalid(rulePath.Child("effectiveTo"), rule.EffectiveTo.String(), "effectiveTo must be after effectiveFrom"))
+	}
+
+	return allErrs
+}
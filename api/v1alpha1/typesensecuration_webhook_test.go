@@ -0,0 +1,115 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+var _ = Describe("TypesenseCuration Webhook", func() {
+	var curation *TypesenseCuration
+
+	BeforeEach(func() {
+		curation = &TypesenseCuration{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "products-curation",
+				Namespace: "default",
+			},
+			Spec: TypesenseCurationSpec{
+				ClusterRef: corev1.LocalObjectReference{Name: "cluster-1"},
+				Collection: "products",
+				Rules: []CurationRuleSpec{
+					{
+						Name: "pin-apple",
+						Rule: CurationRuleMatchSpec{
+							Query: ptr.To("apple"),
+							Match: ptr.To("exact"),
+						},
+						Includes: []CurationIncludeSpec{{ID: "422", Position: 1}},
+					},
+				},
+			},
+		}
+	})
+
+	Context("When creating TypesenseCuration under Validating Webhook", func() {
+		It("Should admit a well formed rule set", func() {
+			_, err := curation.ValidateCreate()
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should deny a query without a match type", func() {
+			curation.Spec.Rules[0].Rule.Match = nil
+
+			_, err := curation.ValidateCreate()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.rules[0].rule.match"))
+		})
+
+		It("Should deny a rule without any action", func() {
+			curation.Spec.Rules[0].Includes = nil
+
+			_, err := curation.ValidateCreate()
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("Should deny duplicate rule names and pinned hits that are also hidden", func() {
+			rule := curation.Spec.Rules[0]
+			rule.Excludes = []CurationExcludeSpec{{ID: "422"}}
+			curation.Spec.Rules = append(curation.Spec.Rules, rule)
+
+			_, err := curation.ValidateCreate()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.rules[1].name"))
+			Expect(err.Error()).To(ContainSubstring("spec.rules[1].excludes[0].id"))
+		})
+
+		It("Should deny an effective window that ends before it starts", func() {
+			now := time.Now()
+			curation.Spec.Rules[0].EffectiveFrom = &metav1.Time{Time: now}
+			curation.Spec.Rules[0].EffectiveTo = &metav1.Time{Time: now.Add(-time.Hour)}
+
+			_, err := curation.ValidateCreate()
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("Should be rejected by the api server", func() {
+			curation.Spec.Rules[0].Rule.Query = nil
+
+			err := k8sClient.Create(ctx, curation)
+			Expect(apierrors.IsInvalid(err) || apierrors.IsForbidden(err)).To(BeTrue())
+		})
+	})
+
+	Context("When updating TypesenseCuration under Validating Webhook", func() {
+		It("Should deny moving the curation to another collection", func() {
+			old := curation.DeepCopy()
+			curation.Spec.Collection = "articles"
+
+			_, err := curation.ValidateUpdate(old)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.collection"))
+		})
+	})
+})
@@ -0,0 +1,100 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TypesensePresetSpec defines the desired state of TypesensePreset
+type TypesensePresetSpec struct {
+	// +kubebuilder:validation:Required
+	ClusterRef corev1.LocalObjectReference `json:"clusterRef"`
+
+	// Name of the preset in Typesense, defaults to the name of the resource
+	// +optional
+	// +kubebuilder:validation:Pattern:=`^[a-zA-Z0-9_\-]+$`
+	Name *string `json:"name,omitempty"`
+
+	// Search holds the parameters of a single search preset, mutually exclusive with MultiSearch
+	// +optional
+	Search *PresetSearchSpec `json:"search,omitempty"`
+
+	// MultiSearch holds the searches of a multi_search preset, mutually exclusive with Search
+	// +optional
+	MultiSearch []PresetSearchSpec `json:"multiSearch,omitempty"`
+
+	// DriftDetection periodically compares the server state with the spec, by default drift is reverted every 5 minutes
+	// +optional
+	DriftDetection *DriftDetectionSpec `json:"driftDetection,omitempty"`
+}
+
+type PresetSearchSpec struct {
+	// Collection is searched with the parameters, which are validated against the schema of the TypesenseCollection
+	// of the same cluster declaring it, or against the live schema of collections created outside the operator
+	// +optional
+	Collection *string `json:"collection,omitempty"`
+
+	// Parameters are passed as is to Typesense e.g. query_by, sort_by or facet_by
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinProperties=1
+	Parameters map[string]string `json:"parameters"`
+}
+
+// TypesensePresetStatus defines the observed state of TypesensePreset
+type TypesensePresetStatus struct {
+
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status,xDescriptors={"urn:alm:descriptor:io.kubernetes.conditions"}
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
+
+	// +optional
+	PresetName string `json:"presetName,omitempty"`
+
+	// +optional
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+
+// TypesensePreset is the Schema for the typesensepresets API
+// +kubebuilder:printcolumn:name="Cluster",type=string,JSONPath=`.spec.clusterRef.name`
+// +kubebuilder:printcolumn:name="Preset",type=string,JSONPath=`.status.presetName`
+// +kubebuilder:printcolumn:name="Last Sync",type=date,JSONPath=`.status.lastSyncTime`
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
+type TypesensePreset struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TypesensePresetSpec   `json:"spec,omitempty"`
+	Status TypesensePresetStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// TypesensePresetList contains a list of TypesensePreset
+type TypesensePresetList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []TypesensePreset `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&TypesensePreset{}, &TypesensePresetList{})
+}
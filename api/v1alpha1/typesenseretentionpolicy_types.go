@@ -0,0 +1,147 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type RetentionTimestampUnit string
+
+const (
+	RetentionTimestampUnitSeconds      RetentionTimestampUnit = "Seconds"
+	RetentionTimestampUnitMilliseconds RetentionTimestampUnit = "Milliseconds"
+)
+
+// TypesenseRetentionPolicySpec defines the desired state of TypesenseRetentionPolicy
+type TypesenseRetentionPolicySpec struct {
+	// +kubebuilder:validation:Required
+	ClusterRef corev1.LocalObjectReference `json:"clusterRef"`
+
+	// Collection, or alias, the aged documents are deleted from
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Collection string `json:"collection"`
+
+	// TimestampField is the numeric field holding the unix timestamp the age of a document is computed from
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	TimestampField string `json:"timestampField"`
+
+	// +optional
+	// +kubebuilder:default:=Seconds
+	// +kubebuilder:validation:Enum=Seconds;Milliseconds
+	TimestampUnit RetentionTimestampUnit `json:"timestampUnit,omitempty"`
+
+	// MaxAge of the documents that are kept, e.g. 720h
+	// +kubebuilder:validation:Required
+	MaxAge metav1.Duration `json:"maxAge"`
+
+	// Schedule of the deletion in cron format
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern:=`(^((\*\/)?([0-5]?[0-9])((\,|\-|\/)([0-5]?[0-9]))*|\*)\s+((\*\/)?((2[0-3]|1[0-9]|[0-9]|00))((\,|\-|\/)(2[0-3]|1[0-9]|[0-9]|00))*|\*)\s+((\*\/)?([1-9]|[12][0-9]|3[01])((\,|\-|\/)([1-9]|[12][0-9]|3[01]))*|\*)\s+((\*\/)?([1-9]|1[0-2])((\,|\-|\/)([1-9]|1[0-2]))*|\*|(jan|feb|mar|apr|may|jun|jul|aug|sep|oct|nov|des))\s+((\*\/)?[0-6]((\,|\-|\/)[0-6])*|\*|00|(sun|mon|tue|wed|thu|fri|sat))\s*$)|@(annually|yearly|monthly|weekly|daily|hourly|reboot)`
+	// +kubebuilder:validation:Type=string
+	Schedule string `json:"schedule"`
+
+	// BatchSize of the deletion, documents are deleted in batches of this size
+	// +optional
+	// +kubebuilder:default=1000
+	// +kubebuilder:validation:Minimum=1
+	BatchSize int `json:"batchSize,omitempty"`
+
+	// +optional
+	Suspend *bool `json:"suspend,omitempty"`
+
+	// Image of the CronJob deleting the documents, it has to provide sh, curl and jq, defaults to the job image
+	// of the operator which ships them
+	// +kubebuilder:validation:Optional
+	Image string `json:"image,omitempty"`
+}
+
+type RetentionRunStatus struct {
+	JobName string `json:"jobName"`
+
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	Succeeded bool `json:"succeeded"`
+
+	// Deleted is the number of documents deleted by the run
+	// +optional
+	Deleted int64 `json:"deleted,omitempty"`
+
+	// Cutoff is the timestamp documents older than were deleted, in the unit of the timestamp field
+	// +optional
+	Cutoff int64 `json:"cutoff,omitempty"`
+
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// TypesenseRetentionPolicyStatus defines the observed state of TypesenseRetentionPolicy
+type TypesenseRetentionPolicyStatus struct {
+
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status,xDescriptors={"urn:alm:descriptor:io.kubernetes.conditions"}
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
+
+	// +optional
+	CronJobName string `json:"cronJobName,omitempty"`
+
+	// +optional
+	LastRun *RetentionRunStatus `json:"lastRun,omitempty"`
+
+	// TotalDeleted is the number of documents deleted by all the runs observed so far
+	// +optional
+	TotalDeleted int64 `json:"totalDeleted,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+
+// TypesenseRetentionPolicy is the Schema for the typesenseretentionpolicies API
+// +kubebuilder:printcolumn:name="Cluster",type=string,JSONPath=`.spec.clusterRef.name`
+// +kubebuilder:printcolumn:name="Collection",type=string,JSONPath=`.spec.collection`
+// +kubebuilder:printcolumn:name="Max Age",type=string,JSONPath=`.spec.maxAge`
+// +kubebuilder:printcolumn:name="Schedule",type=string,JSONPath=`.spec.schedule`
+// +kubebuilder:printcolumn:name="Last Run",type=date,JSONPath=`.status.lastRun.completionTime`
+// +kubebuilder:printcolumn:name="Deleted",type=integer,JSONPath=`.status.lastRun.deleted`
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
+type TypesenseRetentionPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TypesenseRetentionPolicySpec   `json:"spec,omitempty"`
+	Status TypesenseRetentionPolicyStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// TypesenseRetentionPolicyList contains a list of TypesenseRetentionPolicy
+type TypesenseRetentionPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []TypesenseRetentionPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&TypesenseRetentionPolicy{}, &TypesenseRetentionPolicyList{})
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TypesenseScopedKeySpec defines the desired state of TypesenseScopedKey
+type TypesenseScopedKeySpec struct {
+	// ParentKeySecretRef points to the search-only api key the scoped key is derived from
+	// +kubebuilder:validation:Required
+	ParentKeySecretRef corev1.SecretKeySelector `json:"parentKeySecretRef"`
+
+	// SecretName of the Secret the scoped key is published to, defaults to the name of the resource
+	// +optional
+	SecretName *string `json:"secretName,omitempty"`
+
+	// +optional
+	FilterBy *string `json:"filterBy,omitempty"`
+
+	// ExpiresAt is embedded as expires_at, it cannot be later than the expiration of the parent key
+	// +optional
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	LimitMultiSearches *int `json:"limitMultiSearches,omitempty"`
+}
+
+// TypesenseScopedKeyStatus defines the observed state of TypesenseScopedKey
+type TypesenseScopedKeyStatus struct {
+
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status,xDescriptors={"urn:alm:descriptor:io.kubernetes.conditions"}
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
+
+	// +optional
+	SecretName string `json:"secretName,omitempty"`
+
+	// +optional
+	LastGenerationTime *metav1.Time `json:"lastGenerationTime,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+
+// TypesenseScopedKey is the Schema for the typesensescopedkeys API
+// +kubebuilder:printcolumn:name="Secret",type=string,JSONPath=`.status.secretName`
+// +kubebuilder:printcolumn:name="Expires At",type=date,JSONPath=`.spec.expiresAt`
+// +kubebuilder:printcolumn:name="Last Generation",type=date,JSONPath=`.status.lastGenerationTime`
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
+type TypesenseScopedKey struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TypesenseScopedKeySpec   `json:"spec,omitempty"`
+	Status TypesenseScopedKeyStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// TypesenseScopedKeyList contains a list of TypesenseScopedKey
+type TypesenseScopedKeyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []TypesenseScopedKey `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&TypesenseScopedKey{}, &TypesenseScopedKeyList{})
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TypesenseStemmingDictionarySpec defines the desired state of TypesenseStemmingDictionary
+type TypesenseStemmingDictionarySpec struct {
+	// +kubebuilder:validation:Required
+	ClusterRef corev1.LocalObjectReference `json:"clusterRef"`
+
+	// Locale is the language of the dictionary, it is informational only as Typesense does not take
+	// a locale when importing the words, the dictionary applies to whichever fields reference it
+	// +optional
+	// +kubebuilder:validation:Pattern:=`^[a-z]{2}(-[A-Za-z]{2,4})?$`
+	Locale *string `json:"locale,omitempty"`
+
+	// +optional
+	Words []StemmingWordSpec `json:"words,omitempty"`
+
+	// ConfigMapRef points to a ConfigMap key holding the dictionary as JSONL,
+	// one {"word": "...", "root": "..."} object per line, mutually exclusive with Words
+	// +optional
+	ConfigMapRef *corev1.ConfigMapKeySelector `json:"configMapRef,omitempty"`
+
+	// DriftDetection periodically compares the server state with the spec, by default drift is reverted every 5 minutes
+	// +optional
+	DriftDetection *DriftDetectionSpec `json:"driftDetection,omitempty"`
+}
+
+type StemmingWordSpec struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Word string `json:"word"`
+
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Root string `json:"root"`
+}
+
+// TypesenseStemmingDictionaryStatus defines the observed state of TypesenseStemmingDictionary
+type TypesenseStemmingDictionaryStatus struct {
+
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status,xDescriptors={"urn:alm:descriptor:io.kubernetes.conditions"}
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
+
+	// +optional
+	Count int `json:"count,omitempty"`
+
+	// Collections lists the collections with at least one field stemmed with this dictionary
+	// +optional
+	Collections []string `json:"collections,omitempty"`
+
+	// +optional
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+
+// TypesenseStemmingDictionary is the Schema for the typesensestemmingdictionaries API
+// +kubebuilder:printcolumn:name="Cluster",type=string,JSONPath=`.spec.clusterRef.name`
+// +kubebuilder:printcolumn:name="Locale",type=string,JSONPath=`.spec.locale`
+// +kubebuilder:printcolumn:name="Count",type=integer,JSONPath=`.status.count`
+// +kubebuilder:printcolumn:name="Last Sync",type=date,JSONPath=`.status.lastSyncTime`
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
+type TypesenseStemmingDictionary struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TypesenseStemmingDictionarySpec   `json:"spec,omitempty"`
+	Status TypesenseStemmingDictionaryStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// TypesenseStemmingDictionaryList contains a list of TypesenseStemmingDictionary
+type TypesenseStemmingDictionaryList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []TypesenseStemmingDictionary `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&TypesenseStemmingDictionary{}, &TypesenseStemmingDictionaryList{})
+}
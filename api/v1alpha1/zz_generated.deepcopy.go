@@ -21,191 +21,2725 @@ limitations under the License.
 package v1alpha1
 
 import (
-	"k8s.io/api/core/v1"
+	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AdminApiKeyCSISpec) DeepCopyInto(out *AdminApiKeyCSISpec) {
+	*out = *in
+	if in.VolumeAttributes != nil {
+		in, out := &in.VolumeAttributes, &out.VolumeAttributes
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdminApiKeyCSISpec.
+func (in *AdminApiKeyCSISpec) DeepCopy() *AdminApiKeyCSISpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AdminApiKeyCSISpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AdminApiKeyRotationSpec) DeepCopyInto(out *AdminApiKeyRotationSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdminApiKeyRotationSpec.
+func (in *AdminApiKeyRotationSpec) DeepCopy() *AdminApiKeyRotationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AdminApiKeyRotationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AdminApiKeySpec) DeepCopyInto(out *AdminApiKeySpec) {
+	*out = *in
+	if in.CSI != nil {
+		in, out := &in.CSI, &out.CSI
+		*out = new(AdminApiKeyCSISpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdminApiKeySpec.
+func (in *AdminApiKeySpec) DeepCopy() *AdminApiKeySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AdminApiKeySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AdminApiKeyStatus) DeepCopyInto(out *AdminApiKeyStatus) {
+	*out = *in
+	if in.LastRotationTime != nil {
+		in, out := &in.LastRotationTime, &out.LastRotationTime
+		*out = (*in).DeepCopy()
+	}
+	if in.OverlapKeyIDs != nil {
+		in, out := &in.OverlapKeyIDs, &out.OverlapKeyIDs
+		*out = make([]int64, len(*in))
+		copy(*out, *in)
+	}
+	if in.OverlapExpirationTime != nil {
+		in, out := &in.OverlapExpirationTime, &out.OverlapExpirationTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdminApiKeyStatus.
+func (in *AdminApiKeyStatus) DeepCopy() *AdminApiKeyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AdminApiKeyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AnalyticsRuleDestinationSpec) DeepCopyInto(out *AnalyticsRuleDestinationSpec) {
+	*out = *in
+	if in.CounterField != nil {
+		in, out := &in.CounterField, &out.CounterField
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AnalyticsRuleDestinationSpec.
+func (in *AnalyticsRuleDestinationSpec) DeepCopy() *AnalyticsRuleDestinationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AnalyticsRuleDestinationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AnalyticsRuleEventSpec) DeepCopyInto(out *AnalyticsRuleEventSpec) {
+	*out = *in
+	if in.Weight != nil {
+		in, out := &in.Weight, &out.Weight
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AnalyticsRuleEventSpec.
+func (in *AnalyticsRuleEventSpec) DeepCopy() *AnalyticsRuleEventSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AnalyticsRuleEventSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AnalyticsRuleSourceSpec) DeepCopyInto(out *AnalyticsRuleSourceSpec) {
+	*out = *in
+	if in.Collections != nil {
+		in, out := &in.Collections, &out.Collections
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Events != nil {
+		in, out := &in.Events, &out.Events
+		*out = make([]AnalyticsRuleEventSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AnalyticsRuleSourceSpec.
+func (in *AnalyticsRuleSourceSpec) DeepCopy() *AnalyticsRuleSourceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AnalyticsRuleSourceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApiKeyRateLimitSpec) DeepCopyInto(out *ApiKeyRateLimitSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApiKeyRateLimitSpec.
+func (in *ApiKeyRateLimitSpec) DeepCopy() *ApiKeyRateLimitSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ApiKeyRateLimitSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuthSpec) DeepCopyInto(out *AuthSpec) {
+	*out = *in
+	in.JWKS.DeepCopyInto(&out.JWKS)
+	if in.Issuer != nil {
+		in, out := &in.Issuer, &out.Issuer
+		*out = new(string)
+		**out = **in
+	}
+	if in.Audiences != nil {
+		in, out := &in.Audiences, &out.Audiences
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.ParentKeySecretRef.DeepCopyInto(&out.ParentKeySecretRef)
+	if in.Claims != nil {
+		in, out := &in.Claims, &out.Claims
+		*out = make([]ClaimMappingSpec, len(*in))
+		copy(*out, *in)
+	}
+	if in.FilterBy != nil {
+		in, out := &in.FilterBy, &out.FilterBy
+		*out = new(string)
+		**out = **in
+	}
+	if in.LimitMultiSearches != nil {
+		in, out := &in.LimitMultiSearches, &out.LimitMultiSearches
+		*out = new(int)
+		**out = **in
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecurityContext != nil {
+		in, out := &in.SecurityContext, &out.SecurityContext
+		*out = new(corev1.SecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthSpec.
+func (in *AuthSpec) DeepCopy() *AuthSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AuthSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CacheSpec) DeepCopyInto(out *CacheSpec) {
+	*out = *in
+	if in.TTL != nil {
+		in, out := &in.TTL, &out.TTL
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.MaxSize != nil {
+		in, out := &in.MaxSize, &out.MaxSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CacheSpec.
+func (in *CacheSpec) DeepCopy() *CacheSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CacheSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClaimMappingSpec) DeepCopyInto(out *ClaimMappingSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClaimMappingSpec.
+func (in *ClaimMappingSpec) DeepCopy() *ClaimMappingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClaimMappingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CollectionFieldSpec) DeepCopyInto(out *CollectionFieldSpec) {
+	*out = *in
+	if in.Facet != nil {
+		in, out := &in.Facet, &out.Facet
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Optional != nil {
+		in, out := &in.Optional, &out.Optional
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Index != nil {
+		in, out := &in.Index, &out.Index
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Sort != nil {
+		in, out := &in.Sort, &out.Sort
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Infix != nil {
+		in, out := &in.Infix, &out.Infix
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Locale != nil {
+		in, out := &in.Locale, &out.Locale
+		*out = new(string)
+		**out = **in
+	}
+	if in.Stem != nil {
+		in, out := &in.Stem, &out.Stem
+		*out = new(bool)
+		**out = **in
+	}
+	if in.StemDictionary != nil {
+		in, out := &in.StemDictionary, &out.StemDictionary
+		*out = new(string)
+		**out = **in
+	}
+	if in.Reference != nil {
+		in, out := &in.Reference, &out.Reference
+		*out = new(string)
+		**out = **in
+	}
+	if in.NumDim != nil {
+		in, out := &in.NumDim, &out.NumDim
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CollectionFieldSpec.
+func (in *CollectionFieldSpec) DeepCopy() *CollectionFieldSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CollectionFieldSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CollectionMigrationSpec) DeepCopyInto(out *CollectionMigrationSpec) {
+	*out = *in
+	if in.Transform != nil {
+		in, out := &in.Transform, &out.Transform
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CollectionMigrationSpec.
+func (in *CollectionMigrationSpec) DeepCopy() *CollectionMigrationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CollectionMigrationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CollectionMigrationStatus) DeepCopyInto(out *CollectionMigrationStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CollectionMigrationStatus.
+func (in *CollectionMigrationStatus) DeepCopy() *CollectionMigrationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CollectionMigrationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CurationExcludeSpec) DeepCopyInto(out *CurationExcludeSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CurationExcludeSpec.
+func (in *CurationExcludeSpec) DeepCopy() *CurationExcludeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CurationExcludeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CurationIncludeSpec) DeepCopyInto(out *CurationIncludeSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CurationIncludeSpec.
+func (in *CurationIncludeSpec) DeepCopy() *CurationIncludeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CurationIncludeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CurationRuleMatchSpec) DeepCopyInto(out *CurationRuleMatchSpec) {
+	*out = *in
+	if in.Query != nil {
+		in, out := &in.Query, &out.Query
+		*out = new(string)
+		**out = **in
+	}
+	if in.Match != nil {
+		in, out := &in.Match, &out.Match
+		*out = new(string)
+		**out = **in
+	}
+	if in.FilterBy != nil {
+		in, out := &in.FilterBy, &out.FilterBy
+		*out = new(string)
+		**out = **in
+	}
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CurationRuleMatchSpec.
+func (in *CurationRuleMatchSpec) DeepCopy() *CurationRuleMatchSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CurationRuleMatchSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CurationRuleSpec) DeepCopyInto(out *CurationRuleSpec) {
+	*out = *in
+	in.Rule.DeepCopyInto(&out.Rule)
+	if in.Includes != nil {
+		in, out := &in.Includes, &out.Includes
+		*out = make([]CurationIncludeSpec, len(*in))
+		copy(*out, *in)
+	}
+	if in.Excludes != nil {
+		in, out := &in.Excludes, &out.Excludes
+		*out = make([]CurationExcludeSpec, len(*in))
+		copy(*out, *in)
+	}
+	if in.FilterBy != nil {
+		in, out := &in.FilterBy, &out.FilterBy
+		*out = new(string)
+		**out = **in
+	}
+	if in.SortBy != nil {
+		in, out := &in.SortBy, &out.SortBy
+		*out = new(string)
+		**out = **in
+	}
+	if in.ReplaceQuery != nil {
+		in, out := &in.ReplaceQuery, &out.ReplaceQuery
+		*out = new(string)
+		**out = **in
+	}
+	if in.RemoveMatchedTokens != nil {
+		in, out := &in.RemoveMatchedTokens, &out.RemoveMatchedTokens
+		*out = new(bool)
+		**out = **in
+	}
+	if in.FilterCuratedHits != nil {
+		in, out := &in.FilterCuratedHits, &out.FilterCuratedHits
+		*out = new(bool)
+		**out = **in
+	}
+	if in.StopProcessing != nil {
+		in, out := &in.StopProcessing, &out.StopProcessing
+		*out = new(bool)
+		**out = **in
+	}
+	if in.EffectiveFrom != nil {
+		in, out := &in.EffectiveFrom, &out.EffectiveFrom
+		*out = (*in).DeepCopy()
+	}
+	if in.EffectiveTo != nil {
+		in, out := &in.EffectiveTo, &out.EffectiveTo
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CurationRuleSpec.
+func (in *CurationRuleSpec) DeepCopy() *CurationRuleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CurationRuleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DocSearchScraperSpec) DeepCopyInto(out *DocSearchScraperSpec) {
 	*out = *in
-	if in.AuthConfiguration != nil {
-		in, out := &in.AuthConfiguration, &out.AuthConfiguration
-		*out = new(v1.LocalObjectReference)
+	if in.AuthConfiguration != nil {
+		in, out := &in.AuthConfiguration, &out.AuthConfiguration
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DocSearchScraperSpec.
+func (in *DocSearchScraperSpec) DeepCopy() *DocSearchScraperSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DocSearchScraperSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DriftDetectionSpec) DeepCopyInto(out *DriftDetectionSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DriftDetectionSpec.
+func (in *DriftDetectionSpec) DeepCopy() *DriftDetectionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DriftDetectionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FileSourceSpec) DeepCopyInto(out *FileSourceSpec) {
+	*out = *in
+	if in.PersistentVolumeClaim != nil {
+		in, out := &in.PersistentVolumeClaim, &out.PersistentVolumeClaim
+		*out = new(SeedPersistentVolumeClaimSpec)
+		**out = **in
+	}
+	if in.Url != nil {
+		in, out := &in.Url, &out.Url
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FileSourceSpec.
+func (in *FileSourceSpec) DeepCopy() *FileSourceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FileSourceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayHeaderMatch) DeepCopyInto(out *GatewayHeaderMatch) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayHeaderMatch.
+func (in *GatewayHeaderMatch) DeepCopy() *GatewayHeaderMatch {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayHeaderMatch)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayParentReference) DeepCopyInto(out *GatewayParentReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayParentReference.
+func (in *GatewayParentReference) DeepCopy() *GatewayParentReference {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayParentReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayPathMatch) DeepCopyInto(out *GatewayPathMatch) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayPathMatch.
+func (in *GatewayPathMatch) DeepCopy() *GatewayPathMatch {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayPathMatch)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewaySpec) DeepCopyInto(out *GatewaySpec) {
+	*out = *in
+	out.ParentRef = in.ParentRef
+	if in.Hostnames != nil {
+		in, out := &in.Hostnames, &out.Hostnames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Paths != nil {
+		in, out := &in.Paths, &out.Paths
+		*out = make([]GatewayPathMatch, len(*in))
+		copy(*out, *in)
+	}
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make([]GatewayHeaderMatch, len(*in))
+		copy(*out, *in)
+	}
+	if in.Referer != nil {
+		in, out := &in.Referer, &out.Referer
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewaySpec.
+func (in *GatewaySpec) DeepCopy() *GatewaySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewaySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealthCheckSpec) DeepCopyInto(out *HealthCheckSpec) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecurityContext != nil {
+		in, out := &in.SecurityContext, &out.SecurityContext
+		*out = new(corev1.SecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthCheckSpec.
+func (in *HealthCheckSpec) DeepCopy() *HealthCheckSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HealthCheckSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealthyLagThresholdsSpec) DeepCopyInto(out *HealthyLagThresholdsSpec) {
+	*out = *in
+	if in.Read != nil {
+		in, out := &in.Read, &out.Read
+		*out = new(int)
+		**out = **in
+	}
+	if in.Write != nil {
+		in, out := &in.Write, &out.Write
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthyLagThresholdsSpec.
+func (in *HealthyLagThresholdsSpec) DeepCopy() *HealthyLagThresholdsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HealthyLagThresholdsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IngestionFieldMappingSpec) DeepCopyInto(out *IngestionFieldMappingSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngestionFieldMappingSpec.
+func (in *IngestionFieldMappingSpec) DeepCopy() *IngestionFieldMappingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IngestionFieldMappingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IngestionRunStatus) DeepCopyInto(out *IngestionRunStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngestionRunStatus.
+func (in *IngestionRunStatus) DeepCopy() *IngestionRunStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(IngestionRunStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IngestionSourceSpec) DeepCopyInto(out *IngestionSourceSpec) {
+	*out = *in
+	if in.Postgres != nil {
+		in, out := &in.Postgres, &out.Postgres
+		*out = new(PostgresSourceSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.File != nil {
+		in, out := &in.File, &out.File
+		*out = new(FileSourceSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngestionSourceSpec.
+func (in *IngestionSourceSpec) DeepCopy() *IngestionSourceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IngestionSourceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IngressSpec) DeepCopyInto(out *IngressSpec) {
+	*out = *in
+	if in.Referer != nil {
+		in, out := &in.Referer, &out.Referer
+		*out = new(string)
+		**out = **in
+	}
+	if in.HttpDirectives != nil {
+		in, out := &in.HttpDirectives, &out.HttpDirectives
+		*out = new(string)
+		**out = **in
+	}
+	if in.ServerDirectives != nil {
+		in, out := &in.ServerDirectives, &out.ServerDirectives
+		*out = new(string)
+		**out = **in
+	}
+	if in.LocationDirectives != nil {
+		in, out := &in.LocationDirectives, &out.LocationDirectives
+		*out = new(string)
+		**out = **in
+	}
+	if in.ClusterIssuer != nil {
+		in, out := &in.ClusterIssuer, &out.ClusterIssuer
+		*out = new(string)
+		**out = **in
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.TLSSecretName != nil {
+		in, out := &in.TLSSecretName, &out.TLSSecretName
+		*out = new(string)
+		**out = **in
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReadOnlyRootFilesystem != nil {
+		in, out := &in.ReadOnlyRootFilesystem, &out.ReadOnlyRootFilesystem
+		*out = new(ReadOnlyRootFilesystemSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReverseProxy != nil {
+		in, out := &in.ReverseProxy, &out.ReverseProxy
+		*out = new(ReverseProxySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RateLimit != nil {
+		in, out := &in.RateLimit, &out.RateLimit
+		*out = new(RateLimitSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Cache != nil {
+		in, out := &in.Cache, &out.Cache
+		*out = new(CacheSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Auth != nil {
+		in, out := &in.Auth, &out.Auth
+		*out = new(AuthSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PathType != nil {
+		in, out := &in.PathType, &out.PathType
+		*out = new(networkingv1.PathType)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngressSpec.
+func (in *IngressSpec) DeepCopy() *IngressSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IngressSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JWKSSpec) DeepCopyInto(out *JWKSSpec) {
+	*out = *in
+	if in.SecretKeyRef != nil {
+		in, out := &in.SecretKeyRef, &out.SecretKeyRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.URL != nil {
+		in, out := &in.URL, &out.URL
+		*out = new(string)
+		**out = **in
+	}
+	if in.RefreshInterval != nil {
+		in, out := &in.RefreshInterval, &out.RefreshInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JWKSSpec.
+func (in *JWKSSpec) DeepCopy() *JWKSSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(JWKSSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetricsExporterSpec) DeepCopyInto(out *MetricsExporterSpec) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecurityContext != nil {
+		in, out := &in.SecurityContext, &out.SecurityContext
+		*out = new(corev1.SecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricsExporterSpec.
+func (in *MetricsExporterSpec) DeepCopy() *MetricsExporterSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MetricsExporterSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkPolicySpec) DeepCopyInto(out *NetworkPolicySpec) {
+	*out = *in
+	if in.ApiClients != nil {
+		in, out := &in.ApiClients, &out.ApiClients
+		*out = make([]networkingv1.NetworkPolicyPeer, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkPolicySpec.
+func (in *NetworkPolicySpec) DeepCopy() *NetworkPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostgresSourceSpec) DeepCopyInto(out *PostgresSourceSpec) {
+	*out = *in
+	in.ConnectionSecretRef.DeepCopyInto(&out.ConnectionSecretRef)
+	if in.InitialCursor != nil {
+		in, out := &in.InitialCursor, &out.InitialCursor
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresSourceSpec.
+func (in *PostgresSourceSpec) DeepCopy() *PostgresSourceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgresSourceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PresetSearchSpec) DeepCopyInto(out *PresetSearchSpec) {
+	*out = *in
+	if in.Collection != nil {
+		in, out := &in.Collection, &out.Collection
+		*out = new(string)
+		**out = **in
+	}
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PresetSearchSpec.
+func (in *PresetSearchSpec) DeepCopy() *PresetSearchSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PresetSearchSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RateLimitSpec) DeepCopyInto(out *RateLimitSpec) {
+	*out = *in
+	if in.ApiKey != nil {
+		in, out := &in.ApiKey, &out.ApiKey
+		*out = new(ApiKeyRateLimitSpec)
+		**out = **in
+	}
+	if in.ConnectionsPerClient != nil {
+		in, out := &in.ConnectionsPerClient, &out.ConnectionsPerClient
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TrustedProxies != nil {
+		in, out := &in.TrustedProxies, &out.TrustedProxies
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ResponseBody != nil {
+		in, out := &in.ResponseBody, &out.ResponseBody
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RateLimitSpec.
+func (in *RateLimitSpec) DeepCopy() *RateLimitSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RateLimitSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReadOnlyRootFilesystemSpec) DeepCopyInto(out *ReadOnlyRootFilesystemSpec) {
+	*out = *in
+	if in.SecurityContext != nil {
+		in, out := &in.SecurityContext, &out.SecurityContext
+		*out = new(corev1.SecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Volumes != nil {
+		in, out := &in.Volumes, &out.Volumes
+		*out = make([]corev1.Volume, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.VolumeMounts != nil {
+		in, out := &in.VolumeMounts, &out.VolumeMounts
+		*out = make([]corev1.VolumeMount, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReadOnlyRootFilesystemSpec.
+func (in *ReadOnlyRootFilesystemSpec) DeepCopy() *ReadOnlyRootFilesystemSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ReadOnlyRootFilesystemSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RetentionRunStatus) DeepCopyInto(out *RetentionRunStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RetentionRunStatus.
+func (in *RetentionRunStatus) DeepCopy() *RetentionRunStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RetentionRunStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReverseProxyAutoscalingSpec) DeepCopyInto(out *ReverseProxyAutoscalingSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReverseProxyAutoscalingSpec.
+func (in *ReverseProxyAutoscalingSpec) DeepCopy() *ReverseProxyAutoscalingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ReverseProxyAutoscalingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReverseProxyPodDisruptionBudgetSpec) DeepCopyInto(out *ReverseProxyPodDisruptionBudgetSpec) {
+	*out = *in
+	if in.MinAvailable != nil {
+		in, out := &in.MinAvailable, &out.MinAvailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReverseProxyPodDisruptionBudgetSpec.
+func (in *ReverseProxyPodDisruptionBudgetSpec) DeepCopy() *ReverseProxyPodDisruptionBudgetSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ReverseProxyPodDisruptionBudgetSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReverseProxySpec) DeepCopyInto(out *ReverseProxySpec) {
+	*out = *in
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(ReverseProxyAutoscalingSpec)
+		**out = **in
+	}
+	if in.ReadinessProbe != nil {
+		in, out := &in.ReadinessProbe, &out.ReadinessProbe
+		*out = new(corev1.Probe)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LivenessProbe != nil {
+		in, out := &in.LivenessProbe, &out.LivenessProbe
+		*out = new(corev1.Probe)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(corev1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.TopologySpreadConstraints != nil {
+		in, out := &in.TopologySpreadConstraints, &out.TopologySpreadConstraints
+		*out = make([]corev1.TopologySpreadConstraint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PodDisruptionBudget != nil {
+		in, out := &in.PodDisruptionBudget, &out.PodDisruptionBudget
+		*out = new(ReverseProxyPodDisruptionBudgetSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReverseProxySpec.
+func (in *ReverseProxySpec) DeepCopy() *ReverseProxySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ReverseProxySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SeedCollectionSpec) DeepCopyInto(out *SeedCollectionSpec) {
+	*out = *in
+	if in.Fields != nil {
+		in, out := &in.Fields, &out.Fields
+		*out = make([]CollectionFieldSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DefaultSortingField != nil {
+		in, out := &in.DefaultSortingField, &out.DefaultSortingField
+		*out = new(string)
+		**out = **in
+	}
+	if in.Documents != nil {
+		in, out := &in.Documents, &out.Documents
+		*out = make([]SeedDocumentsSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SeedCollectionSpec.
+func (in *SeedCollectionSpec) DeepCopy() *SeedCollectionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SeedCollectionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SeedDocumentsSpec) DeepCopyInto(out *SeedDocumentsSpec) {
+	*out = *in
+	if in.ConfigMapRef != nil {
+		in, out := &in.ConfigMapRef, &out.ConfigMapRef
+		*out = new(corev1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PersistentVolumeClaim != nil {
+		in, out := &in.PersistentVolumeClaim, &out.PersistentVolumeClaim
+		*out = new(SeedPersistentVolumeClaimSpec)
+		**out = **in
+	}
+	if in.Url != nil {
+		in, out := &in.Url, &out.Url
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SeedDocumentsSpec.
+func (in *SeedDocumentsSpec) DeepCopy() *SeedDocumentsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SeedDocumentsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SeedPersistentVolumeClaimSpec) DeepCopyInto(out *SeedPersistentVolumeClaimSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SeedPersistentVolumeClaimSpec.
+func (in *SeedPersistentVolumeClaimSpec) DeepCopy() *SeedPersistentVolumeClaimSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SeedPersistentVolumeClaimSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SeedSpec) DeepCopyInto(out *SeedSpec) {
+	*out = *in
+	if in.Collections != nil {
+		in, out := &in.Collections, &out.Collections
+		*out = make([]SeedCollectionSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SeedSpec.
+func (in *SeedSpec) DeepCopy() *SeedSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SeedSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SeedStatus) DeepCopyInto(out *SeedStatus) {
+	*out = *in
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SeedStatus.
+func (in *SeedStatus) DeepCopy() *SeedStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SeedStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StemmingWordSpec) DeepCopyInto(out *StemmingWordSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StemmingWordSpec.
+func (in *StemmingWordSpec) DeepCopy() *StemmingWordSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(StemmingWordSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageSpec) DeepCopyInto(out *StorageSpec) {
+	*out = *in
+	out.Size = in.Size.DeepCopy()
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageSpec.
+func (in *StorageSpec) DeepCopy() *StorageSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TLSCertManagerSpec) DeepCopyInto(out *TLSCertManagerSpec) {
+	*out = *in
+	out.IssuerRef = in.IssuerRef
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.RenewBefore != nil {
+		in, out := &in.RenewBefore, &out.RenewBefore
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TLSCertManagerSpec.
+func (in *TLSCertManagerSpec) DeepCopy() *TLSCertManagerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TLSCertManagerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TLSIssuerReference) DeepCopyInto(out *TLSIssuerReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TLSIssuerReference.
+func (in *TLSIssuerReference) DeepCopy() *TLSIssuerReference {
+	if in == nil {
+		return nil
+	}
+	out := new(TLSIssuerReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TLSSpec) DeepCopyInto(out *TLSSpec) {
+	*out = *in
+	if in.CertManager != nil {
+		in, out := &in.CertManager, &out.CertManager
+		*out = new(TLSCertManagerSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecretName != nil {
+		in, out := &in.SecretName, &out.SecretName
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TLSSpec.
+func (in *TLSSpec) DeepCopy() *TLSSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TLSSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TypesenseAnalyticsRule) DeepCopyInto(out *TypesenseAnalyticsRule) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TypesenseAnalyticsRule.
+func (in *TypesenseAnalyticsRule) DeepCopy() *TypesenseAnalyticsRule {
+	if in == nil {
+		return nil
+	}
+	out := new(TypesenseAnalyticsRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TypesenseAnalyticsRule) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TypesenseAnalyticsRuleList) DeepCopyInto(out *TypesenseAnalyticsRuleList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]TypesenseAnalyticsRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TypesenseAnalyticsRuleList.
+func (in *TypesenseAnalyticsRuleList) DeepCopy() *TypesenseAnalyticsRuleList {
+	if in == nil {
+		return nil
+	}
+	out := new(TypesenseAnalyticsRuleList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TypesenseAnalyticsRuleList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TypesenseAnalyticsRuleSpec) DeepCopyInto(out *TypesenseAnalyticsRuleSpec) {
+	*out = *in
+	out.ClusterRef = in.ClusterRef
+	if in.Name != nil {
+		in, out := &in.Name, &out.Name
+		*out = new(string)
+		**out = **in
+	}
+	in.Source.DeepCopyInto(&out.Source)
+	if in.Destination != nil {
+		in, out := &in.Destination, &out.Destination
+		*out = new(AnalyticsRuleDestinationSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Limit != nil {
+		in, out := &in.Limit, &out.Limit
+		*out = new(int)
+		**out = **in
+	}
+	if in.ExpandQuery != nil {
+		in, out := &in.ExpandQuery, &out.ExpandQuery
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DriftDetection != nil {
+		in, out := &in.DriftDetection, &out.DriftDetection
+		*out = new(DriftDetectionSpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TypesenseAnalyticsRuleSpec.
+func (in *TypesenseAnalyticsRuleSpec) DeepCopy() *TypesenseAnalyticsRuleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TypesenseAnalyticsRuleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TypesenseAnalyticsRuleStatus) DeepCopyInto(out *TypesenseAnalyticsRuleStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastSyncTime != nil {
+		in, out := &in.LastSyncTime, &out.LastSyncTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TypesenseAnalyticsRuleStatus.
+func (in *TypesenseAnalyticsRuleStatus) DeepCopy() *TypesenseAnalyticsRuleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TypesenseAnalyticsRuleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TypesenseCluster) DeepCopyInto(out *TypesenseCluster) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TypesenseCluster.
+func (in *TypesenseCluster) DeepCopy() *TypesenseCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(TypesenseCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TypesenseCluster) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TypesenseClusterList) DeepCopyInto(out *TypesenseClusterList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]TypesenseCluster, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TypesenseClusterList.
+func (in *TypesenseClusterList) DeepCopy() *TypesenseClusterList {
+	if in == nil {
+		return nil
+	}
+	out := new(TypesenseClusterList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TypesenseClusterList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TypesenseClusterSpec) DeepCopyInto(out *TypesenseClusterSpec) {
+	*out = *in
+	if in.AdminApiKey != nil {
+		in, out := &in.AdminApiKey, &out.AdminApiKey
+		*out = new(AdminApiKeySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AdminApiKeyRotation != nil {
+		in, out := &in.AdminApiKeyRotation, &out.AdminApiKeyRotation
+		*out = new(AdminApiKeyRotationSpec)
+		**out = **in
+	}
+	if in.CorsDomains != nil {
+		in, out := &in.CorsDomains, &out.CorsDomains
+		*out = new(string)
+		**out = **in
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(corev1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PodSecurityContext != nil {
+		in, out := &in.PodSecurityContext, &out.PodSecurityContext
+		*out = new(corev1.PodSecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecurityContext != nil {
+		in, out := &in.SecurityContext, &out.SecurityContext
+		*out = new(corev1.SecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AdditionalServerConfiguration != nil {
+		in, out := &in.AdditionalServerConfiguration, &out.AdditionalServerConfiguration
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+	if in.Storage != nil {
+		in, out := &in.Storage, &out.Storage
+		*out = new(StorageSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Ingress != nil {
+		in, out := &in.Ingress, &out.Ingress
+		*out = new(IngressSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Scrapers != nil {
+		in, out := &in.Scrapers, &out.Scrapers
+		*out = make([]DocSearchScraperSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = new(MetricsExporterSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HealthCheck != nil {
+		in, out := &in.HealthCheck, &out.HealthCheck
+		*out = new(HealthCheckSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TopologySpreadConstraints != nil {
+		in, out := &in.TopologySpreadConstraints, &out.TopologySpreadConstraints
+		*out = make([]corev1.TopologySpreadConstraint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.HealthyLagThresholds != nil {
+		in, out := &in.HealthyLagThresholds, &out.HealthyLagThresholds
+		*out = new(HealthyLagThresholdsSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Seed != nil {
+		in, out := &in.Seed, &out.Seed
+		*out = new(SeedSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(TLSSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NetworkPolicy != nil {
+		in, out := &in.NetworkPolicy, &out.NetworkPolicy
+		*out = new(NetworkPolicySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Gateway != nil {
+		in, out := &in.Gateway, &out.Gateway
+		*out = new(GatewaySpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TypesenseClusterSpec.
+func (in *TypesenseClusterSpec) DeepCopy() *TypesenseClusterSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TypesenseClusterSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TypesenseClusterStatus) DeepCopyInto(out *TypesenseClusterStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Seed != nil {
+		in, out := &in.Seed, &out.Seed
+		*out = new(SeedStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AdminApiKey != nil {
+		in, out := &in.AdminApiKey, &out.AdminApiKey
+		*out = new(AdminApiKeyStatus)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TypesenseClusterStatus.
+func (in *TypesenseClusterStatus) DeepCopy() *TypesenseClusterStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TypesenseClusterStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TypesenseCollection) DeepCopyInto(out *TypesenseCollection) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TypesenseCollection.
+func (in *TypesenseCollection) DeepCopy() *TypesenseCollection {
+	if in == nil {
+		return nil
+	}
+	out := new(TypesenseCollection)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TypesenseCollection) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TypesenseCollectionList) DeepCopyInto(out *TypesenseCollectionList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]TypesenseCollection, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TypesenseCollectionList.
+func (in *TypesenseCollectionList) DeepCopy() *TypesenseCollectionList {
+	if in == nil {
+		return nil
+	}
+	out := new(TypesenseCollectionList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TypesenseCollectionList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TypesenseCollectionSpec) DeepCopyInto(out *TypesenseCollectionSpec) {
+	*out = *in
+	out.ClusterRef = in.ClusterRef
+	if in.Name != nil {
+		in, out := &in.Name, &out.Name
+		*out = new(string)
+		**out = **in
+	}
+	if in.Fields != nil {
+		in, out := &in.Fields, &out.Fields
+		*out = make([]CollectionFieldSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DefaultSortingField != nil {
+		in, out := &in.DefaultSortingField, &out.DefaultSortingField
+		*out = new(string)
+		**out = **in
+	}
+	if in.TokenSeparators != nil {
+		in, out := &in.TokenSeparators, &out.TokenSeparators
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SymbolsToIndex != nil {
+		in, out := &in.SymbolsToIndex, &out.SymbolsToIndex
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.EnableNestedFields != nil {
+		in, out := &in.EnableNestedFields, &out.EnableNestedFields
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Migration != nil {
+		in, out := &in.Migration, &out.Migration
+		*out = new(CollectionMigrationSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DriftDetection != nil {
+		in, out := &in.DriftDetection, &out.DriftDetection
+		*out = new(DriftDetectionSpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TypesenseCollectionSpec.
+func (in *TypesenseCollectionSpec) DeepCopy() *TypesenseCollectionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TypesenseCollectionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TypesenseCollectionStatus) DeepCopyInto(out *TypesenseCollectionStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Migration != nil {
+		in, out := &in.Migration, &out.Migration
+		*out = new(CollectionMigrationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LastSyncTime != nil {
+		in, out := &in.LastSyncTime, &out.LastSyncTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TypesenseCollectionStatus.
+func (in *TypesenseCollectionStatus) DeepCopy() *TypesenseCollectionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TypesenseCollectionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TypesenseConversationModel) DeepCopyInto(out *TypesenseConversationModel) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TypesenseConversationModel.
+func (in *TypesenseConversationModel) DeepCopy() *TypesenseConversationModel {
+	if in == nil {
+		return nil
+	}
+	out := new(TypesenseConversationModel)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TypesenseConversationModel) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TypesenseConversationModelList) DeepCopyInto(out *TypesenseConversationModelList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]TypesenseConversationModel, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TypesenseConversationModelList.
+func (in *TypesenseConversationModelList) DeepCopy() *TypesenseConversationModelList {
+	if in == nil {
+		return nil
+	}
+	out := new(TypesenseConversationModelList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TypesenseConversationModelList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TypesenseConversationModelSpec) DeepCopyInto(out *TypesenseConversationModelSpec) {
+	*out = *in
+	out.ClusterRef = in.ClusterRef
+	if in.Name != nil {
+		in, out := &in.Name, &out.Name
+		*out = new(string)
+		**out = **in
+	}
+	if in.ApiKeySecretRef != nil {
+		in, out := &in.ApiKeySecretRef, &out.ApiKeySecretRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BaseUrl != nil {
+		in, out := &in.BaseUrl, &out.BaseUrl
+		*out = new(string)
+		**out = **in
+	}
+	if in.SystemPrompt != nil {
+		in, out := &in.SystemPrompt, &out.SystemPrompt
+		*out = new(string)
+		**out = **in
+	}
+	if in.Ttl != nil {
+		in, out := &in.Ttl, &out.Ttl
+		*out = new(int)
+		**out = **in
+	}
+	if in.DriftDetection != nil {
+		in, out := &in.DriftDetection, &out.DriftDetection
+		*out = new(DriftDetectionSpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TypesenseConversationModelSpec.
+func (in *TypesenseConversationModelSpec) DeepCopy() *TypesenseConversationModelSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TypesenseConversationModelSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TypesenseConversationModelStatus) DeepCopyInto(out *TypesenseConversationModelStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastSyncTime != nil {
+		in, out := &in.LastSyncTime, &out.LastSyncTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TypesenseConversationModelStatus.
+func (in *TypesenseConversationModelStatus) DeepCopy() *TypesenseConversationModelStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TypesenseConversationModelStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TypesenseCuration) DeepCopyInto(out *TypesenseCuration) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TypesenseCuration.
+func (in *TypesenseCuration) DeepCopy() *TypesenseCuration {
+	if in == nil {
+		return nil
+	}
+	out := new(TypesenseCuration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TypesenseCuration) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TypesenseCurationList) DeepCopyInto(out *TypesenseCurationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]TypesenseCuration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TypesenseCurationList.
+func (in *TypesenseCurationList) DeepCopy() *TypesenseCurationList {
+	if in == nil {
+		return nil
+	}
+	out := new(TypesenseCurationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TypesenseCurationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TypesenseCurationSpec) DeepCopyInto(out *TypesenseCurationSpec) {
+	*out = *in
+	out.ClusterRef = in.ClusterRef
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]CurationRuleSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DriftDetection != nil {
+		in, out := &in.DriftDetection, &out.DriftDetection
+		*out = new(DriftDetectionSpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TypesenseCurationSpec.
+func (in *TypesenseCurationSpec) DeepCopy() *TypesenseCurationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TypesenseCurationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TypesenseCurationStatus) DeepCopyInto(out *TypesenseCurationStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastSyncTime != nil {
+		in, out := &in.LastSyncTime, &out.LastSyncTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TypesenseCurationStatus.
+func (in *TypesenseCurationStatus) DeepCopy() *TypesenseCurationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TypesenseCurationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TypesenseIngestion) DeepCopyInto(out *TypesenseIngestion) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TypesenseIngestion.
+func (in *TypesenseIngestion) DeepCopy() *TypesenseIngestion {
+	if in == nil {
+		return nil
+	}
+	out := new(TypesenseIngestion)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TypesenseIngestion) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TypesenseIngestionList) DeepCopyInto(out *TypesenseIngestionList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]TypesenseIngestion, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TypesenseIngestionList.
+func (in *TypesenseIngestionList) DeepCopy() *TypesenseIngestionList {
+	if in == nil {
+		return nil
+	}
+	out := new(TypesenseIngestionList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TypesenseIngestionList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TypesenseIngestionSpec) DeepCopyInto(out *TypesenseIngestionSpec) {
+	*out = *in
+	out.ClusterRef = in.ClusterRef
+	in.Source.DeepCopyInto(&out.Source)
+	if in.FieldMapping != nil {
+		in, out := &in.FieldMapping, &out.FieldMapping
+		*out = make([]IngestionFieldMappingSpec, len(*in))
+		copy(*out, *in)
+	}
+	if in.Suspend != nil {
+		in, out := &in.Suspend, &out.Suspend
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TypesenseIngestionSpec.
+func (in *TypesenseIngestionSpec) DeepCopy() *TypesenseIngestionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TypesenseIngestionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TypesenseIngestionStatus) DeepCopyInto(out *TypesenseIngestionStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.KeyID != nil {
+		in, out := &in.KeyID, &out.KeyID
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Runs != nil {
+		in, out := &in.Runs, &out.Runs
+		*out = make([]IngestionRunStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TypesenseIngestionStatus.
+func (in *TypesenseIngestionStatus) DeepCopy() *TypesenseIngestionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TypesenseIngestionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TypesensePreset) DeepCopyInto(out *TypesensePreset) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TypesensePreset.
+func (in *TypesensePreset) DeepCopy() *TypesensePreset {
+	if in == nil {
+		return nil
+	}
+	out := new(TypesensePreset)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TypesensePreset) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TypesensePresetList) DeepCopyInto(out *TypesensePresetList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]TypesensePreset, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TypesensePresetList.
+func (in *TypesensePresetList) DeepCopy() *TypesensePresetList {
+	if in == nil {
+		return nil
+	}
+	out := new(TypesensePresetList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TypesensePresetList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TypesensePresetSpec) DeepCopyInto(out *TypesensePresetSpec) {
+	*out = *in
+	out.ClusterRef = in.ClusterRef
+	if in.Name != nil {
+		in, out := &in.Name, &out.Name
+		*out = new(string)
+		**out = **in
+	}
+	if in.Search != nil {
+		in, out := &in.Search, &out.Search
+		*out = new(PresetSearchSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MultiSearch != nil {
+		in, out := &in.MultiSearch, &out.MultiSearch
+		*out = make([]PresetSearchSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DriftDetection != nil {
+		in, out := &in.DriftDetection, &out.DriftDetection
+		*out = new(DriftDetectionSpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TypesensePresetSpec.
+func (in *TypesensePresetSpec) DeepCopy() *TypesensePresetSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TypesensePresetSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TypesensePresetStatus) DeepCopyInto(out *TypesensePresetStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastSyncTime != nil {
+		in, out := &in.LastSyncTime, &out.LastSyncTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TypesensePresetStatus.
+func (in *TypesensePresetStatus) DeepCopy() *TypesensePresetStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TypesensePresetStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TypesenseRetentionPolicy) DeepCopyInto(out *TypesenseRetentionPolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TypesenseRetentionPolicy.
+func (in *TypesenseRetentionPolicy) DeepCopy() *TypesenseRetentionPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(TypesenseRetentionPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TypesenseRetentionPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TypesenseRetentionPolicyList) DeepCopyInto(out *TypesenseRetentionPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]TypesenseRetentionPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TypesenseRetentionPolicyList.
+func (in *TypesenseRetentionPolicyList) DeepCopy() *TypesenseRetentionPolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(TypesenseRetentionPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TypesenseRetentionPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TypesenseRetentionPolicySpec) DeepCopyInto(out *TypesenseRetentionPolicySpec) {
+	*out = *in
+	out.ClusterRef = in.ClusterRef
+	out.MaxAge = in.MaxAge
+	if in.Suspend != nil {
+		in, out := &in.Suspend, &out.Suspend
+		*out = new(bool)
 		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DocSearchScraperSpec.
-func (in *DocSearchScraperSpec) DeepCopy() *DocSearchScraperSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TypesenseRetentionPolicySpec.
+func (in *TypesenseRetentionPolicySpec) DeepCopy() *TypesenseRetentionPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TypesenseRetentionPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TypesenseRetentionPolicyStatus) DeepCopyInto(out *TypesenseRetentionPolicyStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastRun != nil {
+		in, out := &in.LastRun, &out.LastRun
+		*out = new(RetentionRunStatus)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TypesenseRetentionPolicyStatus.
+func (in *TypesenseRetentionPolicyStatus) DeepCopy() *TypesenseRetentionPolicyStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(DocSearchScraperSpec)
+	out := new(TypesenseRetentionPolicyStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HealthCheckSpec) DeepCopyInto(out *HealthCheckSpec) {
+func (in *TypesenseScopedKey) DeepCopyInto(out *TypesenseScopedKey) {
 	*out = *in
-	if in.Resources != nil {
-		in, out := &in.Resources, &out.Resources
-		*out = new(v1.ResourceRequirements)
-		(*in).DeepCopyInto(*out)
-	}
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthCheckSpec.
-func (in *HealthCheckSpec) DeepCopy() *HealthCheckSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TypesenseScopedKey.
+func (in *TypesenseScopedKey) DeepCopy() *TypesenseScopedKey {
 	if in == nil {
 		return nil
 	}
-	out := new(HealthCheckSpec)
+	out := new(TypesenseScopedKey)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TypesenseScopedKey) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IngressSpec) DeepCopyInto(out *IngressSpec) {
+func (in *TypesenseScopedKeyList) DeepCopyInto(out *TypesenseScopedKeyList) {
 	*out = *in
-	if in.Referer != nil {
-		in, out := &in.Referer, &out.Referer
-		*out = new(string)
-		**out = **in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]TypesenseScopedKey, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
-	if in.HttpDirectives != nil {
-		in, out := &in.HttpDirectives, &out.HttpDirectives
-		*out = new(string)
-		**out = **in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TypesenseScopedKeyList.
+func (in *TypesenseScopedKeyList) DeepCopy() *TypesenseScopedKeyList {
+	if in == nil {
+		return nil
 	}
-	if in.ServerDirectives != nil {
-		in, out := &in.ServerDirectives, &out.ServerDirectives
-		*out = new(string)
-		**out = **in
+	out := new(TypesenseScopedKeyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TypesenseScopedKeyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
 	}
-	if in.LocationDirectives != nil {
-		in, out := &in.LocationDirectives, &out.LocationDirectives
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TypesenseScopedKeySpec) DeepCopyInto(out *TypesenseScopedKeySpec) {
+	*out = *in
+	in.ParentKeySecretRef.DeepCopyInto(&out.ParentKeySecretRef)
+	if in.SecretName != nil {
+		in, out := &in.SecretName, &out.SecretName
 		*out = new(string)
 		**out = **in
 	}
-	if in.ClusterIssuer != nil {
-		in, out := &in.ClusterIssuer, &out.ClusterIssuer
+	if in.FilterBy != nil {
+		in, out := &in.FilterBy, &out.FilterBy
 		*out = new(string)
 		**out = **in
 	}
-	if in.Annotations != nil {
-		in, out := &in.Annotations, &out.Annotations
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.ExpiresAt != nil {
+		in, out := &in.ExpiresAt, &out.ExpiresAt
+		*out = (*in).DeepCopy()
 	}
-	if in.TLSSecretName != nil {
-		in, out := &in.TLSSecretName, &out.TLSSecretName
-		*out = new(string)
+	if in.LimitMultiSearches != nil {
+		in, out := &in.LimitMultiSearches, &out.LimitMultiSearches
+		*out = new(int)
 		**out = **in
 	}
-	if in.Resources != nil {
-		in, out := &in.Resources, &out.Resources
-		*out = new(v1.ResourceRequirements)
-		(*in).DeepCopyInto(*out)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TypesenseScopedKeySpec.
+func (in *TypesenseScopedKeySpec) DeepCopy() *TypesenseScopedKeySpec {
+	if in == nil {
+		return nil
 	}
-	if in.ReadOnlyRootFilesystem != nil {
-		in, out := &in.ReadOnlyRootFilesystem, &out.ReadOnlyRootFilesystem
-		*out = new(ReadOnlyRootFilesystemSpec)
-		(*in).DeepCopyInto(*out)
+	out := new(TypesenseScopedKeySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TypesenseScopedKeyStatus) DeepCopyInto(out *TypesenseScopedKeyStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
-	if in.PathType != nil {
-		in, out := &in.PathType, &out.PathType
-		*out = new(networkingv1.PathType)
-		**out = **in
+	if in.LastGenerationTime != nil {
+		in, out := &in.LastGenerationTime, &out.LastGenerationTime
+		*out = (*in).DeepCopy()
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngressSpec.
-func (in *IngressSpec) DeepCopy() *IngressSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TypesenseScopedKeyStatus.
+func (in *TypesenseScopedKeyStatus) DeepCopy() *TypesenseScopedKeyStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(IngressSpec)
+	out := new(TypesenseScopedKeyStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MetricsExporterSpec) DeepCopyInto(out *MetricsExporterSpec) {
+func (in *TypesenseStemmingDictionary) DeepCopyInto(out *TypesenseStemmingDictionary) {
 	*out = *in
-	if in.Resources != nil {
-		in, out := &in.Resources, &out.Resources
-		*out = new(v1.ResourceRequirements)
-		(*in).DeepCopyInto(*out)
-	}
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricsExporterSpec.
-func (in *MetricsExporterSpec) DeepCopy() *MetricsExporterSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TypesenseStemmingDictionary.
+func (in *TypesenseStemmingDictionary) DeepCopy() *TypesenseStemmingDictionary {
 	if in == nil {
 		return nil
 	}
-	out := new(MetricsExporterSpec)
+	out := new(TypesenseStemmingDictionary)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TypesenseStemmingDictionary) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ReadOnlyRootFilesystemSpec) DeepCopyInto(out *ReadOnlyRootFilesystemSpec) {
+func (in *TypesenseStemmingDictionaryList) DeepCopyInto(out *TypesenseStemmingDictionaryList) {
 	*out = *in
-	if in.SecurityContext != nil {
-		in, out := &in.SecurityContext, &out.SecurityContext
-		*out = new(v1.SecurityContext)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Volumes != nil {
-		in, out := &in.Volumes, &out.Volumes
-		*out = make([]v1.Volume, len(*in))
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]TypesenseStemmingDictionary, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.VolumeMounts != nil {
-		in, out := &in.VolumeMounts, &out.VolumeMounts
-		*out = make([]v1.VolumeMount, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TypesenseStemmingDictionaryList.
+func (in *TypesenseStemmingDictionaryList) DeepCopy() *TypesenseStemmingDictionaryList {
+	if in == nil {
+		return nil
 	}
+	out := new(TypesenseStemmingDictionaryList)
+	in.DeepCopyInto(out)
+	return out
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReadOnlyRootFilesystemSpec.
-func (in *ReadOnlyRootFilesystemSpec) DeepCopy() *ReadOnlyRootFilesystemSpec {
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TypesenseStemmingDictionaryList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TypesenseStemmingDictionarySpec) DeepCopyInto(out *TypesenseStemmingDictionarySpec) {
+	*out = *in
+	out.ClusterRef = in.ClusterRef
+	if in.Locale != nil {
+		in, out := &in.Locale, &out.Locale
+		*out = new(string)
+		**out = **in
+	}
+	if in.Words != nil {
+		in, out := &in.Words, &out.Words
+		*out = make([]StemmingWordSpec, len(*in))
+		copy(*out, *in)
+	}
+	if in.ConfigMapRef != nil {
+		in, out := &in.ConfigMapRef, &out.ConfigMapRef
+		*out = new(corev1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DriftDetection != nil {
+		in, out := &in.DriftDetection, &out.DriftDetection
+		*out = new(DriftDetectionSpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TypesenseStemmingDictionarySpec.
+func (in *TypesenseStemmingDictionarySpec) DeepCopy() *TypesenseStemmingDictionarySpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ReadOnlyRootFilesystemSpec)
+	out := new(TypesenseStemmingDictionarySpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *StorageSpec) DeepCopyInto(out *StorageSpec) {
+func (in *TypesenseStemmingDictionaryStatus) DeepCopyInto(out *TypesenseStemmingDictionaryStatus) {
 	*out = *in
-	out.Size = in.Size.DeepCopy()
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Collections != nil {
+		in, out := &in.Collections, &out.Collections
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastSyncTime != nil {
+		in, out := &in.LastSyncTime, &out.LastSyncTime
+		*out = (*in).DeepCopy()
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageSpec.
-func (in *StorageSpec) DeepCopy() *StorageSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TypesenseStemmingDictionaryStatus.
+func (in *TypesenseStemmingDictionaryStatus) DeepCopy() *TypesenseStemmingDictionaryStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(StorageSpec)
+	out := new(TypesenseStemmingDictionaryStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TypesenseCluster) DeepCopyInto(out *TypesenseCluster) {
+func (in *TypesenseStopwords) DeepCopyInto(out *TypesenseStopwords) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -213,18 +2747,18 @@ func (in *TypesenseCluster) DeepCopyInto(out *TypesenseCluster) {
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TypesenseCluster.
-func (in *TypesenseCluster) DeepCopy() *TypesenseCluster {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TypesenseStopwords.
+func (in *TypesenseStopwords) DeepCopy() *TypesenseStopwords {
 	if in == nil {
 		return nil
 	}
-	out := new(TypesenseCluster)
+	out := new(TypesenseStopwords)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *TypesenseCluster) DeepCopyObject() runtime.Object {
+func (in *TypesenseStopwords) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -232,31 +2766,31 @@ func (in *TypesenseCluster) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TypesenseClusterList) DeepCopyInto(out *TypesenseClusterList) {
+func (in *TypesenseStopwordsList) DeepCopyInto(out *TypesenseStopwordsList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]TypesenseCluster, len(*in))
+		*out = make([]TypesenseStopwords, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TypesenseClusterList.
-func (in *TypesenseClusterList) DeepCopy() *TypesenseClusterList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TypesenseStopwordsList.
+func (in *TypesenseStopwordsList) DeepCopy() *TypesenseStopwordsList {
 	if in == nil {
 		return nil
 	}
-	out := new(TypesenseClusterList)
+	out := new(TypesenseStopwordsList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *TypesenseClusterList) DeepCopyObject() runtime.Object {
+func (in *TypesenseStopwordsList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -264,111 +2798,68 @@ func (in *TypesenseClusterList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TypesenseClusterSpec) DeepCopyInto(out *TypesenseClusterSpec) {
+func (in *TypesenseStopwordsSpec) DeepCopyInto(out *TypesenseStopwordsSpec) {
 	*out = *in
-	if in.AdminApiKey != nil {
-		in, out := &in.AdminApiKey, &out.AdminApiKey
-		*out = new(v1.SecretReference)
-		**out = **in
-	}
-	if in.CorsDomains != nil {
-		in, out := &in.CorsDomains, &out.CorsDomains
+	out.ClusterRef = in.ClusterRef
+	if in.Locale != nil {
+		in, out := &in.Locale, &out.Locale
 		*out = new(string)
 		**out = **in
 	}
-	if in.Resources != nil {
-		in, out := &in.Resources, &out.Resources
-		*out = new(v1.ResourceRequirements)
-		(*in).DeepCopyInto(*out)
+	if in.Stopwords != nil {
+		in, out := &in.Stopwords, &out.Stopwords
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.Affinity != nil {
-		in, out := &in.Affinity, &out.Affinity
-		*out = new(v1.Affinity)
+	if in.ConfigMapRef != nil {
+		in, out := &in.ConfigMapRef, &out.ConfigMapRef
+		*out = new(corev1.ConfigMapKeySelector)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.NodeSelector != nil {
-		in, out := &in.NodeSelector, &out.NodeSelector
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.Tolerations != nil {
-		in, out := &in.Tolerations, &out.Tolerations
-		*out = make([]v1.Toleration, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.AdditionalServerConfiguration != nil {
-		in, out := &in.AdditionalServerConfiguration, &out.AdditionalServerConfiguration
-		*out = new(v1.LocalObjectReference)
+	if in.DriftDetection != nil {
+		in, out := &in.DriftDetection, &out.DriftDetection
+		*out = new(DriftDetectionSpec)
 		**out = **in
 	}
-	if in.Storage != nil {
-		in, out := &in.Storage, &out.Storage
-		*out = new(StorageSpec)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Ingress != nil {
-		in, out := &in.Ingress, &out.Ingress
-		*out = new(IngressSpec)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Scrapers != nil {
-		in, out := &in.Scrapers, &out.Scrapers
-		*out = make([]DocSearchScraperSpec, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.Metrics != nil {
-		in, out := &in.Metrics, &out.Metrics
-		*out = new(MetricsExporterSpec)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.HealthCheck != nil {
-		in, out := &in.HealthCheck, &out.HealthCheck
-		*out = new(HealthCheckSpec)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.TopologySpreadConstraints != nil {
-		in, out := &in.TopologySpreadConstraints, &out.TopologySpreadConstraints
-		*out = make([]v1.TopologySpreadConstraint, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TypesenseClusterSpec.
-func (in *TypesenseClusterSpec) DeepCopy() *TypesenseClusterSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TypesenseStopwordsSpec.
+func (in *TypesenseStopwordsSpec) DeepCopy() *TypesenseStopwordsSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(TypesenseClusterSpec)
+	out := new(TypesenseStopwordsSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TypesenseClusterStatus) DeepCopyInto(out *TypesenseClusterStatus) {
+func (in *TypesenseStopwordsStatus) DeepCopyInto(out *TypesenseStopwordsStatus) {
 	*out = *in
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
-		*out = make([]metav1.Condition, len(*in))
+		*out = make([]v1.Condition, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Collections != nil {
+		in, out := &in.Collections, &out.Collections
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastSyncTime != nil {
+		in, out := &in.LastSyncTime, &out.LastSyncTime
+		*out = (*in).DeepCopy()
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TypesenseClusterStatus.
-func (in *TypesenseClusterStatus) DeepCopy() *TypesenseClusterStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TypesenseStopwordsStatus.
+func (in *TypesenseStopwordsStatus) DeepCopy() *TypesenseStopwordsStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(TypesenseClusterStatus)
+	out := new(TypesenseStopwordsStatus)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -0,0 +1,1290 @@
+//go:build !ignore_autogenerated
+
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by controller-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	"k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AdminApiKeyCSISpec) DeepCopyInto(out *AdminApiKeyCSISpec) {
+	*out = *in
+	if in.VolumeAttributes != nil {
+		in, out := &in.VolumeAttributes, &out.VolumeAttributes
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdminApiKeyCSISpec.
+func (in *AdminApiKeyCSISpec) DeepCopy() *AdminApiKeyCSISpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AdminApiKeyCSISpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AdminApiKeyRotationSpec) DeepCopyInto(out *AdminApiKeyRotationSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdminApiKeyRotationSpec.
+func (in *AdminApiKeyRotationSpec) DeepCopy() *AdminApiKeyRotationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AdminApiKeyRotationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AdminApiKeySpec) DeepCopyInto(out *AdminApiKeySpec) {
+	*out = *in
+	if in.CSI != nil {
+		in, out := &in.CSI, &out.CSI
+		*out = new(AdminApiKeyCSISpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdminApiKeySpec.
+func (in *AdminApiKeySpec) DeepCopy() *AdminApiKeySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AdminApiKeySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AdminApiKeyStatus) DeepCopyInto(out *AdminApiKeyStatus) {
+	*out = *in
+	if in.LastRotationTime != nil {
+		in, out := &in.LastRotationTime, &out.LastRotationTime
+		*out = (*in).DeepCopy()
+	}
+	if in.OverlapKeyIDs != nil {
+		in, out := &in.OverlapKeyIDs, &out.OverlapKeyIDs
+		*out = make([]int64, len(*in))
+		copy(*out, *in)
+	}
+	if in.OverlapExpirationTime != nil {
+		in, out := &in.OverlapExpirationTime, &out.OverlapExpirationTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdminApiKeyStatus.
+func (in *AdminApiKeyStatus) DeepCopy() *AdminApiKeyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AdminApiKeyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApiKeyRateLimitSpec) DeepCopyInto(out *ApiKeyRateLimitSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApiKeyRateLimitSpec.
+func (in *ApiKeyRateLimitSpec) DeepCopy() *ApiKeyRateLimitSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ApiKeyRateLimitSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuthSpec) DeepCopyInto(out *AuthSpec) {
+	*out = *in
+	in.JWKS.DeepCopyInto(&out.JWKS)
+	if in.Issuer != nil {
+		in, out := &in.Issuer, &out.Issuer
+		*out = new(string)
+		**out = **in
+	}
+	if in.Audiences != nil {
+		in, out := &in.Audiences, &out.Audiences
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.ParentKeySecretRef.DeepCopyInto(&out.ParentKeySecretRef)
+	if in.Claims != nil {
+		in, out := &in.Claims, &out.Claims
+		*out = make([]ClaimMappingSpec, len(*in))
+		copy(*out, *in)
+	}
+	if in.FilterBy != nil {
+		in, out := &in.FilterBy, &out.FilterBy
+		*out = new(string)
+		**out = **in
+	}
+	if in.LimitMultiSearches != nil {
+		in, out := &in.LimitMultiSearches, &out.LimitMultiSearches
+		*out = new(int)
+		**out = **in
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecurityContext != nil {
+		in, out := &in.SecurityContext, &out.SecurityContext
+		*out = new(v1.SecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthSpec.
+func (in *AuthSpec) DeepCopy() *AuthSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AuthSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CacheSpec) DeepCopyInto(out *CacheSpec) {
+	*out = *in
+	if in.TTL != nil {
+		in, out := &in.TTL, &out.TTL
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.MaxSize != nil {
+		in, out := &in.MaxSize, &out.MaxSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CacheSpec.
+func (in *CacheSpec) DeepCopy() *CacheSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CacheSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClaimMappingSpec) DeepCopyInto(out *ClaimMappingSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClaimMappingSpec.
+func (in *ClaimMappingSpec) DeepCopy() *ClaimMappingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClaimMappingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CollectionFieldSpec) DeepCopyInto(out *CollectionFieldSpec) {
+	*out = *in
+	if in.Facet != nil {
+		in, out := &in.Facet, &out.Facet
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Optional != nil {
+		in, out := &in.Optional, &out.Optional
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Index != nil {
+		in, out := &in.Index, &out.Index
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Sort != nil {
+		in, out := &in.Sort, &out.Sort
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Infix != nil {
+		in, out := &in.Infix, &out.Infix
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Locale != nil {
+		in, out := &in.Locale, &out.Locale
+		*out = new(string)
+		**out = **in
+	}
+	if in.Stem != nil {
+		in, out := &in.Stem, &out.Stem
+		*out = new(bool)
+		**out = **in
+	}
+	if in.StemDictionary != nil {
+		in, out := &in.StemDictionary, &out.StemDictionary
+		*out = new(string)
+		**out = **in
+	}
+	if in.Reference != nil {
+		in, out := &in.Reference, &out.Reference
+		*out = new(string)
+		**out = **in
+	}
+	if in.NumDim != nil {
+		in, out := &in.NumDim, &out.NumDim
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CollectionFieldSpec.
+func (in *CollectionFieldSpec) DeepCopy() *CollectionFieldSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CollectionFieldSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CorsSpec) DeepCopyInto(out *CorsSpec) {
+	*out = *in
+	if in.Domains != nil {
+		in, out := &in.Domains, &out.Domains
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CorsSpec.
+func (in *CorsSpec) DeepCopy() *CorsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CorsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DocSearchScraperSpec) DeepCopyInto(out *DocSearchScraperSpec) {
+	*out = *in
+	if in.AuthConfiguration != nil {
+		in, out := &in.AuthConfiguration, &out.AuthConfiguration
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DocSearchScraperSpec.
+func (in *DocSearchScraperSpec) DeepCopy() *DocSearchScraperSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DocSearchScraperSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayHeaderMatch) DeepCopyInto(out *GatewayHeaderMatch) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayHeaderMatch.
+func (in *GatewayHeaderMatch) DeepCopy() *GatewayHeaderMatch {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayHeaderMatch)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayParentReference) DeepCopyInto(out *GatewayParentReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayParentReference.
+func (in *GatewayParentReference) DeepCopy() *GatewayParentReference {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayParentReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayPathMatch) DeepCopyInto(out *GatewayPathMatch) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayPathMatch.
+func (in *GatewayPathMatch) DeepCopy() *GatewayPathMatch {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayPathMatch)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewaySpec) DeepCopyInto(out *GatewaySpec) {
+	*out = *in
+	out.ParentRef = in.ParentRef
+	if in.Hostnames != nil {
+		in, out := &in.Hostnames, &out.Hostnames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Paths != nil {
+		in, out := &in.Paths, &out.Paths
+		*out = make([]GatewayPathMatch, len(*in))
+		copy(*out, *in)
+	}
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make([]GatewayHeaderMatch, len(*in))
+		copy(*out, *in)
+	}
+	if in.Referer != nil {
+		in, out := &in.Referer, &out.Referer
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewaySpec.
+func (in *GatewaySpec) DeepCopy() *GatewaySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewaySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealthCheckSpec) DeepCopyInto(out *HealthCheckSpec) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecurityContext != nil {
+		in, out := &in.SecurityContext, &out.SecurityContext
+		*out = new(v1.SecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthCheckSpec.
+func (in *HealthCheckSpec) DeepCopy() *HealthCheckSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HealthCheckSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealthyLagThresholdsSpec) DeepCopyInto(out *HealthyLagThresholdsSpec) {
+	*out = *in
+	if in.Read != nil {
+		in, out := &in.Read, &out.Read
+		*out = new(int)
+		**out = **in
+	}
+	if in.Write != nil {
+		in, out := &in.Write, &out.Write
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthyLagThresholdsSpec.
+func (in *HealthyLagThresholdsSpec) DeepCopy() *HealthyLagThresholdsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HealthyLagThresholdsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IngressSpec) DeepCopyInto(out *IngressSpec) {
+	*out = *in
+	if in.Referer != nil {
+		in, out := &in.Referer, &out.Referer
+		*out = new(string)
+		**out = **in
+	}
+	if in.HttpDirectives != nil {
+		in, out := &in.HttpDirectives, &out.HttpDirectives
+		*out = new(string)
+		**out = **in
+	}
+	if in.ServerDirectives != nil {
+		in, out := &in.ServerDirectives, &out.ServerDirectives
+		*out = new(string)
+		**out = **in
+	}
+	if in.LocationDirectives != nil {
+		in, out := &in.LocationDirectives, &out.LocationDirectives
+		*out = new(string)
+		**out = **in
+	}
+	if in.ClusterIssuer != nil {
+		in, out := &in.ClusterIssuer, &out.ClusterIssuer
+		*out = new(string)
+		**out = **in
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.TLSSecretName != nil {
+		in, out := &in.TLSSecretName, &out.TLSSecretName
+		*out = new(string)
+		**out = **in
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReadOnlyRootFilesystem != nil {
+		in, out := &in.ReadOnlyRootFilesystem, &out.ReadOnlyRootFilesystem
+		*out = new(ReadOnlyRootFilesystemSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReverseProxy != nil {
+		in, out := &in.ReverseProxy, &out.ReverseProxy
+		*out = new(ReverseProxySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RateLimit != nil {
+		in, out := &in.RateLimit, &out.RateLimit
+		*out = new(RateLimitSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Cache != nil {
+		in, out := &in.Cache, &out.Cache
+		*out = new(CacheSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Auth != nil {
+		in, out := &in.Auth, &out.Auth
+		*out = new(AuthSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PathType != nil {
+		in, out := &in.PathType, &out.PathType
+		*out = new(networkingv1.PathType)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngressSpec.
+func (in *IngressSpec) DeepCopy() *IngressSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IngressSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JWKSSpec) DeepCopyInto(out *JWKSSpec) {
+	*out = *in
+	if in.SecretKeyRef != nil {
+		in, out := &in.SecretKeyRef, &out.SecretKeyRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.URL != nil {
+		in, out := &in.URL, &out.URL
+		*out = new(string)
+		**out = **in
+	}
+	if in.RefreshInterval != nil {
+		in, out := &in.RefreshInterval, &out.RefreshInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JWKSSpec.
+func (in *JWKSSpec) DeepCopy() *JWKSSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(JWKSSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetricsExporterSpec) DeepCopyInto(out *MetricsExporterSpec) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecurityContext != nil {
+		in, out := &in.SecurityContext, &out.SecurityContext
+		*out = new(v1.SecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricsExporterSpec.
+func (in *MetricsExporterSpec) DeepCopy() *MetricsExporterSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MetricsExporterSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkPolicySpec) DeepCopyInto(out *NetworkPolicySpec) {
+	*out = *in
+	if in.ApiClients != nil {
+		in, out := &in.ApiClients, &out.ApiClients
+		*out = make([]networkingv1.NetworkPolicyPeer, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkPolicySpec.
+func (in *NetworkPolicySpec) DeepCopy() *NetworkPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkingSpec) DeepCopyInto(out *NetworkingSpec) {
+	*out = *in
+	if in.Ingress != nil {
+		in, out := &in.Ingress, &out.Ingress
+		*out = new(IngressSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(TLSSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NetworkPolicy != nil {
+		in, out := &in.NetworkPolicy, &out.NetworkPolicy
+		*out = new(NetworkPolicySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Gateway != nil {
+		in, out := &in.Gateway, &out.Gateway
+		*out = new(GatewaySpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkingSpec.
+func (in *NetworkingSpec) DeepCopy() *NetworkingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PeeringSpec) DeepCopyInto(out *PeeringSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PeeringSpec.
+func (in *PeeringSpec) DeepCopy() *PeeringSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PeeringSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RateLimitSpec) DeepCopyInto(out *RateLimitSpec) {
+	*out = *in
+	if in.ApiKey != nil {
+		in, out := &in.ApiKey, &out.ApiKey
+		*out = new(ApiKeyRateLimitSpec)
+		**out = **in
+	}
+	if in.ConnectionsPerClient != nil {
+		in, out := &in.ConnectionsPerClient, &out.ConnectionsPerClient
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TrustedProxies != nil {
+		in, out := &in.TrustedProxies, &out.TrustedProxies
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ResponseBody != nil {
+		in, out := &in.ResponseBody, &out.ResponseBody
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RateLimitSpec.
+func (in *RateLimitSpec) DeepCopy() *RateLimitSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RateLimitSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReadOnlyRootFilesystemSpec) DeepCopyInto(out *ReadOnlyRootFilesystemSpec) {
+	*out = *in
+	if in.SecurityContext != nil {
+		in, out := &in.SecurityContext, &out.SecurityContext
+		*out = new(v1.SecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Volumes != nil {
+		in, out := &in.Volumes, &out.Volumes
+		*out = make([]v1.Volume, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.VolumeMounts != nil {
+		in, out := &in.VolumeMounts, &out.VolumeMounts
+		*out = make([]v1.VolumeMount, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReadOnlyRootFilesystemSpec.
+func (in *ReadOnlyRootFilesystemSpec) DeepCopy() *ReadOnlyRootFilesystemSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ReadOnlyRootFilesystemSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReverseProxyAutoscalingSpec) DeepCopyInto(out *ReverseProxyAutoscalingSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReverseProxyAutoscalingSpec.
+func (in *ReverseProxyAutoscalingSpec) DeepCopy() *ReverseProxyAutoscalingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ReverseProxyAutoscalingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReverseProxyPodDisruptionBudgetSpec) DeepCopyInto(out *ReverseProxyPodDisruptionBudgetSpec) {
+	*out = *in
+	if in.MinAvailable != nil {
+		in, out := &in.MinAvailable, &out.MinAvailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReverseProxyPodDisruptionBudgetSpec.
+func (in *ReverseProxyPodDisruptionBudgetSpec) DeepCopy() *ReverseProxyPodDisruptionBudgetSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ReverseProxyPodDisruptionBudgetSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReverseProxySpec) DeepCopyInto(out *ReverseProxySpec) {
+	*out = *in
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(ReverseProxyAutoscalingSpec)
+		**out = **in
+	}
+	if in.ReadinessProbe != nil {
+		in, out := &in.ReadinessProbe, &out.ReadinessProbe
+		*out = new(v1.Probe)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LivenessProbe != nil {
+		in, out := &in.LivenessProbe, &out.LivenessProbe
+		*out = new(v1.Probe)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(v1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.TopologySpreadConstraints != nil {
+		in, out := &in.TopologySpreadConstraints, &out.TopologySpreadConstraints
+		*out = make([]v1.TopologySpreadConstraint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PodDisruptionBudget != nil {
+		in, out := &in.PodDisruptionBudget, &out.PodDisruptionBudget
+		*out = new(ReverseProxyPodDisruptionBudgetSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReverseProxySpec.
+func (in *ReverseProxySpec) DeepCopy() *ReverseProxySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ReverseProxySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchedulingSpec) DeepCopyInto(out *SchedulingSpec) {
+	*out = *in
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(v1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.TopologySpreadConstraints != nil {
+		in, out := &in.TopologySpreadConstraints, &out.TopologySpreadConstraints
+		*out = make([]v1.TopologySpreadConstraint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchedulingSpec.
+func (in *SchedulingSpec) DeepCopy() *SchedulingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SchedulingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SeedCollectionSpec) DeepCopyInto(out *SeedCollectionSpec) {
+	*out = *in
+	if in.Fields != nil {
+		in, out := &in.Fields, &out.Fields
+		*out = make([]CollectionFieldSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DefaultSortingField != nil {
+		in, out := &in.DefaultSortingField, &out.DefaultSortingField
+		*out = new(string)
+		**out = **in
+	}
+	if in.Documents != nil {
+		in, out := &in.Documents, &out.Documents
+		*out = make([]SeedDocumentsSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SeedCollectionSpec.
+func (in *SeedCollectionSpec) DeepCopy() *SeedCollectionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SeedCollectionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SeedDocumentsSpec) DeepCopyInto(out *SeedDocumentsSpec) {
+	*out = *in
+	if in.ConfigMapRef != nil {
+		in, out := &in.ConfigMapRef, &out.ConfigMapRef
+		*out = new(v1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PersistentVolumeClaim != nil {
+		in, out := &in.PersistentVolumeClaim, &out.PersistentVolumeClaim
+		*out = new(SeedPersistentVolumeClaimSpec)
+		**out = **in
+	}
+	if in.Url != nil {
+		in, out := &in.Url, &out.Url
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SeedDocumentsSpec.
+func (in *SeedDocumentsSpec) DeepCopy() *SeedDocumentsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SeedDocumentsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SeedPersistentVolumeClaimSpec) DeepCopyInto(out *SeedPersistentVolumeClaimSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SeedPersistentVolumeClaimSpec.
+func (in *SeedPersistentVolumeClaimSpec) DeepCopy() *SeedPersistentVolumeClaimSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SeedPersistentVolumeClaimSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SeedSpec) DeepCopyInto(out *SeedSpec) {
+	*out = *in
+	if in.Collections != nil {
+		in, out := &in.Collections, &out.Collections
+		*out = make([]SeedCollectionSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SeedSpec.
+func (in *SeedSpec) DeepCopy() *SeedSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SeedSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SeedStatus) DeepCopyInto(out *SeedStatus) {
+	*out = *in
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SeedStatus.
+func (in *SeedStatus) DeepCopy() *SeedStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SeedStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServerSpec) DeepCopyInto(out *ServerSpec) {
+	*out = *in
+	if in.AdminApiKey != nil {
+		in, out := &in.AdminApiKey, &out.AdminApiKey
+		*out = new(AdminApiKeySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AdminApiKeyRotation != nil {
+		in, out := &in.AdminApiKeyRotation, &out.AdminApiKeyRotation
+		*out = new(AdminApiKeyRotationSpec)
+		**out = **in
+	}
+	if in.Cors != nil {
+		in, out := &in.Cors, &out.Cors
+		*out = new(CorsSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HealthyLagThresholds != nil {
+		in, out := &in.HealthyLagThresholds, &out.HealthyLagThresholds
+		*out = new(HealthyLagThresholdsSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AdditionalConfiguration != nil {
+		in, out := &in.AdditionalConfiguration, &out.AdditionalConfiguration
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecurityContext != nil {
+		in, out := &in.SecurityContext, &out.SecurityContext
+		*out = new(v1.SecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServerSpec.
+func (in *ServerSpec) DeepCopy() *ServerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ServerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SidecarsSpec) DeepCopyInto(out *SidecarsSpec) {
+	*out = *in
+	if in.MetricsExporter != nil {
+		in, out := &in.MetricsExporter, &out.MetricsExporter
+		*out = new(MetricsExporterSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HealthCheck != nil {
+		in, out := &in.HealthCheck, &out.HealthCheck
+		*out = new(HealthCheckSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SidecarsSpec.
+func (in *SidecarsSpec) DeepCopy() *SidecarsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SidecarsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageSpec) DeepCopyInto(out *StorageSpec) {
+	*out = *in
+	out.Size = in.Size.DeepCopy()
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageSpec.
+func (in *StorageSpec) DeepCopy() *StorageSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TLSCertManagerSpec) DeepCopyInto(out *TLSCertManagerSpec) {
+	*out = *in
+	out.IssuerRef = in.IssuerRef
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.RenewBefore != nil {
+		in, out := &in.RenewBefore, &out.RenewBefore
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TLSCertManagerSpec.
+func (in *TLSCertManagerSpec) DeepCopy() *TLSCertManagerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TLSCertManagerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TLSIssuerReference) DeepCopyInto(out *TLSIssuerReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TLSIssuerReference.
+func (in *TLSIssuerReference) DeepCopy() *TLSIssuerReference {
+	if in == nil {
+		return nil
+	}
+	out := new(TLSIssuerReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TLSSpec) DeepCopyInto(out *TLSSpec) {
+	*out = *in
+	if in.CertManager != nil {
+		in, out := &in.CertManager, &out.CertManager
+		*out = new(TLSCertManagerSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecretName != nil {
+		in, out := &in.SecretName, &out.SecretName
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TLSSpec.
+func (in *TLSSpec) DeepCopy() *TLSSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TLSSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TypesenseCluster) DeepCopyInto(out *TypesenseCluster) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TypesenseCluster.
+func (in *TypesenseCluster) DeepCopy() *TypesenseCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(TypesenseCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TypesenseCluster) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TypesenseClusterList) DeepCopyInto(out *TypesenseClusterList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]TypesenseCluster, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TypesenseClusterList.
+func (in *TypesenseClusterList) DeepCopy() *TypesenseClusterList {
+	if in == nil {
+		return nil
+	}
+	out := new(TypesenseClusterList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TypesenseClusterList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TypesenseClusterSpec) DeepCopyInto(out *TypesenseClusterSpec) {
+	*out = *in
+	in.Server.DeepCopyInto(&out.Server)
+	out.Peering = in.Peering
+	in.Sidecars.DeepCopyInto(&out.Sidecars)
+	in.Networking.DeepCopyInto(&out.Networking)
+	if in.Storage != nil {
+		in, out := &in.Storage, &out.Storage
+		*out = new(StorageSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Scheduling.DeepCopyInto(&out.Scheduling)
+	if in.PodSecurityContext != nil {
+		in, out := &in.PodSecurityContext, &out.PodSecurityContext
+		*out = new(v1.PodSecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Scrapers != nil {
+		in, out := &in.Scrapers, &out.Scrapers
+		*out = make([]DocSearchScraperSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Seed != nil {
+		in, out := &in.Seed, &out.Seed
+		*out = new(SeedSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TypesenseClusterSpec.
+func (in *TypesenseClusterSpec) DeepCopy() *TypesenseClusterSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TypesenseClusterSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TypesenseClusterStatus) DeepCopyInto(out *TypesenseClusterStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Seed != nil {
+		in, out := &in.Seed, &out.Seed
+		*out = new(SeedStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AdminApiKey != nil {
+		in, out := &in.AdminApiKey, &out.AdminApiKey
+		*out = new(AdminApiKeyStatus)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TypesenseClusterStatus.
+func (in *TypesenseClusterStatus) DeepCopy() *TypesenseClusterStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TypesenseClusterStatus)
+	in.DeepCopyInto(out)
+	return out
+}
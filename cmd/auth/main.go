@@ -0,0 +1,135 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/akyriako/typesense-operator/internal/auth"
+	"go.uber.org/zap/zapcore"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+// stringsFlag collects the values of a flag that can be repeated
+type stringsFlag []string
+
+func (f *stringsFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *stringsFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
+// auth is the sidecar the operator runs next to nginx when spec.ingress.auth is set, it verifies the bearer tokens
+// of the requests nginx asks about and answers with a scoped search key derived from their claims.
+func main() {
+	var listenAddr, probeAddr string
+	var audiences, claims stringsFlag
+	var config auth.Config
+	flag.StringVar(&listenAddr, "listen-address", "127.0.0.1:8089", "The address the auth endpoint binds to.")
+	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8090", "The address the probe endpoint binds to.")
+	flag.StringVar(&config.JWKSFile, "jwks-file", "", "The file holding the JSON Web Key Set.")
+	flag.StringVar(&config.JWKSURL, "jwks-url", "", "The url of the JSON Web Key Set, takes precedence over --jwks-file.")
+	flag.DurationVar(&config.RefreshInterval, "jwks-refresh-interval", 10*time.Minute, "The interval the keys are reloaded at.")
+	flag.StringVar(&config.ParentKeyFile, "parent-key-file", "", "The file holding the search-only api key the scoped keys are derived from.")
+	flag.StringVar(&config.Issuer, "issuer", "", "The issuer the iss claim of the tokens has to match.")
+	flag.Var(&audiences, "audience", "An audience the aud claim of the tokens may contain, can be repeated.")
+	flag.Var(&claims, "claim", "A claim=field pair restricting the scoped keys to the documents whose field matches the claim, can be repeated.")
+	flag.StringVar(&config.FilterBy, "filter-by", "", "The filter added to every scoped key.")
+	flag.IntVar(&config.LimitMultiSearches, "limit-multi-searches", 0, "The searches a multi search request of a scoped key may contain.")
+
+	opts := zap.Options{
+		TimeEncoder:     zapcore.ISO8601TimeEncoder,
+		StacktraceLevel: zapcore.DPanicLevel,
+	}
+
+	opts.BindFlags(flag.CommandLine)
+	flag.Parse()
+
+	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
+
+	if config.ParentKeyFile == "" || (config.JWKSFile == "" && config.JWKSURL == "") {
+		fmt.Fprintln(os.Stderr, "--parent-key-file and one of --jwks-file or --jwks-url are required")
+		os.Exit(2)
+	}
+
+	for _, claim := range claims {
+		name, field, found := strings.Cut(claim, "=")
+		if !found || name == "" || field == "" {
+			fmt.Fprintf(os.Stderr, "--claim %q is not a claim=field pair\n", claim)
+			os.Exit(2)
+		}
+		config.Claims = append(config.Claims, auth.ClaimMapping{Claim: name, Field: field})
+	}
+	config.Audiences = audiences
+
+	if err := run(ctrl.SetupSignalHandler(), listenAddr, probeAddr, config); err != nil {
+		fmt.Fprintf(os.Stderr, "auth sidecar failed: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, listenAddr, probeAddr string, config auth.Config) error {
+	logger := ctrl.Log.WithName("auth")
+
+	server := auth.NewServer(config)
+	// the readiness probe fails until the keys are loaded
+	go server.Run(ctx)
+
+	probes := http.NewServeMux()
+	probes.Handle("/healthz", server)
+
+	servers := []*http.Server{
+		{Addr: listenAddr, Handler: server, ReadHeaderTimeout: 5 * time.Second},
+		{Addr: probeAddr, Handler: probes, ReadHeaderTimeout: 5 * time.Second},
+	}
+
+	errs := make(chan error, len(servers))
+	for _, server := range servers {
+		go func() {
+			if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+				errs <- err
+			}
+		}()
+	}
+
+	logger.Info("serving auth requests", "address", listenAddr, "probes", probeAddr)
+
+	var err error
+	select {
+	case <-ctx.Done():
+	case err = <-errs:
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	for _, server := range servers {
+		_ = server.Shutdown(shutdownCtx)
+	}
+
+	return err
+}
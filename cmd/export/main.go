@@ -0,0 +1,120 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
+	_ "k8s.io/client-go/plugin/pkg/client/auth"
+
+	tsv1alpha1 "github.com/akyriako/typesense-operator/api/v1alpha1"
+	"github.com/akyriako/typesense-operator/internal/controller"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var scheme = runtime.NewScheme()
+
+func init() {
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(tsv1alpha1.AddToScheme(scheme))
+}
+
+// export connects to a TypesenseCluster and writes its collections, overrides, presets and stopwords
+// as manifests of the operator, so that a cluster configured by hand can be adopted without recreating anything.
+func main() {
+	var namespace, cluster, apiUrl, apiKey, outputDir string
+	var timeout time.Duration
+	flag.StringVar(&namespace, "namespace", "default", "The namespace of the TypesenseCluster.")
+	flag.StringVar(&cluster, "cluster", "", "The name of the TypesenseCluster to export.")
+	flag.StringVar(&apiUrl, "api-url", "", "The url of the Typesense API, e.g. http://localhost:8108 when port-forwarding. "+
+		"Defaults to the resolver service of the cluster, which is only reachable from within the Kubernetes cluster.")
+	flag.StringVar(&apiKey, "api-key", "", "The admin api key, defaults to the one the cluster was bootstrapped with.")
+	flag.StringVar(&outputDir, "output-dir", "", "The directory to write one manifest per resource to, defaults to stdout.")
+	flag.DurationVar(&timeout, "timeout", time.Minute, "The timeout of the export.")
+	flag.Parse()
+
+	if cluster == "" {
+		fmt.Fprintln(os.Stderr, "--cluster is required")
+		os.Exit(2)
+	}
+
+	if err := run(namespace, cluster, apiUrl, apiKey, outputDir, timeout); err != nil {
+		fmt.Fprintf(os.Stderr, "exporting cluster %s/%s failed: %v\n", namespace, cluster, err)
+		os.Exit(1)
+	}
+}
+
+func run(namespace, cluster, apiUrl, apiKey, outputDir string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	config, err := ctrl.GetConfig()
+	if err != nil {
+		return err
+	}
+
+	c, err := client.New(config, client.Options{Scheme: scheme})
+	if err != nil {
+		return err
+	}
+
+	ts := &tsv1alpha1.TypesenseCluster{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: cluster}, ts); err != nil {
+		return err
+	}
+
+	state, err := controller.ExportClusterState(ctx, c, ts, apiUrl, apiKey)
+	if err != nil {
+		return err
+	}
+
+	if outputDir == "" {
+		return state.WriteManifests(os.Stdout)
+	}
+
+	for _, note := range state.Notes {
+		fmt.Fprintf(os.Stderr, "note: %s\n", note)
+	}
+
+	if err := os.MkdirAll(outputDir, 0o755); err != nil {
+		return err
+	}
+
+	for _, obj := range state.Objects {
+		manifest, err := controller.MarshalManifest(obj)
+		if err != nil {
+			return err
+		}
+
+		if err := os.WriteFile(filepath.Join(outputDir, controller.GetManifestFileName(obj)), manifest, 0o644); err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "exported %d resources to %s\n", len(state.Objects), outputDir)
+	return nil
+}
@@ -17,11 +17,14 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"crypto/tls"
+	"errors"
 	"flag"
 	"go.uber.org/zap/zapcore"
 	"k8s.io/client-go/discovery"
 	"os"
+	"strings"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
@@ -32,6 +35,7 @@ import (
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
 	tsv1alpha1 "github.com/akyriako/typesense-operator/api/v1alpha1"
+	tsv1beta1 "github.com/akyriako/typesense-operator/api/v1beta1"
 	"github.com/akyriako/typesense-operator/internal/controller"
 	discoveryv1 "k8s.io/api/discovery/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -40,6 +44,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
@@ -59,6 +64,7 @@ func init() {
 	// +kubebuilder:scaffold:scheme
 
 	utilruntime.Must(tsv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(tsv1beta1.AddToScheme(scheme))
 	// +kubebuilder:scaffold:scheme
 }
 
@@ -68,6 +74,8 @@ func main() {
 	var probeAddr string
 	var secureMetrics bool
 	var enableHTTP2 bool
+	var jobImage string
+	var authImage string
 	var tlsOpts []func(*tls.Config)
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
@@ -79,6 +87,12 @@ func main() {
 		"If set, the metrics endpoint is served securely via HTTPS. Use --metrics-secure=false to use HTTP instead.")
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
+	flag.StringVar(&jobImage, "job-image", os.Getenv("RELATED_IMAGE_JOBS"),
+		"The image of the Jobs and CronJobs that do not set one, it has to provide sh, curl, jq and mlr. "+
+			"Defaults to $RELATED_IMAGE_JOBS, which the manifests set to the job image built from Dockerfile.jobs.")
+	flag.StringVar(&authImage, "auth-image", os.Getenv("RELATED_IMAGE_AUTH"),
+		"The image of the auth sidecar of the reverse proxies that do not set one, it has to provide /auth. "+
+			"Defaults to $RELATED_IMAGE_AUTH, which the manifests set to the operator image.")
 
 	opts := zap.Options{
 		Development:     true,
@@ -91,6 +105,15 @@ func main() {
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
+	if jobImage == "" {
+		setupLog.Error(errors.New("neither --job-image nor RELATED_IMAGE_JOBS is set"), "unable to start manager")
+		os.Exit(1)
+	}
+	if authImage == "" {
+		setupLog.Error(errors.New("neither --auth-image nor RELATED_IMAGE_AUTH is set"), "unable to start manager")
+		os.Exit(1)
+	}
+
 	// if the enable-http2 flag is false (the default), http/2 should be disabled
 	// due to its vulnerabilities. More specifically, disabling http/2 will
 	// prevent from being vulnerable to the HTTP/2 Stream Cancellation and
@@ -165,16 +188,123 @@ func main() {
 	}
 
 	if err = (&controller.TypesenseClusterReconciler{
-		Client:          mgr.GetClient(),
-		Scheme:          mgr.GetScheme(),
-		Recorder:        mgr.GetEventRecorderFor("typesensecluster-controller"),
-		DiscoveryClient: discoveryClient,
+		Client:            mgr.GetClient(),
+		Scheme:            mgr.GetScheme(),
+		Recorder:          mgr.GetEventRecorderFor("typesensecluster-controller"),
+		DiscoveryClient:   discoveryClient,
+		OperatorNamespace: getOperatorNamespace(),
+		JobImage:          jobImage,
+		AuthImage:         authImage,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "TypesenseCluster")
 		os.Exit(1)
 	}
+	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		if err = (&tsv1alpha1.TypesenseCluster{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "TypesenseCluster")
+			os.Exit(1)
+		}
+	}
+	if err = (&controller.TypesenseCurationReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("typesensecuration-controller"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "TypesenseCuration")
+		os.Exit(1)
+	}
+	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		if err = (&tsv1alpha1.TypesenseCuration{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "TypesenseCuration")
+			os.Exit(1)
+		}
+	}
+	if err = (&controller.TypesenseStopwordsReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("typesensestopwords-controller"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "TypesenseStopwords")
+		os.Exit(1)
+	}
+	if err = (&controller.TypesenseStemmingDictionaryReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("typesensestemmingdictionary-controller"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "TypesenseStemmingDictionary")
+		os.Exit(1)
+	}
+	if err = (&controller.TypesensePresetReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("typesensepreset-controller"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "TypesensePreset")
+		os.Exit(1)
+	}
+	if err = (&controller.TypesenseAnalyticsRuleReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("typesenseanalyticsrule-controller"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "TypesenseAnalyticsRule")
+		os.Exit(1)
+	}
+	if err = (&controller.TypesenseConversationModelReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("typesenseconversationmodel-controller"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "TypesenseConversationModel")
+		os.Exit(1)
+	}
+	if err = (&controller.TypesenseCollectionReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("typesensecollection-controller"),
+		JobImage: jobImage,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "TypesenseCollection")
+		os.Exit(1)
+	}
+	if err = (&controller.TypesenseScopedKeyReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("typesensescopedkey-controller"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "TypesenseScopedKey")
+		os.Exit(1)
+	}
+	if err = (&controller.TypesenseRetentionPolicyReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("typesenseretentionpolicy-controller"),
+		JobImage: jobImage,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "TypesenseRetentionPolicy")
+		os.Exit(1)
+	}
+	if err = (&controller.TypesenseIngestionReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("typesenseingestion-controller"),
+		JobImage: jobImage,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "TypesenseIngestion")
+		os.Exit(1)
+	}
 	// +kubebuilder:scaffold:builder
 
+	// rewrite the clusters persisted as v1alpha1 in the v1beta1 storage version
+	if err := mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+		controller.MigrateTypesenseClusterStorageVersion(ctx, mgr.GetClient())
+		return nil
+	})); err != nil {
+		setupLog.Error(err, "unable to set up storage version migration")
+		os.Exit(1)
+	}
+
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
 		os.Exit(1)
@@ -190,3 +320,18 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// getOperatorNamespace returns the namespace the operator runs in, from the downward api or the service account,
+// or empty when running out of cluster
+func getOperatorNamespace() string {
+	if namespace := os.Getenv("POD_NAMESPACE"); namespace != "" {
+		return namespace
+	}
+
+	namespace, err := os.ReadFile("/var/run/secrets/kubernetes.io/serviceaccount/namespace")
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimSpace(string(namespace))
+}
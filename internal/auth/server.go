@@ -0,0 +1,458 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	scopedKeyHeader = "X-Typesense-Api-Key"
+	originalMethod  = "X-Original-Method"
+	originalURI     = "X-Original-URI"
+
+	// clockSkew is the leeway of the exp, nbf and iat claims
+	clockSkew = time.Minute
+	// minRefreshInterval throttles the reloads of the keys triggered by tokens signed with an unknown key
+	minRefreshInterval = time.Minute
+	retryInterval      = 10 * time.Second
+	maxJWKSSize        = 1 << 20
+	debugLevel         = 1
+)
+
+// signatureCurves are the asymmetric algorithms a token may be signed with, along with the curve their EC keys
+// have to be on; go-jose checks the length of an ECDSA signature but not the curve of the key verifying it
+var signatureCurves = map[jose.SignatureAlgorithm]elliptic.Curve{
+	jose.RS256: nil, jose.RS384: nil, jose.RS512: nil,
+	jose.PS256: nil, jose.PS384: nil, jose.PS512: nil,
+	jose.ES256: elliptic.P256(), jose.ES384: elliptic.P384(), jose.ES512: elliptic.P521(),
+}
+
+// ClaimMapping restricts the scoped keys to the documents whose Field matches the value of the Claim
+type ClaimMapping struct {
+	Claim string
+	Field string
+}
+
+// Config configures the auth sidecar of the reverse proxy, see spec.ingress.auth
+type Config struct {
+	JWKSFile           string
+	JWKSURL            string
+	RefreshInterval    time.Duration
+	ParentKeyFile      string
+	Issuer             string
+	Audiences          []string
+	Claims             []ClaimMapping
+	FilterBy           string
+	LimitMultiSearches int
+}
+
+// Server answers the auth_request subrequests of nginx: it verifies the bearer token of a request against
+// the JSON Web Key Set and returns a scoped search key restricted by the claims of the token in the
+// X-Typesense-Api-Key header, which nginx forwards to Typesense in place of the token.
+type Server struct {
+	config     Config
+	httpClient *http.Client
+	logger     logr.Logger
+	now        func() time.Time
+
+	refreshMu   sync.Mutex
+	mu          sync.RWMutex
+	keys        []jose.JSONWebKey
+	parentKey   string
+	refreshedAt time.Time
+}
+
+func NewServer(config Config) *Server {
+	return &Server{
+		config:     config,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		logger:     log.Log.WithName("auth"),
+		now:        time.Now,
+	}
+}
+
+// Run loads the keys and the parent key, and reloads them every refresh interval until the context is done;
+// failed loads are retried sooner
+func (s *Server) Run(ctx context.Context) {
+	for {
+		interval := s.config.RefreshInterval
+		if err := s.Refresh(ctx); err != nil {
+			s.logger.Error(err, "refreshing keys failed")
+			interval = min(interval, retryInterval)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+// Refresh reloads the JSON Web Key Set and the parent key, keeping the previous ones if either fails to load
+func (s *Server) Refresh(ctx context.Context) error {
+	s.refreshMu.Lock()
+	defer s.refreshMu.Unlock()
+
+	parentKey, err := os.ReadFile(s.config.ParentKeyFile)
+	if err != nil {
+		return fmt.Errorf("reading parent key failed: %w", err)
+	}
+
+	data, err := s.readJWKS(ctx)
+	if err != nil {
+		return fmt.Errorf("reading jwks failed: %w", err)
+	}
+
+	keys, err := parseJSONWebKeySet(data)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.keys = keys
+	s.parentKey = strings.TrimSpace(string(parentKey))
+	s.refreshedAt = s.now()
+
+	return nil
+}
+
+func (s *Server) readJWKS(ctx context.Context) ([]byte, error) {
+	if s.config.JWKSURL == "" {
+		return os.ReadFile(s.config.JWKSFile)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.config.JWKSURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s returned %s", s.config.JWKSURL, resp.Status)
+	}
+
+	return io.ReadAll(io.LimitReader(resp.Body, maxJWKSSize))
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	switch req.URL.Path {
+	case "/auth":
+		s.serveAuth(w, req)
+	case "/healthz":
+		s.mu.RLock()
+		ready := len(s.keys) > 0 && s.parentKey != ""
+		s.mu.RUnlock()
+
+		if !ready {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// serveAuth answers 200 with a scoped key, 401 without a valid token and 403 when the token does not carry the
+// claims the key is scoped by; nginx turns any other status into an internal error
+func (s *Server) serveAuth(w http.ResponseWriter, req *http.Request) {
+	// preflight requests carry no credentials, Typesense answers them on its own
+	if req.Header.Get(originalMethod) == http.MethodOptions {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	// an api key in the query string would take the place of the scoped key
+	if uri, err := url.ParseRequestURI(req.Header.Get(originalURI)); err == nil {
+		for name := range uri.Query() {
+			if strings.EqualFold(name, "x-typesense-api-key") {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+		}
+	}
+
+	scheme, token, found := strings.Cut(req.Header.Get("Authorization"), " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	claims, registered, err := s.verifyToken(req.Context(), strings.TrimSpace(token))
+	if err != nil {
+		s.logger.V(debugLevel).Info("rejecting token", "reason", err.Error())
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	params, err := s.buildScopedKeyParameters(claims, registered.Expiry.Time())
+	if err != nil {
+		s.logger.V(debugLevel).Info("rejecting token", "reason", err.Error())
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	s.mu.RLock()
+	parentKey := s.parentKey
+	s.mu.RUnlock()
+
+	scopedKey, err := GenerateScopedSearchKey(parentKey, params)
+	if err != nil {
+		s.logger.Error(err, "generating scoped key failed")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set(scopedKeyHeader, scopedKey)
+	w.WriteHeader(http.StatusOK)
+}
+
+// verifyToken checks the signature, the expiration, the issuer and the audience of a JWT and returns its claims,
+// along with the registered ones it was validated against
+func (s *Server) verifyToken(ctx context.Context, token string) (map[string]any, *jwt.Claims, error) {
+	algorithms := make([]jose.SignatureAlgorithm, 0, len(signatureCurves))
+	for algorithm := range signatureCurves {
+		algorithms = append(algorithms, algorithm)
+	}
+
+	signature, err := jose.ParseSignedCompact(token, algorithms)
+	if err != nil {
+		return nil, nil, fmt.Errorf("malformed token: %w", err)
+	}
+	header := signature.Signatures[0].Header
+
+	keys := s.getSigningKeys(header)
+	if len(keys) == 0 && header.KeyID != "" {
+		s.mu.RLock()
+		stale := s.now().Sub(s.refreshedAt) > minRefreshInterval
+		s.mu.RUnlock()
+
+		if stale {
+			if err := s.Refresh(ctx); err != nil {
+				s.logger.Error(err, "refreshing keys failed")
+			}
+			keys = s.getSigningKeys(header)
+		}
+	}
+
+	var payload []byte
+	for _, key := range keys {
+		if payload, err = signature.Verify(key.Key); err == nil {
+			break
+		}
+	}
+	if payload == nil {
+		return nil, nil, fmt.Errorf("invalid signature of key %q with algorithm %q", header.KeyID, header.Algorithm)
+	}
+
+	var registered jwt.Claims
+	if err := json.Unmarshal(payload, &registered); err != nil {
+		return nil, nil, fmt.Errorf("malformed token claims: %w", err)
+	}
+	if registered.Expiry == nil {
+		return nil, nil, fmt.Errorf("token does not expire")
+	}
+
+	expected := jwt.Expected{Issuer: s.config.Issuer, AnyAudience: s.config.Audiences, Time: s.now()}
+	if err := registered.ValidateWithLeeway(expected, clockSkew); err != nil {
+		return nil, nil, err
+	}
+
+	// the claims are decoded once more for the mapping, keeping their numbers as they are
+	var claims map[string]any
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.UseNumber()
+	if err := decoder.Decode(&claims); err != nil {
+		return nil, nil, fmt.Errorf("malformed token claims: %w", err)
+	}
+
+	return claims, &registered, nil
+}
+
+// getSigningKeys returns the keys that may have signed a token with the given header: the key id and the algorithm
+// of the key, if any, have to match, and so does the type of the key and the curve of an EC key
+func (s *Server) getSigningKeys(header jose.Header) []jose.JSONWebKey {
+	algorithm := jose.SignatureAlgorithm(header.Algorithm)
+	curve := signatureCurves[algorithm]
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var keys []jose.JSONWebKey
+	for _, key := range s.keys {
+		if header.KeyID != "" && key.KeyID != header.KeyID {
+			continue
+		}
+		if key.Algorithm != "" && key.Algorithm != header.Algorithm {
+			continue
+		}
+
+		switch publicKey := key.Key.(type) {
+		case *rsa.PublicKey:
+			if curve != nil {
+				continue
+			}
+		case *ecdsa.PublicKey:
+			if curve == nil || publicKey.Curve != curve {
+				continue
+			}
+		default:
+			continue
+		}
+
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+// buildScopedKeyParameters restricts the scoped key to the documents matching the mapped claims and lets it
+// expire together with the token
+func (s *Server) buildScopedKeyParameters(claims map[string]any, expiresAt time.Time) (ScopedKeyParameters, error) {
+	var filters []string
+	if s.config.FilterBy != "" {
+		filters = append(filters, s.config.FilterBy)
+	}
+
+	for _, mapping := range s.config.Claims {
+		value, ok := claims[mapping.Claim]
+		if !ok || value == nil {
+			return ScopedKeyParameters{}, fmt.Errorf("token lacks claim %s", mapping.Claim)
+		}
+
+		filter, err := formatClaimFilter(mapping.Field, value)
+		if err != nil {
+			return ScopedKeyParameters{}, fmt.Errorf("claim %s: %w", mapping.Claim, err)
+		}
+		filters = append(filters, filter)
+	}
+
+	// the filter of the spec is kept apart from the ones of the claims in case it is a disjunction
+	if len(filters) > 1 && s.config.FilterBy != "" {
+		filters[0] = "(" + filters[0] + ")"
+	}
+
+	return ScopedKeyParameters{
+		FilterBy:           strings.Join(filters, " && "),
+		ExpiresAt:          expiresAt.Unix(),
+		LimitMultiSearches: s.config.LimitMultiSearches,
+	}, nil
+}
+
+// formatClaimFilter matches a field against the value of a claim, or against any of its values if it is an array
+func formatClaimFilter(field string, value any) (string, error) {
+	values, isArray := value.([]any)
+	if !isArray {
+		values = []any{value}
+	}
+	if len(values) == 0 {
+		return "", fmt.Errorf("claim is empty")
+	}
+
+	formatted := make([]string, 0, len(values))
+	for _, value := range values {
+		switch v := value.(type) {
+		case string:
+			if strings.Contains(v, "`") {
+				return "", fmt.Errorf("claim contains a backtick")
+			}
+			formatted = append(formatted, "`"+v+"`")
+		case json.Number:
+			formatted = append(formatted, v.String())
+		case bool:
+			formatted = append(formatted, strconv.FormatBool(v))
+		default:
+			return "", fmt.Errorf("claim of type %T cannot be matched", value)
+		}
+	}
+
+	if isArray {
+		return fmt.Sprintf("%s:=[%s]", field, strings.Join(formatted, ", ")), nil
+	}
+
+	return fmt.Sprintf("%s:=%s", field, formatted[0]), nil
+}
+
+// parseJSONWebKeySet returns the RSA and EC signing keys of a JSON Web Key Set, the keys of any other type are
+// skipped as they cannot sign a JWT the sidecar accepts
+func parseJSONWebKeySet(data []byte) ([]jose.JSONWebKey, error) {
+	var set struct {
+		Keys []json.RawMessage `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("malformed jwks: %w", err)
+	}
+
+	keys := make([]jose.JSONWebKey, 0, len(set.Keys))
+	for _, raw := range set.Keys {
+		var header struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			Use string `json:"use"`
+		}
+		if err := json.Unmarshal(raw, &header); err != nil {
+			return nil, fmt.Errorf("malformed jwks: %w", err)
+		}
+		if (header.Kty != "RSA" && header.Kty != "EC") || (header.Use != "" && header.Use != "sig") {
+			continue
+		}
+
+		var key jose.JSONWebKey
+		if err := key.UnmarshalJSON(raw); err != nil {
+			return nil, fmt.Errorf("malformed key %q of jwks: %w", header.Kid, err)
+		}
+		if !key.IsPublic() {
+			key = key.Public()
+		}
+
+		keys = append(keys, key)
+	}
+
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("jwks holds no RSA or EC signing keys")
+	}
+
+	return keys, nil
+}
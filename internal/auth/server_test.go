@@ -0,0 +1,254 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var _ = Describe("Reverse Proxy Auth", func() {
+	Context("When verifying the bearer tokens of the reverse proxy", func() {
+		ctx := context.Background()
+
+		var (
+			rsaKey *rsa.PrivateKey
+			jwks   string
+			idp    *httptest.Server
+			auth   *Server
+		)
+
+		encode := func(v any) string {
+			data, err := json.Marshal(v)
+			Expect(err).NotTo(HaveOccurred())
+			return base64.RawURLEncoding.EncodeToString(data)
+		}
+
+		rsaJWK := func(kid string, key *rsa.PrivateKey) map[string]string {
+			return map[string]string{
+				"kty": "RSA", "kid": kid, "use": "sig", "alg": "RS256",
+				"n": base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e": base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}
+		}
+
+		signRS256 := func(kid string, key *rsa.PrivateKey, claims map[string]any) string {
+			input := encode(map[string]string{"alg": "RS256", "typ": "JWT", "kid": kid}) + "." + encode(claims)
+			digest := sha256.Sum256([]byte(input))
+			signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+			Expect(err).NotTo(HaveOccurred())
+			return input + "." + base64.RawURLEncoding.EncodeToString(signature)
+		}
+
+		validClaims := func() map[string]any {
+			return map[string]any{
+				"iss":    "https://idp.example.com",
+				"aud":    []string{"search", "account"},
+				"exp":    time.Now().Add(time.Hour).Unix(),
+				"tenant": "acme",
+				"groups": []any{"sales", 7},
+			}
+		}
+
+		authenticate := func(header http.Header) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(http.MethodGet, "/auth", nil)
+			req.Header = header
+			recorder := httptest.NewRecorder()
+			auth.ServeHTTP(recorder, req)
+			return recorder
+		}
+
+		bearer := func(token string) http.Header {
+			return http.Header{
+				"Authorization": {"Bearer " + token},
+				originalMethod:  {http.MethodGet},
+				originalURI:     {"/collections/products/documents/search?q=shoes"},
+			}
+		}
+
+		BeforeEach(func() {
+			var err error
+			rsaKey, err = rsa.GenerateKey(rand.Reader, 2048)
+			Expect(err).NotTo(HaveOccurred())
+
+			jwks = `{"keys": [` + string(must(json.Marshal(rsaJWK("key-1", rsaKey)))) + `]}`
+			idp = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				_, _ = w.Write([]byte(jwks))
+			}))
+
+			parentKeyFile := filepath.Join(GinkgoT().TempDir(), "key")
+			Expect(os.WriteFile(parentKeyFile, []byte("searchOnlyKey\n"), 0o600)).To(Succeed())
+
+			auth = NewServer(Config{
+				JWKSURL:         idp.URL,
+				RefreshInterval: time.Hour,
+				ParentKeyFile:   parentKeyFile,
+				Issuer:          "https://idp.example.com",
+				Audiences:       []string{"search"},
+				Claims: []ClaimMapping{
+					{Claim: "tenant", Field: "tenant_id"},
+					{Claim: "groups", Field: "group"},
+				},
+				FilterBy:           "public:=true || internal:=false",
+				LimitMultiSearches: 5,
+			})
+			auth.logger = log.Log
+			Expect(auth.Refresh(ctx)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			idp.Close()
+		})
+
+		It("should answer a valid token with a key scoped by its claims", func() {
+			claims := validClaims()
+			response := authenticate(bearer(signRS256("key-1", rsaKey, claims)))
+			Expect(response.Code).To(Equal(http.StatusOK))
+
+			expected, err := GenerateScopedSearchKey("searchOnlyKey", ScopedKeyParameters{
+				FilterBy:           "(public:=true || internal:=false) && tenant_id:=`acme` && group:=[`sales`, 7]",
+				ExpiresAt:          claims["exp"].(int64),
+				LimitMultiSearches: 5,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response.Header().Get(scopedKeyHeader)).To(Equal(expected))
+		})
+
+		It("should reject requests without a valid token", func() {
+			Expect(authenticate(http.Header{originalMethod: {http.MethodGet}}).Code).To(Equal(http.StatusUnauthorized))
+			Expect(authenticate(http.Header{"Authorization": {"Basic dXNlcjpwYXNz"}}).Code).To(Equal(http.StatusUnauthorized))
+
+			expired := validClaims()
+			expired["exp"] = time.Now().Add(-2 * time.Minute).Unix()
+			Expect(authenticate(bearer(signRS256("key-1", rsaKey, expired))).Code).To(Equal(http.StatusUnauthorized))
+
+			issuer := validClaims()
+			issuer["iss"] = "https://evil.example.com"
+			Expect(authenticate(bearer(signRS256("key-1", rsaKey, issuer))).Code).To(Equal(http.StatusUnauthorized))
+
+			audience := validClaims()
+			audience["aud"] = "account"
+			Expect(authenticate(bearer(signRS256("key-1", rsaKey, audience))).Code).To(Equal(http.StatusUnauthorized))
+
+			token := signRS256("key-1", rsaKey, validClaims())
+			parts := strings.Split(token, ".")
+			tampered := parts[0] + "." + encode(map[string]any{"iss": "https://idp.example.com", "aud": "search", "exp": time.Now().Add(time.Hour).Unix(), "tenant": "other", "groups": []string{"sales"}}) + "." + parts[2]
+			Expect(authenticate(bearer(tampered)).Code).To(Equal(http.StatusUnauthorized))
+
+			unsigned := encode(map[string]string{"alg": "none"}) + "." + parts[1] + "."
+			Expect(authenticate(bearer(unsigned)).Code).To(Equal(http.StatusUnauthorized))
+		})
+
+		It("should forbid tokens lacking a claim and api keys in the query string", func() {
+			claims := validClaims()
+			delete(claims, "tenant")
+			Expect(authenticate(bearer(signRS256("key-1", rsaKey, claims))).Code).To(Equal(http.StatusForbidden))
+
+			claims = validClaims()
+			claims["tenant"] = "acme` || tenant_id:=`other"
+			Expect(authenticate(bearer(signRS256("key-1", rsaKey, claims))).Code).To(Equal(http.StatusForbidden))
+
+			header := bearer(signRS256("key-1", rsaKey, validClaims()))
+			header.Set(originalURI, "/multi_search?X-TYPESENSE-API-KEY=adminKey")
+			Expect(authenticate(header).Code).To(Equal(http.StatusForbidden))
+
+			response := authenticate(http.Header{originalMethod: {http.MethodOptions}})
+			Expect(response.Code).To(Equal(http.StatusOK))
+			Expect(response.Header().Get(scopedKeyHeader)).To(BeEmpty())
+		})
+
+		It("should reload the keys when a token is signed with an unknown key", func() {
+			rotated, err := rsa.GenerateKey(rand.Reader, 2048)
+			Expect(err).NotTo(HaveOccurred())
+			jwks = `{"keys": [` + string(must(json.Marshal(rsaJWK("key-2", rotated)))) + `]}`
+
+			token := signRS256("key-2", rotated, validClaims())
+			Expect(authenticate(bearer(token)).Code).To(Equal(http.StatusUnauthorized))
+
+			auth.now = func() time.Time { return time.Now().Add(2 * minRefreshInterval) }
+			Expect(authenticate(bearer(token)).Code).To(Equal(http.StatusOK))
+			Expect(authenticate(bearer(signRS256("key-1", rsaKey, validClaims()))).Code).To(Equal(http.StatusUnauthorized))
+		})
+
+		It("should verify EC keys read from a file", func() {
+			ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+			Expect(err).NotTo(HaveOccurred())
+
+			jwksFile := filepath.Join(GinkgoT().TempDir(), "jwks.json")
+			Expect(os.WriteFile(jwksFile, must(json.Marshal(map[string]any{"keys": []map[string]string{
+				{"kty": "oct", "k": "c2VjcmV0"},
+				{"kty": "EC", "crv": "P-256", "x": base64.RawURLEncoding.EncodeToString(ecKey.X.FillBytes(make([]byte, 32))), "y": base64.RawURLEncoding.EncodeToString(ecKey.Y.FillBytes(make([]byte, 32)))},
+			}})), 0o600)).To(Succeed())
+
+			auth.config.JWKSURL, auth.config.JWKSFile = "", jwksFile
+			auth.config.Claims, auth.config.FilterBy = nil, ""
+			Expect(auth.Refresh(ctx)).To(Succeed())
+
+			input := encode(map[string]string{"alg": "ES256"}) + "." + encode(validClaims())
+			digest := sha256.Sum256([]byte(input))
+			r, s, err := ecdsa.Sign(rand.Reader, ecKey, digest[:])
+			Expect(err).NotTo(HaveOccurred())
+			signature := append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+
+			Expect(authenticate(bearer(input + "." + base64.RawURLEncoding.EncodeToString(signature))).Code).To(Equal(http.StatusOK))
+			Expect(authenticate(bearer(signRS256("key-1", rsaKey, validClaims()))).Code).To(Equal(http.StatusUnauthorized))
+		})
+
+		It("should reject an EC key on another curve than the one of the algorithm", func() {
+			ecKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+			Expect(err).NotTo(HaveOccurred())
+
+			jwks = string(must(json.Marshal(map[string]any{"keys": []map[string]string{
+				{"kty": "EC", "kid": "key-3", "crv": "P-384", "x": base64.RawURLEncoding.EncodeToString(ecKey.X.FillBytes(make([]byte, 48))), "y": base64.RawURLEncoding.EncodeToString(ecKey.Y.FillBytes(make([]byte, 48)))},
+			}})))
+			Expect(auth.Refresh(ctx)).To(Succeed())
+
+			sign := func(digest []byte) string {
+				r, s, err := ecdsa.Sign(rand.Reader, ecKey, digest)
+				Expect(err).NotTo(HaveOccurred())
+				return base64.RawURLEncoding.EncodeToString(append(r.FillBytes(make([]byte, 48)), s.FillBytes(make([]byte, 48))...))
+			}
+
+			input := encode(map[string]string{"alg": "ES256", "kid": "key-3"}) + "." + encode(validClaims())
+			digest := sha256.Sum256([]byte(input))
+			Expect(authenticate(bearer(input + "." + sign(digest[:]))).Code).To(Equal(http.StatusUnauthorized))
+
+			input = encode(map[string]string{"alg": "ES384", "kid": "key-3"}) + "." + encode(validClaims())
+			digest384 := sha512.Sum384([]byte(input))
+			Expect(authenticate(bearer(input + "." + sign(digest384[:]))).Code).To(Equal(http.StatusOK))
+		})
+	})
+})
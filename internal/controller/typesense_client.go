@@ -0,0 +1,197 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	tsv1alpha1 "github.com/akyriako/typesense-operator/api/v1alpha1"
+	"io"
+	v1 "k8s.io/api/core/v1"
+	"net/http"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"time"
+)
+
+const typesenseClientTimeout = 10 * time.Second
+
+// typesenseClient is a thin admin client for the Typesense REST API, used by the
+// controllers that manage data-plane resources (overrides, presets, stopwords etc.)
+type typesenseClient struct {
+	baseUrl    string
+	apiKey     string
+	httpClient *http.Client
+}
+
+type typesenseApiError struct {
+	StatusCode int
+	Message    string `json:"message"`
+}
+
+func (e *typesenseApiError) Error() string {
+	return fmt.Sprintf("typesense api returned %d: %s", e.StatusCode, e.Message)
+}
+
+func isTypesenseNotFound(err error) bool {
+	var apiErr *typesenseApiError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+func newTypesenseClient(baseUrl string, apiKey string) *typesenseClient {
+	return &typesenseClient{
+		baseUrl: baseUrl,
+		apiKey:  apiKey,
+		httpClient: &http.Client{
+			Timeout: typesenseClientTimeout,
+		},
+	}
+}
+
+// newTypesenseClientForCluster returns an admin client for the resolver service of the given cluster,
+// authenticated with the same admin api key the StatefulSet is bootstrapped with.
+func newTypesenseClientForCluster(ctx context.Context, c client.Client, ts *tsv1alpha1.TypesenseCluster) (*typesenseClient, error) {
+	secret := &v1.Secret{}
+	if err := c.Get(ctx, adminApiKeyObjectKey(ts), secret); err != nil {
+		return nil, err
+	}
+
+	apiKey := getAdminApiKey(ts, secret)
+	if len(apiKey) == 0 {
+		return nil, fmt.Errorf("secret %s does not contain key %s", secret.Name, ts.Spec.GetAdminApiKeySecretKey())
+	}
+
+	tc := newTypesenseClient(getClusterApiUrl(ts), string(apiKey))
+	if err := setTypesenseClientTLS(ctx, c, ts, tc); err != nil {
+		return nil, err
+	}
+
+	return tc, nil
+}
+
+// setTypesenseClientTLS makes the client verify the api against the CA of the cluster certificate, if tls is enabled.
+func setTypesenseClientTLS(ctx context.Context, c client.Client, ts *tsv1alpha1.TypesenseCluster, tc *typesenseClient) error {
+	transport, err := newTLSTransport(ctx, c, ts, "")
+	if err != nil {
+		return err
+	}
+
+	if transport != nil {
+		tc.httpClient.Transport = transport
+	}
+
+	return nil
+}
+
+func getClusterApiUrl(ts *tsv1alpha1.TypesenseCluster) string {
+	return fmt.Sprintf("%s://%s.%s.svc.cluster.local:%d", getApiProtocol(ts), fmt.Sprintf(ClusterRestService, ts.Name), ts.Namespace, ts.Spec.ApiPort)
+}
+
+func (c *typesenseClient) get(ctx context.Context, path string, result any) error {
+	return c.do(ctx, http.MethodGet, path, nil, result)
+}
+
+func (c *typesenseClient) post(ctx context.Context, path string, payload any, result any) error {
+	return c.do(ctx, http.MethodPost, path, payload, result)
+}
+
+func (c *typesenseClient) put(ctx context.Context, path string, payload any, result any) error {
+	return c.do(ctx, http.MethodPut, path, payload, result)
+}
+
+func (c *typesenseClient) patch(ctx context.Context, path string, payload any, result any) error {
+	return c.do(ctx, http.MethodPatch, path, payload, result)
+}
+
+func (c *typesenseClient) delete(ctx context.Context, path string) error {
+	return c.do(ctx, http.MethodDelete, path, nil, nil)
+}
+
+func (c *typesenseClient) do(ctx context.Context, method string, path string, payload any, result any) error {
+	var body io.Reader
+	if payload != nil {
+		b, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(b)
+	}
+
+	respBody, err := c.send(ctx, method, path, "application/json", body)
+	if err != nil {
+		return err
+	}
+
+	if result == nil || len(respBody) == 0 {
+		return nil
+	}
+
+	return json.Unmarshal(respBody, result)
+}
+
+// importJsonl posts a newline delimited JSON payload, as expected by the import endpoints,
+// and returns the raw JSONL response with the outcome of every line.
+func (c *typesenseClient) importJsonl(ctx context.Context, path string, lines []byte) ([]byte, error) {
+	return c.send(ctx, http.MethodPost, path, "text/plain", bytes.NewReader(lines))
+}
+
+func (c *typesenseClient) send(ctx context.Context, method string, path string, contentType string, body io.Reader) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.baseUrl+path, body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("x-typesense-api-key", c.apiKey)
+	if body != nil {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		apiErr := &typesenseApiError{StatusCode: resp.StatusCode}
+		if err := json.Unmarshal(respBody, apiErr); err != nil || apiErr.Message == "" {
+			apiErr.Message = http.StatusText(resp.StatusCode)
+		}
+		return nil, apiErr
+	}
+
+	return respBody, nil
+}
+
+type typesenseImportResult struct {
+	Success  bool   `json:"success"`
+	Error    string `json:"error,omitempty"`
+	Document string `json:"document,omitempty"`
+}
+
+// getImportFailures parses the JSONL response of an import endpoint and returns the lines that failed.
+func getImportFailures(response []byte) ([]typesenseImportResult, error) {
+	var failures []typesenseImportResult
+	for _, line := range bytes.Split(response, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+
+		var result typesenseImportResult
+		if err := json.Unmarshal(line, &result); err != nil {
+			return nil, err
+		}
+
+		if !result.Success {
+			failures = append(failures, result)
+		}
+	}
+
+	return failures, nil
+}
@@ -0,0 +1,135 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+type typesenseCollectionField struct {
+	Name           string `json:"name"`
+	Type           string `json:"type"`
+	Facet          *bool  `json:"facet,omitempty"`
+	Optional       *bool  `json:"optional,omitempty"`
+	Index          *bool  `json:"index,omitempty"`
+	Sort           *bool  `json:"sort,omitempty"`
+	Infix          *bool  `json:"infix,omitempty"`
+	Locale         string `json:"locale,omitempty"`
+	Stem           *bool  `json:"stem,omitempty"`
+	StemDictionary string `json:"stem_dictionary,omitempty"`
+	Reference      string `json:"reference,omitempty"`
+	NumDim         *int   `json:"num_dim,omitempty"`
+	Drop           *bool  `json:"drop,omitempty"`
+}
+
+type typesenseCollection struct {
+	Name                string                     `json:"name"`
+	Fields              []typesenseCollectionField `json:"fields"`
+	DefaultSortingField string                     `json:"default_sorting_field,omitempty"`
+	TokenSeparators     []string                   `json:"token_separators,omitempty"`
+	SymbolsToIndex      []string                   `json:"symbols_to_index,omitempty"`
+	EnableNestedFields  *bool                      `json:"enable_nested_fields,omitempty"`
+	NumDocuments        int64                      `json:"num_documents,omitempty"`
+}
+
+type typesenseAlias struct {
+	Name           string `json:"name,omitempty"`
+	CollectionName string `json:"collection_name"`
+}
+
+type typesensePreset struct {
+	Name  string         `json:"name"`
+	Value map[string]any `json:"value"`
+}
+
+type typesensePresetList struct {
+	Presets []typesensePreset `json:"presets"`
+}
+
+func (c *typesenseClient) listCollections(ctx context.Context) ([]typesenseCollection, error) {
+	var collections []typesenseCollection
+	if err := c.get(ctx, "/collections", &collections); err != nil {
+		return nil, err
+	}
+
+	return collections, nil
+}
+
+func (c *typesenseClient) listPresets(ctx context.Context) ([]typesensePreset, error) {
+	var presets typesensePresetList
+	if err := c.get(ctx, "/presets", &presets); err != nil {
+		return nil, err
+	}
+
+	return presets.Presets, nil
+}
+
+// getPresetSearches flattens a preset value to the search parameters it contains,
+// either the value itself or every entry of a multi_search preset.
+func getPresetSearches(preset typesensePreset) []map[string]any {
+	searches, ok := preset.Value["searches"].([]any)
+	if !ok {
+		return []map[string]any{preset.Value}
+	}
+
+	params := make([]map[string]any, 0, len(searches))
+	for _, search := range searches {
+		if s, ok := search.(map[string]any); ok {
+			params = append(params, s)
+		}
+	}
+
+	return params
+}
+
+func (c *typesenseClient) getCollection(ctx context.Context, name string) (*typesenseCollection, error) {
+	collection := &typesenseCollection{}
+	if err := c.get(ctx, fmt.Sprintf("/collections/%s", url.PathEscape(name)), collection); err != nil {
+		return nil, err
+	}
+
+	return collection, nil
+}
+
+func (c *typesenseClient) getAlias(ctx context.Context, name string) (*typesenseAlias, error) {
+	alias := &typesenseAlias{}
+	if err := c.get(ctx, fmt.Sprintf("/aliases/%s", url.PathEscape(name)), alias); err != nil {
+		return nil, err
+	}
+
+	return alias, nil
+}
+
+func (c *typesenseClient) upsertAlias(ctx context.Context, name string, collection string) error {
+	return c.put(ctx, fmt.Sprintf("/aliases/%s", url.PathEscape(name)), typesenseAlias{CollectionName: collection}, nil)
+}
+
+// getField looks a field up by name, honouring wildcard field definitions (e.g. `.*` or `price_.*`)
+// and nested fields that are declared through their parent object.
+func (c *typesenseCollection) getField(name string) (typesenseCollectionField, bool) {
+	for _, field := range c.Fields {
+		if field.Name == name {
+			return field, true
+		}
+	}
+
+	for _, field := range c.Fields {
+		if strings.Contains(field.Name, "*") {
+			if re, err := regexp.Compile("^" + field.Name + "$"); err == nil && re.MatchString(name) {
+				return field, true
+			}
+		}
+
+		if strings.HasPrefix(field.Type, "object") && strings.HasPrefix(name, field.Name+".") {
+			return field, true
+		}
+	}
+
+	return typesenseCollectionField{}, false
+}
+
+func (f typesenseCollectionField) isFacet() bool {
+	return f.Facet != nil && *f.Facet
+}
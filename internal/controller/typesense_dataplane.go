@@ -0,0 +1,158 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	tsv1alpha1 "github.com/akyriako/typesense-operator/api/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sort"
+	"time"
+)
+
+const (
+	dataPlaneFinalizer    = "ts.opentelekomcloud.com/finalizer"
+	dataPlaneRequeueAfter = 30 * time.Second
+
+	ConditionReasonSynced             = "Synced"
+	ConditionReasonSyncFailed         = "SyncFailed"
+	ConditionReasonClusterNotFound    = "ClusterNotFound"
+	ConditionReasonClusterNotReady    = "ClusterNotReady"
+	ConditionReasonCollectionNotFound = "CollectionNotFound"
+	ConditionReasonInvalidSpec        = "InvalidSpec"
+)
+
+// getTargetCluster resolves the TypesenseCluster a data-plane resource points at
+// and returns an admin client for it. The cluster is expected to live in the same namespace.
+func getTargetCluster(ctx context.Context, c client.Client, namespace string, ref v1.LocalObjectReference) (*tsv1alpha1.TypesenseCluster, *typesenseClient, string, error) {
+	ts := &tsv1alpha1.TypesenseCluster{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: ref.Name}, ts); err != nil {
+		return nil, nil, ConditionReasonClusterNotFound, err
+	}
+
+	if !meta.IsStatusConditionTrue(ts.Status.Conditions, ConditionTypeReady) {
+		return ts, nil, ConditionReasonClusterNotReady, fmt.Errorf("typesense cluster %s is not ready", ts.Name)
+	}
+
+	tc, err := newTypesenseClientForCluster(ctx, c, ts)
+	if err != nil {
+		return ts, nil, ConditionReasonClusterNotReady, err
+	}
+
+	return ts, tc, "", nil
+}
+
+// patchObjectStatus patches the status subresource of any data-plane resource with the changes applied by patcher.
+func patchObjectStatus(ctx context.Context, c client.Client, obj client.Object, patcher func()) error {
+	patch := client.MergeFrom(obj.DeepCopyObject().(client.Object))
+	patcher()
+
+	return c.Status().Patch(ctx, obj, patch)
+}
+
+func setDataPlaneCondition(conditions *[]metav1.Condition, generation int64, reason string, err error) {
+	condition := metav1.Condition{
+		Type:               ConditionTypeReady,
+		Status:             metav1.ConditionTrue,
+		Reason:             reason,
+		Message:            "Resource is in sync",
+		ObservedGeneration: generation,
+	}
+
+	if err != nil {
+		condition.Status = metav1.ConditionFalse
+		condition.Message = err.Error()
+	}
+
+	meta.SetStatusCondition(conditions, condition)
+}
+
+func addDataPlaneFinalizer(ctx context.Context, c client.Client, obj client.Object) error {
+	if controllerutil.ContainsFinalizer(obj, dataPlaneFinalizer) {
+		return nil
+	}
+
+	patch := client.MergeFrom(obj.DeepCopyObject().(client.Object))
+	controllerutil.AddFinalizer(obj, dataPlaneFinalizer)
+
+	return c.Patch(ctx, obj, patch)
+}
+
+func removeDataPlaneFinalizer(ctx context.Context, c client.Client, obj client.Object) error {
+	if !controllerutil.ContainsFinalizer(obj, dataPlaneFinalizer) {
+		return nil
+	}
+
+	patch := client.MergeFrom(obj.DeepCopyObject().(client.Object))
+	controllerutil.RemoveFinalizer(obj, dataPlaneFinalizer)
+
+	return c.Patch(ctx, obj, patch)
+}
+
+// getConfigMapKey returns the content of a ConfigMap key referenced by a data-plane resource.
+func getConfigMapKey(ctx context.Context, c client.Client, namespace string, selector *v1.ConfigMapKeySelector) (string, error) {
+	cm := &v1.ConfigMap{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: selector.Name}, cm); err != nil {
+		return "", err
+	}
+
+	value, ok := cm.Data[selector.Key]
+	if !ok {
+		return "", fmt.Errorf("configmap %s does not contain key %s", selector.Name, selector.Key)
+	}
+
+	return value, nil
+}
+
+// getSecretKey returns the content of a Secret key referenced by a data-plane resource.
+func getSecretKey(ctx context.Context, c client.Client, namespace string, selector *v1.SecretKeySelector) (string, error) {
+	secret := &v1.Secret{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: selector.Name}, secret); err != nil {
+		return "", err
+	}
+
+	value, ok := secret.Data[selector.Key]
+	if !ok || len(value) == 0 {
+		return "", fmt.Errorf("secret %s does not contain key %s", selector.Name, selector.Key)
+	}
+
+	return string(value), nil
+}
+
+// enqueueReferencingObjects maps an event of a ConfigMap or Secret to every resource of the list type, in the same namespace,
+// for which refersTo returns true; so content sourced from ConfigMaps or Secrets is re-applied whenever it changes.
+func enqueueReferencingObjects(c client.Client, list client.ObjectList, refersTo func(obj client.Object, name string) bool) handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, source client.Object) []reconcile.Request {
+		objects := list.DeepCopyObject().(client.ObjectList)
+		if err := c.List(ctx, objects, client.InNamespace(source.GetNamespace())); err != nil {
+			return nil
+		}
+
+		var requests []reconcile.Request
+		_ = meta.EachListItem(objects, func(o runtime.Object) error {
+			obj := o.(client.Object)
+			if refersTo(obj, source.GetName()) {
+				requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(obj)})
+			}
+			return nil
+		})
+
+		return requests
+	})
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
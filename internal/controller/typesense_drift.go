@@ -0,0 +1,194 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	tsv1alpha1 "github.com/akyriako/typesense-operator/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	ConditionTypeDrifted = "Drifted"
+
+	ConditionReasonDriftDetected   = "DriftDetected"
+	ConditionReasonDriftReconciled = "DriftReconciled"
+	ConditionReasonNoDrift         = "NoDrift"
+
+	driftMessageMaxLines = 10
+)
+
+// diffServerObjects compares the server objects a data-plane resource manages, keyed by their id, with the live ones.
+// Objects are compared in their JSON form and only the attributes set in the desired object count,
+// so defaults filled in by Typesense are not reported as drift.
+func diffServerObjects(kind string, desired map[string]any, live map[string]any) ([]string, error) {
+	var diff []string
+
+	for _, id := range sortedKeys(toKeySet(desired)) {
+		l, ok := live[id]
+		if !ok {
+			diff = append(diff, fmt.Sprintf("%s %s is missing", kind, id))
+			continue
+		}
+
+		d, err := toJsonValue(desired[id])
+		if err != nil {
+			return nil, err
+		}
+
+		lv, err := toJsonValue(l)
+		if err != nil {
+			return nil, err
+		}
+
+		diff = append(diff, diffJsonValues(fmt.Sprintf("%s %s", kind, id), d, lv)...)
+	}
+
+	for _, id := range sortedKeys(toKeySet(live)) {
+		if _, ok := desired[id]; !ok {
+			diff = append(diff, fmt.Sprintf("%s %s is not in the spec", kind, id))
+		}
+	}
+
+	return diff, nil
+}
+
+func diffJsonValues(path string, desired any, live any) []string {
+	d, ok := desired.(map[string]any)
+	if !ok {
+		if reflect.DeepEqual(desired, live) {
+			return nil
+		}
+
+		return []string{fmt.Sprintf("%s: %s, expected %s", path, toJsonString(live), toJsonString(desired))}
+	}
+
+	l, ok := live.(map[string]any)
+	if !ok {
+		return []string{fmt.Sprintf("%s: %s, expected %s", path, toJsonString(live), toJsonString(desired))}
+	}
+
+	keys := make([]string, 0, len(d))
+	for key := range d {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var diff []string
+	for _, key := range keys {
+		diff = append(diff, diffJsonValues(path+"."+key, d[key], l[key])...)
+	}
+
+	return diff
+}
+
+func toJsonValue(v any) (any, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var value any
+	return value, json.Unmarshal(b, &value)
+}
+
+func toJsonString(v any) string {
+	if v == nil {
+		return "<unset>"
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+
+	return string(b)
+}
+
+func toKeySet(m map[string]any) map[string]bool {
+	set := make(map[string]bool, len(m))
+	for key := range m {
+		set[key] = true
+	}
+
+	return set
+}
+
+// evaluateDrift decides whether the desired state of a data-plane resource has to be applied. Differences found
+// while the spec is unchanged since the last successful sync are drift, anything else is a change of the spec itself.
+func evaluateDrift(spec *tsv1alpha1.DriftDetectionSpec, conditions []metav1.Condition, generation int64, diff []string) (drifted bool, apply bool) {
+	if spec.GetPolicy() == tsv1alpha1.DriftPolicyIgnore {
+		return false, true
+	}
+
+	synced := meta.FindStatusCondition(conditions, ConditionTypeReady)
+	if synced == nil || synced.Status != metav1.ConditionTrue || synced.ObservedGeneration != generation {
+		return false, true
+	}
+
+	if len(diff) == 0 {
+		return false, false
+	}
+
+	return true, spec.GetPolicy() == tsv1alpha1.DriftPolicyReconcile
+}
+
+func setDriftCondition(conditions *[]metav1.Condition, generation int64, spec *tsv1alpha1.DriftDetectionSpec, drifted bool, applied bool, diff []string) {
+	if spec.GetPolicy() == tsv1alpha1.DriftPolicyIgnore {
+		meta.RemoveStatusCondition(conditions, ConditionTypeDrifted)
+		return
+	}
+
+	condition := metav1.Condition{
+		Type:               ConditionTypeDrifted,
+		Status:             metav1.ConditionFalse,
+		Reason:             ConditionReasonNoDrift,
+		Message:            "Server state matches the spec",
+		ObservedGeneration: generation,
+	}
+
+	if drifted {
+		condition.Message = formatDrift(diff)
+		if applied {
+			condition.Reason = ConditionReasonDriftReconciled
+		} else {
+			condition.Status = metav1.ConditionTrue
+			condition.Reason = ConditionReasonDriftDetected
+		}
+	}
+
+	meta.SetStatusCondition(conditions, condition)
+}
+
+func recordDriftEvent(recorder record.EventRecorder, obj runtime.Object, applied bool, diff []string) {
+	if applied {
+		recorder.Event(obj, "Normal", ConditionReasonDriftReconciled, formatDrift(diff))
+		return
+	}
+
+	recorder.Event(obj, "Warning", ConditionReasonDriftDetected, formatDrift(diff))
+}
+
+// formatDrift renders a diff as one line per difference, capped to keep the condition readable.
+func formatDrift(diff []string) string {
+	if len(diff) <= driftMessageMaxLines {
+		return strings.Join(diff, "\n")
+	}
+
+	return fmt.Sprintf("%s\nand %d more differences", strings.Join(diff[:driftMessageMaxLines], "\n"), len(diff)-driftMessageMaxLines)
+}
+
+// getDriftRequeueAfter returns the interval drift is checked at, or zero when drift is ignored.
+func getDriftRequeueAfter(spec *tsv1alpha1.DriftDetectionSpec) time.Duration {
+	if spec.GetPolicy() == tsv1alpha1.DriftPolicyIgnore {
+		return 0
+	}
+
+	return time.Duration(spec.GetIntervalInSeconds()) * time.Second
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	tsv1alpha1 "github.com/akyriako/typesense-operator/api/v1alpha1"
+)
+
+var _ = Describe("Typesense Drift", func() {
+	Context("When comparing server objects with the spec", func() {
+		It("should only report the attributes set in the spec", func() {
+			desired := map[string]any{
+				"pin-shoes": typesenseOverride{ID: "pin-shoes", Rule: typesenseOverrideRule{Query: "shoes", Match: "exact"}},
+				"hide-old":  typesenseOverride{ID: "hide-old", Rule: typesenseOverrideRule{Query: "old", Match: "contains"}},
+			}
+			live := map[string]any{
+				"pin-shoes": map[string]any{"id": "pin-shoes", "rule": map[string]any{"query": "shoes", "match": "exact"}, "stop_processing": true},
+				"hide-old":  map[string]any{"id": "hide-old", "rule": map[string]any{"query": "new", "match": "contains"}},
+				"manual":    map[string]any{"id": "manual"},
+			}
+
+			diff, err := diffServerObjects("override", desired, live)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(diff).To(Equal([]string{
+				`override hide-old.rule.query: "new", expected "old"`,
+				"override manual is not in the spec",
+			}))
+
+			delete(live, "pin-shoes")
+			diff, err = diffServerObjects("override", desired, live)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(diff).To(ContainElement("override pin-shoes is missing"))
+		})
+
+		It("should only treat differences to an already synced spec as drift", func() {
+			diff := []string{"override manual is not in the spec"}
+			synced := []metav1.Condition{{Type: ConditionTypeReady, Status: metav1.ConditionTrue, ObservedGeneration: 2}}
+
+			drifted, apply := evaluateDrift(nil, synced, 2, diff)
+			Expect(drifted).To(BeTrue())
+			Expect(apply).To(BeTrue())
+
+			drifted, apply = evaluateDrift(nil, synced, 2, nil)
+			Expect(drifted).To(BeFalse())
+			Expect(apply).To(BeFalse())
+
+			drifted, apply = evaluateDrift(nil, synced, 3, diff)
+			Expect(drifted).To(BeFalse())
+			Expect(apply).To(BeTrue())
+
+			report := &tsv1alpha1.DriftDetectionSpec{Policy: tsv1alpha1.DriftPolicyReport}
+			drifted, apply = evaluateDrift(report, synced, 2, diff)
+			Expect(drifted).To(BeTrue())
+			Expect(apply).To(BeFalse())
+
+			var conditions []metav1.Condition
+			setDriftCondition(&conditions, 2, report, drifted, apply, diff)
+			Expect(conditions).To(HaveLen(1))
+			Expect(conditions[0].Status).To(Equal(metav1.ConditionTrue))
+			Expect(conditions[0].Reason).To(Equal(ConditionReasonDriftDetected))
+
+			ignore := &tsv1alpha1.DriftDetectionSpec{Policy: tsv1alpha1.DriftPolicyIgnore}
+			drifted, apply = evaluateDrift(ignore, synced, 2, diff)
+			Expect(drifted).To(BeFalse())
+			Expect(apply).To(BeTrue())
+			Expect(getDriftRequeueAfter(ignore)).To(BeZero())
+
+			setDriftCondition(&conditions, 2, ignore, drifted, apply, diff)
+			Expect(conditions).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,473 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	tsv1alpha1 "github.com/akyriako/typesense-operator/api/v1alpha1"
+	"io"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/utils/ptr"
+	"net/url"
+	"regexp"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+	"sort"
+	"strings"
+	"time"
+)
+
+// typesenseKeyNeverExpires is the expires_at Typesense reports for api keys created without one
+const typesenseKeyNeverExpires = 64723363199
+
+var (
+	invalidResourceNameChars = regexp.MustCompile(`[^a-z0-9-]+`)
+	validCurationRuleName    = regexp.MustCompile(`^[a-zA-Z0-9_\-]+$`)
+)
+
+type typesenseAliasList struct {
+	Aliases []typesenseAlias `json:"aliases"`
+}
+
+type typesenseSynonymList struct {
+	Synonyms []json.RawMessage `json:"synonyms"`
+}
+
+type typesenseStopwordsList struct {
+	Stopwords []typesenseStopwordsSet `json:"stopwords"`
+}
+
+type typesenseApiKey struct {
+	ID          int64    `json:"id"`
+	Description string   `json:"description"`
+	Actions     []string `json:"actions"`
+	Collections []string `json:"collections"`
+	ExpiresAt   int64    `json:"expires_at"`
+	ValuePrefix string   `json:"value_prefix"`
+	Value       string   `json:"value,omitempty"`
+}
+
+type typesenseApiKeyList struct {
+	Keys []typesenseApiKey `json:"keys"`
+}
+
+// ClusterState is the data-plane configuration of a running cluster, expressed as resources of the operator.
+type ClusterState struct {
+	Objects []client.Object
+
+	// Notes lists what was found on the server but cannot be adopted by a resource of the operator
+	Notes []string
+}
+
+// ExportClusterState reads collections, aliases, overrides, presets, stopwords, synonyms and api keys of a cluster,
+// and converts them to resources that adopt the existing server objects as they are. apiUrl and apiKey are optional,
+// by default the resolver service of the cluster and its admin api key are used.
+func ExportClusterState(ctx context.Context, c client.Client, ts *tsv1alpha1.TypesenseCluster, apiUrl string, apiKey string) (*ClusterState, error) {
+	if apiKey == "" {
+		tc, err := newTypesenseClientForCluster(ctx, c, ts)
+		if err != nil {
+			return nil, err
+		}
+		apiKey = tc.apiKey
+	}
+
+	if apiUrl == "" {
+		apiUrl = getClusterApiUrl(ts)
+	}
+
+	tc := newTypesenseClient(strings.TrimSuffix(apiUrl, "/"), apiKey)
+	if err := setTypesenseClientTLS(ctx, c, ts, tc); err != nil {
+		return nil, err
+	}
+
+	return exportClusterState(ctx, tc, ts)
+}
+
+func exportClusterState(ctx context.Context, tc *typesenseClient, ts *tsv1alpha1.TypesenseCluster) (*ClusterState, error) {
+	state := &ClusterState{}
+
+	collections, err := tc.listCollections(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(collections, func(i, j int) bool { return collections[i].Name < collections[j].Name })
+
+	var aliases typesenseAliasList
+	if err := tc.get(ctx, "/aliases", &aliases); err != nil {
+		return nil, err
+	}
+	sort.Slice(aliases.Aliases, func(i, j int) bool { return aliases.Aliases[i].Name < aliases.Aliases[j].Name })
+
+	byName := make(map[string]typesenseCollection, len(collections))
+	for _, collection := range collections {
+		byName[collection.Name] = collection
+	}
+
+	// collections are exported under the alias pointing to them, the rest under their own name
+	var exported []*tsv1alpha1.TypesenseCollection
+	aliased := map[string]bool{}
+	for _, alias := range aliases.Aliases {
+		collection, ok := byName[alias.CollectionName]
+		if !ok {
+			state.Notes = append(state.Notes, fmt.Sprintf("alias %s points to missing collection %s, skipped", alias.Name, alias.CollectionName))
+			continue
+		}
+
+		if aliased[collection.Name] {
+			state.Notes = append(state.Notes, fmt.Sprintf("alias %s points to collection %s which is already exported under another alias, skipped", alias.Name, collection.Name))
+			continue
+		}
+		aliased[collection.Name] = true
+
+		revision := getPhysicalCollectionRevision(alias.Name, collection.Name)
+		if revision == 0 {
+			state.Notes = append(state.Notes, fmt.Sprintf("collection %s behind alias %s does not follow the <alias>_v<revision> naming, it is adopted as revision 1", collection.Name, alias.Name))
+		}
+
+		exported = append(exported, exportCollection(ts, alias.Name, max(revision, 1), collection))
+	}
+
+	for _, collection := range collections {
+		if !aliased[collection.Name] {
+			exported = append(exported, exportCollection(ts, collection.Name, 1, collection))
+		}
+	}
+
+	// overrides and synonyms are read through the name the collection is exported with
+	for _, col := range exported {
+		state.Objects = append(state.Objects, col)
+		name := getCollectionAlias(col)
+
+		curation, notes, err := exportCuration(ctx, tc, ts, name)
+		if err != nil {
+			return nil, err
+		}
+		state.Notes = append(state.Notes, notes...)
+		if curation != nil {
+			state.Objects = append(state.Objects, curation)
+		}
+
+		var synonyms typesenseSynonymList
+		if err := tc.get(ctx, fmt.Sprintf("/collections/%s/synonyms", url.PathEscape(name)), &synonyms); err != nil {
+			return nil, err
+		}
+		if len(synonyms.Synonyms) > 0 {
+			state.Notes = append(state.Notes, fmt.Sprintf("collection %s has %d synonyms, synonyms are not managed by the operator", name, len(synonyms.Synonyms)))
+		}
+	}
+
+	presets, err := tc.listPresets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(presets, func(i, j int) bool { return presets[i].Name < presets[j].Name })
+
+	for _, preset := range presets {
+		state.Objects = append(state.Objects, exportPreset(ts, preset))
+	}
+
+	var stopwords typesenseStopwordsList
+	if err := tc.get(ctx, "/stopwords", &stopwords); err != nil {
+		return nil, err
+	}
+	sort.Slice(stopwords.Stopwords, func(i, j int) bool { return stopwords.Stopwords[i].ID < stopwords.Stopwords[j].ID })
+
+	for _, set := range stopwords.Stopwords {
+		// stopwords sets are named after their resource, so their id has to be a valid resource name
+		if errs := validation.IsDNS1123Subdomain(set.ID); len(errs) > 0 {
+			state.Notes = append(state.Notes, fmt.Sprintf("stopwords set %s is not a valid resource name, skipped", set.ID))
+			continue
+		}
+
+		stopwordsSet := &tsv1alpha1.TypesenseStopwords{
+			ObjectMeta: newExportObjectMeta(ts, set.ID),
+			Spec: tsv1alpha1.TypesenseStopwordsSpec{
+				ClusterRef: v1.LocalObjectReference{Name: ts.Name},
+				Stopwords:  set.Stopwords,
+			},
+		}
+		if set.Locale != "" {
+			stopwordsSet.Spec.Locale = ptr.To(set.Locale)
+		}
+
+		state.Objects = append(state.Objects, stopwordsSet)
+	}
+
+	var keys typesenseApiKeyList
+	if err := tc.get(ctx, "/keys", &keys); err != nil {
+		return nil, err
+	}
+
+	for _, key := range keys.Keys {
+		note := fmt.Sprintf("api key %d (%s...) %q actions=%s collections=%s", key.ID, key.ValuePrefix, key.Description, strings.Join(key.Actions, ","), strings.Join(key.Collections, ","))
+		if key.ExpiresAt > 0 && key.ExpiresAt != typesenseKeyNeverExpires {
+			note += " expires_at=" + time.Unix(key.ExpiresAt, 0).UTC().Format(time.RFC3339)
+		}
+		state.Notes = append(state.Notes, note+", api keys are not managed by the operator")
+	}
+
+	if err := setExportTypeMeta(state.Objects); err != nil {
+		return nil, err
+	}
+
+	return state, nil
+}
+
+func exportCollection(ts *tsv1alpha1.TypesenseCluster, name string, revision int, collection typesenseCollection) *tsv1alpha1.TypesenseCollection {
+	col := &tsv1alpha1.TypesenseCollection{
+		ObjectMeta: newExportObjectMeta(ts, name),
+		Spec: tsv1alpha1.TypesenseCollectionSpec{
+			ClusterRef:         v1.LocalObjectReference{Name: ts.Name},
+			Revision:           revision,
+			TokenSeparators:    collection.TokenSeparators,
+			SymbolsToIndex:     collection.SymbolsToIndex,
+			EnableNestedFields: collection.EnableNestedFields,
+			DeletionPolicy:     CollectionDeletionPolicyRetain,
+		},
+	}
+
+	if col.Name != name {
+		col.Spec.Name = ptr.To(name)
+	}
+
+	if collection.DefaultSortingField != "" {
+		col.Spec.DefaultSortingField = ptr.To(collection.DefaultSortingField)
+	}
+
+	for _, field := range collection.Fields {
+		col.Spec.Fields = append(col.Spec.Fields, exportCollectionField(field))
+	}
+
+	return col
+}
+
+// exportCollectionField keeps only the attributes that differ from the defaults of Typesense,
+// attributes left out are not compared when the schema is reconciled.
+func exportCollectionField(field typesenseCollectionField) tsv1alpha1.CollectionFieldSpec {
+	spec := tsv1alpha1.CollectionFieldSpec{
+		Name: field.Name,
+		Type: field.Type,
+	}
+
+	ifTrue := func(b *bool) *bool {
+		if ptr.Deref(b, false) {
+			return ptr.To(true)
+		}
+		return nil
+	}
+	ifNotEmpty := func(s string) *string {
+		if s != "" {
+			return ptr.To(s)
+		}
+		return nil
+	}
+
+	spec.Facet = ifTrue(field.Facet)
+	spec.Optional = ifTrue(field.Optional)
+	spec.Infix = ifTrue(field.Infix)
+	spec.Stem = ifTrue(field.Stem)
+	spec.Locale = ifNotEmpty(field.Locale)
+	spec.StemDictionary = ifNotEmpty(field.StemDictionary)
+	spec.Reference = ifNotEmpty(field.Reference)
+	spec.NumDim = field.NumDim
+
+	if field.Index != nil && !*field.Index {
+		spec.Index = ptr.To(false)
+	}
+
+	// numbers and booleans are sortable by default, strings are not
+	sortable := strings.HasPrefix(field.Type, "int") || strings.HasPrefix(field.Type, "float") || strings.HasPrefix(field.Type, "bool")
+	if field.Sort != nil && *field.Sort != sortable && !strings.HasSuffix(field.Type, "[]") {
+		spec.Sort = field.Sort
+	}
+
+	return spec
+}
+
+func exportCuration(ctx context.Context, tc *typesenseClient, ts *tsv1alpha1.TypesenseCluster, collection string) (*tsv1alpha1.TypesenseCuration, []string, error) {
+	var overrides typesenseOverrideList
+	if err := tc.get(ctx, fmt.Sprintf("/collections/%s/overrides", url.PathEscape(collection)), &overrides); err != nil {
+		return nil, nil, err
+	}
+
+	if len(overrides.Overrides) == 0 {
+		return nil, nil, nil
+	}
+
+	curation := &tsv1alpha1.TypesenseCuration{
+		ObjectMeta: newExportObjectMeta(ts, collection+"-curation"),
+		Spec: tsv1alpha1.TypesenseCurationSpec{
+			ClusterRef: v1.LocalObjectReference{Name: ts.Name},
+			Collection: collection,
+		},
+	}
+
+	var notes []string
+	for _, override := range overrides.Overrides {
+		if !validCurationRuleName.MatchString(override.ID) {
+			notes = append(notes, fmt.Sprintf("override %s of collection %s is not a valid rule name, skipped", override.ID, collection))
+			continue
+		}
+
+		rule := tsv1alpha1.CurationRuleSpec{
+			Name:                override.ID,
+			FilterBy:            override.FilterBy,
+			SortBy:              override.SortBy,
+			ReplaceQuery:        override.ReplaceQuery,
+			RemoveMatchedTokens: override.RemoveMatchedTokens,
+			FilterCuratedHits:   override.FilterCuratedHits,
+			StopProcessing:      override.StopProcessing,
+			Rule: tsv1alpha1.CurationRuleMatchSpec{
+				Tags: override.Rule.Tags,
+			},
+		}
+
+		if override.Rule.Query != "" {
+			rule.Rule.Query = ptr.To(override.Rule.Query)
+		}
+		if override.Rule.Match != "" {
+			rule.Rule.Match = ptr.To(override.Rule.Match)
+		}
+		if override.Rule.FilterBy != "" {
+			rule.Rule.FilterBy = ptr.To(override.Rule.FilterBy)
+		}
+
+		for _, include := range override.Includes {
+			rule.Includes = append(rule.Includes, tsv1alpha1.CurationIncludeSpec{ID: include.ID, Position: include.Position})
+		}
+		for _, exclude := range override.Excludes {
+			rule.Excludes = append(rule.Excludes, tsv1alpha1.CurationExcludeSpec{ID: exclude.ID})
+		}
+
+		if override.EffectiveFromTs != nil {
+			rule.EffectiveFrom = &metav1.Time{Time: time.Unix(*override.EffectiveFromTs, 0).UTC()}
+		}
+		if override.EffectiveToTs != nil {
+			rule.EffectiveTo = &metav1.Time{Time: time.Unix(*override.EffectiveToTs, 0).UTC()}
+		}
+
+		curation.Spec.Rules = append(curation.Spec.Rules, rule)
+	}
+
+	if len(curation.Spec.Rules) == 0 {
+		return nil, notes, nil
+	}
+
+	return curation, notes, nil
+}
+
+func exportPreset(ts *tsv1alpha1.TypesenseCluster, preset typesensePreset) *tsv1alpha1.TypesensePreset {
+	p := &tsv1alpha1.TypesensePreset{
+		ObjectMeta: newExportObjectMeta(ts, preset.Name),
+		Spec: tsv1alpha1.TypesensePresetSpec{
+			ClusterRef: v1.LocalObjectReference{Name: ts.Name},
+		},
+	}
+
+	if p.Name != preset.Name {
+		p.Spec.Name = ptr.To(preset.Name)
+	}
+
+	toSearch := func(value map[string]any) tsv1alpha1.PresetSearchSpec {
+		search := tsv1alpha1.PresetSearchSpec{Parameters: map[string]string{}}
+		for k, v := range value {
+			if k == "collection" {
+				search.Collection = ptr.To(fmt.Sprint(v))
+				continue
+			}
+
+			if s, ok := v.(string); ok {
+				search.Parameters[k] = s
+			} else if b, err := json.Marshal(v); err == nil {
+				search.Parameters[k] = string(b)
+			}
+		}
+		return search
+	}
+
+	if _, ok := preset.Value["searches"]; ok {
+		for _, search := range getPresetSearches(preset) {
+			p.Spec.MultiSearch = append(p.Spec.MultiSearch, toSearch(search))
+		}
+	} else {
+		p.Spec.Search = ptr.To(toSearch(preset.Value))
+	}
+
+	return p
+}
+
+// newExportObjectMeta derives a valid resource name from the name of a server object.
+func newExportObjectMeta(ts *tsv1alpha1.TypesenseCluster, name string) metav1.ObjectMeta {
+	resourceName := strings.Trim(invalidResourceNameChars.ReplaceAllString(strings.ToLower(name), "-"), "-")
+	if len(resourceName) > validation.DNS1123SubdomainMaxLength {
+		resourceName = strings.Trim(resourceName[:validation.DNS1123SubdomainMaxLength], "-")
+	}
+
+	return metav1.ObjectMeta{
+		Name:      resourceName,
+		Namespace: ts.Namespace,
+	}
+}
+
+func setExportTypeMeta(objects []client.Object) error {
+	for _, obj := range objects {
+		gvks, _, err := exportScheme.ObjectKinds(obj)
+		if err != nil {
+			return err
+		}
+		obj.GetObjectKind().SetGroupVersionKind(gvks[0])
+	}
+
+	return nil
+}
+
+var exportScheme = func() *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	_ = tsv1alpha1.AddToScheme(scheme)
+	return scheme
+}()
+
+// WriteManifests writes the notes as comments, followed by every resource as a YAML document.
+func (s *ClusterState) WriteManifests(w io.Writer) error {
+	var buf bytes.Buffer
+	for _, note := range s.Notes {
+		fmt.Fprintf(&buf, "# %s\n", note)
+	}
+
+	for _, obj := range s.Objects {
+		manifest, err := MarshalManifest(obj)
+		if err != nil {
+			return err
+		}
+
+		buf.WriteString("---\n")
+		buf.Write(manifest)
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// MarshalManifest converts a resource to YAML, leaving out its status and the metadata set by the api server.
+func MarshalManifest(obj client.Object) ([]byte, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	delete(content, "status")
+	if metadata, ok := content["metadata"].(map[string]any); ok {
+		delete(metadata, "creationTimestamp")
+	}
+
+	return yaml.Marshal(content)
+}
+
+// GetManifestFileName returns a file name that is unique for every exported resource.
+func GetManifestFileName(obj client.Object) string {
+	return fmt.Sprintf("%s_%s.yaml", strings.ToLower(obj.GetObjectKind().GroupVersionKind().Kind), obj.GetName())
+}
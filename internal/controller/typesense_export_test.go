@@ -0,0 +1,144 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	tsv1alpha1 "github.com/akyriako/typesense-operator/api/v1alpha1"
+)
+
+var _ = Describe("TypesenseCluster Export", func() {
+	Context("When exporting the state of a cluster", func() {
+		ctx := context.Background()
+
+		var server *httptest.Server
+
+		ts := &tsv1alpha1.TypesenseCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-1", Namespace: "search"},
+		}
+
+		responses := map[string]string{
+			"/collections": `[
+				{"name": "products_v2", "default_sorting_field": "price", "fields": [
+					{"name": "title", "type": "string", "facet": false, "index": true, "optional": false, "sort": false, "infix": false, "locale": ""},
+					{"name": "price", "type": "float", "facet": true, "index": true, "optional": false, "sort": true},
+					{"name": "secret", "type": "string", "index": false, "optional": true}
+				]},
+				{"name": "Brands", "fields": [{"name": "name", "type": "string", "sort": true}]}
+			]`,
+			"/aliases":                        `{"aliases": [{"name": "products", "collection_name": "products_v2"}, {"name": "stale", "collection_name": "gone"}]}`,
+			"/collections/products/overrides": `{"overrides": [{"id": "pin-shoes", "rule": {"query": "shoes", "match": "exact"}, "includes": [{"id": "42", "position": 1}], "effective_from_ts": 1700000000}]}`,
+			"/collections/products/synonyms":  `{"synonyms": [{"id": "sneakers", "synonyms": ["sneakers", "trainers"]}]}`,
+			"/collections/Brands/overrides":   `{"overrides": []}`,
+			"/collections/Brands/synonyms":    `{"synonyms": []}`,
+			"/presets":                        `{"presets": [{"name": "listing_view", "value": {"searches": [{"collection": "products", "query_by": "title", "per_page": 10}]}}]}`,
+			"/stopwords":                      `{"stopwords": [{"id": "common-words", "stopwords": ["the", "a"], "locale": "en"}, {"id": "Bad_Name", "stopwords": ["x"]}]}`,
+			"/keys":                           `{"keys": [{"id": 1, "description": "frontend", "actions": ["documents:search"], "collections": ["products"], "expires_at": 64723363199, "value_prefix": "Abcd"}]}`,
+		}
+
+		BeforeEach(func() {
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				response, ok := responses[req.URL.Path]
+				if !ok {
+					w.WriteHeader(http.StatusNotFound)
+					_, _ = w.Write([]byte(`{"message": "Not Found"}`))
+					return
+				}
+				_, _ = w.Write([]byte(response))
+			}))
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		It("should convert server objects to resources that adopt them", func() {
+			state, err := exportClusterState(ctx, newTypesenseClient(server.URL, "admin"), ts)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(state.Objects).To(HaveLen(5))
+
+			products := state.Objects[0].(*tsv1alpha1.TypesenseCollection)
+			Expect(products.Name).To(Equal("products"))
+			Expect(products.Namespace).To(Equal("search"))
+			Expect(products.Spec.Name).To(BeNil())
+			Expect(products.Spec.Revision).To(Equal(2))
+			Expect(products.Spec.DeletionPolicy).To(Equal(CollectionDeletionPolicyRetain))
+			Expect(products.Spec.DefaultSortingField).To(Equal(ptr.To("price")))
+			Expect(products.Spec.Fields).To(Equal([]tsv1alpha1.CollectionFieldSpec{
+				{Name: "title", Type: "string"},
+				{Name: "price", Type: "float", Facet: ptr.To(true)},
+				{Name: "secret", Type: "string", Optional: ptr.To(true), Index: ptr.To(false)},
+			}))
+			Expect(products.Kind).To(Equal("TypesenseCollection"))
+
+			curation := state.Objects[1].(*tsv1alpha1.TypesenseCuration)
+			Expect(curation.Name).To(Equal("products-curation"))
+			Expect(curation.Spec.Collection).To(Equal("products"))
+			Expect(curation.Spec.Rules).To(HaveLen(1))
+			Expect(curation.Spec.Rules[0].Includes).To(Equal([]tsv1alpha1.CurationIncludeSpec{{ID: "42", Position: 1}}))
+			Expect(curation.Spec.Rules[0].EffectiveFrom.Unix()).To(Equal(int64(1700000000)))
+
+			brands := state.Objects[2].(*tsv1alpha1.TypesenseCollection)
+			Expect(brands.Name).To(Equal("brands"))
+			Expect(brands.Spec.Name).To(Equal(ptr.To("Brands")))
+			Expect(brands.Spec.Revision).To(Equal(1))
+			Expect(brands.Spec.Fields[0].Sort).To(Equal(ptr.To(true)))
+
+			preset := state.Objects[3].(*tsv1alpha1.TypesensePreset)
+			Expect(preset.Name).To(Equal("listing-view"))
+			Expect(preset.Spec.Name).To(Equal(ptr.To("listing_view")))
+			Expect(preset.Spec.MultiSearch).To(Equal([]tsv1alpha1.PresetSearchSpec{
+				{Collection: ptr.To("products"), Parameters: map[string]string{"query_by": "title", "per_page": "10"}},
+			}))
+
+			stopwords := state.Objects[4].(*tsv1alpha1.TypesenseStopwords)
+			Expect(stopwords.Name).To(Equal("common-words"))
+			Expect(stopwords.Spec.Locale).To(Equal(ptr.To("en")))
+
+			Expect(state.Notes).To(ConsistOf(
+				ContainSubstring("alias stale points to missing collection gone"),
+				ContainSubstring("collection products has 1 synonyms"),
+				ContainSubstring("stopwords set Bad_Name is not a valid resource name"),
+				And(ContainSubstring("api key 1 (Abcd...)"), Not(ContainSubstring("expires_at"))),
+			))
+		})
+
+		It("should write manifests without status and server metadata", func() {
+			state, err := exportClusterState(ctx, newTypesenseClient(server.URL, "admin"), ts)
+			Expect(err).NotTo(HaveOccurred())
+
+			var buf bytes.Buffer
+			Expect(state.WriteManifests(&buf)).To(Succeed())
+
+			manifests := buf.String()
+			Expect(manifests).To(HavePrefix("# alias stale points to missing collection gone"))
+			Expect(manifests).To(ContainSubstring("---\napiVersion: ts.opentelekomcloud.com/v1alpha1\nkind: TypesenseCollection\n"))
+			Expect(manifests).NotTo(ContainSubstring("status:"))
+			Expect(manifests).NotTo(ContainSubstring("creationTimestamp"))
+			Expect(GetManifestFileName(state.Objects[0])).To(Equal("typesensecollection_products.yaml"))
+		})
+	})
+})
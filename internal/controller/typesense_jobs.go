@@ -0,0 +1,118 @@
+package controller
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// jobRun is the outcome of a finished Job spawned by the CronJob of a data-plane resource.
+type jobRun struct {
+	Name           string
+	StartTime      *metav1.Time
+	CompletionTime *metav1.Time
+	Succeeded      bool
+	// Message is the reason given by the Job when it failed
+	Message string
+	// TerminationMessage is what the container wrote to its termination log,
+	// or the tail of its logs when it failed without writing one
+	TerminationMessage string
+}
+
+// listFinishedJobRuns returns the runs of the Jobs carrying the given labels that started after since, oldest first.
+// Jobs that are still running are left out, they are picked up once they finish.
+func listFinishedJobRuns(ctx context.Context, c client.Client, namespace string, labels map[string]string, since *metav1.Time) ([]jobRun, error) {
+	var jobs batchv1.JobList
+	if err := c.List(ctx, &jobs, client.InNamespace(namespace), client.MatchingLabels(labels)); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(jobs.Items, func(i, j int) bool {
+		return jobs.Items[i].CreationTimestamp.Before(&jobs.Items[j].CreationTimestamp)
+	})
+
+	var runs []jobRun
+	for _, job := range jobs.Items {
+		if since != nil && !since.Before(getJobStartTime(&job)) {
+			continue
+		}
+
+		var pods corev1.PodList
+		if err := c.List(ctx, &pods, client.InNamespace(job.Namespace), client.MatchingLabels{batchv1.JobNameLabel: job.Name}); err != nil {
+			return nil, err
+		}
+
+		if run, finished := getJobRun(&job, pods.Items); finished {
+			runs = append(runs, run)
+		}
+	}
+
+	return runs, nil
+}
+
+func getJobRun(job *batchv1.Job, pods []corev1.Pod) (jobRun, bool) {
+	run := jobRun{
+		Name:      job.Name,
+		StartTime: getJobStartTime(job),
+	}
+
+	finished := false
+	for _, condition := range job.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+
+		switch condition.Type {
+		case batchv1.JobComplete:
+			finished, run.Succeeded = true, true
+			run.CompletionTime = job.Status.CompletionTime
+		case batchv1.JobFailed:
+			finished = true
+			run.CompletionTime = ptr.To(condition.LastTransitionTime)
+			run.Message = condition.Message
+		}
+	}
+
+	if !finished {
+		return run, false
+	}
+
+	for _, pod := range pods {
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.State.Terminated != nil && status.State.Terminated.Message != "" {
+				run.TerminationMessage = strings.TrimSpace(status.State.Terminated.Message)
+			}
+		}
+	}
+
+	return run, true
+}
+
+func getJobStartTime(job *batchv1.Job) *metav1.Time {
+	if job.Status.StartTime != nil {
+		return job.Status.StartTime
+	}
+
+	return job.CreationTimestamp.DeepCopy()
+}
+
+// enqueueJobOwner maps an event of a Job to the data-plane resource named in its label; Jobs spawned by a CronJob
+// are owned by the CronJob, so they cannot be matched to the resource through their owner references.
+func enqueueJobOwner(label string) handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, job client.Object) []reconcile.Request {
+		name, ok := job.GetLabels()[label]
+		if !ok {
+			return nil
+		}
+
+		return []reconcile.Request{{NamespacedName: client.ObjectKey{Namespace: job.GetNamespace(), Name: name}}}
+	})
+}
@@ -0,0 +1,315 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"net/url"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	tsv1alpha1 "github.com/akyriako/typesense-operator/api/v1alpha1"
+)
+
+const (
+	ConditionReasonAnalyticsDisabled = "AnalyticsDisabled"
+
+	AnalyticsRuleHealthy  = "Healthy"
+	AnalyticsRuleDegraded = "Degraded"
+	AnalyticsRuleUnknown  = "Unknown"
+)
+
+// TypesenseAnalyticsRuleReconciler reconciles a TypesenseAnalyticsRule object
+type TypesenseAnalyticsRuleReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	logger   logr.Logger
+	Recorder record.EventRecorder
+}
+
+type typesenseAnalyticsEvent struct {
+	Type   string `json:"type"`
+	Name   string `json:"name"`
+	Weight *int   `json:"weight,omitempty"`
+}
+
+type typesenseAnalyticsSource struct {
+	Collections []string                  `json:"collections"`
+	Events      []typesenseAnalyticsEvent `json:"events,omitempty"`
+}
+
+type typesenseAnalyticsDestination struct {
+	Collection   string  `json:"collection"`
+	CounterField *string `json:"counter_field,omitempty"`
+}
+
+type typesenseAnalyticsParams struct {
+	Source      typesenseAnalyticsSource       `json:"source"`
+	Destination *typesenseAnalyticsDestination `json:"destination,omitempty"`
+	Limit       *int                           `json:"limit,omitempty"`
+	ExpandQuery *bool                          `json:"expand_query,omitempty"`
+}
+
+type typesenseAnalyticsRule struct {
+	Name   string                   `json:"name"`
+	Type   string                   `json:"type"`
+	Params typesenseAnalyticsParams `json:"params"`
+}
+
+// +kubebuilder:rbac:groups=ts.opentelekomcloud.com,resources=typesenseanalyticsrules,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=ts.opentelekomcloud.com,resources=typesenseanalyticsrules/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=ts.opentelekomcloud.com,resources=typesenseanalyticsrules/finalizers,verbs=update
+
+// Reconcile upserts the analytics rule once the cluster runs with search analytics enabled
+// and its source and destination collections exist.
+func (r *TypesenseAnalyticsRuleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	r.logger = log.Log.WithValues("namespace", req.Namespace, "analyticsrule", req.Name)
+	r.logger.Info("reconciling analytics rule")
+
+	var rule tsv1alpha1.TypesenseAnalyticsRule
+	if err := r.Get(ctx, req.NamespacedName, &rule); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	ts, tc, reason, err := getTargetCluster(ctx, r.Client, rule.Namespace, rule.Spec.ClusterRef)
+	if err != nil {
+		if !rule.DeletionTimestamp.IsZero() && reason == ConditionReasonClusterNotFound {
+			return ctrl.Result{}, removeDataPlaneFinalizer(ctx, r.Client, &rule)
+		}
+
+		r.logger.Error(err, "resolving target cluster failed")
+		return ctrl.Result{RequeueAfter: dataPlaneRequeueAfter}, r.setRuleCondition(ctx, &rule, AnalyticsRuleUnknown, reason, err)
+	}
+
+	if !rule.DeletionTimestamp.IsZero() {
+		if controllerutil.ContainsFinalizer(&rule, dataPlaneFinalizer) {
+			name := rule.Status.RuleName
+			if name == "" {
+				name = r.getRuleName(&rule)
+			}
+
+			if err := tc.delete(ctx, r.getRulePath(name)); err != nil && !isTypesenseNotFound(err) {
+				r.logger.Error(err, "deleting analytics rule failed")
+				return ctrl.Result{}, err
+			}
+		}
+
+		return ctrl.Result{}, removeDataPlaneFinalizer(ctx, r.Client, &rule)
+	}
+
+	if err := addDataPlaneFinalizer(ctx, r.Client, &rule); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// the cluster controller enables analytics as soon as it sees this rule, wait for the rollout restarting the nodes
+	sts := &appsv1.StatefulSet{}
+	if err := r.Get(ctx, client.ObjectKey{Namespace: ts.Namespace, Name: fmt.Sprintf(ClusterStatefulSet, ts.Name)}, sts); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if !isAnalyticsEnabled(sts) {
+		err := fmt.Errorf("search analytics are not enabled yet on typesense cluster %s", ts.Name)
+		r.logger.V(debugLevel).Info(err.Error())
+
+		return ctrl.Result{RequeueAfter: dataPlaneRequeueAfter}, r.setRuleCondition(ctx, &rule, AnalyticsRuleUnknown, ConditionReasonAnalyticsDisabled, err)
+	}
+
+	if err := validateAnalyticsRule(&rule); err != nil {
+		r.Recorder.Event(&rule, "Warning", ConditionReasonInvalidSpec, err.Error())
+		return ctrl.Result{}, r.setRuleCondition(ctx, &rule, AnalyticsRuleDegraded, ConditionReasonInvalidSpec, err)
+	}
+
+	if reason, err := r.checkRuleCollections(ctx, tc, &rule); err != nil {
+		r.logger.Error(err, "validating analytics rule collections failed")
+		r.Recorder.Event(&rule, "Warning", reason, err.Error())
+
+		return ctrl.Result{RequeueAfter: dataPlaneRequeueAfter}, r.setRuleCondition(ctx, &rule, AnalyticsRuleDegraded, reason, err)
+	}
+
+	name := r.getRuleName(&rule)
+	desired := buildTypesenseAnalyticsRule(name, &rule)
+
+	if rule.Status.RuleName != "" && rule.Status.RuleName != name {
+		r.logger.V(debugLevel).Info("deleting renamed analytics rule", "rule", rule.Status.RuleName)
+		if err := tc.delete(ctx, r.getRulePath(rule.Status.RuleName)); err != nil && !isTypesenseNotFound(err) {
+			return ctrl.Result{RequeueAfter: dataPlaneRequeueAfter}, r.setRuleCondition(ctx, &rule, AnalyticsRuleDegraded, ConditionReasonSyncFailed, err)
+		}
+	}
+
+	diff, err := r.diffRule(ctx, tc, desired)
+	if err != nil {
+		r.logger.Error(err, "reading analytics rule failed")
+		return ctrl.Result{RequeueAfter: dataPlaneRequeueAfter}, r.setRuleCondition(ctx, &rule, AnalyticsRuleDegraded, ConditionReasonSyncFailed, err)
+	}
+
+	drifted, apply := evaluateDrift(rule.Spec.DriftDetection, rule.Status.Conditions, rule.Generation, diff)
+	if drifted {
+		r.logger.Info("analytics rule drifted from the spec", "differences", len(diff), "reconcile", apply)
+		recordDriftEvent(r.Recorder, &rule, apply, diff)
+	}
+
+	if apply {
+		r.logger.V(debugLevel).Info("upserting analytics rule", "rule", name)
+		if err := tc.put(ctx, r.getRulePath(name), desired, nil); err != nil {
+			r.logger.Error(err, "upserting analytics rule failed")
+			r.Recorder.Event(&rule, "Warning", ConditionReasonSyncFailed, err.Error())
+
+			return ctrl.Result{RequeueAfter: dataPlaneRequeueAfter}, r.setRuleCondition(ctx, &rule, AnalyticsRuleDegraded, ConditionReasonSyncFailed, err)
+		}
+	}
+
+	err = patchObjectStatus(ctx, r.Client, &rule, func() {
+		setDataPlaneCondition(&rule.Status.Conditions, rule.Generation, ConditionReasonSynced, nil)
+		setDriftCondition(&rule.Status.Conditions, rule.Generation, rule.Spec.DriftDetection, drifted, apply, diff)
+		rule.Status.RuleName = name
+		rule.Status.Health = AnalyticsRuleHealthy
+		rule.Status.LastSyncTime = ptr.To(metav1.Now())
+	})
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	r.logger.Info("reconciling analytics rule completed", "rule", name)
+
+	// collections may be dropped behind our back, so health is re-evaluated periodically
+	return ctrl.Result{RequeueAfter: dataPlaneRequeueAfter}, nil
+}
+
+func (r *TypesenseAnalyticsRuleReconciler) diffRule(ctx context.Context, tc *typesenseClient, desired typesenseAnalyticsRule) ([]string, error) {
+	live := map[string]any{}
+
+	current := typesenseAnalyticsRule{}
+	if err := tc.get(ctx, r.getRulePath(desired.Name), &current); err != nil {
+		if !isTypesenseNotFound(err) {
+			return nil, err
+		}
+	} else {
+		live[desired.Name] = current
+	}
+
+	return diffServerObjects("analytics rule", map[string]any{desired.Name: desired}, live)
+}
+
+func validateAnalyticsRule(rule *tsv1alpha1.TypesenseAnalyticsRule) error {
+	switch rule.Spec.Type {
+	case "popular_queries", "nohits_queries":
+		if rule.Spec.Destination == nil {
+			return fmt.Errorf("%s rules require a destination collection", rule.Spec.Type)
+		}
+	case "counter":
+		if rule.Spec.Destination == nil || rule.Spec.Destination.CounterField == nil {
+			return fmt.Errorf("counter rules require a destination collection and counter field")
+		}
+		if len(rule.Spec.Source.Events) == 0 {
+			return fmt.Errorf("counter rules require at least one source event")
+		}
+	case "log":
+		if len(rule.Spec.Source.Events) == 0 {
+			return fmt.Errorf("log rules require at least one source event")
+		}
+	}
+
+	return nil
+}
+
+// checkRuleCollections verifies that the source and destination collections of the rule exist.
+func (r *TypesenseAnalyticsRuleReconciler) checkRuleCollections(ctx context.Context, tc *typesenseClient, rule *tsv1alpha1.TypesenseAnalyticsRule) (string, error) {
+	collections := rule.Spec.Source.Collections
+	if rule.Spec.Destination != nil {
+		collections = append(append([]string{}, collections...), rule.Spec.Destination.Collection)
+	}
+
+	for _, name := range collections {
+		collection, err := tc.getCollection(ctx, name)
+		if err != nil {
+			if isTypesenseNotFound(err) {
+				return ConditionReasonCollectionNotFound, fmt.Errorf("collection %s was not found", name)
+			}
+			return ConditionReasonSyncFailed, err
+		}
+
+		if rule.Spec.Destination != nil && rule.Spec.Destination.CounterField != nil && name == rule.Spec.Destination.Collection {
+			if _, ok := collection.getField(*rule.Spec.Destination.CounterField); !ok {
+				return ConditionReasonInvalidSpec, fmt.Errorf("counter field %s does not exist in collection %s", *rule.Spec.Destination.CounterField, name)
+			}
+		}
+	}
+
+	return "", nil
+}
+
+func buildTypesenseAnalyticsRule(name string, rule *tsv1alpha1.TypesenseAnalyticsRule) typesenseAnalyticsRule {
+	ar := typesenseAnalyticsRule{
+		Name: name,
+		Type: rule.Spec.Type,
+		Params: typesenseAnalyticsParams{
+			Source: typesenseAnalyticsSource{
+				Collections: rule.Spec.Source.Collections,
+			},
+			Limit:       rule.Spec.Limit,
+			ExpandQuery: rule.Spec.ExpandQuery,
+		},
+	}
+
+	for _, event := range rule.Spec.Source.Events {
+		ar.Params.Source.Events = append(ar.Params.Source.Events, typesenseAnalyticsEvent{
+			Type:   event.Type,
+			Name:   event.Name,
+			Weight: event.Weight,
+		})
+	}
+
+	if rule.Spec.Destination != nil {
+		ar.Params.Destination = &typesenseAnalyticsDestination{
+			Collection:   rule.Spec.Destination.Collection,
+			CounterField: rule.Spec.Destination.CounterField,
+		}
+	}
+
+	return ar
+}
+
+func (r *TypesenseAnalyticsRuleReconciler) getRuleName(rule *tsv1alpha1.TypesenseAnalyticsRule) string {
+	return ptr.Deref(rule.Spec.Name, rule.Name)
+}
+
+func (r *TypesenseAnalyticsRuleReconciler) getRulePath(name string) string {
+	return fmt.Sprintf("/analytics/rules/%s", url.PathEscape(name))
+}
+
+func (r *TypesenseAnalyticsRuleReconciler) setRuleCondition(ctx context.Context, rule *tsv1alpha1.TypesenseAnalyticsRule, health string, reason string, err error) error {
+	return patchObjectStatus(ctx, r.Client, rule, func() {
+		setDataPlaneCondition(&rule.Status.Conditions, rule.Generation, reason, err)
+		rule.Status.Health = health
+	})
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *TypesenseAnalyticsRuleReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&tsv1alpha1.TypesenseAnalyticsRule{}, eventFilters).
+		Complete(r)
+}
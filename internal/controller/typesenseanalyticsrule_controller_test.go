@@ -0,0 +1,144 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	tsv1alpha1 "github.com/akyriako/typesense-operator/api/v1alpha1"
+)
+
+var _ = Describe("TypesenseAnalyticsRule Controller", func() {
+	Context("When validating an analytics rule", func() {
+		ctx := context.Background()
+
+		var server *httptest.Server
+
+		rule := &tsv1alpha1.TypesenseAnalyticsRule{
+			ObjectMeta: metav1.ObjectMeta{Name: "product-clicks", Namespace: "default"},
+			Spec: tsv1alpha1.TypesenseAnalyticsRuleSpec{
+				ClusterRef: corev1.LocalObjectReference{Name: "cluster-1"},
+				Type:       "counter",
+				Source: tsv1alpha1.AnalyticsRuleSourceSpec{
+					Collections: []string{"products"},
+					Events:      []tsv1alpha1.AnalyticsRuleEventSpec{{Type: "click", Name: "products_click", Weight: ptr.To(1)}},
+				},
+				Destination: &tsv1alpha1.AnalyticsRuleDestinationSpec{
+					Collection:   "products",
+					CounterField: ptr.To("popularity"),
+				},
+			},
+		}
+
+		BeforeEach(func() {
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				if req.URL.Path != "/collections/products" {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+
+				_ = json.NewEncoder(w).Encode(typesenseCollection{
+					Name:   "products",
+					Fields: []typesenseCollectionField{{Name: "popularity", Type: "int32"}},
+				})
+			}))
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		It("should accept a counter rule with existing collections and counter field", func() {
+			reconciler := &TypesenseAnalyticsRuleReconciler{}
+
+			Expect(validateAnalyticsRule(rule)).To(Succeed())
+			_, err := reconciler.checkRuleCollections(ctx, newTypesenseClient(server.URL, "admin"), rule)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should report a missing destination collection", func() {
+			reconciler := &TypesenseAnalyticsRuleReconciler{}
+
+			missing := rule.DeepCopy()
+			missing.Spec.Destination.Collection = "product_clicks"
+
+			reason, err := reconciler.checkRuleCollections(ctx, newTypesenseClient(server.URL, "admin"), missing)
+			Expect(err).To(HaveOccurred())
+			Expect(reason).To(Equal(ConditionReasonCollectionNotFound))
+		})
+
+		It("should require events for counter rules", func() {
+			invalid := rule.DeepCopy()
+			invalid.Spec.Source.Events = nil
+
+			Expect(validateAnalyticsRule(invalid)).NotTo(Succeed())
+		})
+	})
+
+	Context("When waiting for search analytics", func() {
+		statefulSet := func(enabled bool) *appsv1.StatefulSet {
+			return &appsv1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster-1-sts", Namespace: "default", Generation: 2},
+				Spec: appsv1.StatefulSetSpec{
+					Replicas: ptr.To[int32](3),
+					Template: corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{
+								{Name: "typesense", Env: []corev1.EnvVar{{Name: analyticsEnabledEnvVar, Value: strconv.FormatBool(enabled)}}},
+							},
+						},
+					},
+				},
+				Status: appsv1.StatefulSetStatus{
+					ObservedGeneration: 2,
+					Replicas:           3,
+					ReadyReplicas:      3,
+					UpdatedReplicas:    3,
+					CurrentRevision:    "cluster-1-sts-2",
+					UpdateRevision:     "cluster-1-sts-2",
+				},
+			}
+		}
+
+		It("should report analytics enabled once every node restarted with them", func() {
+			Expect(isAnalyticsEnabled(statefulSet(true))).To(BeTrue())
+			Expect(isAnalyticsEnabled(statefulSet(false))).To(BeFalse())
+		})
+
+		It("should not report analytics enabled while the nodes are restarting", func() {
+			sts := statefulSet(true)
+			sts.Status.UpdatedReplicas = 1
+			sts.Status.CurrentRevision = "cluster-1-sts-1"
+			Expect(isAnalyticsEnabled(sts)).To(BeFalse())
+
+			sts = statefulSet(true)
+			sts.Status.ObservedGeneration = 1
+			Expect(isAnalyticsEnabled(sts)).To(BeFalse())
+		})
+	})
+})
@@ -0,0 +1,75 @@
+package controller
+
+import (
+	"context"
+	tsv1alpha1 "github.com/akyriako/typesense-operator/api/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"strconv"
+)
+
+const (
+	analyticsEnabledEnvVar = "TYPESENSE_ENABLE_SEARCH_ANALYTICS"
+	analyticsDir           = "/usr/share/typesense/data/analytics"
+)
+
+// getAnalyticsEnv enables search analytics on the server as soon as at least one
+// TypesenseAnalyticsRule targets the cluster, analytics events are persisted on the data volume.
+func (r *TypesenseClusterReconciler) getAnalyticsEnv(ctx context.Context, ts *tsv1alpha1.TypesenseCluster) ([]corev1.EnvVar, error) {
+	var rules tsv1alpha1.TypesenseAnalyticsRuleList
+	if err := r.List(ctx, &rules, client.InNamespace(ts.Namespace)); err != nil {
+		return nil, err
+	}
+
+	for _, rule := range rules.Items {
+		if rule.Spec.ClusterRef.Name == ts.Name && rule.DeletionTimestamp.IsZero() {
+			r.logger.V(debugLevel).Info("enabling search analytics", "rule", rule.Name)
+
+			return []corev1.EnvVar{
+				{
+					Name:  analyticsEnabledEnvVar,
+					Value: strconv.FormatBool(true),
+				},
+				{
+					Name:  "TYPESENSE_ANALYTICS_DIR",
+					Value: analyticsDir,
+				},
+			}, nil
+		}
+	}
+
+	return []corev1.EnvVar{}, nil
+}
+
+// isAnalyticsEnabled reports whether every node runs with search analytics enabled, that is the StatefulSet enables
+// them and the rollout restarting the nodes with the flag has completed
+func isAnalyticsEnabled(sts *appsv1.StatefulSet) bool {
+	if !isAnalyticsEnvEnabled(sts) {
+		return false
+	}
+
+	replicas := ptr.Deref(sts.Spec.Replicas, 1)
+	return sts.Status.ObservedGeneration >= sts.Generation &&
+		sts.Status.CurrentRevision == sts.Status.UpdateRevision &&
+		sts.Status.UpdatedReplicas == replicas &&
+		sts.Status.ReadyReplicas == replicas
+}
+
+func isAnalyticsEnvEnabled(sts *appsv1.StatefulSet) bool {
+	for _, container := range sts.Spec.Template.Spec.Containers {
+		if container.Name != "typesense" {
+			continue
+		}
+
+		for _, env := range container.Env {
+			if env.Name == analyticsEnabledEnvVar {
+				enabled, _ := strconv.ParseBool(env.Value)
+				return enabled
+			}
+		}
+	}
+
+	return false
+}
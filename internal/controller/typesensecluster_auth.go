@@ -0,0 +1,183 @@
+package controller
+
+import (
+	"fmt"
+	"path"
+
+	tsv1alpha1 "github.com/akyriako/typesense-operator/api/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	reverseProxyAuthPort                = 8089
+	reverseProxyAuthProbePort           = 8090
+	reverseProxyAuthParentKeyVolumeName = "typesense-auth-parent-key"
+	reverseProxyAuthParentKeyMountPath  = "/etc/typesense-auth/parent-key"
+	reverseProxyAuthParentKeyFile       = "key"
+	reverseProxyAuthJWKSVolumeName      = "typesense-auth-jwks"
+	reverseProxyAuthJWKSMountPath       = "/etc/typesense-auth/jwks"
+	reverseProxyAuthJWKSFile            = "jwks.json"
+	reverseProxyAuthBinary              = "/auth"
+)
+
+// getReverseProxyAuthVolumes returns the Secrets of the parent key and of the JSON Web Key Set, they are mounted
+// as directories so that the sidecar picks up their rotation
+func getReverseProxyAuthVolumes(ts *tsv1alpha1.TypesenseCluster) []v1.Volume {
+	if ts.Spec.Ingress == nil || ts.Spec.Ingress.Auth == nil {
+		return nil
+	}
+
+	auth := ts.Spec.Ingress.Auth
+	volumes := []v1.Volume{
+		{
+			Name: reverseProxyAuthParentKeyVolumeName,
+			VolumeSource: v1.VolumeSource{
+				Secret: &v1.SecretVolumeSource{
+					SecretName: auth.ParentKeySecretRef.Name,
+					Items:      []v1.KeyToPath{{Key: auth.ParentKeySecretRef.Key, Path: reverseProxyAuthParentKeyFile}},
+				},
+			},
+		},
+	}
+
+	if jwks := auth.JWKS.SecretKeyRef; jwks != nil && auth.JWKS.URL == nil {
+		volumes = append(volumes, v1.Volume{
+			Name: reverseProxyAuthJWKSVolumeName,
+			VolumeSource: v1.VolumeSource{
+				Secret: &v1.SecretVolumeSource{
+					SecretName: jwks.Name,
+					Items:      []v1.KeyToPath{{Key: jwks.Key, Path: reverseProxyAuthJWKSFile}},
+				},
+			},
+		})
+	}
+
+	return volumes
+}
+
+func getReverseProxyAuthVolumeMounts(ts *tsv1alpha1.TypesenseCluster) []v1.VolumeMount {
+	mounts := []v1.VolumeMount{
+		{
+			Name:      reverseProxyAuthParentKeyVolumeName,
+			MountPath: reverseProxyAuthParentKeyMountPath,
+			ReadOnly:  true,
+		},
+	}
+
+	if jwks := ts.Spec.Ingress.Auth.JWKS; jwks.SecretKeyRef != nil && jwks.URL == nil {
+		mounts = append(mounts, v1.VolumeMount{
+			Name:      reverseProxyAuthJWKSVolumeName,
+			MountPath: reverseProxyAuthJWKSMountPath,
+			ReadOnly:  true,
+		})
+	}
+
+	return mounts
+}
+
+// getReverseProxyAuthArgs translates spec.ingress.auth to the flags of the auth binary
+func getReverseProxyAuthArgs(ts *tsv1alpha1.TypesenseCluster) []string {
+	auth := ts.Spec.Ingress.Auth
+
+	args := []string{
+		fmt.Sprintf("--listen-address=127.0.0.1:%d", reverseProxyAuthPort),
+		fmt.Sprintf("--health-probe-bind-address=:%d", reverseProxyAuthProbePort),
+		fmt.Sprintf("--parent-key-file=%s", path.Join(reverseProxyAuthParentKeyMountPath, reverseProxyAuthParentKeyFile)),
+		fmt.Sprintf("--jwks-refresh-interval=%s", auth.JWKS.GetRefreshInterval()),
+	}
+
+	if auth.JWKS.URL != nil {
+		args = append(args, fmt.Sprintf("--jwks-url=%s", *auth.JWKS.URL))
+	} else {
+		args = append(args, fmt.Sprintf("--jwks-file=%s", path.Join(reverseProxyAuthJWKSMountPath, reverseProxyAuthJWKSFile)))
+	}
+
+	if auth.Issuer != nil {
+		args = append(args, fmt.Sprintf("--issuer=%s", *auth.Issuer))
+	}
+	for _, audience := range auth.Audiences {
+		args = append(args, fmt.Sprintf("--audience=%s", audience))
+	}
+	for _, claim := range auth.Claims {
+		args = append(args, fmt.Sprintf("--claim=%s=%s", claim.Claim, claim.Field))
+	}
+	if auth.FilterBy != nil {
+		args = append(args, fmt.Sprintf("--filter-by=%s", *auth.FilterBy))
+	}
+	if auth.LimitMultiSearches != nil {
+		args = append(args, fmt.Sprintf("--limit-multi-searches=%d", *auth.LimitMultiSearches))
+	}
+
+	return args
+}
+
+// getReverseProxyAuthContainer returns the sidecar verifying the bearer tokens for nginx, or nil without spec.ingress.auth;
+// it answers nginx on the loopback interface only, the scoped keys never leave the pod
+func (r *TypesenseClusterReconciler) getReverseProxyAuthContainer(ts *tsv1alpha1.TypesenseCluster) *v1.Container {
+	if ts.Spec.Ingress == nil || ts.Spec.Ingress.Auth == nil {
+		return nil
+	}
+
+	auth := ts.Spec.Ingress.Auth
+	image := auth.Image
+	if image == "" {
+		image = r.AuthImage
+	}
+
+	return &v1.Container{
+		Name:            fmt.Sprintf(ClusterReverseProxyAuth, ts.Name),
+		Image:           image,
+		Command:         []string{reverseProxyAuthBinary},
+		Args:            getReverseProxyAuthArgs(ts),
+		Resources:       auth.GetResources(),
+		SecurityContext: auth.GetSecurityContext(),
+		VolumeMounts:    getReverseProxyAuthVolumeMounts(ts),
+		ReadinessProbe: withProbeDefaults(&v1.Probe{
+			ProbeHandler: v1.ProbeHandler{
+				HTTPGet: &v1.HTTPGetAction{
+					Path: "/healthz",
+					Port: intstr.FromInt32(reverseProxyAuthProbePort),
+				},
+			},
+		}),
+	}
+}
+
+// syncReverseProxyAuthContainer adds, updates or removes the auth sidecar following spec.ingress.auth, and reports
+// whether the containers of the Deployment changed
+func (r *TypesenseClusterReconciler) syncReverseProxyAuthContainer(deployment *appsv1.Deployment, ts *tsv1alpha1.TypesenseCluster) bool {
+	podSpec := &deployment.Spec.Template.Spec
+	desired := r.getReverseProxyAuthContainer(ts)
+
+	if desired == nil {
+		if len(podSpec.Containers) == 1 {
+			return false
+		}
+
+		podSpec.Containers = podSpec.Containers[:1]
+		return true
+	}
+
+	if len(podSpec.Containers) == 1 {
+		podSpec.Containers = append(podSpec.Containers, *desired)
+		return true
+	}
+
+	current := &podSpec.Containers[1]
+	if current.Name == desired.Name &&
+		current.Image == desired.Image &&
+		equality.Semantic.DeepEqual(desired.Command, current.Command) &&
+		equality.Semantic.DeepEqual(desired.Args, current.Args) &&
+		equality.Semantic.DeepEqual(desired.Resources, current.Resources) &&
+		equality.Semantic.DeepEqual(desired.SecurityContext, current.SecurityContext) &&
+		equality.Semantic.DeepEqual(desired.VolumeMounts, current.VolumeMounts) &&
+		equality.Semantic.DeepEqual(desired.ReadinessProbe, current.ReadinessProbe) {
+		return false
+	}
+
+	podSpec.Containers = append(podSpec.Containers[:1], *desired)
+	return true
+}
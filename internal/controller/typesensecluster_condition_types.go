@@ -12,6 +12,8 @@ type ConditionQuorum string
 // Definitions to manage status conditions
 const (
 	ConditionTypeReady = "Ready"
+	// ConditionTypeGatewayBackendTLS is only set while the BackendTLSPolicy of the gateway has to be skipped
+	ConditionTypeGatewayBackendTLS = "GatewayBackendTLS"
 
 	ConditionReasonReconciliationInProgress                              = "ReconciliationInProgress"
 	ConditionReasonSecretNotReady                                        = "SecretNotReady"
@@ -20,6 +22,11 @@ const (
 	ConditionReasonIngressNotReady                                       = "IngressNotReady"
 	ConditionReasonScrapersNotReady                                      = "ScrapersNotReady"
 	ConditionReasonMetricsExporterNotReady                               = "MetricsExporterNotReady"
+	ConditionReasonCertificateNotReady                                   = "CertificateNotReady"
+	ConditionReasonAdminApiKeyRotationFailed                             = "AdminApiKeyRotationFailed"
+	ConditionReasonNetworkPolicyNotReady                                 = "NetworkPolicyNotReady"
+	ConditionReasonGatewayNotReady                                       = "GatewayNotReady"
+	ConditionReasonBackendTLSPolicyNotServed                             = "BackendTLSPolicyNotServed"
 	ConditionReasonQuorumStateUnknown                    ConditionQuorum = "QuorumStateUnknown"
 	ConditionReasonQuorumReady                           ConditionQuorum = "QuorumReady"
 	ConditionReasonQuorumNotReady                        ConditionQuorum = "QuorumNotReady"
@@ -40,6 +47,10 @@ func (r *TypesenseClusterReconciler) initConditions(ctx context.Context, ts *tsv
 		if err := r.patchStatus(ctx, ts, func(status *tsv1alpha1.TypesenseClusterStatus) {
 			meta.SetStatusCondition(&ts.Status.Conditions, metav1.Condition{Type: ConditionTypeReady, Status: metav1.ConditionUnknown, Reason: ConditionReasonReconciliationInProgress, Message: InitReconciliationMessage})
 			status.Phase = "Bootstrapping"
+			// the seed is armed only here, so that adding spec.seed to an existing cluster never imports anything
+			if ts.Spec.Seed != nil {
+				status.Seed = &tsv1alpha1.SeedStatus{Phase: SeedPhasePending}
+			}
 		}); err != nil {
 			r.logger.Error(err, UpdateStatusMessageFailed)
 			return err
@@ -52,7 +52,7 @@ func (r *TypesenseClusterReconciler) ReconcileConfigMap(ctx context.Context, ts
 	return &configMapExists, nil
 }
 
-const nodeNameLenLimit = 64
+const nodeNameLenLimit = tsv1alpha1.NodeNameLenLimit
 
 func (r *TypesenseClusterReconciler) createConfigMap(ctx context.Context, key client.ObjectKey, ts *tsv1alpha1.TypesenseCluster) (*v1.ConfigMap, error) {
 	nodes, err := r.getNodes(ctx, ts, ts.Spec.Replicas, true)
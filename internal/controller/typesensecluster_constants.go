@@ -1,20 +1,24 @@
 package controller
 
 const (
-	ClusterNodesConfigMap           = "%s-nodeslist"
-	ClusterAdminApiKeySecret        = "%s-admin-key"
-	ClusterAdminApiKeySecretKeyName = "typesense-api-key"
+	ClusterNodesConfigMap            = "%s-nodeslist"
+	ClusterAdminApiKeySecret         = "%s-admin-key"
+	ClusterAdminApiKeySecretKeyName  = "typesense-api-key"
+	ClusterPreviousAdminApiKeySecret = "%s-admin-key-previous"
 
 	ClusterHeadlessService = "%s-sts-svc"
 	ClusterRestService     = "%s-svc"
 	ClusterStatefulSet     = "%s-sts"
 	ClusterAppLabel        = "%s-sts"
 
-	ClusterReverseProxyAppLabel  = "%s-rp"
-	ClusterReverseProxyIngress   = "%s-reverse-proxy"
-	ClusterReverseProxyConfigMap = "%s-reverse-proxy-config"
-	ClusterReverseProxy          = "%s-reverse-proxy"
-	ClusterReverseProxyService   = "%s-reverse-proxy-svc"
+	ClusterReverseProxyAppLabel            = "%s-rp"
+	ClusterReverseProxyIngress             = "%s-reverse-proxy"
+	ClusterReverseProxyConfigMap           = "%s-reverse-proxy-config"
+	ClusterReverseProxy                    = "%s-reverse-proxy"
+	ClusterReverseProxyService             = "%s-reverse-proxy-svc"
+	ClusterReverseProxyAutoscaler          = "%s-reverse-proxy-hpa"
+	ClusterReverseProxyPodDisruptionBudget = "%s-reverse-proxy-pdb"
+	ClusterReverseProxyAuth                = "%s-reverse-proxy-auth"
 
 	//TODO Remove them future version 0.2.15
 
@@ -28,4 +32,16 @@ const (
 
 	ClusterScraperCronJob          = "%s-scraper"
 	ClusterScraperCronJobContainer = "%s-docsearch-scraper"
+
+	ClusterSeedJob = "%s-seed"
+
+	ClusterCertificate       = "%s-certificate"
+	ClusterCertificateSecret = "%s-certificate-tls"
+
+	ClusterNetworkPolicy  = "%s-network-policy"
+	ClusterApiClientLabel = "ts.opentelekomcloud.com/api-client"
+
+	ClusterHTTPRoute          = "%s-httproute"
+	ClusterBackendTLSPolicy   = "%s-backend-tls-policy"
+	ClusterGatewayCAConfigMap = "%s-gateway-ca"
 )
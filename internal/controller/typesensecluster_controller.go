@@ -23,6 +23,7 @@ import (
 	"github.com/pkg/errors"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
+	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/discovery"
@@ -46,6 +47,15 @@ type TypesenseClusterReconciler struct {
 	logger          logr.Logger
 	Recorder        record.EventRecorder
 	DiscoveryClient *discovery.DiscoveryClient
+
+	// OperatorNamespace is where the operator runs, it is let through the network policies of the clusters
+	OperatorNamespace string
+
+	// JobImage runs the Jobs and CronJobs that do not set an image of their own, it provides sh, curl and jq
+	JobImage string
+
+	// AuthImage runs the auth sidecar of the reverse proxy when spec.ingress.auth sets no image, it provides /auth
+	AuthImage string
 }
 
 type TypesenseClusterReconciliationPhase struct {
@@ -69,6 +79,17 @@ var (
 		},
 	})
 
+	// clusterEventFilters additionally let through a requested admin api key rotation, which only changes an annotation
+	clusterEventFilters = builder.WithPredicates(predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return e.ObjectOld.GetGeneration() != e.ObjectNew.GetGeneration() ||
+				e.ObjectOld.GetAnnotations()[AdminApiKeyRotationAnnotation] != e.ObjectNew.GetAnnotations()[AdminApiKeyRotationAnnotation]
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return !e.DeleteStateUnknown
+		},
+	})
+
 	requeueAfter = time.Second * 30
 )
 
@@ -82,12 +103,18 @@ var (
 // +kubebuilder:rbac:groups="",resources=pods/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=autoscaling,resources=horizontalpodautoscalers,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
 // +kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=batch,resources=cronjobs,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=monitoring.coreos.com,resources=servicemonitors,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=monitoring.coreos.com,resources=podmonitors,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=discovery.k8s.io,resources=endpointslices,verbs=get;list;watch
+// +kubebuilder:rbac:groups=cert-manager.io,resources=certificates,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=httproutes;backendtlspolicies,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=secrets-store.csi.x-k8s.io,resources=secretproviderclasses,verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -112,7 +139,7 @@ func (r *TypesenseClusterReconciler) Reconcile(ctx context.Context, req ctrl.Req
 		return ctrl.Result{}, err
 	}
 
-	// Update strategy: Admin Secret is Immutable, will not be updated on any future change
+	// Update strategy: Admin Secret is Immutable, it is only replaced by a rotation of the admin api key
 	secret, err := r.ReconcileSecret(ctx, ts)
 	if err != nil {
 		cerr := r.setConditionNotReady(ctx, &ts, ConditionReasonSecretNotReady, err)
@@ -142,6 +169,36 @@ func (r *TypesenseClusterReconciler) Reconcile(ctx context.Context, req ctrl.Req
 		return ctrl.Result{}, err
 	}
 
+	// Update strategy: Update the spec of the NetworkPolicy, if the ports or the allowed clients changed
+	err = r.ReconcileNetworkPolicy(ctx, ts)
+	if err != nil {
+		cerr := r.setConditionNotReady(ctx, &ts, ConditionReasonNetworkPolicyNotReady, err)
+		if cerr != nil {
+			err = errors.Wrap(err, cerr.Error())
+		}
+		return ctrl.Result{}, err
+	}
+
+	// Update strategy: Update the fields of the Certificate owned by the operator, if changes are identified
+	err = r.ReconcileCertificate(ctx, ts)
+	if err != nil {
+		cerr := r.setConditionNotReady(ctx, &ts, ConditionReasonCertificateNotReady, err)
+		if cerr != nil {
+			err = errors.Wrap(err, cerr.Error())
+		}
+		return ctrl.Result{}, err
+	}
+
+	// Update strategy: Roll the nodes onto a new admin api key, keeping the previous one valid for an overlap window
+	secret, err = r.ReconcileAdminApiKeyRotation(ctx, &ts, secret)
+	if err != nil {
+		cerr := r.setConditionNotReady(ctx, &ts, ConditionReasonAdminApiKeyRotationFailed, err)
+		if cerr != nil {
+			err = errors.Wrap(err, cerr.Error())
+		}
+		return ctrl.Result{}, err
+	}
+
 	// Update strategy: Update the existing objects, if changes are identified in api and peering ports
 	err = r.ReconcileIngress(ctx, ts)
 	if err != nil {
@@ -152,6 +209,16 @@ func (r *TypesenseClusterReconciler) Reconcile(ctx context.Context, req ctrl.Req
 		return ctrl.Result{}, err
 	}
 
+	// Update strategy: Update the owned fields of the existing HTTPRoute and BackendTLSPolicy, if changes are identified
+	err = r.ReconcileGateway(ctx, &ts)
+	if err != nil {
+		cerr := r.setConditionNotReady(ctx, &ts, ConditionReasonGatewayNotReady, err)
+		if cerr != nil {
+			err = errors.Wrap(err, cerr.Error())
+		}
+		return ctrl.Result{}, err
+	}
+
 	// Update strategy: Drop the existing objects and recreate them, if changes are identified
 	err = r.ReconcileScraper(ctx, ts)
 	if err != nil {
@@ -235,6 +302,12 @@ func (r *TypesenseClusterReconciler) Reconcile(ctx context.Context, req ctrl.Req
 				if report {
 					r.Recorder.Eventf(&ts, "Normal", string(condition), toTitle("quorum is ready"))
 				}
+
+				err = r.ReconcileSeed(ctx, &ts)
+				if err != nil {
+					r.logger.Error(err, "seeding cluster failed")
+					r.Recorder.Eventf(&ts, "Warning", ConditionReasonSeedFailed, toTitle(err.Error()))
+				}
 			}
 		}
 		cond = condition
@@ -253,6 +326,20 @@ func (r *TypesenseClusterReconciler) Reconcile(ctx context.Context, req ctrl.Req
 // SetupWithManager sets up the controller with the Manager.
 func (r *TypesenseClusterReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&tsv1alpha1.TypesenseCluster{}, eventFilters).
+		For(&tsv1alpha1.TypesenseCluster{}, clusterEventFilters).
+		// a changed admin api key is rolled onto the nodes right away, see ReconcileAdminApiKeyRotation, and so is
+		// a renewed certificate or CA, which are read from the same tls Secret
+		Watches(&v1.Secret{}, enqueueReferencingObjects(mgr.GetClient(), &tsv1alpha1.TypesenseClusterList{}, isClusterSecret)).
 		Complete(r)
 }
+
+// isClusterSecret tells whether the cluster reads the named Secret, either for its admin api key or for its
+// certificate and the CA that signed it.
+func isClusterSecret(obj client.Object, name string) bool {
+	ts := obj.(*tsv1alpha1.TypesenseCluster)
+	if adminApiKeyObjectKey(ts).Name == name {
+		return true
+	}
+
+	return ts.Spec.IsTLSEnabled() && getTLSSecretName(ts) == name
+}
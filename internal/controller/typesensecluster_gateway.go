@@ -0,0 +1,413 @@
+package controller
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	tsv1alpha1 "github.com/akyriako/typesense-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	gatewayApiGroup = "gateway.networking.k8s.io"
+
+	gatewayCAFile = "ca.crt"
+)
+
+var (
+	// the versions the operator can manage, by order of preference; they share the spec the operator sets
+	httpRouteVersions        = []string{"v1", "v1beta1"}
+	backendTLSPolicyVersions = []string{"v1", "v1alpha3"}
+
+	// the spec fields owned by the operator, the desired values carry the defaults of the Gateway API
+	// so that they compare equal to what the api server stores
+	httpRouteSpecFields        = []string{"parentRefs", "hostnames", "rules"}
+	backendTLSPolicySpecFields = []string{"targetRefs", "validation"}
+)
+
+// ReconcileGateway attaches an HTTPRoute to the Gateway of spec.gateway, routing to the reverse proxy of the ingress
+// if it runs one, or else to the resolver service. When the api is served over tls, a BackendTLSPolicy makes the
+// Gateway verify the service against the CA of the cluster certificate, copied to a ConfigMap. A Gateway API that
+// does not serve BackendTLSPolicy yet leaves the route without one, which the GatewayBackendTLS condition reports.
+func (r *TypesenseClusterReconciler) ReconcileGateway(ctx context.Context, ts *tsv1alpha1.TypesenseCluster) error {
+	r.logger.V(debugLevel).Info("reconciling gateway")
+
+	routeObjectKey := client.ObjectKey{Namespace: ts.Namespace, Name: fmt.Sprintf(ClusterHTTPRoute, ts.Name)}
+	policyObjectKey := client.ObjectKey{Namespace: ts.Namespace, Name: fmt.Sprintf(ClusterBackendTLSPolicy, ts.Name)}
+	caObjectKey := client.ObjectKey{Namespace: ts.Namespace, Name: fmt.Sprintf(ClusterGatewayCAConfigMap, ts.Name)}
+
+	if ts.Spec.Gateway == nil {
+		if err := r.deleteGatewayApiObject(ctx, "HTTPRoute", httpRouteVersions, routeObjectKey, ts); err != nil {
+			return err
+		}
+		if err := r.deleteBackendTLSPolicy(ctx, policyObjectKey, caObjectKey, ts); err != nil {
+			return err
+		}
+		return r.setGatewayBackendTLSCondition(ctx, ts, nil)
+	}
+
+	routeGVK, err := r.getGatewayApiKind("HTTPRoute", httpRouteVersions)
+	if err != nil {
+		return err
+	}
+	if routeGVK.Empty() {
+		return fmt.Errorf("gateway api group %s does not serve HTTPRoute in any of the versions %s", gatewayApiGroup, strings.Join(httpRouteVersions, ", "))
+	}
+
+	if err := r.applyUnstructured(ctx, buildHTTPRoute(routeGVK, routeObjectKey, ts), httpRouteSpecFields, ts); err != nil {
+		return err
+	}
+
+	if !ts.Spec.IsTLSEnabled() || usesReverseProxy(ts) {
+		if err := r.deleteBackendTLSPolicy(ctx, policyObjectKey, caObjectKey, ts); err != nil {
+			return err
+		}
+		return r.setGatewayBackendTLSCondition(ctx, ts, nil)
+	}
+
+	policyGVK, err := r.getGatewayApiKind("BackendTLSPolicy", backendTLSPolicyVersions)
+	if err != nil {
+		return err
+	}
+	if policyGVK.Empty() {
+		err := fmt.Errorf("gateway api group %s does not serve BackendTLSPolicy in any of the versions %s, the gateway cannot verify the tls certificate of the api",
+			gatewayApiGroup, strings.Join(backendTLSPolicyVersions, ", "))
+		r.logger.Info("skipping backendtlspolicy", "reason", err.Error())
+		return r.setGatewayBackendTLSCondition(ctx, ts, err)
+	}
+
+	if err := r.reconcileGatewayCAConfigMap(ctx, caObjectKey, ts); err != nil {
+		return err
+	}
+
+	if err := r.applyUnstructured(ctx, buildBackendTLSPolicy(policyGVK, policyObjectKey, caObjectKey.Name, ts), backendTLSPolicySpecFields, ts); err != nil {
+		return err
+	}
+
+	return r.setGatewayBackendTLSCondition(ctx, ts, nil)
+}
+
+// getGatewayApiKind returns the kind in the first of the given versions the api server serves it in, or an empty
+// GroupVersionKind when it serves none of them.
+func (r *TypesenseClusterReconciler) getGatewayApiKind(kind string, versions []string) (schema.GroupVersionKind, error) {
+	for _, version := range versions {
+		groupVersion := schema.GroupVersion{Group: gatewayApiGroup, Version: version}
+		resources, err := r.DiscoveryClient.ServerResourcesForGroupVersion(groupVersion.String())
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return schema.GroupVersionKind{}, err
+		}
+
+		for _, resource := range resources.APIResources {
+			if resource.Kind == kind {
+				return groupVersion.WithKind(kind), nil
+			}
+		}
+	}
+
+	return schema.GroupVersionKind{}, nil
+}
+
+// setGatewayBackendTLSCondition reports the BackendTLSPolicy that had to be skipped, or clears the condition once
+// the policy is applied or not needed anymore.
+func (r *TypesenseClusterReconciler) setGatewayBackendTLSCondition(ctx context.Context, ts *tsv1alpha1.TypesenseCluster, err error) error {
+	if err == nil {
+		if meta.FindStatusCondition(ts.Status.Conditions, ConditionTypeGatewayBackendTLS) == nil {
+			return nil
+		}
+		return r.patchStatus(ctx, ts, func(status *tsv1alpha1.TypesenseClusterStatus) {
+			meta.RemoveStatusCondition(&status.Conditions, ConditionTypeGatewayBackendTLS)
+		})
+	}
+
+	condition := metav1.Condition{Type: ConditionTypeGatewayBackendTLS, Status: metav1.ConditionFalse, Reason: ConditionReasonBackendTLSPolicyNotServed, Message: err.Error()}
+	if current := meta.FindStatusCondition(ts.Status.Conditions, ConditionTypeGatewayBackendTLS); current != nil &&
+		current.Status == condition.Status && current.Reason == condition.Reason && current.Message == condition.Message {
+		return nil
+	}
+
+	r.Recorder.Event(ts, "Warning", condition.Reason, condition.Message)
+	return r.patchStatus(ctx, ts, func(status *tsv1alpha1.TypesenseClusterStatus) {
+		meta.SetStatusCondition(&status.Conditions, condition)
+	})
+}
+
+// reconcileGatewayCAConfigMap keeps the CA of the cluster certificate in a ConfigMap, the only source of
+// CA certificates every implementation of BackendTLSPolicy supports
+func (r *TypesenseClusterReconciler) reconcileGatewayCAConfigMap(ctx context.Context, key client.ObjectKey, ts *tsv1alpha1.TypesenseCluster) error {
+	secret, err := getTLSSecret(ctx, r.Client, ts)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return fmt.Errorf("waiting for tls secret %s", getTLSSecretName(ts))
+		}
+		return err
+	}
+	data := map[string]string{gatewayCAFile: string(secret.Data[ts.Spec.TLS.GetCAKey()])}
+
+	cm := &corev1.ConfigMap{}
+	if err := r.Get(ctx, key, cm); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+
+		cm = &corev1.ConfigMap{
+			ObjectMeta: getObjectMeta(ts, &key.Name, nil),
+			Data:       data,
+		}
+		if err := ctrl.SetControllerReference(ts, cm, r.Scheme); err != nil {
+			return err
+		}
+
+		r.logger.V(debugLevel).Info("creating gateway ca config map", "configmap", key.Name)
+		return r.Create(ctx, cm)
+	}
+
+	if cm.Data[gatewayCAFile] == data[gatewayCAFile] {
+		return nil
+	}
+
+	r.logger.V(debugLevel).Info("updating gateway ca config map", "configmap", key.Name)
+	cm.Data = data
+	return r.Update(ctx, cm)
+}
+
+func (r *TypesenseClusterReconciler) deleteBackendTLSPolicy(ctx context.Context, policyKey client.ObjectKey, caKey client.ObjectKey, ts *tsv1alpha1.TypesenseCluster) error {
+	if err := r.deleteGatewayApiObject(ctx, "BackendTLSPolicy", backendTLSPolicyVersions, policyKey, ts); err != nil {
+		return err
+	}
+
+	cm := &corev1.ConfigMap{}
+	if err := r.Get(ctx, caKey, cm); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+
+	if !metav1.IsControlledBy(cm, ts) {
+		return nil
+	}
+
+	r.logger.V(debugLevel).Info("deleting gateway ca config map", "configmap", caKey.Name)
+	return client.IgnoreNotFound(r.Delete(ctx, cm))
+}
+
+// applyUnstructured creates the desired object of a kind the operator has no types for, or updates the given
+// spec fields of the existing one if they differ
+func (r *TypesenseClusterReconciler) applyUnstructured(ctx context.Context, desired *unstructured.Unstructured, fields []string, ts *tsv1alpha1.TypesenseCluster) error {
+	kind := strings.ToLower(desired.GetKind())
+	if err := ctrl.SetControllerReference(ts, desired, r.Scheme); err != nil {
+		return err
+	}
+
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(desired.GroupVersionKind())
+	if err := r.Get(ctx, client.ObjectKeyFromObject(desired), existing); err != nil {
+		if !apierrors.IsNotFound(err) {
+			r.logger.Error(err, fmt.Sprintf("unable to fetch %s: %s", kind, desired.GetName()))
+			return err
+		}
+
+		r.logger.V(debugLevel).Info(fmt.Sprintf("creating %s", kind), kind, desired.GetName())
+		if err := r.Create(ctx, desired); err != nil {
+			r.logger.Error(err, fmt.Sprintf("creating %s failed", kind), kind, desired.GetName())
+			return err
+		}
+		return nil
+	}
+
+	if !metav1.IsControlledBy(existing, ts) {
+		return fmt.Errorf("%s %s is not controlled by %s", kind, desired.GetName(), ts.Name)
+	}
+
+	if !updateUnstructuredSpec(existing, desired, fields) {
+		return nil
+	}
+
+	r.logger.V(debugLevel).Info(fmt.Sprintf("updating %s", kind), kind, desired.GetName())
+	if err := r.Update(ctx, existing); err != nil {
+		r.logger.Error(err, fmt.Sprintf("updating %s failed", kind), kind, desired.GetName())
+		return err
+	}
+
+	return nil
+}
+
+// deleteGatewayApiObject deletes the object through every version the operator manages, those that are not served
+// are skipped, so the object is found whichever version it was created with.
+func (r *TypesenseClusterReconciler) deleteGatewayApiObject(ctx context.Context, kind string, versions []string, key client.ObjectKey, ts *tsv1alpha1.TypesenseCluster) error {
+	for _, version := range versions {
+		gvk := schema.GroupVersionKind{Group: gatewayApiGroup, Version: version, Kind: kind}
+		if err := r.deleteUnstructured(ctx, gvk, key, ts); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deleteUnstructured deletes the object controlled by the cluster, nothing is done if its kind is not installed
+func (r *TypesenseClusterReconciler) deleteUnstructured(ctx context.Context, gvk schema.GroupVersionKind, key client.ObjectKey, ts *tsv1alpha1.TypesenseCluster) error {
+	object := &unstructured.Unstructured{}
+	object.SetGroupVersionKind(gvk)
+	if err := r.Get(ctx, key, object); err != nil {
+		if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			return nil
+		}
+		return err
+	}
+
+	if !metav1.IsControlledBy(object, ts) {
+		return nil
+	}
+
+	r.logger.V(debugLevel).Info(fmt.Sprintf("deleting %s", strings.ToLower(gvk.Kind)), strings.ToLower(gvk.Kind), key.Name)
+	return client.IgnoreNotFound(r.Delete(ctx, object))
+}
+
+// usesReverseProxy reports whether the api is exposed through the nginx reverse proxy of the ingress
+func usesReverseProxy(ts *tsv1alpha1.TypesenseCluster) bool {
+	return ts.Spec.Ingress != nil && !ts.Spec.Ingress.IsDirect()
+}
+
+// getGatewayBackend returns the service and port the HTTPRoute forwards to
+func getGatewayBackend(ts *tsv1alpha1.TypesenseCluster) (string, int) {
+	if usesReverseProxy(ts) {
+		return fmt.Sprintf(ClusterReverseProxyService, ts.Name), 80
+	}
+
+	return fmt.Sprintf(ClusterRestService, ts.Name), ts.Spec.ApiPort
+}
+
+func buildHTTPRoute(gvk schema.GroupVersionKind, key client.ObjectKey, ts *tsv1alpha1.TypesenseCluster) *unstructured.Unstructured {
+	gateway := ts.Spec.Gateway
+
+	parentRef := map[string]any{
+		"group": gatewayApiGroup,
+		"kind":  "Gateway",
+		"name":  gateway.ParentRef.Name,
+	}
+	if gateway.ParentRef.Namespace != "" {
+		parentRef["namespace"] = gateway.ParentRef.Namespace
+	}
+	if gateway.ParentRef.SectionName != "" {
+		parentRef["sectionName"] = gateway.ParentRef.SectionName
+	}
+
+	headers := make([]any, 0, len(gateway.Headers)+1)
+	for _, header := range gateway.Headers {
+		headers = append(headers, map[string]any{
+			"type":  cmp.Or(header.Type, "Exact"),
+			"name":  header.Name,
+			"value": header.Value,
+		})
+	}
+	if gateway.Referer != nil {
+		headers = append(headers, map[string]any{
+			"type":  "RegularExpression",
+			"name":  "Referer",
+			"value": getRefererPattern(*gateway.Referer),
+		})
+	}
+
+	paths := gateway.Paths
+	if len(paths) == 0 {
+		paths = []tsv1alpha1.GatewayPathMatch{{Type: "PathPrefix", Value: "/"}}
+	}
+
+	// a request has to match one of the paths and every header
+	matches := make([]any, 0, len(paths))
+	for _, path := range paths {
+		match := map[string]any{
+			"path": map[string]any{
+				"type":  cmp.Or(path.Type, "PathPrefix"),
+				"value": path.Value,
+			},
+		}
+		if len(headers) > 0 {
+			match["headers"] = headers
+		}
+		matches = append(matches, match)
+	}
+
+	backendName, backendPort := getGatewayBackend(ts)
+	spec := map[string]any{
+		"parentRefs": []any{parentRef},
+		"rules": []any{
+			map[string]any{
+				"matches": matches,
+				"backendRefs": []any{
+					map[string]any{
+						"group":  "",
+						"kind":   "Service",
+						"name":   backendName,
+						"port":   int64(backendPort),
+						"weight": int64(1),
+					},
+				},
+			},
+		},
+	}
+	if len(gateway.Hostnames) > 0 {
+		hostnames := make([]any, 0, len(gateway.Hostnames))
+		for _, hostname := range gateway.Hostnames {
+			hostnames = append(hostnames, hostname)
+		}
+		spec["hostnames"] = hostnames
+	}
+
+	route := &unstructured.Unstructured{}
+	route.SetGroupVersionKind(gvk)
+	route.SetName(key.Name)
+	route.SetNamespace(key.Namespace)
+	route.SetLabels(getLabels(ts))
+	route.Object["spec"] = spec
+
+	return route
+}
+
+func buildBackendTLSPolicy(gvk schema.GroupVersionKind, key client.ObjectKey, caConfigMapName string, ts *tsv1alpha1.TypesenseCluster) *unstructured.Unstructured {
+	serviceName := fmt.Sprintf(ClusterRestService, ts.Name)
+
+	policy := &unstructured.Unstructured{}
+	policy.SetGroupVersionKind(gvk)
+	policy.SetName(key.Name)
+	policy.SetNamespace(key.Namespace)
+	policy.SetLabels(getLabels(ts))
+	policy.Object["spec"] = map[string]any{
+		"targetRefs": []any{
+			map[string]any{
+				"group": "",
+				"kind":  "Service",
+				"name":  serviceName,
+			},
+		},
+		"validation": map[string]any{
+			"caCertificateRefs": []any{
+				map[string]any{
+					"group": "",
+					"kind":  "ConfigMap",
+					"name":  caConfigMapName,
+				},
+			},
+			// one of the names of the cluster certificate
+			"hostname": fmt.Sprintf("%s.%s.svc", serviceName, ts.Namespace),
+		},
+	}
+
+	return policy
+}
+
+// getRefererPattern matches a Referer header of the given host, over http or https and with any path
+func getRefererPattern(host string) string {
+	return fmt.Sprintf("^https?://%s(:[0-9]+)?(/.*)?$", regexp.QuoteMeta(host))
+}
@@ -0,0 +1,248 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	tsv1alpha1 "github.com/akyriako/typesense-operator/api/v1alpha1"
+)
+
+var _ = Describe("TypesenseCluster Gateway", func() {
+	Context("When spec.gateway is set", func() {
+		ctx := context.Background()
+		routeKey := client.ObjectKey{Namespace: "search", Name: "cluster-1-httproute"}
+		routeGVK := schema.GroupVersionKind{Group: gatewayApiGroup, Version: "v1", Kind: "HTTPRoute"}
+		policyGVK := schema.GroupVersionKind{Group: gatewayApiGroup, Version: "v1", Kind: "BackendTLSPolicy"}
+
+		// newDiscoveryClient serves the given kinds of the gateway api group, by version
+		newDiscoveryClient := func(kinds map[string][]string) (*discovery.DiscoveryClient, func()) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				version := req.URL.Path[len("/apis/"+gatewayApiGroup+"/"):]
+				if _, ok := kinds[version]; !ok {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+
+				resources := metav1.APIResourceList{TypeMeta: metav1.TypeMeta{Kind: "APIResourceList", APIVersion: "v1"}, GroupVersion: gatewayApiGroup + "/" + version}
+				for _, kind := range kinds[version] {
+					resources.APIResources = append(resources.APIResources, metav1.APIResource{Name: kind, Namespaced: true, Kind: kind})
+				}
+				w.Header().Set("Content-Type", "application/json")
+				_ = json.NewEncoder(w).Encode(resources)
+			}))
+
+			return discovery.NewDiscoveryClientForConfigOrDie(&rest.Config{Host: server.URL}), server.Close
+		}
+
+		newCluster := func(gateway *tsv1alpha1.GatewaySpec) *tsv1alpha1.TypesenseCluster {
+			return &tsv1alpha1.TypesenseCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster-1", Namespace: "search", UID: "uid-1"},
+				Spec: tsv1alpha1.TypesenseClusterSpec{
+					ApiPort: 8108,
+					Gateway: gateway,
+				},
+			}
+		}
+
+		It("should route every path to the resolver service by default", func() {
+			ts := newCluster(&tsv1alpha1.GatewaySpec{
+				ParentRef: tsv1alpha1.GatewayParentReference{Name: "public", Namespace: "gateways"},
+				Hostnames: []string{"search.example.com"},
+			})
+
+			route := buildHTTPRoute(routeGVK, routeKey, ts)
+			Expect(route.GetKind()).To(Equal("HTTPRoute"))
+
+			spec := route.Object["spec"].(map[string]any)
+			Expect(spec["hostnames"]).To(Equal([]any{"search.example.com"}))
+			Expect(spec["parentRefs"]).To(Equal([]any{map[string]any{
+				"group":     "gateway.networking.k8s.io",
+				"kind":      "Gateway",
+				"name":      "public",
+				"namespace": "gateways",
+			}}))
+
+			rule := spec["rules"].([]any)[0].(map[string]any)
+			Expect(rule["matches"]).To(Equal([]any{map[string]any{
+				"path": map[string]any{"type": "PathPrefix", "value": "/"},
+			}}))
+			Expect(rule["backendRefs"]).To(Equal([]any{map[string]any{
+				"group":  "",
+				"kind":   "Service",
+				"name":   "cluster-1-svc",
+				"port":   int64(8108),
+				"weight": int64(1),
+			}}))
+
+			ts.Spec.Ingress = &tsv1alpha1.IngressSpec{Host: "search.example.com"}
+			backendRef, _, _ := unstructured.NestedSlice(buildHTTPRoute(routeGVK, routeKey, ts).Object["spec"].(map[string]any)["rules"].([]any)[0].(map[string]any), "backendRefs")
+			Expect(backendRef[0]).To(HaveKeyWithValue("name", "cluster-1-reverse-proxy-svc"))
+			Expect(backendRef[0]).To(HaveKeyWithValue("port", int64(80)))
+		})
+
+		It("should require the headers and the referer on every path", func() {
+			ts := newCluster(&tsv1alpha1.GatewaySpec{
+				ParentRef: tsv1alpha1.GatewayParentReference{Name: "public"},
+				Paths: []tsv1alpha1.GatewayPathMatch{
+					{Value: "/collections"},
+					{Type: "Exact", Value: "/multi_search"},
+				},
+				Headers: []tsv1alpha1.GatewayHeaderMatch{{Name: "X-Tenant", Value: "acme"}},
+				Referer: ptr.To("app.example.com"),
+			})
+
+			spec := buildHTTPRoute(routeGVK, routeKey, ts).Object["spec"].(map[string]any)
+			matches := spec["rules"].([]any)[0].(map[string]any)["matches"].([]any)
+			Expect(matches).To(HaveLen(2))
+			Expect(matches[1].(map[string]any)["path"]).To(Equal(map[string]any{"type": "Exact", "value": "/multi_search"}))
+
+			for _, match := range matches {
+				Expect(match.(map[string]any)["headers"]).To(Equal([]any{
+					map[string]any{"type": "Exact", "name": "X-Tenant", "value": "acme"},
+					map[string]any{"type": "RegularExpression", "name": "Referer", "value": `^https?://app\.example\.com(:[0-9]+)?(/.*)?$`},
+				}))
+			}
+			Expect(spec).NotTo(HaveKey("hostnames"))
+		})
+
+		It("should verify the resolver service over tls and clean up when tls is disabled", func() {
+			s := runtime.NewScheme()
+			Expect(clientgoscheme.AddToScheme(s)).To(Succeed())
+			Expect(tsv1alpha1.AddToScheme(s)).To(Succeed())
+
+			ts := newCluster(&tsv1alpha1.GatewaySpec{ParentRef: tsv1alpha1.GatewayParentReference{Name: "public"}})
+			ts.Spec.TLS = &tsv1alpha1.TLSSpec{SecretName: ptr.To("api-tls")}
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "api-tls", Namespace: "search"},
+				Data: map[string][]byte{
+					corev1.TLSCertKey:       []byte("cert"),
+					corev1.TLSPrivateKeyKey: []byte("key"),
+					"ca.crt":                []byte("ca-1"),
+				},
+			}
+			reconciler := &TypesenseClusterReconciler{
+				Client: fake.NewClientBuilder().WithScheme(s).WithObjects(ts, secret).Build(),
+				Scheme: s,
+				logger: log.Log,
+			}
+
+			caKey := client.ObjectKey{Namespace: "search", Name: "cluster-1-gateway-ca"}
+			Expect(reconciler.reconcileGatewayCAConfigMap(ctx, caKey, ts)).To(Succeed())
+
+			cm := &corev1.ConfigMap{}
+			Expect(reconciler.Get(ctx, caKey, cm)).To(Succeed())
+			Expect(cm.Data).To(Equal(map[string]string{"ca.crt": "ca-1"}))
+			Expect(metav1.IsControlledBy(cm, ts)).To(BeTrue())
+
+			secret.Data["ca.crt"] = []byte("ca-2")
+			Expect(reconciler.Update(ctx, secret)).To(Succeed())
+			Expect(reconciler.reconcileGatewayCAConfigMap(ctx, caKey, ts)).To(Succeed())
+			Expect(reconciler.Get(ctx, caKey, cm)).To(Succeed())
+			Expect(cm.Data["ca.crt"]).To(Equal("ca-2"))
+
+			policyKey := client.ObjectKey{Namespace: "search", Name: "cluster-1-backend-tls-policy"}
+			policy := buildBackendTLSPolicy(policyGVK, policyKey, caKey.Name, ts)
+			hostname, _, _ := unstructured.NestedString(policy.Object, "spec", "validation", "hostname")
+			Expect(hostname).To(Equal("cluster-1-svc.search.svc"))
+
+			Expect(reconciler.deleteBackendTLSPolicy(ctx, policyKey, caKey, ts)).To(Succeed())
+			Expect(reconciler.Get(ctx, caKey, cm)).NotTo(Succeed())
+		})
+
+		It("should not update a route that already matches", func() {
+			ts := newCluster(&tsv1alpha1.GatewaySpec{ParentRef: tsv1alpha1.GatewayParentReference{Name: "public"}})
+			desired := buildHTTPRoute(routeGVK, routeKey, ts)
+
+			live := desired.DeepCopy()
+			Expect(unstructured.SetNestedField(live.Object, "default", "spec", "status")).To(Succeed())
+			Expect(updateUnstructuredSpec(live, desired, httpRouteSpecFields)).To(BeFalse())
+
+			ts.Spec.Gateway.Hostnames = []string{"search.example.com"}
+			Expect(updateUnstructuredSpec(live, buildHTTPRoute(routeGVK, routeKey, ts), httpRouteSpecFields)).To(BeTrue())
+			Expect(live.Object["spec"]).To(HaveKeyWithValue("hostnames", []any{"search.example.com"}))
+		})
+
+		It("should prefer the v1 kinds and skip a BackendTLSPolicy that is not served", func() {
+			discoveryClient, closeServer := newDiscoveryClient(map[string][]string{
+				"v1":       {"HTTPRoute", "GRPCRoute"},
+				"v1alpha3": {"BackendTLSPolicy"},
+			})
+			defer closeServer()
+
+			s := runtime.NewScheme()
+			Expect(clientgoscheme.AddToScheme(s)).To(Succeed())
+			Expect(tsv1alpha1.AddToScheme(s)).To(Succeed())
+
+			ts := newCluster(&tsv1alpha1.GatewaySpec{ParentRef: tsv1alpha1.GatewayParentReference{Name: "public"}})
+			ts.Spec.TLS = &tsv1alpha1.TLSSpec{SecretName: ptr.To("api-tls")}
+			reconciler := &TypesenseClusterReconciler{
+				Client:          fake.NewClientBuilder().WithScheme(s).WithObjects(ts).WithStatusSubresource(ts).Build(),
+				Scheme:          s,
+				DiscoveryClient: discoveryClient,
+				Recorder:        record.NewFakeRecorder(10),
+				logger:          log.Log,
+			}
+
+			gvk, err := reconciler.getGatewayApiKind("HTTPRoute", httpRouteVersions)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(gvk).To(Equal(routeGVK))
+
+			gvk, err = reconciler.getGatewayApiKind("BackendTLSPolicy", backendTLSPolicyVersions)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(gvk.Version).To(Equal("v1alpha3"))
+
+			// a gateway api without any BackendTLSPolicy still gets its route, the policy is reported as skipped
+			reconciler.DiscoveryClient, closeServer = newDiscoveryClient(map[string][]string{"v1": {"HTTPRoute"}})
+			defer closeServer()
+
+			Expect(reconciler.ReconcileGateway(ctx, ts)).To(Succeed())
+
+			route := &unstructured.Unstructured{}
+			route.SetGroupVersionKind(routeGVK)
+			Expect(reconciler.Get(ctx, routeKey, route)).To(Succeed())
+
+			condition := meta.FindStatusCondition(ts.Status.Conditions, ConditionTypeGatewayBackendTLS)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Reason).To(Equal(ConditionReasonBackendTLSPolicyNotServed))
+
+			ts.Spec.TLS = nil
+			Expect(reconciler.ReconcileGateway(ctx, ts)).To(Succeed())
+			Expect(meta.FindStatusCondition(ts.Status.Conditions, ConditionTypeGatewayBackendTLS)).To(BeNil())
+		})
+	})
+})
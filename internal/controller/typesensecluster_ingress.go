@@ -5,6 +5,8 @@ import (
 	"context"
 	"fmt"
 	"maps"
+	"path"
+	"slices"
 	"strconv"
 	"strings"
 	"text/template"
@@ -14,8 +16,10 @@ import (
 
 	tsv1alpha1 "github.com/akyriako/typesense-operator/api/v1alpha1"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	v1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -25,11 +29,67 @@ import (
 )
 
 const (
-	confTemplate = `events {}
+	confTemplate = `{{- define "upstream"}}
+			  proxy_pass_request_headers on;
+			  {{- if .AuthPort}}
+			  auth_request /_auth;
+			  auth_request_set $typesense_api_key $upstream_http_x_typesense_api_key;
+			  proxy_set_header X-TYPESENSE-API-KEY $typesense_api_key;
+			  proxy_set_header Authorization "";
+			  {{- end}}
+			  {{- if .TrustedCertificate}}
+			  proxy_ssl_verify on;
+			  proxy_ssl_trusted_certificate {{.TrustedCertificate}};
+			  proxy_ssl_name {{.ServiceName}}-svc;
+			  proxy_ssl_server_name on;
+			  {{- end}}
+
+			  {{- if .LocationDirectives}}
+			  {{.LocationDirectives}}
+			  {{- end}}
+		{{- end}}
+		{{- define "cache"}}
+			  proxy_cache search_cache;
+			  proxy_cache_valid 200 {{.Cache.TTL}};
+			  proxy_cache_lock on;
+			  add_header X-Cache-Status $upstream_cache_status always;
+		{{- end}}events {}
 		http {
 		  {{- if .HttpDirectives}}
 		  {{.HttpDirectives}}
 		  {{- end}}
+		  {{- with .RateLimit}}
+		  {{- range .TrustedProxies}}
+		  set_real_ip_from {{.}};
+		  {{- end}}
+		  real_ip_header X-Forwarded-For;
+		  real_ip_recursive on;
+		  geo $rate_limit_dollar {
+			default "$";
+		  }
+		  limit_req_zone $binary_remote_addr zone=requests_per_client:10m rate={{.RequestsPerSecond}}r/s;
+		  {{- if .ApiKey}}
+		  limit_req_zone {{if $.AuthPort}}$http_authorization{{else}}$http_x_typesense_api_key{{end}} zone=requests_per_api_key:10m rate={{.ApiKey.RequestsPerSecond}}r/s;
+		  {{- end}}
+		  {{- if .ConnectionsPerClient}}
+		  limit_conn_zone $binary_remote_addr zone=connections_per_client:10m;
+		  {{- end}}
+		  limit_req_status 429;
+		  limit_conn_status 429;
+		  {{- end}}
+		  {{- with .Cache}}
+		  proxy_cache_path {{.Path}} levels=1:2 keys_zone=search_cache:10m max_size={{.MaxSize}} inactive={{.TTL}} use_temp_path=off;
+		  map $request_method $search_cache_bypass {
+			GET 0;
+			HEAD 0;
+			default 1;
+		  }
+		  map "$request_method:$content_length" $multi_search_cache_bypass {
+			default 1;
+			"~^(GET|HEAD):" 0;
+			"~^POST:[0-9]{1,4}$" 0;
+		  }
+		  {{- end}}
 		  server {
 			listen 80;
 
@@ -39,17 +99,73 @@ const (
 			{{- if .ServerDirectives}}
 			{{.ServerDirectives}}
 			{{- end}}
+			{{- with .RateLimit}}
+			limit_req zone=requests_per_client burst={{.Burst}} nodelay;
+			{{- if .ApiKey}}
+			limit_req zone=requests_per_api_key burst={{.ApiKey.Burst}} nodelay;
+			{{- end}}
+			{{- if .ConnectionsPerClient}}
+			limit_conn connections_per_client {{.ConnectionsPerClient}};
+			{{- end}}
+			error_page 429 @rate_limited;
+			location @rate_limited {
+			  default_type application/json;
+			  add_header Retry-After 1 always;
+			  return 429 '{{.ResponseBody}}';
+			}
+			{{- end}}
+			{{- if .AuthPort}}
+			error_page 401 @unauthorized;
+			location @unauthorized {
+			  default_type application/json;
+			  add_header WWW-Authenticate Bearer always;
+			  return 401 '{"message": "Unauthorized."}';
+			}
+			location = /_auth {
+			  internal;
+			  proxy_pass http://127.0.0.1:{{.AuthPort}}/auth;
+			  proxy_pass_request_body off;
+			  proxy_set_header Content-Length "";
+			  proxy_set_header X-Original-Method $request_method;
+			  proxy_set_header X-Original-URI $request_uri;
+			}
+			{{- end}}
+			{{- if .Cache}}
+			location ~ ^/collections/[^/]+/documents/search$ {
+			  proxy_pass {{.Protocol}}://{{.ServiceName}}-svc:{{.ServicePort}};
+			  {{- template "upstream" .}}
+			  {{- template "cache" .}}
+			  proxy_cache_key "$request_method$request_uri{{.ApiKeyVariable}}";
+			  proxy_cache_bypass $search_cache_bypass;
+			  proxy_no_cache $search_cache_bypass;
+			}
+			location = /multi_search {
+			  proxy_pass {{.Protocol}}://{{.ServiceName}}-svc:{{.ServicePort}};
+			  {{- template "upstream" .}}
+			  {{- template "cache" .}}
+			  client_body_buffer_size 16k;
+			  client_body_in_single_buffer on;
+			  proxy_cache_methods GET HEAD POST;
+			  proxy_cache_key "$request_method$request_uri{{.ApiKeyVariable}}$request_body";
+			  proxy_cache_bypass $multi_search_cache_bypass;
+			  proxy_no_cache $multi_search_cache_bypass;
+			}
+			{{- end}}
 			location / {
-			  proxy_pass http://{{.ServiceName}}-svc:{{.ServicePort}}/;
-			  proxy_pass_request_headers on;
-
-			  {{- if .LocationDirectives}}
-			  {{.LocationDirectives}}
-			  {{- end}}
+			  proxy_pass {{.Protocol}}://{{.ServiceName}}-svc:{{.ServicePort}}/;
+			  {{- template "upstream" .}}
 			}
 		  }
 		}`
 
+	ingressCertManagerClusterIssuer  = "cert-manager.io/cluster-issuer"
+	ingressNginxConfigurationSnippet = "nginx.ingress.kubernetes.io/configuration-snippet"
+	ingressNginxBackendProtocol      = "nginx.ingress.kubernetes.io/backend-protocol"
+	ingressNginxProxySSLSecret       = "nginx.ingress.kubernetes.io/proxy-ssl-secret"
+	ingressNginxProxySSLVerify       = "nginx.ingress.kubernetes.io/proxy-ssl-verify"
+	ingressNginxProxySSLName         = "nginx.ingress.kubernetes.io/proxy-ssl-name"
+	ingressNginxProxySSLServerName   = "nginx.ingress.kubernetes.io/proxy-ssl-server-name"
+
 	referer = `valid_referers server_names %s;
 					if ($invalid_referer) {
 				  		return 403;
@@ -89,12 +205,12 @@ func (r *TypesenseClusterReconciler) ReconcileIngress(ctx context.Context, ts ts
 		}
 	} else {
 		if ts.Spec.Ingress.Host != ig.Spec.Rules[0].Host ||
-			(ts.Spec.Ingress.ClusterIssuer != nil && *ts.Spec.Ingress.ClusterIssuer != ig.Annotations["cert-manager.io/cluster-issuer"]) ||
-			!reflect.DeepEqual(ts.Spec.Ingress.Annotations, r.getIngressAnnotations(ig)) ||
+			!equalOwnedIngressAnnotations(&ts, r.getIngressAnnotations(&ts), ig.Annotations) ||
 			(ts.Spec.Ingress.TLSSecretName != nil && *ts.Spec.Ingress.TLSSecretName != ig.Spec.TLS[0].SecretName) ||
 			ts.Spec.Ingress.IngressClassName != *ig.Spec.IngressClassName ||
 			ts.Spec.Ingress.Path != ig.Spec.Rules[0].IngressRuleValue.HTTP.Paths[0].Path ||
-			*ts.Spec.Ingress.PathType != *ig.Spec.Rules[0].IngressRuleValue.HTTP.Paths[0].PathType {
+			*ts.Spec.Ingress.PathType != *ig.Spec.Rules[0].IngressRuleValue.HTTP.Paths[0].PathType ||
+			!reflect.DeepEqual(getIngressBackend(&ts), ig.Spec.Rules[0].IngressRuleValue.HTTP.Paths[0].Backend) {
 
 			r.logger.V(debugLevel).Info("updating ingress", "ingress", ingressObjectKey.Name)
 
@@ -107,6 +223,10 @@ func (r *TypesenseClusterReconciler) ReconcileIngress(ctx context.Context, ts ts
 
 	}
 
+	if ts.Spec.Ingress.IsDirect() {
+		return r.deleteIngressReverseProxy(ctx, &ts, ig)
+	}
+
 	configMapName := fmt.Sprintf(ClusterReverseProxyConfigMap, ts.Name)
 	configMapExists := true
 	configMapObjectKey := client.ObjectKey{Namespace: ts.Namespace, Name: configMapName}
@@ -184,11 +304,16 @@ func (r *TypesenseClusterReconciler) ReconcileIngress(ctx context.Context, ts ts
 
 		readOnlyRootFilesystemSpecsNeedUpdate := false
 		if ts.Spec.Ingress.ReadOnlyRootFilesystem == nil {
-			if deployment.Spec.Template.Spec.Containers[0].SecurityContext != nil {
+			desiredVolumes := r.getDefaultReverseProxyVolumes(&ts)
+			desiredMounts := r.getDefaultReverseProxyVolumeMounts(&ts)
+
+			if deployment.Spec.Template.Spec.Containers[0].SecurityContext != nil ||
+				needsSyncVolumes(desiredVolumes, deployment.Spec.Template.Spec.Volumes) ||
+				needsSyncMounts(desiredMounts, deployment.Spec.Template.Spec.Containers[0].VolumeMounts) {
 				readOnlyRootFilesystemSpecsNeedUpdate = true
 				deployment.Spec.Template.Spec.Containers[0].SecurityContext = nil
-				deployment.Spec.Template.Spec.Volumes = r.getDefaultReverseProxyVolumes(ts.Name)
-				deployment.Spec.Template.Spec.Containers[0].VolumeMounts = r.getDefaultReverseProxyVolumeMounts()
+				deployment.Spec.Template.Spec.Volumes = desiredVolumes
+				deployment.Spec.Template.Spec.Containers[0].VolumeMounts = desiredMounts
 			}
 		} else {
 			securityContext := ts.Spec.Ingress.ReadOnlyRootFilesystem.SecurityContext
@@ -203,7 +328,7 @@ func (r *TypesenseClusterReconciler) ReconcileIngress(ctx context.Context, ts ts
 				deployment.Spec.Template.Spec.Containers[0].SecurityContext = securityContext
 			}
 
-			desiredVolumes := r.getDefaultReverseProxyVolumes(ts.Name)
+			desiredVolumes := r.getDefaultReverseProxyVolumes(&ts)
 			desiredVolumes = append(desiredVolumes, ts.Spec.Ingress.ReadOnlyRootFilesystem.Volumes...)
 
 			existingVolumes := deployment.Spec.Template.Spec.Volumes
@@ -212,7 +337,7 @@ func (r *TypesenseClusterReconciler) ReconcileIngress(ctx context.Context, ts ts
 				deployment.Spec.Template.Spec.Volumes = desiredVolumes
 			}
 
-			desiredMounts := r.getDefaultReverseProxyVolumeMounts()
+			desiredMounts := r.getDefaultReverseProxyVolumeMounts(&ts)
 			desiredMounts = append(desiredMounts, ts.Spec.Ingress.ReadOnlyRootFilesystem.VolumeMounts...)
 
 			existingMounts := deployment.Spec.Template.Spec.Containers[0].VolumeMounts
@@ -222,7 +347,21 @@ func (r *TypesenseClusterReconciler) ReconcileIngress(ctx context.Context, ts ts
 			}
 		}
 
-		if configMapUpdated || deploymentResourcesNeedUpdate || deploymentImageNeedUpdate || readOnlyRootFilesystemSpecsNeedUpdate {
+		podSpecNeedUpdate := syncReverseProxyPodSpec(deployment, &ts)
+		if r.syncReverseProxyAuthContainer(deployment, &ts) {
+			podSpecNeedUpdate = true
+		}
+
+		deploymentReplicasNeedUpdate := false
+		if ts.Spec.Ingress.GetReverseProxySpec().Autoscaling == nil {
+			desiredReplicas := ts.Spec.Ingress.GetReverseProxyReplicas()
+			if ptr.Deref(deployment.Spec.Replicas, 1) != desiredReplicas {
+				deploymentReplicasNeedUpdate = true
+				deployment.Spec.Replicas = ptr.To(desiredReplicas)
+			}
+		}
+
+		if configMapUpdated || deploymentResourcesNeedUpdate || deploymentImageNeedUpdate || readOnlyRootFilesystemSpecsNeedUpdate || podSpecNeedUpdate {
 			if deployment.Spec.Template.Annotations == nil {
 				deployment.Spec.Template.Annotations = make(map[string]string)
 			}
@@ -234,6 +373,13 @@ func (r *TypesenseClusterReconciler) ReconcileIngress(ctx context.Context, ts ts
 				r.logger.Error(err, "adding restart annotation to ingress reverse proxy deployment failed", "deployment", deploymentObjectKey.Name)
 				return err
 			}
+		} else if deploymentReplicasNeedUpdate {
+			r.logger.V(debugLevel).Info("scaling ingress reverse proxy deployment", "deployment", deploymentObjectKey.Name, "replicas", *deployment.Spec.Replicas)
+
+			if err := r.Update(ctx, deployment); err != nil {
+				r.logger.Error(err, "scaling ingress reverse proxy deployment failed", "deployment", deploymentObjectKey.Name)
+				return err
+			}
 		}
 	}
 
@@ -259,9 +405,18 @@ func (r *TypesenseClusterReconciler) ReconcileIngress(ctx context.Context, ts ts
 			r.logger.Error(err, "creating ingress reverse proxy service failed", "service", serviceNameObjectKey.Name)
 			return err
 		}
+	} else {
+		if err := r.updateIngressService(ctx, service, &ts); err != nil {
+			r.logger.Error(err, "updating ingress reverse proxy service failed", "service", serviceNameObjectKey.Name)
+			return err
+		}
 	}
 
-	return nil
+	if err := r.ReconcileReverseProxyAutoscaler(ctx, &ts, ig); err != nil {
+		return err
+	}
+
+	return r.ReconcileReverseProxyPodDisruptionBudget(ctx, &ts, ig)
 }
 
 func (r *TypesenseClusterReconciler) createIngress(ctx context.Context, key client.ObjectKey, ts *tsv1alpha1.TypesenseCluster) (*networkingv1.Ingress, error) {
@@ -269,30 +424,14 @@ func (r *TypesenseClusterReconciler) createIngress(ctx context.Context, key clie
 		return nil, fmt.Errorf("cluster issuer or tls secret name must be set, skipping ingress creation")
 	}
 
-	annotations := map[string]string{}
-	var tlsSecretName string
-
-	if ts.Spec.Ingress.ClusterIssuer != nil {
-		annotations["cert-manager.io/cluster-issuer"] = *ts.Spec.Ingress.ClusterIssuer
-		tlsSecretName = fmt.Sprintf("%s-reverse-proxy-%s-certificate-tls", ts.Name, *ts.Spec.Ingress.ClusterIssuer)
-	}
-
-	if ts.Spec.Ingress.Annotations != nil {
-		maps.Copy(annotations, ts.Spec.Ingress.Annotations)
-	}
-
-	if ts.Spec.Ingress.TLSSecretName != nil {
-		tlsSecretName = *ts.Spec.Ingress.TLSSecretName
-	}
-
 	ingress := &networkingv1.Ingress{
-		ObjectMeta: getObjectMeta(ts, &key.Name, annotations),
+		ObjectMeta: getObjectMeta(ts, &key.Name, r.getIngressAnnotations(ts)),
 		Spec: networkingv1.IngressSpec{
 			IngressClassName: ptr.To(ts.Spec.Ingress.IngressClassName),
 			TLS: []networkingv1.IngressTLS{
 				{
 					Hosts:      []string{ts.Spec.Ingress.Host},
-					SecretName: tlsSecretName,
+					SecretName: getIngressTLSSecretName(ts),
 				},
 			},
 			Rules: []networkingv1.IngressRule{
@@ -304,14 +443,7 @@ func (r *TypesenseClusterReconciler) createIngress(ctx context.Context, key clie
 								{
 									Path:     ts.Spec.Ingress.Path,
 									PathType: ts.Spec.Ingress.PathType,
-									Backend: networkingv1.IngressBackend{
-										Service: &networkingv1.IngressServiceBackend{
-											Name: fmt.Sprintf(ClusterReverseProxyService, ts.Name),
-											Port: networkingv1.ServiceBackendPort{
-												Number: 80,
-											},
-										},
-									},
+									Backend:  getIngressBackend(ts),
 								},
 							},
 						},
@@ -344,24 +476,9 @@ func (r *TypesenseClusterReconciler) updateIngress(ctx context.Context, ig netwo
 	ig.Spec.IngressClassName = ptr.To[string](ts.Spec.Ingress.IngressClassName)
 	ig.Spec.Rules[0].HTTP.Paths[0].Path = ts.Spec.Ingress.Path
 	ig.Spec.Rules[0].HTTP.Paths[0].PathType = ts.Spec.Ingress.PathType
-
-	annotations := map[string]string{}
-	var tlsSecretName string
-
-	if ts.Spec.Ingress.ClusterIssuer != nil {
-		annotations["cert-manager.io/cluster-issuer"] = *ts.Spec.Ingress.ClusterIssuer
-		tlsSecretName = fmt.Sprintf("%s-reverse-proxy-%s-certificate-tls", ts.Name, *ts.Spec.Ingress.ClusterIssuer)
-	}
-
-	if ts.Spec.Ingress.Annotations != nil {
-		maps.Copy(annotations, ts.Spec.Ingress.Annotations)
-	}
-	ig.Annotations = annotations
-
-	if ts.Spec.Ingress.TLSSecretName != nil {
-		tlsSecretName = *ts.Spec.Ingress.TLSSecretName
-	}
-	ig.Spec.TLS[0].SecretName = tlsSecretName
+	ig.Spec.Rules[0].HTTP.Paths[0].Backend = getIngressBackend(ts)
+	ig.Annotations = mergeOwnedIngressAnnotations(ts, r.getIngressAnnotations(ts), ig.Annotations)
+	ig.Spec.TLS[0].SecretName = getIngressTLSSecretName(ts)
 
 	if err := r.Patch(ctx, &ig, patch); err != nil {
 		return nil, err
@@ -379,20 +496,150 @@ func (r *TypesenseClusterReconciler) deleteIngress(ctx context.Context, ig *netw
 	return nil
 }
 
-func (r *TypesenseClusterReconciler) getIngressAnnotations(ig *networkingv1.Ingress) map[string]string {
-	annotations := make(map[string]string, len(ig.Annotations))
-	for k, v := range ig.Annotations {
-		annotations[k] = v
+// getIngressAnnotations returns the annotations of the ingress, the ones set in the spec take precedence over the
+// ones the operator generates. In Direct mode the checks of the reverse proxy are translated to ingress-nginx annotations,
+// other ingress controllers ignore them.
+func (r *TypesenseClusterReconciler) getIngressAnnotations(ts *tsv1alpha1.TypesenseCluster) map[string]string {
+	annotations := map[string]string{}
+
+	if ts.Spec.Ingress.ClusterIssuer != nil {
+		annotations[ingressCertManagerClusterIssuer] = *ts.Spec.Ingress.ClusterIssuer
+	}
+
+	if ts.Spec.Ingress.IsDirect() {
+		if ts.Spec.IsTLSEnabled() {
+			annotations[ingressNginxBackendProtocol] = "HTTPS"
+
+			// ingress-nginx only reads the CA from the ca.crt key of the secret
+			if ts.Spec.TLS.GetCAKey() == tlsCAFile {
+				annotations[ingressNginxProxySSLSecret] = fmt.Sprintf("%s/%s", ts.Namespace, getTLSSecretName(ts))
+				annotations[ingressNginxProxySSLVerify] = "on"
+				annotations[ingressNginxProxySSLName] = fmt.Sprintf(ClusterRestService, ts.Name)
+				annotations[ingressNginxProxySSLServerName] = "on"
+			}
+		}
+	}
+
+	maps.Copy(annotations, ts.Spec.Ingress.Annotations)
+	return annotations
+}
+
+// getOwnedIngressAnnotations returns the annotation keys the operator manages on the ingress, the rest belong to
+// other controllers and are left alone. The configuration snippet is still listed so that it gets removed from
+// ingresses created before Direct mode stopped emitting it.
+func getOwnedIngressAnnotations(ts *tsv1alpha1.TypesenseCluster) []string {
+	owned := []string{
+		ingressCertManagerClusterIssuer,
+		ingressNginxConfigurationSnippet,
+		ingressNginxBackendProtocol,
+		ingressNginxProxySSLSecret,
+		ingressNginxProxySSLVerify,
+		ingressNginxProxySSLName,
+		ingressNginxProxySSLServerName,
+	}
+	for key := range ts.Spec.Ingress.Annotations {
+		owned = append(owned, key)
+	}
+
+	return owned
+}
+
+func equalOwnedIngressAnnotations(ts *tsv1alpha1.TypesenseCluster, desired, current map[string]string) bool {
+	for _, key := range getOwnedIngressAnnotations(ts) {
+		desiredValue, desiredOk := desired[key]
+		currentValue, currentOk := current[key]
+		if desiredOk != currentOk || desiredValue != currentValue {
+			return false
+		}
+	}
+
+	return true
+}
+
+// mergeOwnedIngressAnnotations sets the desired annotations over the current ones and drops the owned ones that
+// are not desired anymore
+func mergeOwnedIngressAnnotations(ts *tsv1alpha1.TypesenseCluster, desired, current map[string]string) map[string]string {
+	annotations := maps.Clone(current)
+	if annotations == nil {
+		annotations = map[string]string{}
 	}
 
-	delete(annotations, "cert-manager.io/cluster-issuer")
-	if len(annotations) == 0 {
-		annotations = nil
+	for _, key := range getOwnedIngressAnnotations(ts) {
+		delete(annotations, key)
 	}
+	maps.Copy(annotations, desired)
 
 	return annotations
 }
 
+func getIngressTLSSecretName(ts *tsv1alpha1.TypesenseCluster) string {
+	if ts.Spec.Ingress.TLSSecretName != nil {
+		return *ts.Spec.Ingress.TLSSecretName
+	}
+
+	if ts.Spec.Ingress.ClusterIssuer != nil {
+		return fmt.Sprintf("%s-reverse-proxy-%s-certificate-tls", ts.Name, *ts.Spec.Ingress.ClusterIssuer)
+	}
+
+	return ""
+}
+
+// getIngressBackend returns the reverse proxy service, or the api service in Direct mode
+func getIngressBackend(ts *tsv1alpha1.TypesenseCluster) networkingv1.IngressBackend {
+	if ts.Spec.Ingress.IsDirect() {
+		return networkingv1.IngressBackend{
+			Service: &networkingv1.IngressServiceBackend{
+				Name: fmt.Sprintf(ClusterRestService, ts.Name),
+				Port: networkingv1.ServiceBackendPort{
+					Number: int32(ts.Spec.ApiPort),
+				},
+			},
+		}
+	}
+
+	return networkingv1.IngressBackend{
+		Service: &networkingv1.IngressServiceBackend{
+			Name: fmt.Sprintf(ClusterReverseProxyService, ts.Name),
+			Port: networkingv1.ServiceBackendPort{
+				Number: 80,
+			},
+		},
+	}
+}
+
+// deleteIngressReverseProxy removes the reverse proxy tier when the ingress switches to Direct mode
+func (r *TypesenseClusterReconciler) deleteIngressReverseProxy(ctx context.Context, ts *tsv1alpha1.TypesenseCluster, ig *networkingv1.Ingress) error {
+	objects := []client.Object{
+		&autoscalingv2.HorizontalPodAutoscaler{ObjectMeta: metav1.ObjectMeta{Namespace: ts.Namespace, Name: fmt.Sprintf(ClusterReverseProxyAutoscaler, ts.Name)}},
+		&policyv1.PodDisruptionBudget{ObjectMeta: metav1.ObjectMeta{Namespace: ts.Namespace, Name: fmt.Sprintf(ClusterReverseProxyPodDisruptionBudget, ts.Name)}},
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: ts.Namespace, Name: fmt.Sprintf(ClusterReverseProxy, ts.Name)}},
+		&v1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: ts.Namespace, Name: fmt.Sprintf(ClusterReverseProxyService, ts.Name)}},
+		&v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: ts.Namespace, Name: fmt.Sprintf(ClusterReverseProxyConfigMap, ts.Name)}},
+	}
+
+	for _, object := range objects {
+		name := object.GetName()
+		if err := r.Get(ctx, client.ObjectKeyFromObject(object), object); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+
+		if !metav1.IsControlledBy(object, ig) {
+			continue
+		}
+
+		r.logger.V(debugLevel).Info("deleting ingress reverse proxy object", "name", name)
+		if err := r.Delete(ctx, object); client.IgnoreNotFound(err) != nil {
+			r.logger.Error(err, "deleting ingress reverse proxy object failed", "name", name)
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (r *TypesenseClusterReconciler) createIngressConfigMap(ctx context.Context, key client.ObjectKey, ts *tsv1alpha1.TypesenseCluster, ig *networkingv1.Ingress) (*v1.ConfigMap, error) {
 	nginxConf, err := r.getIngressNginxConf(ts)
 	if err != nil {
@@ -476,6 +723,12 @@ func (r *TypesenseClusterReconciler) getIngressNginxConf(ts *tsv1alpha1.Typesens
 		Referer            string
 		ServiceName        string
 		ServicePort        string
+		Protocol           string
+		TrustedCertificate string
+		RateLimit          *rateLimitConf
+		Cache              *cacheConf
+		AuthPort           int
+		ApiKeyVariable     string
 	}{
 		HttpDirectives:     httpDirectives,
 		ServerDirectives:   serverDirectives,
@@ -483,6 +736,26 @@ func (r *TypesenseClusterReconciler) getIngressNginxConf(ts *tsv1alpha1.Typesens
 		Referer:            ref,
 		ServiceName:        ts.Name,
 		ServicePort:        strconv.Itoa(ts.Spec.ApiPort),
+		Protocol:           getApiProtocol(ts),
+		ApiKeyVariable:     "$http_x_typesense_api_key",
+	}
+
+	if ts.Spec.IsTLSEnabled() {
+		nginxConfData.TrustedCertificate = path.Join(tlsMountPath, tlsCAFile)
+	}
+
+	if ts.Spec.Ingress != nil && ts.Spec.Ingress.RateLimit != nil {
+		nginxConfData.RateLimit = getRateLimitConf(ts.Spec.Ingress.RateLimit)
+	}
+
+	if ts.Spec.Ingress != nil && ts.Spec.Ingress.Cache != nil {
+		nginxConfData.Cache = getCacheConf(ts.Spec.Ingress.Cache)
+	}
+
+	// the scoped key of the auth sidecar replaces the one of the client, requests are throttled per token instead
+	if ts.Spec.Ingress != nil && ts.Spec.Ingress.Auth != nil {
+		nginxConfData.AuthPort = reverseProxyAuthPort
+		nginxConfData.ApiKeyVariable = "$typesense_api_key"
 	}
 
 	tmpl, err := template.New("nginxConf").Parse(confTemplate)
@@ -502,34 +775,76 @@ func (r *TypesenseClusterReconciler) getIngressNginxConf(ts *tsv1alpha1.Typesens
 	return conf, nil
 }
 
-func (r *TypesenseClusterReconciler) getDefaultReverseProxyVolumes(tsClusterName string) []v1.Volume {
-	return []v1.Volume{
+// rateLimitConf is the spec.ingress.rateLimit rendered by the nginx conf template
+type rateLimitConf struct {
+	RequestsPerSecond    int32
+	Burst                int32
+	ApiKey               *tsv1alpha1.ApiKeyRateLimitSpec
+	ConnectionsPerClient int32
+	TrustedProxies       []string
+	ResponseBody         string
+}
+
+func getRateLimitConf(rateLimit *tsv1alpha1.RateLimitSpec) *rateLimitConf {
+	return &rateLimitConf{
+		RequestsPerSecond:    rateLimit.RequestsPerSecond,
+		Burst:                rateLimit.Burst,
+		ApiKey:               rateLimit.ApiKey,
+		ConnectionsPerClient: ptr.Deref(rateLimit.ConnectionsPerClient, 0),
+		TrustedProxies:       rateLimit.GetTrustedProxies(),
+		// the body is returned as a single quoted nginx string, which expands variables and has no escape for $,
+		// so a literal $ comes from a geo variable set to it
+		ResponseBody: strings.NewReplacer(`\`, `\\`, `'`, `\'`, `$`, `${rate_limit_dollar}`).Replace(rateLimit.GetResponseBody()),
+	}
+}
+
+// cacheConf is the spec.ingress.cache rendered by the nginx conf template
+type cacheConf struct {
+	Path    string
+	TTL     string
+	MaxSize int64
+}
+
+func getCacheConf(cache *tsv1alpha1.CacheSpec) *cacheConf {
+	maxSize := cache.GetMaxSize()
+
+	return &cacheConf{
+		Path: reverseProxyCacheMountPath,
+		TTL:  fmt.Sprintf("%ds", max(int64(cache.GetTTL().Seconds()), 1)),
+		// nginx evicts the least recently used responses above max_size, after the fact, so it is kept
+		// below the size limit of the emptyDir to not get the pod evicted
+		MaxSize: maxSize.Value() * 9 / 10,
+	}
+}
+
+func (r *TypesenseClusterReconciler) getDefaultReverseProxyVolumes(ts *tsv1alpha1.TypesenseCluster) []v1.Volume {
+	return append([]v1.Volume{
 		{
 			Name: "nginx-config",
 			VolumeSource: v1.VolumeSource{
 				ConfigMap: &v1.ConfigMapVolumeSource{
 					LocalObjectReference: v1.LocalObjectReference{
-						Name: fmt.Sprintf(ClusterReverseProxyConfigMap, tsClusterName),
+						Name: fmt.Sprintf(ClusterReverseProxyConfigMap, ts.Name),
 					},
 				},
 			},
 		},
-	}
+	}, slices.Concat(getTLSVolumes(ts, false), getReverseProxyCacheVolumes(ts), getReverseProxyAuthVolumes(ts))...)
 }
 
-func (r *TypesenseClusterReconciler) getDefaultReverseProxyVolumeMounts() []v1.VolumeMount {
-	return []v1.VolumeMount{
+func (r *TypesenseClusterReconciler) getDefaultReverseProxyVolumeMounts(ts *tsv1alpha1.TypesenseCluster) []v1.VolumeMount {
+	return append([]v1.VolumeMount{
 		{
 			Name:      "nginx-config",
 			MountPath: "/etc/nginx/nginx.conf",
 			SubPath:   "nginx.conf",
 		},
-	}
+	}, slices.Concat(getTLSVolumeMounts(ts), getReverseProxyCacheVolumeMounts(ts))...)
 }
 
 func (r *TypesenseClusterReconciler) createIngressDeployment(ctx context.Context, key client.ObjectKey, ts *tsv1alpha1.TypesenseCluster, ig *networkingv1.Ingress) (*appsv1.Deployment, error) {
-	volumes := r.getDefaultReverseProxyVolumes(ts.Name)
-	volumeMounts := r.getDefaultReverseProxyVolumeMounts()
+	volumes := r.getDefaultReverseProxyVolumes(ts)
+	volumeMounts := r.getDefaultReverseProxyVolumeMounts(ts)
 	var securityContext *v1.SecurityContext
 
 	if ts.Spec.Ingress.ReadOnlyRootFilesystem != nil {
@@ -546,10 +861,13 @@ func (r *TypesenseClusterReconciler) createIngressDeployment(ctx context.Context
 
 	}
 
+	reverseProxy := ts.Spec.Ingress.GetReverseProxySpec()
+	readinessProbe, livenessProbe := getReverseProxyProbes(ts)
+
 	deployment := &appsv1.Deployment{
 		ObjectMeta: getReverseProxyObjectMeta(ts, &key.Name, nil),
 		Spec: appsv1.DeploymentSpec{
-			Replicas: ptr.To[int32](3),
+			Replicas: ptr.To(getReverseProxyDeploymentReplicas(ts)),
 			Selector: &metav1.LabelSelector{
 				MatchLabels: getReverseProxyLabels(ts),
 			},
@@ -570,14 +888,23 @@ func (r *TypesenseClusterReconciler) createIngressDeployment(ctx context.Context
 							Resources:       ts.Spec.Ingress.GetReverseProxyResources(),
 							VolumeMounts:    volumeMounts,
 							SecurityContext: securityContext,
+							ReadinessProbe:  readinessProbe,
+							LivenessProbe:   livenessProbe,
 						},
 					},
-					Volumes: volumes,
+					Volumes:                   volumes,
+					Affinity:                  reverseProxy.Affinity,
+					Tolerations:               reverseProxy.Tolerations,
+					TopologySpreadConstraints: ts.Spec.Ingress.GetReverseProxyTopologySpreadConstraints(getReverseProxyLabels(ts)),
 				},
 			},
 		},
 	}
 
+	if auth := r.getReverseProxyAuthContainer(ts); auth != nil {
+		deployment.Spec.Template.Spec.Containers = append(deployment.Spec.Template.Spec.Containers, *auth)
+	}
+
 	if err := ctrl.SetControllerReference(ig, deployment, r.Scheme); err != nil {
 		return nil, err
 	}
@@ -593,7 +920,7 @@ func (r *TypesenseClusterReconciler) createIngressService(ctx context.Context, k
 	service := &v1.Service{
 		ObjectMeta: getReverseProxyObjectMeta(ts, &key.Name, nil),
 		Spec: v1.ServiceSpec{
-			Type:     v1.ServiceTypeNodePort,
+			Type:     ts.Spec.Ingress.GetReverseProxyServiceType(),
 			Selector: getReverseProxyLabels(ts),
 			Ports: []v1.ServicePort{
 				{
@@ -0,0 +1,448 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	tsv1alpha1 "github.com/akyriako/typesense-operator/api/v1alpha1"
+)
+
+var _ = Describe("TypesenseCluster Ingress", func() {
+	Context("When the ingress is in Direct mode", func() {
+		ctx := context.Background()
+
+		newCluster := func() *tsv1alpha1.TypesenseCluster {
+			return &tsv1alpha1.TypesenseCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster-1", Namespace: "search", UID: "uid-1"},
+				Spec: tsv1alpha1.TypesenseClusterSpec{
+					ApiPort: 8108,
+					Ingress: &tsv1alpha1.IngressSpec{
+						Host:             "search.example.com",
+						IngressClassName: "nginx",
+						TLSSecretName:    ptr.To("search-tls"),
+						Image:            "nginx:alpine",
+						Path:             "/",
+						PathType:         ptr.To(networkingv1.PathTypePrefix),
+					},
+				},
+			}
+		}
+
+		It("should route to the api service and remove the reverse proxy", func() {
+			s := runtime.NewScheme()
+			Expect(clientgoscheme.AddToScheme(s)).To(Succeed())
+			Expect(tsv1alpha1.AddToScheme(s)).To(Succeed())
+
+			ts := newCluster()
+			reconciler := &TypesenseClusterReconciler{
+				Client: fake.NewClientBuilder().WithScheme(s).WithObjects(ts).Build(),
+				Scheme: s,
+				logger: log.Log,
+			}
+
+			Expect(reconciler.ReconcileIngress(ctx, *ts)).To(Succeed())
+			deploymentKey := client.ObjectKey{Namespace: "search", Name: "cluster-1-reverse-proxy"}
+			Expect(reconciler.Get(ctx, deploymentKey, &appsv1.Deployment{})).To(Succeed())
+
+			ig := &networkingv1.Ingress{}
+			ingressKey := client.ObjectKey{Namespace: "search", Name: "cluster-1-reverse-proxy"}
+			Expect(reconciler.Get(ctx, ingressKey, ig)).To(Succeed())
+			ig.Annotations = map[string]string{
+				"external-dns.alpha.kubernetes.io/hostname": "search.example.com",
+				ingressNginxConfigurationSnippet:            "valid_referers server_names app.example.com;",
+			}
+			Expect(reconciler.Update(ctx, ig)).To(Succeed())
+
+			ts.Spec.Ingress.Mode = tsv1alpha1.IngressModeDirect
+			Expect(reconciler.ReconcileIngress(ctx, *ts)).To(Succeed())
+
+			Expect(reconciler.Get(ctx, ingressKey, ig)).To(Succeed())
+			Expect(ig.Spec.Rules[0].HTTP.Paths[0].Backend.Service.Name).To(Equal("cluster-1-svc"))
+			Expect(ig.Spec.Rules[0].HTTP.Paths[0].Backend.Service.Port.Number).To(Equal(int32(8108)))
+			Expect(ig.Annotations).To(HaveKeyWithValue("external-dns.alpha.kubernetes.io/hostname", "search.example.com"))
+			Expect(ig.Annotations).NotTo(HaveKey(ingressNginxConfigurationSnippet))
+
+			resourceVersion := ig.ResourceVersion
+			Expect(reconciler.ReconcileIngress(ctx, *ts)).To(Succeed())
+			Expect(reconciler.Get(ctx, ingressKey, ig)).To(Succeed())
+			Expect(ig.ResourceVersion).To(Equal(resourceVersion))
+
+			Expect(apierrors.IsNotFound(reconciler.Get(ctx, deploymentKey, &appsv1.Deployment{}))).To(BeTrue())
+			Expect(apierrors.IsNotFound(reconciler.Get(ctx, client.ObjectKey{Namespace: "search", Name: "cluster-1-reverse-proxy-svc"}, &corev1.Service{}))).To(BeTrue())
+			Expect(apierrors.IsNotFound(reconciler.Get(ctx, client.ObjectKey{Namespace: "search", Name: "cluster-1-reverse-proxy-config"}, &corev1.ConfigMap{}))).To(BeTrue())
+		})
+
+		It("should verify the api over tls and let the spec override the generated annotations", func() {
+			ts := newCluster()
+			ts.Spec.Ingress.Mode = tsv1alpha1.IngressModeDirect
+			ts.Spec.TLS = &tsv1alpha1.TLSSpec{SecretName: ptr.To("api-tls")}
+			ts.Spec.Ingress.Annotations = map[string]string{ingressNginxProxySSLVerify: "off"}
+
+			reconciler := &TypesenseClusterReconciler{logger: log.Log}
+			annotations := reconciler.getIngressAnnotations(ts)
+			Expect(annotations).To(HaveKeyWithValue(ingressNginxBackendProtocol, "HTTPS"))
+			Expect(annotations).To(HaveKeyWithValue(ingressNginxProxySSLSecret, "search/api-tls"))
+			Expect(annotations).To(HaveKeyWithValue(ingressNginxProxySSLName, "cluster-1-svc"))
+			Expect(annotations).To(HaveKeyWithValue(ingressNginxProxySSLVerify, "off"))
+
+			ts.Spec.TLS.CAKey = "root.pem"
+			Expect(reconciler.getIngressAnnotations(ts)).NotTo(HaveKey(ingressNginxProxySSLSecret))
+
+			ts.Spec.Ingress.Mode = tsv1alpha1.IngressModeReverseProxy
+			Expect(reconciler.getIngressAnnotations(ts)).To(Equal(map[string]string{ingressNginxProxySSLVerify: "off"}))
+		})
+	})
+
+	Context("When the ingress runs a reverse proxy", func() {
+		ctx := context.Background()
+		deploymentKey := client.ObjectKey{Namespace: "search", Name: "cluster-1-reverse-proxy"}
+		serviceKey := client.ObjectKey{Namespace: "search", Name: "cluster-1-reverse-proxy-svc"}
+		hpaKey := client.ObjectKey{Namespace: "search", Name: "cluster-1-reverse-proxy-hpa"}
+		pdbKey := client.ObjectKey{Namespace: "search", Name: "cluster-1-reverse-proxy-pdb"}
+
+		It("should scale, schedule and expose the reverse proxy in place", func() {
+			s := runtime.NewScheme()
+			Expect(clientgoscheme.AddToScheme(s)).To(Succeed())
+			Expect(tsv1alpha1.AddToScheme(s)).To(Succeed())
+
+			ts := &tsv1alpha1.TypesenseCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster-1", Namespace: "search", UID: "uid-1"},
+				Spec: tsv1alpha1.TypesenseClusterSpec{
+					ApiPort: 8108,
+					Ingress: &tsv1alpha1.IngressSpec{
+						Host:             "search.example.com",
+						IngressClassName: "nginx",
+						TLSSecretName:    ptr.To("search-tls"),
+						Image:            "nginx:alpine",
+						Path:             "/",
+						PathType:         ptr.To(networkingv1.PathTypePrefix),
+					},
+				},
+			}
+			reconciler := &TypesenseClusterReconciler{
+				Client: fake.NewClientBuilder().WithScheme(s).WithObjects(ts).Build(),
+				Scheme: s,
+				logger: log.Log,
+			}
+
+			Expect(reconciler.ReconcileIngress(ctx, *ts)).To(Succeed())
+
+			deployment := &appsv1.Deployment{}
+			Expect(reconciler.Get(ctx, deploymentKey, deployment)).To(Succeed())
+			Expect(*deployment.Spec.Replicas).To(Equal(int32(3)))
+			Expect(deployment.Spec.Template.Spec.Containers[0].ReadinessProbe.TCPSocket.Port.IntVal).To(Equal(int32(80)))
+			Expect(deployment.Spec.Template.Spec.Containers[0].LivenessProbe.PeriodSeconds).To(Equal(int32(10)))
+
+			service := &corev1.Service{}
+			Expect(reconciler.Get(ctx, serviceKey, service)).To(Succeed())
+			Expect(service.Spec.Type).To(Equal(corev1.ServiceTypeNodePort))
+
+			pdb := &policyv1.PodDisruptionBudget{}
+			Expect(reconciler.Get(ctx, pdbKey, pdb)).To(Succeed())
+			Expect(pdb.Spec.MaxUnavailable.IntVal).To(Equal(int32(1)))
+			Expect(pdb.Spec.Selector.MatchLabels).To(Equal(getReverseProxyLabels(ts)))
+
+			Expect(reconciler.ReconcileIngress(ctx, *ts)).To(Succeed())
+			Expect(reconciler.Get(ctx, deploymentKey, deployment)).To(Succeed())
+			Expect(deployment.Spec.Template.Annotations).NotTo(HaveKey("kubectl.kubernetes.io/restartedAt"))
+
+			ts.Spec.Ingress.ReverseProxy = &tsv1alpha1.ReverseProxySpec{
+				Replicas:            ptr.To[int32](5),
+				Tolerations:         []corev1.Toleration{{Key: "edge", Operator: corev1.TolerationOpExists}},
+				PodDisruptionBudget: &tsv1alpha1.ReverseProxyPodDisruptionBudgetSpec{MinAvailable: ptr.To(intstr.FromInt32(2))},
+				ServiceType:         corev1.ServiceTypeClusterIP,
+			}
+			Expect(reconciler.ReconcileIngress(ctx, *ts)).To(Succeed())
+
+			Expect(reconciler.Get(ctx, deploymentKey, deployment)).To(Succeed())
+			Expect(*deployment.Spec.Replicas).To(Equal(int32(5)))
+			Expect(deployment.Spec.Template.Spec.Tolerations).To(HaveLen(1))
+			Expect(deployment.Spec.Template.Annotations).To(HaveKey("kubectl.kubernetes.io/restartedAt"))
+
+			Expect(reconciler.Get(ctx, serviceKey, service)).To(Succeed())
+			Expect(service.Spec.Type).To(Equal(corev1.ServiceTypeClusterIP))
+
+			Expect(reconciler.Get(ctx, pdbKey, pdb)).To(Succeed())
+			Expect(pdb.Spec.MinAvailable.IntVal).To(Equal(int32(2)))
+			Expect(pdb.Spec.MaxUnavailable).To(BeNil())
+
+			ts.Spec.Ingress.ReverseProxy.Autoscaling = &tsv1alpha1.ReverseProxyAutoscalingSpec{MinReplicas: 2, MaxReplicas: 6}
+			Expect(reconciler.ReconcileIngress(ctx, *ts)).To(Succeed())
+
+			hpa := &autoscalingv2.HorizontalPodAutoscaler{}
+			Expect(reconciler.Get(ctx, hpaKey, hpa)).To(Succeed())
+			Expect(hpa.Spec.ScaleTargetRef.Name).To(Equal("cluster-1-reverse-proxy"))
+			Expect(*hpa.Spec.MinReplicas).To(Equal(int32(2)))
+			Expect(hpa.Spec.MaxReplicas).To(Equal(int32(6)))
+			Expect(*hpa.Spec.Metrics[0].Resource.Target.AverageUtilization).To(Equal(int32(80)))
+
+			ts.Spec.Ingress.ReverseProxy.Autoscaling = nil
+			Expect(reconciler.ReconcileIngress(ctx, *ts)).To(Succeed())
+			Expect(apierrors.IsNotFound(reconciler.Get(ctx, hpaKey, hpa))).To(BeTrue())
+		})
+	})
+
+	Context("When the reverse proxy is rate limited", func() {
+		ctx := context.Background()
+
+		It("should render the limits and restart the reverse proxy when they change", func() {
+			s := runtime.NewScheme()
+			Expect(clientgoscheme.AddToScheme(s)).To(Succeed())
+			Expect(tsv1alpha1.AddToScheme(s)).To(Succeed())
+
+			ts := &tsv1alpha1.TypesenseCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster-1", Namespace: "search", UID: "uid-1"},
+				Spec: tsv1alpha1.TypesenseClusterSpec{
+					ApiPort: 8108,
+					Ingress: &tsv1alpha1.IngressSpec{
+						Host:             "search.example.com",
+						IngressClassName: "nginx",
+						TLSSecretName:    ptr.To("search-tls"),
+						Image:            "nginx:alpine",
+						Path:             "/",
+						PathType:         ptr.To(networkingv1.PathTypePrefix),
+					},
+				},
+			}
+			reconciler := &TypesenseClusterReconciler{
+				Client: fake.NewClientBuilder().WithScheme(s).WithObjects(ts).Build(),
+				Scheme: s,
+				logger: log.Log,
+			}
+
+			Expect(reconciler.ReconcileIngress(ctx, *ts)).To(Succeed())
+
+			ts.Spec.Ingress.RateLimit = &tsv1alpha1.RateLimitSpec{
+				RequestsPerSecond:    20,
+				Burst:                40,
+				ApiKey:               &tsv1alpha1.ApiKeyRateLimitSpec{RequestsPerSecond: 100, Burst: 200},
+				ConnectionsPerClient: ptr.To[int32](10),
+				ResponseBody:         ptr.To(`{"message": "Slow down, it's busy.", "cost": "$1"}`),
+			}
+			conf, err := reconciler.getIngressNginxConf(ts)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(conf).To(ContainSubstring("set_real_ip_from 10.0.0.0/8;"))
+			Expect(conf).To(ContainSubstring("limit_req_zone $binary_remote_addr zone=requests_per_client:10m rate=20r/s;"))
+			Expect(conf).To(ContainSubstring("limit_req_zone $http_x_typesense_api_key zone=requests_per_api_key:10m rate=100r/s;"))
+			Expect(conf).To(ContainSubstring("limit_req zone=requests_per_client burst=40 nodelay;"))
+			Expect(conf).To(ContainSubstring("limit_req zone=requests_per_api_key burst=200 nodelay;"))
+			Expect(conf).To(ContainSubstring("limit_conn connections_per_client 10;"))
+			Expect(conf).To(ContainSubstring(`geo $rate_limit_dollar {`))
+			Expect(conf).To(ContainSubstring(`return 429 '{"message": "Slow down, it\'s busy.", "cost": "${rate_limit_dollar}1"}';`))
+
+			Expect(reconciler.ReconcileIngress(ctx, *ts)).To(Succeed())
+
+			cm := &corev1.ConfigMap{}
+			Expect(reconciler.Get(ctx, client.ObjectKey{Namespace: "search", Name: "cluster-1-reverse-proxy-config"}, cm)).To(Succeed())
+			Expect(cm.Data["nginx.conf"]).To(Equal(conf))
+
+			deployment := &appsv1.Deployment{}
+			Expect(reconciler.Get(ctx, client.ObjectKey{Namespace: "search", Name: "cluster-1-reverse-proxy"}, deployment)).To(Succeed())
+			Expect(deployment.Spec.Template.Annotations).To(HaveKey("kubectl.kubernetes.io/restartedAt"))
+
+			ts.Spec.Ingress.RateLimit = &tsv1alpha1.RateLimitSpec{RequestsPerSecond: 20, TrustedProxies: []string{"100.64.0.0/10"}}
+			conf, err = reconciler.getIngressNginxConf(ts)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(conf).To(ContainSubstring("set_real_ip_from 100.64.0.0/10;"))
+			Expect(conf).NotTo(ContainSubstring("10.0.0.0/8"))
+			Expect(conf).NotTo(ContainSubstring("requests_per_api_key"))
+			Expect(conf).NotTo(ContainSubstring("limit_conn "))
+			Expect(conf).To(ContainSubstring(`return 429 '{"message": "Too many requests."}';`))
+		})
+	})
+
+	Context("When the reverse proxy caches searches", func() {
+		ctx := context.Background()
+
+		It("should cache the search endpoints in an emptyDir that lives as long as its pod", func() {
+			s := runtime.NewScheme()
+			Expect(clientgoscheme.AddToScheme(s)).To(Succeed())
+			Expect(tsv1alpha1.AddToScheme(s)).To(Succeed())
+
+			ts := &tsv1alpha1.TypesenseCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster-1", Namespace: "search", UID: "uid-1"},
+				Spec: tsv1alpha1.TypesenseClusterSpec{
+					ApiPort: 8108,
+					Ingress: &tsv1alpha1.IngressSpec{
+						Host:             "search.example.com",
+						IngressClassName: "nginx",
+						TLSSecretName:    ptr.To("search-tls"),
+						Image:            "nginx:alpine",
+						Path:             "/",
+						PathType:         ptr.To(networkingv1.PathTypePrefix),
+						Cache: &tsv1alpha1.CacheSpec{
+							TTL:     &metav1.Duration{Duration: 30 * time.Second},
+							MaxSize: ptr.To(resource.MustParse("100Mi")),
+						},
+					},
+				},
+			}
+			reconciler := &TypesenseClusterReconciler{
+				Client: fake.NewClientBuilder().WithScheme(s).WithObjects(ts).Build(),
+				Scheme: s,
+				logger: log.Log,
+			}
+
+			conf, err := reconciler.getIngressNginxConf(ts)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(conf).To(ContainSubstring("proxy_cache_path /var/cache/typesense levels=1:2 keys_zone=search_cache:10m max_size=94371840 inactive=30s use_temp_path=off;"))
+			Expect(conf).To(ContainSubstring("location ~ ^/collections/[^/]+/documents/search$ {\n\t\t\t  proxy_pass http://cluster-1-svc:8108;"))
+			Expect(conf).To(ContainSubstring(`proxy_cache_key "$request_method$request_uri$http_x_typesense_api_key";`))
+			Expect(conf).To(ContainSubstring("location = /multi_search {"))
+			Expect(conf).To(ContainSubstring(`proxy_cache_key "$request_method$request_uri$http_x_typesense_api_key$request_body";`))
+			Expect(conf).To(ContainSubstring("proxy_cache_valid 200 30s;"))
+			Expect(conf).To(ContainSubstring("proxy_pass http://cluster-1-svc:8108/;"))
+
+			Expect(reconciler.ReconcileIngress(ctx, *ts)).To(Succeed())
+
+			deploymentKey := client.ObjectKey{Namespace: "search", Name: "cluster-1-reverse-proxy"}
+			deployment := &appsv1.Deployment{}
+			Expect(reconciler.Get(ctx, deploymentKey, deployment)).To(Succeed())
+			Expect(deployment.Spec.Template.Spec.Volumes).To(ContainElement(corev1.Volume{
+				Name: "nginx-cache",
+				VolumeSource: corev1.VolumeSource{
+					EmptyDir: &corev1.EmptyDirVolumeSource{SizeLimit: ptr.To(resource.MustParse("100Mi"))},
+				},
+			}))
+			Expect(deployment.Spec.Template.Spec.Containers[0].VolumeMounts).To(ContainElement(corev1.VolumeMount{Name: "nginx-cache", MountPath: "/var/cache/typesense"}))
+
+			ts.Spec.Ingress.Cache = nil
+			Expect(reconciler.ReconcileIngress(ctx, *ts)).To(Succeed())
+			Expect(reconciler.Get(ctx, deploymentKey, deployment)).To(Succeed())
+			Expect(deployment.Spec.Template.Spec.Volumes).NotTo(ContainElement(HaveField("Name", "nginx-cache")))
+		})
+	})
+
+	Context("When the reverse proxy authenticates requests", func() {
+		ctx := context.Background()
+
+		It("should run the auth sidecar next to nginx and swap the bearer token for its scoped key", func() {
+			s := runtime.NewScheme()
+			Expect(clientgoscheme.AddToScheme(s)).To(Succeed())
+			Expect(tsv1alpha1.AddToScheme(s)).To(Succeed())
+
+			ts := &tsv1alpha1.TypesenseCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster-1", Namespace: "search", UID: "uid-1"},
+				Spec: tsv1alpha1.TypesenseClusterSpec{
+					ApiPort: 8108,
+					Ingress: &tsv1alpha1.IngressSpec{
+						Host:             "search.example.com",
+						IngressClassName: "nginx",
+						TLSSecretName:    ptr.To("search-tls"),
+						Image:            "nginx:alpine",
+						Path:             "/",
+						PathType:         ptr.To(networkingv1.PathTypePrefix),
+						Cache:            &tsv1alpha1.CacheSpec{},
+						Auth: &tsv1alpha1.AuthSpec{
+							JWKS: tsv1alpha1.JWKSSpec{
+								SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "idp-jwks"}, Key: "keys"},
+							},
+							Issuer:             ptr.To("https://idp.example.com"),
+							Audiences:          []string{"search"},
+							ParentKeySecretRef: corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "search-key"}, Key: "key"},
+							Claims:             []tsv1alpha1.ClaimMappingSpec{{Claim: "tenant", Field: "tenant_id"}},
+						},
+					},
+				},
+			}
+			reconciler := &TypesenseClusterReconciler{
+				Client:    fake.NewClientBuilder().WithScheme(s).WithObjects(ts).Build(),
+				Scheme:    s,
+				logger:    log.Log,
+				AuthImage: "typesense-operator:test",
+			}
+
+			conf, err := reconciler.getIngressNginxConf(ts)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(conf).To(ContainSubstring("location = /_auth {\n\t\t\t  internal;\n\t\t\t  proxy_pass http://127.0.0.1:8089/auth;"))
+			Expect(conf).To(ContainSubstring("auth_request /_auth;"))
+			Expect(conf).To(ContainSubstring("proxy_set_header X-TYPESENSE-API-KEY $typesense_api_key;"))
+			Expect(conf).To(ContainSubstring(`proxy_cache_key "$request_method$request_uri$typesense_api_key";`))
+			Expect(conf).To(ContainSubstring("error_page 401 @unauthorized;"))
+
+			Expect(reconciler.ReconcileIngress(ctx, *ts)).To(Succeed())
+
+			deploymentKey := client.ObjectKey{Namespace: "search", Name: "cluster-1-reverse-proxy"}
+			deployment := &appsv1.Deployment{}
+			Expect(reconciler.Get(ctx, deploymentKey, deployment)).To(Succeed())
+			Expect(deployment.Spec.Template.Spec.Containers).To(HaveLen(2))
+
+			auth := deployment.Spec.Template.Spec.Containers[1]
+			Expect(auth.Name).To(Equal("cluster-1-reverse-proxy-auth"))
+			Expect(auth.Image).To(Equal("typesense-operator:test"))
+			Expect(auth.Command).To(Equal([]string{"/auth"}))
+			Expect(auth.Args).To(Equal([]string{
+				"--listen-address=127.0.0.1:8089",
+				"--health-probe-bind-address=:8090",
+				"--parent-key-file=/etc/typesense-auth/parent-key/key",
+				"--jwks-refresh-interval=10m0s",
+				"--jwks-file=/etc/typesense-auth/jwks/jwks.json",
+				"--issuer=https://idp.example.com",
+				"--audience=search",
+				"--claim=tenant=tenant_id",
+			}))
+			Expect(deployment.Spec.Template.Spec.Volumes).To(ContainElement(corev1.Volume{
+				Name: "typesense-auth-jwks",
+				VolumeSource: corev1.VolumeSource{
+					Secret: &corev1.SecretVolumeSource{SecretName: "idp-jwks", Items: []corev1.KeyToPath{{Key: "keys", Path: "jwks.json"}}},
+				},
+			}))
+			Expect(deployment.Spec.Template.Spec.Containers[0].VolumeMounts).NotTo(ContainElement(HaveField("Name", "typesense-auth-parent-key")))
+
+			Expect(reconciler.ReconcileIngress(ctx, *ts)).To(Succeed())
+			Expect(reconciler.Get(ctx, deploymentKey, deployment)).To(Succeed())
+			Expect(deployment.Spec.Template.Annotations).NotTo(HaveKey("kubectl.kubernetes.io/restartedAt"))
+
+			ts.Spec.Ingress.Auth.JWKS = tsv1alpha1.JWKSSpec{URL: ptr.To("https://idp.example.com/.well-known/jwks.json")}
+			Expect(reconciler.ReconcileIngress(ctx, *ts)).To(Succeed())
+			Expect(reconciler.Get(ctx, deploymentKey, deployment)).To(Succeed())
+			Expect(deployment.Spec.Template.Spec.Containers[1].Args).To(ContainElement("--jwks-url=https://idp.example.com/.well-known/jwks.json"))
+			Expect(deployment.Spec.Template.Spec.Volumes).NotTo(ContainElement(HaveField("Name", "typesense-auth-jwks")))
+			Expect(deployment.Spec.Template.Annotations).To(HaveKey("kubectl.kubernetes.io/restartedAt"))
+
+			ts.Spec.Ingress.Auth = nil
+			Expect(reconciler.ReconcileIngress(ctx, *ts)).To(Succeed())
+			Expect(reconciler.Get(ctx, deploymentKey, deployment)).To(Succeed())
+			Expect(deployment.Spec.Template.Spec.Containers).To(HaveLen(1))
+			Expect(deployment.Spec.Template.Spec.Volumes).NotTo(ContainElement(HaveField("Name", "typesense-auth-parent-key")))
+
+			conf, err = reconciler.getIngressNginxConf(ts)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(conf).NotTo(ContainSubstring("auth_request"))
+		})
+	})
+})
@@ -0,0 +1,156 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	tsv1alpha1 "github.com/akyriako/typesense-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const namespaceNameLabel = "kubernetes.io/metadata.name"
+
+func (r *TypesenseClusterReconciler) ReconcileNetworkPolicy(ctx context.Context, ts tsv1alpha1.TypesenseCluster) error {
+	r.logger.V(debugLevel).Info("reconciling network policy")
+
+	networkPolicyName := fmt.Sprintf(ClusterNetworkPolicy, ts.Name)
+	networkPolicyObjectKey := client.ObjectKey{Namespace: ts.Namespace, Name: networkPolicyName}
+
+	var networkPolicy = &networkingv1.NetworkPolicy{}
+	if err := r.Get(ctx, networkPolicyObjectKey, networkPolicy); err != nil {
+		if !apierrors.IsNotFound(err) {
+			r.logger.Error(err, fmt.Sprintf("unable to fetch network policy: %s", networkPolicyName))
+			return err
+		}
+
+		if ts.Spec.NetworkPolicy == nil {
+			return nil
+		}
+
+		r.logger.V(debugLevel).Info("creating network policy", "policy", networkPolicyObjectKey.Name)
+
+		desired := r.buildNetworkPolicy(networkPolicyObjectKey, &ts)
+		if err := ctrl.SetControllerReference(&ts, desired, r.Scheme); err != nil {
+			return err
+		}
+
+		if err := r.Create(ctx, desired); err != nil {
+			r.logger.Error(err, "creating network policy failed", "policy", networkPolicyObjectKey.Name)
+			return err
+		}
+
+		return nil
+	}
+
+	if !metav1.IsControlledBy(networkPolicy, &ts) {
+		if ts.Spec.NetworkPolicy == nil {
+			return nil
+		}
+		return fmt.Errorf("network policy %s is not controlled by %s", networkPolicyName, ts.Name)
+	}
+
+	if ts.Spec.NetworkPolicy == nil {
+		r.logger.V(debugLevel).Info("deleting network policy", "policy", networkPolicyObjectKey.Name)
+
+		if err := r.Delete(ctx, networkPolicy); err != nil && !apierrors.IsNotFound(err) {
+			r.logger.Error(err, "deleting network policy failed", "policy", networkPolicyObjectKey.Name)
+			return err
+		}
+
+		return nil
+	}
+
+	desired := r.buildNetworkPolicy(networkPolicyObjectKey, &ts)
+	if equality.Semantic.DeepEqual(networkPolicy.Spec, desired.Spec) {
+		return nil
+	}
+
+	r.logger.V(debugLevel).Info("updating network policy", "policy", networkPolicyObjectKey.Name)
+
+	networkPolicy.Spec = desired.Spec
+	if err := r.Update(ctx, networkPolicy); err != nil {
+		r.logger.Error(err, "updating network policy failed", "policy", networkPolicyObjectKey.Name)
+		return err
+	}
+
+	return nil
+}
+
+// buildNetworkPolicy isolates the nodes of the cluster: the peering port is only reachable by the other nodes,
+// the api by the operator, the reverse proxy, the jobs of the operator and the peers of spec.networkPolicy.apiClients,
+// and the metrics port by the namespace of Prometheus.
+func (r *TypesenseClusterReconciler) buildNetworkPolicy(key client.ObjectKey, ts *tsv1alpha1.TypesenseCluster) *networkingv1.NetworkPolicy {
+	spec := ts.Spec.NetworkPolicy
+
+	apiClients := []networkingv1.NetworkPolicyPeer{
+		r.getOperatorNetworkPolicyPeer(),
+		{PodSelector: &metav1.LabelSelector{MatchLabels: getReverseProxyLabels(ts)}},
+		{PodSelector: &metav1.LabelSelector{MatchLabels: getApiClientLabels(ts)}},
+	}
+	apiClients = append(apiClients, spec.ApiClients...)
+
+	return &networkingv1.NetworkPolicy{
+		ObjectMeta: getObjectMeta(ts, &key.Name, nil),
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: getLabels(ts)},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+			Ingress: []networkingv1.NetworkPolicyIngressRule{
+				{
+					From: []networkingv1.NetworkPolicyPeer{
+						{PodSelector: &metav1.LabelSelector{MatchLabels: getLabels(ts)}},
+					},
+					Ports: getNetworkPolicyPorts(ts.Spec.PeeringPort, ts.Spec.ApiPort, 8808),
+				},
+				{
+					From:  apiClients,
+					Ports: getNetworkPolicyPorts(ts.Spec.ApiPort, 8808),
+				},
+				{
+					From: []networkingv1.NetworkPolicyPeer{
+						{
+							NamespaceSelector: &metav1.LabelSelector{
+								MatchLabels: map[string]string{namespaceNameLabel: spec.GetPrometheusNamespace()},
+							},
+						},
+					},
+					Ports: getNetworkPolicyPorts(metricsPort),
+				},
+			},
+		},
+	}
+}
+
+// getOperatorNetworkPolicyPeer selects the pods of the operator, in any namespace if the operator runs out of cluster
+func (r *TypesenseClusterReconciler) getOperatorNetworkPolicyPeer() networkingv1.NetworkPolicyPeer {
+	namespaceSelector := &metav1.LabelSelector{}
+	if r.OperatorNamespace != "" {
+		namespaceSelector.MatchLabels = map[string]string{namespaceNameLabel: r.OperatorNamespace}
+	}
+
+	return networkingv1.NetworkPolicyPeer{
+		NamespaceSelector: namespaceSelector,
+		PodSelector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{"control-plane": "controller-manager"},
+		},
+	}
+}
+
+func getNetworkPolicyPorts(ports ...int) []networkingv1.NetworkPolicyPort {
+	policyPorts := make([]networkingv1.NetworkPolicyPort, 0, len(ports))
+	for _, port := range ports {
+		policyPorts = append(policyPorts, networkingv1.NetworkPolicyPort{
+			Protocol: ptr.To(corev1.ProtocolTCP),
+			Port:     ptr.To(intstr.FromInt32(int32(port))),
+		})
+	}
+
+	return policyPorts
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	tsv1alpha1 "github.com/akyriako/typesense-operator/api/v1alpha1"
+)
+
+var _ = Describe("TypesenseCluster NetworkPolicy", func() {
+	Context("When spec.networkPolicy is set", func() {
+		ctx := context.Background()
+		key := client.ObjectKey{Namespace: "search", Name: "cluster-1-network-policy"}
+
+		var reconciler *TypesenseClusterReconciler
+		var cluster *tsv1alpha1.TypesenseCluster
+
+		BeforeEach(func() {
+			s := runtime.NewScheme()
+			Expect(clientgoscheme.AddToScheme(s)).To(Succeed())
+			Expect(tsv1alpha1.AddToScheme(s)).To(Succeed())
+
+			cluster = &tsv1alpha1.TypesenseCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster-1", Namespace: "search", UID: "cluster-1-uid"},
+				Spec: tsv1alpha1.TypesenseClusterSpec{
+					ApiPort:     8108,
+					PeeringPort: 8107,
+					NetworkPolicy: &tsv1alpha1.NetworkPolicySpec{
+						ApiClients: []networkingv1.NetworkPolicyPeer{
+							{NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "search"}}},
+						},
+					},
+				},
+			}
+
+			reconciler = &TypesenseClusterReconciler{
+				Client:            fake.NewClientBuilder().WithScheme(s).WithObjects(cluster).Build(),
+				Scheme:            s,
+				logger:            log.Log,
+				OperatorNamespace: "typesense-operator-system",
+			}
+		})
+
+		It("should only admit the nodes to the peering port", func() {
+			Expect(reconciler.ReconcileNetworkPolicy(ctx, *cluster)).To(Succeed())
+
+			policy := &networkingv1.NetworkPolicy{}
+			Expect(reconciler.Get(ctx, key, policy)).To(Succeed())
+			Expect(policy.Spec.PodSelector.MatchLabels).To(Equal(map[string]string{"app": "cluster-1-sts"}))
+			Expect(policy.Spec.Ingress).To(HaveLen(3))
+
+			peering := policy.Spec.Ingress[0]
+			Expect(peering.From).To(HaveLen(1))
+			Expect(peering.From[0].PodSelector.MatchLabels).To(Equal(map[string]string{"app": "cluster-1-sts"}))
+			Expect(peering.Ports[0].Port).To(Equal(ptr.To(intstr.FromInt32(8107))))
+
+			api := policy.Spec.Ingress[1]
+			Expect(api.Ports[0].Port).To(Equal(ptr.To(intstr.FromInt32(8108))))
+			Expect(api.From).To(HaveLen(4))
+			Expect(api.From[0].NamespaceSelector.MatchLabels).To(Equal(map[string]string{namespaceNameLabel: "typesense-operator-system"}))
+			Expect(api.From[1].PodSelector.MatchLabels).To(Equal(map[string]string{"app": "cluster-1-rp"}))
+			Expect(api.From[2].PodSelector.MatchLabels).To(Equal(map[string]string{ClusterApiClientLabel: "cluster-1"}))
+			Expect(api.From[3].NamespaceSelector.MatchLabels).To(Equal(map[string]string{"team": "search"}))
+
+			metrics := policy.Spec.Ingress[2]
+			Expect(metrics.From[0].NamespaceSelector.MatchLabels).To(Equal(map[string]string{namespaceNameLabel: "monitoring"}))
+			Expect(metrics.Ports[0].Port).To(Equal(ptr.To(intstr.FromInt32(metricsPort))))
+		})
+
+		It("should follow the ports of the cluster and be deleted with spec.networkPolicy", func() {
+			Expect(reconciler.ReconcileNetworkPolicy(ctx, *cluster)).To(Succeed())
+
+			cluster.Spec.ApiPort = 9108
+			Expect(reconciler.ReconcileNetworkPolicy(ctx, *cluster)).To(Succeed())
+
+			policy := &networkingv1.NetworkPolicy{}
+			Expect(reconciler.Get(ctx, key, policy)).To(Succeed())
+			Expect(policy.Spec.Ingress[1].Ports[0].Port).To(Equal(ptr.To(intstr.FromInt32(9108))))
+
+			cluster.Spec.NetworkPolicy = nil
+			Expect(reconciler.ReconcileNetworkPolicy(ctx, *cluster)).To(Succeed())
+			Expect(apierrors.IsNotFound(reconciler.Get(ctx, key, policy))).To(BeTrue())
+		})
+	})
+})
@@ -41,6 +41,16 @@ func (r *TypesenseClusterReconciler) ReconcileQuorum(ctx context.Context, ts *ts
 		Timeout: 500 * time.Millisecond,
 	}
 
+	// nodes may be reached by ip, so the name verified is the one of the headless service
+	serverName := fmt.Sprintf("%s.%s.svc.cluster.local", fmt.Sprintf(ClusterHeadlessService, ts.Name), ts.Namespace)
+	transport, err := newTLSTransport(ctx, r.Client, ts, serverName)
+	if err != nil {
+		return ConditionReasonQuorumNotReady, 0, err
+	}
+	if transport != nil {
+		httpClient.Transport = transport
+	}
+
 	queuedWrites := 0
 	healthyWriteLagThreshold := r.getHealthyWriteLagThreshold(ctx, ts)
 
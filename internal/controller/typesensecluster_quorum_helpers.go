@@ -9,6 +9,7 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/utils/ptr"
 	"net"
 	"net/http"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -18,7 +19,7 @@ import (
 
 func (r *TypesenseClusterReconciler) getNodeStatus(ctx context.Context, httpClient *http.Client, node NodeEndpoint, ts *tsv1alpha1.TypesenseCluster, secret *v1.Secret) (NodeStatus, error) {
 	fqdn := r.getNodeEndpoint(ts, node.IP.String())
-	url := fmt.Sprintf("http://%s:%d/status", fqdn, ts.Spec.ApiPort)
+	url := fmt.Sprintf("%s://%s:%d/status", getApiProtocol(ts), fqdn, ts.Spec.ApiPort)
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
@@ -26,7 +27,7 @@ func (r *TypesenseClusterReconciler) getNodeStatus(ctx context.Context, httpClie
 		return NodeStatus{State: ErrorState}, nil
 	}
 
-	apiKey := secret.Data[ClusterAdminApiKeySecretKeyName]
+	apiKey := getAdminApiKey(ts, secret)
 	req.Header.Set("x-typesense-api-key", string(apiKey))
 
 	resp, err := httpClient.Do(req)
@@ -93,7 +94,7 @@ func (r *TypesenseClusterReconciler) getClusterStatus(nodesStatus map[string]Nod
 
 func (r *TypesenseClusterReconciler) getNodeHealth(ctx context.Context, httpClient *http.Client, node NodeEndpoint, ts *tsv1alpha1.TypesenseCluster) (NodeHealth, error) {
 	fqdn := r.getNodeEndpoint(ts, node.IP.String())
-	url := fmt.Sprintf("http://%s:%d/health", fqdn, ts.Spec.ApiPort)
+	url := fmt.Sprintf("%s://%s:%d/health", getApiProtocol(ts), fqdn, ts.Spec.ApiPort)
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
@@ -165,6 +166,10 @@ func getMinimumRequiredNodes(availableNodes int) int {
 }
 
 func (r *TypesenseClusterReconciler) getHealthyWriteLagThreshold(ctx context.Context, ts *tsv1alpha1.TypesenseCluster) int {
+	if thresholds := ts.Spec.HealthyLagThresholds; thresholds != nil && thresholds.Write != nil {
+		return *thresholds.Write
+	}
+
 	if ts.Spec.AdditionalServerConfiguration == nil {
 		return HealthyWriteLagDefaultValue
 	}
@@ -193,6 +198,10 @@ func (r *TypesenseClusterReconciler) getHealthyWriteLagThreshold(ctx context.Con
 }
 
 func (r *TypesenseClusterReconciler) getHealthyReadLagThreshold(ctx context.Context, ts *tsv1alpha1.TypesenseCluster) int {
+	if thresholds := ts.Spec.HealthyLagThresholds; thresholds != nil && thresholds.Read != nil {
+		return *thresholds.Read
+	}
+
 	if ts.Spec.AdditionalServerConfiguration == nil {
 		return HealthyReadLagDefaultValue
 	}
@@ -224,6 +233,14 @@ func (r *TypesenseClusterReconciler) getHealthyLagThresholds(ctx context.Context
 	read = HealthyReadLagDefaultValue
 	write = HealthyWriteLagDefaultValue
 
+	// the thresholds of the spec take precedence over the ones of the additional server configuration
+	defer func() {
+		if thresholds := ts.Spec.HealthyLagThresholds; thresholds != nil {
+			read = ptr.Deref(thresholds.Read, read)
+			write = ptr.Deref(thresholds.Write, write)
+		}
+	}()
+
 	if ts.Spec.AdditionalServerConfiguration == nil {
 		return
 	}
@@ -262,3 +279,21 @@ func (r *TypesenseClusterReconciler) getHealthyLagThresholds(ctx context.Context
 
 	return
 }
+
+// getHealthyLagEnv passes the thresholds of the spec to typesense, overriding the additional server configuration
+func getHealthyLagEnv(ts *tsv1alpha1.TypesenseCluster) []v1.EnvVar {
+	thresholds := ts.Spec.HealthyLagThresholds
+	if thresholds == nil {
+		return nil
+	}
+
+	env := make([]v1.EnvVar, 0, 2)
+	if thresholds.Read != nil {
+		env = append(env, v1.EnvVar{Name: HealthyReadLagKey, Value: strconv.Itoa(*thresholds.Read)})
+	}
+	if thresholds.Write != nil {
+		env = append(env, v1.EnvVar{Name: HealthyWriteLagKey, Value: strconv.Itoa(*thresholds.Write)})
+	}
+
+	return env
+}
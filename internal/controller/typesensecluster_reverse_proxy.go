@@ -0,0 +1,305 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	tsv1alpha1 "github.com/akyriako/typesense-operator/api/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	reverseProxyCacheVolumeName = "nginx-cache"
+	reverseProxyCacheMountPath  = "/var/cache/typesense"
+)
+
+// getReverseProxyDeploymentReplicas returns the replicas a new reverse proxy Deployment starts with
+func getReverseProxyDeploymentReplicas(ts *tsv1alpha1.TypesenseCluster) int32 {
+	if autoscaling := ts.Spec.Ingress.GetReverseProxySpec().Autoscaling; autoscaling != nil {
+		return max(autoscaling.MinReplicas, 1)
+	}
+
+	return ts.Spec.Ingress.GetReverseProxyReplicas()
+}
+
+// getReverseProxyProbes returns the readiness and liveness probes of the nginx container, with the defaults
+// of the api server filled in so that they compare equal to the ones stored in the Deployment
+func getReverseProxyProbes(ts *tsv1alpha1.TypesenseCluster) (*v1.Probe, *v1.Probe) {
+	reverseProxy := ts.Spec.Ingress.GetReverseProxySpec()
+
+	defaultProbe := &v1.Probe{
+		ProbeHandler: v1.ProbeHandler{
+			TCPSocket: &v1.TCPSocketAction{
+				Port: intstr.FromInt32(80),
+			},
+		},
+	}
+
+	readinessProbe, livenessProbe := defaultProbe, defaultProbe
+	if reverseProxy.ReadinessProbe != nil {
+		readinessProbe = reverseProxy.ReadinessProbe
+	}
+	if reverseProxy.LivenessProbe != nil {
+		livenessProbe = reverseProxy.LivenessProbe
+	}
+
+	return withProbeDefaults(readinessProbe), withProbeDefaults(livenessProbe)
+}
+
+func withProbeDefaults(probe *v1.Probe) *v1.Probe {
+	probe = probe.DeepCopy()
+
+	if probe.TimeoutSeconds == 0 {
+		probe.TimeoutSeconds = 1
+	}
+	if probe.PeriodSeconds == 0 {
+		probe.PeriodSeconds = 10
+	}
+	if probe.SuccessThreshold == 0 {
+		probe.SuccessThreshold = 1
+	}
+	if probe.FailureThreshold == 0 {
+		probe.FailureThreshold = 3
+	}
+	if probe.HTTPGet != nil && probe.HTTPGet.Scheme == "" {
+		probe.HTTPGet.Scheme = v1.URISchemeHTTP
+	}
+
+	return probe
+}
+
+// syncReverseProxyPodSpec copies the probes and the scheduling constraints of spec.ingress.reverseProxy to the
+// Deployment, and reports whether any of them changed
+func syncReverseProxyPodSpec(deployment *appsv1.Deployment, ts *tsv1alpha1.TypesenseCluster) bool {
+	reverseProxy := ts.Spec.Ingress.GetReverseProxySpec()
+	podSpec := &deployment.Spec.Template.Spec
+	readinessProbe, livenessProbe := getReverseProxyProbes(ts)
+	topologySpreadConstraints := ts.Spec.Ingress.GetReverseProxyTopologySpreadConstraints(getReverseProxyLabels(ts))
+
+	changed := false
+	if !equality.Semantic.DeepEqual(readinessProbe, podSpec.Containers[0].ReadinessProbe) {
+		podSpec.Containers[0].ReadinessProbe = readinessProbe
+		changed = true
+	}
+	if !equality.Semantic.DeepEqual(livenessProbe, podSpec.Containers[0].LivenessProbe) {
+		podSpec.Containers[0].LivenessProbe = livenessProbe
+		changed = true
+	}
+	if !equality.Semantic.DeepEqual(reverseProxy.Affinity, podSpec.Affinity) {
+		podSpec.Affinity = reverseProxy.Affinity
+		changed = true
+	}
+	if !equality.Semantic.DeepEqual(reverseProxy.Tolerations, podSpec.Tolerations) {
+		podSpec.Tolerations = reverseProxy.Tolerations
+		changed = true
+	}
+	if !equality.Semantic.DeepEqual(topologySpreadConstraints, podSpec.TopologySpreadConstraints) {
+		podSpec.TopologySpreadConstraints = topologySpreadConstraints
+		changed = true
+	}
+
+	return changed
+}
+
+func (r *TypesenseClusterReconciler) updateIngressService(ctx context.Context, service *v1.Service, ts *tsv1alpha1.TypesenseCluster) error {
+	serviceType := ts.Spec.Ingress.GetReverseProxyServiceType()
+	if service.Spec.Type == serviceType {
+		return nil
+	}
+
+	service.Spec.Type = serviceType
+	if serviceType == v1.ServiceTypeClusterIP {
+		for i := range service.Spec.Ports {
+			service.Spec.Ports[i].NodePort = 0
+		}
+	}
+
+	r.logger.V(debugLevel).Info("updating ingress reverse proxy service type", "service", service.Name, "type", serviceType)
+	return r.Update(ctx, service)
+}
+
+// ReconcileReverseProxyAutoscaler keeps a HorizontalPodAutoscaler scaling the reverse proxy on its cpu utilization
+// while spec.ingress.reverseProxy.autoscaling is set
+func (r *TypesenseClusterReconciler) ReconcileReverseProxyAutoscaler(ctx context.Context, ts *tsv1alpha1.TypesenseCluster, ig *networkingv1.Ingress) error {
+	key := client.ObjectKey{Namespace: ts.Namespace, Name: fmt.Sprintf(ClusterReverseProxyAutoscaler, ts.Name)}
+	autoscaling := ts.Spec.Ingress.GetReverseProxySpec().Autoscaling
+
+	hpa := &autoscalingv2.HorizontalPodAutoscaler{}
+	if err := r.Get(ctx, key, hpa); err != nil {
+		if !apierrors.IsNotFound(err) {
+			r.logger.Error(err, fmt.Sprintf("unable to fetch ingress reverse proxy autoscaler: %s", key.Name))
+			return err
+		}
+
+		if autoscaling == nil {
+			return nil
+		}
+
+		hpa = &autoscalingv2.HorizontalPodAutoscaler{
+			ObjectMeta: getReverseProxyObjectMeta(ts, &key.Name, nil),
+			Spec:       getReverseProxyAutoscalerSpec(ts, autoscaling),
+		}
+		if err := ctrl.SetControllerReference(ig, hpa, r.Scheme); err != nil {
+			return err
+		}
+
+		r.logger.V(debugLevel).Info("creating ingress reverse proxy autoscaler", "hpa", key.Name)
+		return r.Create(ctx, hpa)
+	}
+
+	if !metav1.IsControlledBy(hpa, ig) {
+		return nil
+	}
+
+	if autoscaling == nil {
+		r.logger.V(debugLevel).Info("deleting ingress reverse proxy autoscaler", "hpa", key.Name)
+		return client.IgnoreNotFound(r.Delete(ctx, hpa))
+	}
+
+	desired := getReverseProxyAutoscalerSpec(ts, autoscaling)
+	if equality.Semantic.DeepEqual(desired.ScaleTargetRef, hpa.Spec.ScaleTargetRef) &&
+		equality.Semantic.DeepEqual(desired.MinReplicas, hpa.Spec.MinReplicas) &&
+		desired.MaxReplicas == hpa.Spec.MaxReplicas &&
+		equality.Semantic.DeepEqual(desired.Metrics, hpa.Spec.Metrics) {
+		return nil
+	}
+
+	hpa.Spec.ScaleTargetRef = desired.ScaleTargetRef
+	hpa.Spec.MinReplicas = desired.MinReplicas
+	hpa.Spec.MaxReplicas = desired.MaxReplicas
+	hpa.Spec.Metrics = desired.Metrics
+
+	r.logger.V(debugLevel).Info("updating ingress reverse proxy autoscaler", "hpa", key.Name)
+	return r.Update(ctx, hpa)
+}
+
+func getReverseProxyAutoscalerSpec(ts *tsv1alpha1.TypesenseCluster, autoscaling *tsv1alpha1.ReverseProxyAutoscalingSpec) autoscalingv2.HorizontalPodAutoscalerSpec {
+	targetCPUUtilizationPercentage := autoscaling.TargetCPUUtilizationPercentage
+	if targetCPUUtilizationPercentage == 0 {
+		targetCPUUtilizationPercentage = 80
+	}
+
+	return autoscalingv2.HorizontalPodAutoscalerSpec{
+		ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{
+			APIVersion: appsv1.SchemeGroupVersion.String(),
+			Kind:       "Deployment",
+			Name:       fmt.Sprintf(ClusterReverseProxy, ts.Name),
+		},
+		MinReplicas: ptr.To(max(autoscaling.MinReplicas, 1)),
+		MaxReplicas: autoscaling.MaxReplicas,
+		Metrics: []autoscalingv2.MetricSpec{
+			{
+				Type: autoscalingv2.ResourceMetricSourceType,
+				Resource: &autoscalingv2.ResourceMetricSource{
+					Name: v1.ResourceCPU,
+					Target: autoscalingv2.MetricTarget{
+						Type:               autoscalingv2.UtilizationMetricType,
+						AverageUtilization: ptr.To(targetCPUUtilizationPercentage),
+					},
+				},
+			},
+		},
+	}
+}
+
+// ReconcileReverseProxyPodDisruptionBudget keeps a PodDisruptionBudget for the reverse proxy, allowing at most one
+// unavailable pod unless spec.ingress.reverseProxy.podDisruptionBudget says otherwise
+func (r *TypesenseClusterReconciler) ReconcileReverseProxyPodDisruptionBudget(ctx context.Context, ts *tsv1alpha1.TypesenseCluster, ig *networkingv1.Ingress) error {
+	key := client.ObjectKey{Namespace: ts.Namespace, Name: fmt.Sprintf(ClusterReverseProxyPodDisruptionBudget, ts.Name)}
+	desired := getReverseProxyPodDisruptionBudgetSpec(ts)
+
+	pdb := &policyv1.PodDisruptionBudget{}
+	if err := r.Get(ctx, key, pdb); err != nil {
+		if !apierrors.IsNotFound(err) {
+			r.logger.Error(err, fmt.Sprintf("unable to fetch ingress reverse proxy pod disruption budget: %s", key.Name))
+			return err
+		}
+
+		pdb = &policyv1.PodDisruptionBudget{
+			ObjectMeta: getReverseProxyObjectMeta(ts, &key.Name, nil),
+			Spec:       desired,
+		}
+		if err := ctrl.SetControllerReference(ig, pdb, r.Scheme); err != nil {
+			return err
+		}
+
+		r.logger.V(debugLevel).Info("creating ingress reverse proxy pod disruption budget", "pdb", key.Name)
+		return r.Create(ctx, pdb)
+	}
+
+	if !metav1.IsControlledBy(pdb, ig) {
+		return nil
+	}
+
+	if equality.Semantic.DeepEqual(desired.MinAvailable, pdb.Spec.MinAvailable) &&
+		equality.Semantic.DeepEqual(desired.MaxUnavailable, pdb.Spec.MaxUnavailable) &&
+		equality.Semantic.DeepEqual(desired.Selector, pdb.Spec.Selector) {
+		return nil
+	}
+
+	pdb.Spec.MinAvailable = desired.MinAvailable
+	pdb.Spec.MaxUnavailable = desired.MaxUnavailable
+	pdb.Spec.Selector = desired.Selector
+
+	r.logger.V(debugLevel).Info("updating ingress reverse proxy pod disruption budget", "pdb", key.Name)
+	return r.Update(ctx, pdb)
+}
+
+func getReverseProxyPodDisruptionBudgetSpec(ts *tsv1alpha1.TypesenseCluster) policyv1.PodDisruptionBudgetSpec {
+	spec := policyv1.PodDisruptionBudgetSpec{
+		Selector: &metav1.LabelSelector{
+			MatchLabels: getReverseProxyLabels(ts),
+		},
+	}
+
+	pdb := ts.Spec.Ingress.GetReverseProxySpec().PodDisruptionBudget
+	if pdb == nil || (pdb.MinAvailable == nil && pdb.MaxUnavailable == nil) {
+		spec.MaxUnavailable = ptr.To(intstr.FromInt32(1))
+		return spec
+	}
+
+	spec.MinAvailable = pdb.MinAvailable
+	spec.MaxUnavailable = pdb.MaxUnavailable
+	return spec
+}
+
+func getReverseProxyCacheVolumes(ts *tsv1alpha1.TypesenseCluster) []v1.Volume {
+	if ts.Spec.Ingress == nil || ts.Spec.Ingress.Cache == nil {
+		return nil
+	}
+
+	return []v1.Volume{
+		{
+			Name: reverseProxyCacheVolumeName,
+			VolumeSource: v1.VolumeSource{
+				EmptyDir: &v1.EmptyDirVolumeSource{
+					SizeLimit: ptr.To(ts.Spec.Ingress.Cache.GetMaxSize()),
+				},
+			},
+		},
+	}
+}
+
+func getReverseProxyCacheVolumeMounts(ts *tsv1alpha1.TypesenseCluster) []v1.VolumeMount {
+	if ts.Spec.Ingress == nil || ts.Spec.Ingress.Cache == nil {
+		return nil
+	}
+
+	return []v1.VolumeMount{
+		{
+			Name:      reverseProxyCacheVolumeName,
+			MountPath: reverseProxyCacheMountPath,
+		},
+	}
+}
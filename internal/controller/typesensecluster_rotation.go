@@ -0,0 +1,273 @@
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"time"
+
+	tsv1alpha1 "github.com/akyriako/typesense-operator/api/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const AdminApiKeyRotationAnnotation = "ts.opentelekomcloud.com/rotate-admin-api-key"
+
+// ReconcileAdminApiKeyRotation rolls the cluster onto a new admin api key, generated when the rotation annotation
+// changes or read from the Secret spec.adminApiKey now points to. Before the nodes restart, both the previous and
+// the new key are registered as api keys expiring after the overlap window, so that every node accepts both of them
+// while the StatefulSet rolls one pod at a time, and clients of the previous key keep working for a while after.
+func (r *TypesenseClusterReconciler) ReconcileAdminApiKeyRotation(ctx context.Context, ts *tsv1alpha1.TypesenseCluster, secret *v1.Secret) (*v1.Secret, error) {
+	r.logger.V(debugLevel).Info("reconciling admin api key rotation")
+
+	status := ts.Status.AdminApiKey
+	request := ts.Annotations[AdminApiKeyRotationAnnotation]
+
+	if len(getAdminApiKey(ts, secret)) == 0 {
+		// the Secret is not synced by the CSI driver yet, there is no key to compare with
+		return secret, nil
+	}
+
+	if status == nil {
+		// the key the cluster was bootstrapped with, or that of a cluster created before rotations
+		return secret, r.patchStatus(ctx, ts, func(status *tsv1alpha1.TypesenseClusterStatus) {
+			status.AdminApiKey = &tsv1alpha1.AdminApiKeyStatus{
+				SecretName:      secret.Name,
+				Fingerprint:     getAdminApiKeyFingerprint(getAdminApiKey(ts, secret)),
+				RotationRequest: request,
+			}
+		})
+	}
+
+	if status.OverlapExpirationTime != nil && time.Now().After(status.OverlapExpirationTime.Time) {
+		r.cleanupAdminApiKeyOverlap(ctx, ts)
+		status = ts.Status.AdminApiKey
+	}
+
+	if request != "" && request != status.RotationRequest {
+		r.logger.Info("rotating admin api key", "secret", secret.Name)
+
+		var err error
+		secret, err = r.regenerateAdminApiKey(ctx, ts, secret)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := r.patchStatus(ctx, ts, func(status *tsv1alpha1.TypesenseClusterStatus) {
+			status.AdminApiKey.RotationRequest = request
+		}); err != nil {
+			return nil, err
+		}
+		status = ts.Status.AdminApiKey
+	}
+
+	fingerprint := getAdminApiKeyFingerprint(getAdminApiKey(ts, secret))
+	if fingerprint == status.Fingerprint {
+		if secret.Name == status.SecretName {
+			return secret, nil
+		}
+
+		// another Secret holding the very same key, there is nothing to roll
+		return secret, r.patchStatus(ctx, ts, func(status *tsv1alpha1.TypesenseClusterStatus) {
+			status.AdminApiKey.SecretName = secret.Name
+		})
+	}
+
+	expiresAt := time.Now().Add(ts.Spec.GetAdminApiKeyRotationOverlap())
+	overlapKeyIDs := status.OverlapKeyIDs
+
+	previous, err := r.getPreviousAdminApiKey(ctx, ts)
+	if err != nil {
+		return nil, err
+	}
+
+	if previous == "" || !meta.IsStatusConditionTrue(ts.Status.Conditions, ConditionTypeReady) {
+		// without the previous key or a ready quorum nothing can be registered, the nodes just restart on the new key
+		r.Recorder.Event(ts, "Warning", ConditionReasonAdminApiKeyRotationFailed, "Rotating Admin Api Key Without An Overlap Window")
+	} else {
+		// the nodes are still running with the previous key
+		tc := newTypesenseClient(getClusterApiUrl(ts), previous)
+		if err := setTypesenseClientTLS(ctx, r.Client, ts, tc); err != nil {
+			return nil, err
+		}
+
+		ids, err := createAdminApiKeyOverlap(ctx, tc, previous, string(getAdminApiKey(ts, secret)), expiresAt)
+		if err != nil {
+			return nil, err
+		}
+		overlapKeyIDs = append(overlapKeyIDs, ids...)
+	}
+
+	if err := r.patchStatus(ctx, ts, func(status *tsv1alpha1.TypesenseClusterStatus) {
+		status.AdminApiKey.SecretName = secret.Name
+		status.AdminApiKey.Fingerprint = fingerprint
+		status.AdminApiKey.LastRotationTime = ptr.To(metav1.Now())
+		status.AdminApiKey.OverlapKeyIDs = overlapKeyIDs
+		status.AdminApiKey.OverlapExpirationTime = ptr.To(metav1.NewTime(expiresAt))
+	}); err != nil {
+		return nil, err
+	}
+
+	r.Recorder.Event(ts, "Normal", "AdminApiKeyRotated", "Admin Api Key Rotated, Rolling Cluster Nodes")
+	return secret, nil
+}
+
+// regenerateAdminApiKey writes a new key to the admin api key Secret, after keeping the one the nodes are running with.
+// The Secret created by the operator is immutable so it is replaced, one referenced by spec.adminApiKey is updated in place.
+func (r *TypesenseClusterReconciler) regenerateAdminApiKey(ctx context.Context, ts *tsv1alpha1.TypesenseCluster, secret *v1.Secret) (*v1.Secret, error) {
+	if ts.Spec.AdminApiKey != nil && ts.Spec.AdminApiKey.CSI != nil {
+		return nil, fmt.Errorf("admin api key secret %s is synced by a csi driver, rotate the key in the secret store", secret.Name)
+	}
+
+	owned := metav1.IsControlledBy(secret, ts)
+	if ptr.Deref(secret.Immutable, false) && !owned {
+		return nil, fmt.Errorf("admin api key secret %s is immutable, point spec.adminApiKey to a new Secret to rotate it", secret.Name)
+	}
+
+	if getAdminApiKeyFingerprint(getAdminApiKey(ts, secret)) == ts.Status.AdminApiKey.Fingerprint {
+		if err := r.savePreviousAdminApiKey(ctx, ts, getAdminApiKey(ts, secret)); err != nil {
+			return nil, err
+		}
+	}
+
+	if ptr.Deref(secret.Immutable, false) {
+		if err := r.Delete(ctx, secret); err != nil && !apierrors.IsNotFound(err) {
+			return nil, err
+		}
+
+		return r.createAdminApiKey(ctx, client.ObjectKeyFromObject(secret), ts)
+	}
+
+	token, err := generateToken()
+	if err != nil {
+		return nil, err
+	}
+
+	secret.Data[ts.Spec.GetAdminApiKeySecretKey()] = []byte(token)
+	if err := r.Update(ctx, secret); err != nil {
+		return nil, err
+	}
+
+	return secret, nil
+}
+
+func (r *TypesenseClusterReconciler) savePreviousAdminApiKey(ctx context.Context, ts *tsv1alpha1.TypesenseCluster, key []byte) error {
+	name := fmt.Sprintf(ClusterPreviousAdminApiKeySecret, ts.Name)
+
+	previous := &v1.Secret{}
+	if err := r.Get(ctx, client.ObjectKey{Namespace: ts.Namespace, Name: name}, previous); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+
+		previous = &v1.Secret{
+			ObjectMeta: getObjectMeta(ts, &name, nil),
+			Type:       v1.SecretTypeOpaque,
+			Data: map[string][]byte{
+				ClusterAdminApiKeySecretKeyName: key,
+			},
+		}
+		if err := ctrl.SetControllerReference(ts, previous, r.Scheme); err != nil {
+			return err
+		}
+
+		return r.Create(ctx, previous)
+	}
+
+	previous.Data = map[string][]byte{
+		ClusterAdminApiKeySecretKeyName: key,
+	}
+	return r.Update(ctx, previous)
+}
+
+// getPreviousAdminApiKey returns the key the nodes are running with, kept by a rotation of the operator or still in
+// the Secret spec.adminApiKey pointed to before. It is empty if neither holds it anymore.
+func (r *TypesenseClusterReconciler) getPreviousAdminApiKey(ctx context.Context, ts *tsv1alpha1.TypesenseCluster) (string, error) {
+	// the previous key is kept by the operator under the default key, the referenced Secret holds it under spec.adminApiKey.key
+	for name, key := range map[string]string{
+		fmt.Sprintf(ClusterPreviousAdminApiKeySecret, ts.Name): ClusterAdminApiKeySecretKeyName,
+		ts.Status.AdminApiKey.SecretName:                       ts.Spec.GetAdminApiKeySecretKey(),
+	} {
+		secret := &v1.Secret{}
+		if err := r.Get(ctx, client.ObjectKey{Namespace: ts.Namespace, Name: name}, secret); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return "", err
+		}
+
+		if getAdminApiKeyFingerprint(secret.Data[key]) == ts.Status.AdminApiKey.Fingerprint {
+			return string(secret.Data[key]), nil
+		}
+	}
+
+	return "", nil
+}
+
+// createAdminApiKeyOverlap registers the new key, so the nodes that have not restarted yet accept it,
+// and the previous one, so it stays valid once every node has restarted; both expire with the overlap window.
+func createAdminApiKeyOverlap(ctx context.Context, tc *typesenseClient, previous string, next string, expiresAt time.Time) ([]int64, error) {
+	ids := make([]int64, 0, 2)
+	for _, key := range []struct {
+		description string
+		value       string
+	}{
+		{description: "admin api key rotation: new key", value: next},
+		{description: "admin api key rotation: previous key", value: previous},
+	} {
+		payload := typesenseApiKey{
+			Description: key.description,
+			Actions:     []string{"*"},
+			Collections: []string{"*"},
+			ExpiresAt:   expiresAt.Unix(),
+			Value:       key.value,
+		}
+
+		var created typesenseApiKey
+		if err := tc.post(ctx, "/keys", payload, &created); err != nil {
+			return ids, err
+		}
+		ids = append(ids, created.ID)
+	}
+
+	return ids, nil
+}
+
+// cleanupAdminApiKeyOverlap deletes the expired api keys of the last rotation and the previous key. Failures are
+// only logged, the cleanup is retried on the next reconciliation.
+func (r *TypesenseClusterReconciler) cleanupAdminApiKeyOverlap(ctx context.Context, ts *tsv1alpha1.TypesenseCluster) {
+	tc, err := newTypesenseClientForCluster(ctx, r.Client, ts)
+	if err != nil {
+		r.logger.Error(err, "cleaning up admin api key overlap failed")
+		return
+	}
+
+	for _, id := range ts.Status.AdminApiKey.OverlapKeyIDs {
+		if err := tc.delete(ctx, fmt.Sprintf("/keys/%d", id)); err != nil && !isTypesenseNotFound(err) {
+			r.logger.Error(err, "deleting admin api key overlap failed", "id", id)
+			return
+		}
+	}
+
+	previous := &v1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: ts.Namespace, Name: fmt.Sprintf(ClusterPreviousAdminApiKeySecret, ts.Name)}}
+	if err := r.Delete(ctx, previous); err != nil && !apierrors.IsNotFound(err) {
+		r.logger.Error(err, "deleting previous admin api key failed", "secret", previous.Name)
+		return
+	}
+
+	if err := r.patchStatus(ctx, ts, func(status *tsv1alpha1.TypesenseClusterStatus) {
+		status.AdminApiKey.OverlapKeyIDs = nil
+		status.AdminApiKey.OverlapExpirationTime = nil
+	}); err != nil {
+		r.logger.Error(err, "cleaning up admin api key overlap failed")
+	}
+}
+
+func getAdminApiKeyFingerprint(apiKey []byte) string {
+	return fmt.Sprintf("%x", sha256.Sum256(apiKey))
+}
@@ -0,0 +1,245 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	tsv1alpha1 "github.com/akyriako/typesense-operator/api/v1alpha1"
+)
+
+var _ = Describe("TypesenseCluster Admin Api Key Rotation", func() {
+	Context("When rotating the admin api key", func() {
+		ctx := context.Background()
+
+		var (
+			reconciler *TypesenseClusterReconciler
+			cluster    *tsv1alpha1.TypesenseCluster
+		)
+
+		getSecret := func(name string) *corev1.Secret {
+			secret := &corev1.Secret{}
+			Expect(reconciler.Get(ctx, client.ObjectKey{Namespace: "default", Name: name}, secret)).To(Succeed())
+			return secret
+		}
+
+		BeforeEach(func() {
+			s := runtime.NewScheme()
+			Expect(clientgoscheme.AddToScheme(s)).To(Succeed())
+			Expect(tsv1alpha1.AddToScheme(s)).To(Succeed())
+
+			cluster = &tsv1alpha1.TypesenseCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster-1", Namespace: "default", UID: "cluster-1-uid"},
+			}
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster-1-admin-key", Namespace: "default"},
+				Immutable:  ptr.To(true),
+				Data:       map[string][]byte{ClusterAdminApiKeySecretKeyName: []byte("first")},
+			}
+			Expect(ctrl.SetControllerReference(cluster, secret, s)).To(Succeed())
+
+			reconciler = &TypesenseClusterReconciler{
+				Client: fake.NewClientBuilder().
+					WithScheme(s).
+					WithObjects(cluster, secret).
+					WithStatusSubresource(cluster).
+					Build(),
+				Scheme:   s,
+				logger:   log.Log,
+				Recorder: record.NewFakeRecorder(10),
+			}
+		})
+
+		It("should generate a new key and keep the previous one", func() {
+			secret, err := reconciler.ReconcileAdminApiKeyRotation(ctx, cluster, getSecret("cluster-1-admin-key"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cluster.Status.AdminApiKey).NotTo(BeNil())
+			Expect(cluster.Status.AdminApiKey.Fingerprint).To(Equal(getAdminApiKeyFingerprint(secret.Data[ClusterAdminApiKeySecretKeyName])))
+			Expect(cluster.Status.AdminApiKey.LastRotationTime).To(BeNil())
+
+			cluster.Annotations = map[string]string{AdminApiKeyRotationAnnotation: "2026-10-18"}
+			secret, err = reconciler.ReconcileAdminApiKeyRotation(ctx, cluster, secret)
+			Expect(err).NotTo(HaveOccurred())
+
+			rotated := getSecret("cluster-1-admin-key")
+			Expect(string(rotated.Data[ClusterAdminApiKeySecretKeyName])).NotTo(Equal("first"))
+			Expect(rotated.Data).To(Equal(secret.Data))
+			Expect(string(getSecret("cluster-1-admin-key-previous").Data[ClusterAdminApiKeySecretKeyName])).To(Equal("first"))
+
+			status := cluster.Status.AdminApiKey
+			Expect(status.RotationRequest).To(Equal("2026-10-18"))
+			Expect(status.Fingerprint).To(Equal(getAdminApiKeyFingerprint(rotated.Data[ClusterAdminApiKeySecretKeyName])))
+			Expect(status.LastRotationTime).NotTo(BeNil())
+			Expect(status.OverlapExpirationTime).NotTo(BeNil())
+
+			// the cluster was not ready, so there is no overlap window
+			Expect(status.OverlapKeyIDs).To(BeEmpty())
+			Expect(reconciler.Recorder.(*record.FakeRecorder).Events).To(Receive(ContainSubstring(ConditionReasonAdminApiKeyRotationFailed)))
+
+			// handled requests are not rotated twice
+			_, err = reconciler.ReconcileAdminApiKeyRotation(ctx, cluster, rotated)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(getSecret("cluster-1-admin-key").Data).To(Equal(rotated.Data))
+		})
+
+		It("should follow spec.adminApiKey to another Secret", func() {
+			secret, err := reconciler.ReconcileAdminApiKeyRotation(ctx, cluster, getSecret("cluster-1-admin-key"))
+			Expect(err).NotTo(HaveOccurred())
+
+			other := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "bootstrap-key", Namespace: "default"},
+				Data:       map[string][]byte{ClusterAdminApiKeySecretKeyName: []byte("second")},
+			}
+			Expect(reconciler.Create(ctx, other)).To(Succeed())
+			meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{Type: ConditionTypeReady, Status: metav1.ConditionFalse, Reason: "QuorumNotReady"})
+
+			_, err = reconciler.ReconcileAdminApiKeyRotation(ctx, cluster, other)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cluster.Status.AdminApiKey.SecretName).To(Equal("bootstrap-key"))
+			Expect(cluster.Status.AdminApiKey.Fingerprint).To(Equal(getAdminApiKeyFingerprint(other.Data[ClusterAdminApiKeySecretKeyName])))
+			Expect(cluster.Status.AdminApiKey.Fingerprint).NotTo(Equal(getAdminApiKeyFingerprint(secret.Data[ClusterAdminApiKeySecretKeyName])))
+		})
+
+		It("should wait for a Secret synced by a csi driver and roll onto its changed key", func() {
+			cluster.Spec.AdminApiKey = &tsv1alpha1.AdminApiKeySpec{
+				Name: "typesense-admin",
+				Key:  "api-key",
+				CSI:  &tsv1alpha1.AdminApiKeyCSISpec{VolumeAttributes: map[string]string{"secretProviderClass": "typesense"}},
+			}
+			Expect(reconciler.Update(ctx, cluster)).To(Succeed())
+
+			// the operator reads the key from the synced Secret, so the class has to sync it
+			spc := &unstructured.Unstructured{Object: map[string]any{
+				"metadata": map[string]any{"name": "typesense", "namespace": "default"},
+				"spec": map[string]any{
+					"provider": "vault",
+					"secretObjects": []any{
+						map[string]any{"secretName": "typesense-admin", "type": "Opaque", "data": []any{
+							map[string]any{"objectName": "typesense-admin-key", "key": "other-key"},
+						}},
+					},
+				},
+			}}
+			spc.SetGroupVersionKind(secretProviderClassGVK)
+			Expect(reconciler.Create(ctx, spc)).To(Succeed())
+
+			_, err := reconciler.ReconcileSecret(ctx, *cluster)
+			Expect(err).To(MatchError(ContainSubstring("secretproviderclass typesense must sync key api-key to secret typesense-admin")))
+
+			Expect(unstructured.SetNestedSlice(spc.Object, []any{
+				map[string]any{"secretName": "typesense-admin", "type": "Opaque", "data": []any{
+					map[string]any{"objectName": "typesense-admin-key", "key": "api-key"},
+				}},
+			}, "spec", "secretObjects")).To(Succeed())
+			Expect(reconciler.Update(ctx, spc)).To(Succeed())
+
+			secret, err := reconciler.ReconcileSecret(ctx, *cluster)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(secret.Name).To(Equal("typesense-admin"))
+
+			_, err = reconciler.ReconcileAdminApiKeyRotation(ctx, cluster, secret)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cluster.Status.AdminApiKey).To(BeNil())
+
+			synced := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "typesense-admin", Namespace: "default"},
+				Data:       map[string][]byte{"api-key": []byte("first")},
+			}
+			Expect(reconciler.Create(ctx, synced)).To(Succeed())
+
+			_, err = reconciler.ReconcileAdminApiKeyRotation(ctx, cluster, getSecret("typesense-admin"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cluster.Status.AdminApiKey.Fingerprint).To(Equal(getAdminApiKeyFingerprint([]byte("first"))))
+
+			synced.Data["api-key"] = []byte("second")
+			Expect(reconciler.Update(ctx, synced)).To(Succeed())
+
+			_, err = reconciler.ReconcileAdminApiKeyRotation(ctx, cluster, getSecret("typesense-admin"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cluster.Status.AdminApiKey.Fingerprint).To(Equal(getAdminApiKeyFingerprint([]byte("second"))))
+			Expect(cluster.Status.AdminApiKey.LastRotationTime).NotTo(BeNil())
+
+			cluster.Annotations = map[string]string{AdminApiKeyRotationAnnotation: "2026-10-18"}
+			_, err = reconciler.ReconcileAdminApiKeyRotation(ctx, cluster, getSecret("typesense-admin"))
+			Expect(err).To(MatchError(ContainSubstring("rotate the key in the secret store")))
+		})
+
+		It("should refuse to regenerate an immutable Secret it does not own", func() {
+			other := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "bootstrap-key", Namespace: "default"},
+				Immutable:  ptr.To(true),
+				Data:       map[string][]byte{ClusterAdminApiKeySecretKeyName: []byte("second")},
+			}
+			Expect(reconciler.Create(ctx, other)).To(Succeed())
+
+			_, err := reconciler.ReconcileAdminApiKeyRotation(ctx, cluster, other)
+			Expect(err).NotTo(HaveOccurred())
+
+			cluster.Annotations = map[string]string{AdminApiKeyRotationAnnotation: "now"}
+			_, err = reconciler.ReconcileAdminApiKeyRotation(ctx, cluster, other)
+			Expect(err).To(MatchError(ContainSubstring("secret bootstrap-key is immutable")))
+		})
+
+		It("should register both keys until the overlap window expires", func() {
+			var created []typesenseApiKey
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				Expect(req.URL.Path).To(Equal("/keys"))
+				Expect(req.Header.Get("x-typesense-api-key")).To(Equal("first"))
+
+				var key typesenseApiKey
+				Expect(json.NewDecoder(req.Body).Decode(&key)).To(Succeed())
+				key.ID = int64(len(created) + 1)
+				created = append(created, key)
+				_ = json.NewEncoder(w).Encode(key)
+			}))
+			defer server.Close()
+
+			expiresAt := time.Now().Add(15 * time.Minute)
+			ids, err := createAdminApiKeyOverlap(ctx, newTypesenseClient(server.URL, "first"), "first", "second", expiresAt)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ids).To(Equal([]int64{1, 2}))
+
+			Expect(created).To(HaveLen(2))
+			Expect(created[0].Value).To(Equal("second"))
+			Expect(created[1].Value).To(Equal("first"))
+			for _, key := range created {
+				Expect(key.Actions).To(Equal([]string{"*"}))
+				Expect(key.Collections).To(Equal([]string{"*"}))
+				Expect(key.ExpiresAt).To(Equal(expiresAt.Unix()))
+			}
+		})
+	})
+})
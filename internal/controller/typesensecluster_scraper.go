@@ -10,6 +10,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/ptr"
+	"path"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"strconv"
@@ -81,12 +82,21 @@ func (r *TypesenseClusterReconciler) ReconcileScraper(ctx context.Context, ts ts
 			container := scraperCronJob.Spec.JobTemplate.Spec.Template.Spec.Containers[0]
 
 			for _, env := range container.Env {
-				if env.Name == "CONFIG" && env.Value != scraper.Config {
+				if (env.Name == "CONFIG" && env.Value != scraper.Config) ||
+					(env.Name == "TYPESENSE_PROTOCOL" && env.Value != getApiProtocol(&ts)) ||
+					(env.Name == "TYPESENSE_API_KEY" && env.ValueFrom != nil && env.ValueFrom.SecretKeyRef != nil &&
+						(env.ValueFrom.SecretKeyRef.Name != r.getAdminApiKeyObjectKey(&ts).Name ||
+							env.ValueFrom.SecretKeyRef.Key != ts.Spec.GetAdminApiKeySecretKey())) {
 					hasChangedConfig = true
 					break
 				}
 			}
 
+			podLabels := scraperCronJob.Spec.JobTemplate.Spec.Template.Labels
+			if podLabels[ClusterApiClientLabel] != ts.Name {
+				hasChangedConfig = true
+			}
+
 			if scraperCronJob.Spec.Schedule != scraper.Schedule || container.Image != scraper.Image || hasChangedConfig {
 				hasChanged = true
 			}
@@ -128,13 +138,14 @@ func (r *TypesenseClusterReconciler) createScraper(ctx context.Context, key clie
 				Spec: batchv1.JobSpec{
 					BackoffLimit: ptr.To[int32](0),
 					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{Labels: getApiClientLabels(ts)},
 						Spec: corev1.PodSpec{
 							RestartPolicy: corev1.RestartPolicyNever,
 							Containers: []corev1.Container{
 								{
 									Name:  fmt.Sprintf(ClusterScraperCronJobContainer, scraperSpec.Name),
 									Image: scraperSpec.Image,
-									Env: []corev1.EnvVar{
+									Env: append([]corev1.EnvVar{
 										{
 											Name:  "CONFIG",
 											Value: scraperSpec.Config,
@@ -143,7 +154,7 @@ func (r *TypesenseClusterReconciler) createScraper(ctx context.Context, key clie
 											Name: "TYPESENSE_API_KEY",
 											ValueFrom: &corev1.EnvVarSource{
 												SecretKeyRef: &corev1.SecretKeySelector{
-													Key: ClusterAdminApiKeySecretKeyName,
+													Key: ts.Spec.GetAdminApiKeySecretKey(),
 													LocalObjectReference: corev1.LocalObjectReference{
 														Name: r.getAdminApiKeyObjectKey(ts).Name,
 													},
@@ -160,9 +171,9 @@ func (r *TypesenseClusterReconciler) createScraper(ctx context.Context, key clie
 										},
 										{
 											Name:  "TYPESENSE_PROTOCOL",
-											Value: "http",
+											Value: getApiProtocol(ts),
 										},
-									},
+									}, r.getScraperTLSEnv(ts)...),
 									EnvFrom: scraperSpec.GetScraperAuthConfiguration(),
 									Resources: corev1.ResourceRequirements{
 										Limits: corev1.ResourceList{
@@ -174,8 +185,11 @@ func (r *TypesenseClusterReconciler) createScraper(ctx context.Context, key clie
 											corev1.ResourceMemory: resource.MustParse("112Mi"),
 										},
 									},
+									VolumeMounts: r.getScraperTLSVolumeMounts(ts),
 								},
 							},
+							InitContainers: getTLSCABundleInitContainer(ts, r.JobImage),
+							Volumes:        r.getScraperTLSVolumes(ts),
 						},
 					},
 				},
@@ -196,6 +210,48 @@ func (r *TypesenseClusterReconciler) createScraper(ctx context.Context, key clie
 	return nil
 }
 
+// getScraperTLSEnv points the scraper to a bundle of the system CAs and the CA of the api certificate,
+// as it crawls public sites besides pushing to Typesense.
+func (r *TypesenseClusterReconciler) getScraperTLSEnv(ts *tsv1alpha1.TypesenseCluster) []corev1.EnvVar {
+	if !ts.Spec.IsTLSEnabled() {
+		return nil
+	}
+
+	return []corev1.EnvVar{
+		{
+			Name:  "REQUESTS_CA_BUNDLE",
+			Value: path.Join(tlsCABundleMount, tlsCABundleFile),
+		},
+	}
+}
+
+func (r *TypesenseClusterReconciler) getScraperTLSVolumes(ts *tsv1alpha1.TypesenseCluster) []corev1.Volume {
+	if !ts.Spec.IsTLSEnabled() {
+		return nil
+	}
+
+	return append(getTLSVolumes(ts, false), corev1.Volume{
+		Name: tlsCABundleVolume,
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
+		},
+	})
+}
+
+func (r *TypesenseClusterReconciler) getScraperTLSVolumeMounts(ts *tsv1alpha1.TypesenseCluster) []corev1.VolumeMount {
+	if !ts.Spec.IsTLSEnabled() {
+		return nil
+	}
+
+	return []corev1.VolumeMount{
+		{
+			Name:      tlsCABundleVolume,
+			MountPath: tlsCABundleMount,
+			ReadOnly:  true,
+		},
+	}
+}
+
 func (r *TypesenseClusterReconciler) deleteScraper(ctx context.Context, scraper *batchv1.CronJob) error {
 	err := r.Delete(ctx, scraper)
 	if err != nil {
@@ -6,21 +6,44 @@ import (
 	tsv1alpha1 "github.com/akyriako/typesense-operator/api/v1alpha1"
 	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+const (
+	adminApiKeyVolumeName = "admin-api-key"
+	adminApiKeyMountPath  = "/etc/typesense/admin-api-key"
+	adminApiKeyCSIDriver  = "secrets-store.csi.k8s.io"
+
+	secretProviderClassAttribute = "secretProviderClass"
+)
+
+var secretProviderClassGVK = schema.GroupVersionKind{Group: "secrets-store.csi.x-k8s.io", Version: "v1", Kind: "SecretProviderClass"}
+
 func (r *TypesenseClusterReconciler) ReconcileSecret(ctx context.Context, ts tsv1alpha1.TypesenseCluster) (*v1.Secret, error) {
 	r.logger.V(debugLevel).Info("reconciling secret")
 
 	secretExists := true
 	secretObjectKey := r.getAdminApiKeyObjectKey(&ts)
 
+	if ts.Spec.AdminApiKey != nil && ts.Spec.AdminApiKey.CSI != nil {
+		if err := r.validateSecretProviderClass(ctx, &ts); err != nil {
+			return nil, err
+		}
+	}
+
 	var secret = &v1.Secret{}
 	if err := r.Get(ctx, secretObjectKey, secret); err != nil {
 		if apierrors.IsNotFound(err) && ts.Spec.AdminApiKey == nil {
 			secretExists = false
+		} else if apierrors.IsNotFound(err) && ts.Spec.AdminApiKey.CSI != nil {
+			// the CSI driver syncs the Secret once the first pod mounts the volume, until then the key is unknown
+			r.logger.Info("waiting for the csi driver to sync the admin api key", "secret", secretObjectKey)
+			return &v1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: secretObjectKey.Namespace, Name: secretObjectKey.Name}}, nil
 		} else {
 			r.logger.Error(err, fmt.Sprintf("unable to fetch secret: %s", secretObjectKey))
 			return secret, err
@@ -72,7 +95,50 @@ func (r *TypesenseClusterReconciler) createAdminApiKey(
 	return secret, nil
 }
 
+// validateSecretProviderClass makes sure the SecretProviderClass of the CSI volume syncs the admin api key to its
+// Secret with secretObjects. The operator does not mount the volume, so that Secret is the only place it can read
+// the key from; a class that does not sync it would leave the cluster waiting for the Secret forever.
+func (r *TypesenseClusterReconciler) validateSecretProviderClass(ctx context.Context, ts *tsv1alpha1.TypesenseCluster) error {
+	csi := ts.Spec.AdminApiKey.CSI
+	if csi.Driver != "" && csi.Driver != adminApiKeyCSIDriver {
+		return nil
+	}
+
+	name := csi.VolumeAttributes[secretProviderClassAttribute]
+	if name == "" {
+		return fmt.Errorf("volumeAttributes of the admin api key csi volume must set %s", secretProviderClassAttribute)
+	}
+
+	spc := &unstructured.Unstructured{}
+	spc.SetGroupVersionKind(secretProviderClassGVK)
+	if err := r.Get(ctx, client.ObjectKey{Namespace: ts.Namespace, Name: name}, spc); err != nil {
+		return fmt.Errorf("unable to fetch secretproviderclass %s: %w", name, err)
+	}
+
+	secretName, key := ts.Spec.AdminApiKey.Name, ts.Spec.GetAdminApiKeySecretKey()
+	secretObjects, _, _ := unstructured.NestedSlice(spc.Object, "spec", "secretObjects")
+	for _, secretObject := range secretObjects {
+		object, ok := secretObject.(map[string]any)
+		if !ok || object["secretName"] != secretName {
+			continue
+		}
+
+		data, _, _ := unstructured.NestedSlice(object, "data")
+		for _, entry := range data {
+			if entry, ok := entry.(map[string]any); ok && entry["key"] == key {
+				return nil
+			}
+		}
+	}
+
+	return fmt.Errorf("secretproviderclass %s must sync key %s to secret %s with its secretObjects", name, key, secretName)
+}
+
 func (r *TypesenseClusterReconciler) getAdminApiKeyObjectKey(ts *tsv1alpha1.TypesenseCluster) client.ObjectKey {
+	return adminApiKeyObjectKey(ts)
+}
+
+func adminApiKeyObjectKey(ts *tsv1alpha1.TypesenseCluster) client.ObjectKey {
 	if ts.Spec.AdminApiKey != nil {
 		return client.ObjectKey{
 			Namespace: ts.Namespace,
@@ -85,3 +151,48 @@ func (r *TypesenseClusterReconciler) getAdminApiKeyObjectKey(ts *tsv1alpha1.Type
 		Name:      fmt.Sprintf(ClusterAdminApiKeySecret, ts.Name),
 	}
 }
+
+// getAdminApiKey returns the admin api key held in the Secret under the key spec.adminApiKey sets
+func getAdminApiKey(ts *tsv1alpha1.TypesenseCluster, secret *v1.Secret) []byte {
+	return secret.Data[ts.Spec.GetAdminApiKeySecretKey()]
+}
+
+// getAdminApiKeyVolumes returns the secrets store CSI volume of the admin api key, mounting it makes the driver
+// fetch the key and sync it to the Secret the containers read it from
+func getAdminApiKeyVolumes(ts *tsv1alpha1.TypesenseCluster) []v1.Volume {
+	if ts.Spec.AdminApiKey == nil || ts.Spec.AdminApiKey.CSI == nil {
+		return nil
+	}
+
+	driver := ts.Spec.AdminApiKey.CSI.Driver
+	if driver == "" {
+		driver = adminApiKeyCSIDriver
+	}
+
+	return []v1.Volume{
+		{
+			Name: adminApiKeyVolumeName,
+			VolumeSource: v1.VolumeSource{
+				CSI: &v1.CSIVolumeSource{
+					Driver:           driver,
+					ReadOnly:         ptr.To(true),
+					VolumeAttributes: ts.Spec.AdminApiKey.CSI.VolumeAttributes,
+				},
+			},
+		},
+	}
+}
+
+func getAdminApiKeyVolumeMounts(ts *tsv1alpha1.TypesenseCluster) []v1.VolumeMount {
+	if ts.Spec.AdminApiKey == nil || ts.Spec.AdminApiKey.CSI == nil {
+		return nil
+	}
+
+	return []v1.VolumeMount{
+		{
+			Name:      adminApiKeyVolumeName,
+			MountPath: adminApiKeyMountPath,
+			ReadOnly:  true,
+		},
+	}
+}
@@ -0,0 +1,362 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	tsv1alpha1 "github.com/akyriako/typesense-operator/api/v1alpha1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"strconv"
+	"strings"
+)
+
+const (
+	SeedPhasePending   = "Pending"
+	SeedPhaseSeeding   = "Seeding"
+	SeedPhaseCompleted = "Completed"
+
+	ConditionReasonSeedFailed    = "SeedFailed"
+	ConditionReasonSeedCompleted = "SeedCompleted"
+)
+
+// seedScript imports every JSONL file given to seed() in batches of BATCH_SIZE lines, retrying only the lines that
+// failed (their position in the import response matches the one in the batch). Blank lines are skipped, and the
+// batches are spilled to the scratch volume instead of memory.
+const seedScript = `set -euo pipefail
+base="${TYPESENSE_PROTOCOL}://${TYPESENSE_HOST}:${TYPESENSE_PORT}"
+
+seed() {
+  collection="$1"
+  file="$2"
+  rm -f /tmp/batch_*
+  { grep -v '^[[:space:]]*$' "${file}" || true; } | split -l "${BATCH_SIZE}" - /tmp/batch_
+
+  for batch in /tmp/batch_*; do
+    attempt=0
+    while [ -s "${batch}" ]; do
+      curl -sSf -X POST -H "X-TYPESENSE-API-KEY: ${TYPESENSE_API_KEY}" -H "Content-Type: text/plain" \
+        --data-binary @"${batch}" "${base}/collections/${collection}/documents/import?action=upsert" > /tmp/results
+      failed="$(grep -vn '"success":true' /tmp/results | cut -d: -f1 | tr '\n' ' ' || true)"
+      awk -v failed="${failed}" 'BEGIN { n = split(failed, lines, " "); for (i = 1; i <= n; i++) retry[lines[i]] } FNR in retry' "${batch}" > "${batch}.retry"
+      mv "${batch}.retry" "${batch}"
+
+      if [ -s "${batch}" ]; then
+        attempt=$((attempt + 1))
+        if [ "${attempt}" -gt "${MAX_RETRIES}" ]; then
+          echo "$(wc -l < "${batch}") documents of ${file} failed to import into ${collection}:"
+          grep -v '"success":true' /tmp/results | head -n 1
+          exit 1
+        fi
+        sleep "${attempt}"
+      fi
+    done
+  done
+}
+`
+
+// ReconcileSeed imports spec.seed once the quorum is ready for the first time. The seed is armed only when the cluster
+// is bootstrapped, adding spec.seed to an existing cluster has no effect. Collections that already exist are left
+// untouched, and documents are upserted by a Job so that a seed interrupted halfway can safely start over.
+func (r *TypesenseClusterReconciler) ReconcileSeed(ctx context.Context, ts *tsv1alpha1.TypesenseCluster) error {
+	seed := ts.Spec.Seed
+	if seed == nil || ts.Status.Seed == nil || ts.Status.Seed.Phase == SeedPhaseCompleted {
+		return nil
+	}
+
+	tc, err := newTypesenseClientForCluster(ctx, r.Client, ts)
+	if err != nil {
+		return err
+	}
+
+	jobKey := client.ObjectKey{Namespace: ts.Namespace, Name: fmt.Sprintf(ClusterSeedJob, ts.Name)}
+	job := &batchv1.Job{}
+	if err := r.Get(ctx, jobKey, job); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+
+		if ts.Status.Seed.Phase == SeedPhasePending {
+			r.logger.Info("seeding cluster", "collections", len(seed.Collections))
+			err := r.patchStatus(ctx, ts, func(status *tsv1alpha1.TypesenseClusterStatus) {
+				status.Seed = &tsv1alpha1.SeedStatus{Phase: SeedPhaseSeeding}
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		if err := r.seedCollections(ctx, tc, ts); err != nil {
+			if perr := r.setSeedMessage(ctx, ts, err.Error()); perr != nil {
+				return perr
+			}
+			return err
+		}
+
+		r.logger.V(debugLevel).Info("creating seed job", "job", jobKey.Name)
+		job = r.buildSeedJob(jobKey, ts)
+		if err := ctrl.SetControllerReference(ts, job, r.Scheme); err != nil {
+			return err
+		}
+
+		if err := r.Create(ctx, job); err != nil {
+			return err
+		}
+
+		return r.setSeedMessage(ctx, ts, fmt.Sprintf("waiting for job %s to import the documents", jobKey.Name))
+	}
+
+	completed, err := isSeedJobCompleted(job)
+	if err != nil {
+		// dropping the job starts the seed over in the next reconciliation loop
+		if derr := r.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground)); derr != nil && !apierrors.IsNotFound(derr) {
+			return derr
+		}
+		if perr := r.setSeedMessage(ctx, ts, err.Error()); perr != nil {
+			return perr
+		}
+		return err
+	}
+
+	if !completed {
+		return nil
+	}
+
+	var documents int64
+	for _, collection := range seed.Collections {
+		live, err := tc.getCollection(ctx, collection.Name)
+		if err != nil {
+			return err
+		}
+		documents += live.NumDocuments
+	}
+
+	err = r.patchStatus(ctx, ts, func(status *tsv1alpha1.TypesenseClusterStatus) {
+		status.Seed = &tsv1alpha1.SeedStatus{
+			Phase:          SeedPhaseCompleted,
+			Documents:      documents,
+			CompletionTime: ptr.To(metav1.Now()),
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	r.logger.Info("seeding cluster completed", "documents", documents)
+	r.Recorder.Event(ts, "Normal", ConditionReasonSeedCompleted, fmt.Sprintf("Seeded %d documents", documents))
+	return nil
+}
+
+// seedCollections creates the seed collections that do not exist yet, their documents are imported by the seed Job.
+func (r *TypesenseClusterReconciler) seedCollections(ctx context.Context, tc *typesenseClient, ts *tsv1alpha1.TypesenseCluster) error {
+	for _, collection := range ts.Spec.Seed.Collections {
+		_, err := tc.getCollection(ctx, collection.Name)
+		if err == nil {
+			continue
+		}
+		if !isTypesenseNotFound(err) {
+			return err
+		}
+
+		r.logger.V(debugLevel).Info("creating seed collection", "collection", collection.Name)
+		err = tc.post(ctx, "/collections", typesenseCollection{
+			Name:                collection.Name,
+			Fields:              buildTypesenseCollectionFields(collection.Fields),
+			DefaultSortingField: ptr.Deref(collection.DefaultSortingField, ""),
+		}, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *TypesenseClusterReconciler) setSeedMessage(ctx context.Context, ts *tsv1alpha1.TypesenseCluster, message string) error {
+	return r.patchStatus(ctx, ts, func(status *tsv1alpha1.TypesenseClusterStatus) {
+		status.Seed = &tsv1alpha1.SeedStatus{Phase: SeedPhaseSeeding, Message: message}
+	})
+}
+
+func isSeedJobCompleted(job *batchv1.Job) (bool, error) {
+	for _, condition := range job.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+
+		switch condition.Type {
+		case batchv1.JobComplete:
+			return true, nil
+		case batchv1.JobFailed:
+			return false, fmt.Errorf("seed job %s failed: %s", job.Name, condition.Message)
+		}
+	}
+
+	return false, nil
+}
+
+func (r *TypesenseClusterReconciler) buildSeedJob(key client.ObjectKey, ts *tsv1alpha1.TypesenseCluster) *batchv1.Job {
+	seed := ts.Spec.Seed
+	image := seed.Image
+	if image == "" {
+		image = r.JobImage
+	}
+
+	batchSize := seed.BatchSize
+	if batchSize < 1 {
+		batchSize = 100
+	}
+
+	script := seedScript
+	volumes := []corev1.Volume{
+		{
+			Name: "scratch",
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{},
+			},
+		},
+	}
+	volumeMounts := []corev1.VolumeMount{
+		{
+			Name:      "scratch",
+			MountPath: "/tmp",
+		},
+	}
+
+	// urls are downloaded to the scratch volume by an init container, whose curl trusts the system CAs rather
+	// than only the CA of the api certificate, so that public sites can still be reached when TLS is enabled
+	var env []corev1.EnvVar
+	downloadScript := "set -eu\n"
+	for _, collection := range seed.Collections {
+		for _, source := range collection.Documents {
+			name := fmt.Sprintf("seed-%d", len(volumes)-1+len(env))
+
+			switch {
+			case source.ConfigMapRef != nil:
+				volumes = append(volumes, corev1.Volume{
+					Name: name,
+					VolumeSource: corev1.VolumeSource{
+						ConfigMap: &corev1.ConfigMapVolumeSource{
+							LocalObjectReference: source.ConfigMapRef.LocalObjectReference,
+							Items:                []corev1.KeyToPath{{Key: source.ConfigMapRef.Key, Path: source.ConfigMapRef.Key}},
+						},
+					},
+				})
+				volumeMounts = append(volumeMounts, corev1.VolumeMount{
+					Name:      name,
+					MountPath: "/" + name,
+					ReadOnly:  true,
+				})
+
+				script += fmt.Sprintf("seed '%s' '/%s/%s'\n", collection.Name, name, source.ConfigMapRef.Key)
+			case source.PersistentVolumeClaim != nil:
+				volumes = append(volumes, corev1.Volume{
+					Name: name,
+					VolumeSource: corev1.VolumeSource{
+						PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+							ClaimName: source.PersistentVolumeClaim.ClaimName,
+							ReadOnly:  true,
+						},
+					},
+				})
+				volumeMounts = append(volumeMounts, corev1.VolumeMount{
+					Name:      name,
+					MountPath: "/" + name,
+					ReadOnly:  true,
+				})
+
+				script += fmt.Sprintf("seed '%s' '/%s/%s'\n", collection.Name, name, strings.TrimPrefix(source.PersistentVolumeClaim.Path, "/"))
+			case source.Url != nil:
+				// urls are handed over in the environment, so that they never have to be quoted for the shell
+				variable := strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+				env = append(env, corev1.EnvVar{Name: variable, Value: *source.Url})
+
+				downloadScript += fmt.Sprintf("curl -sSfL -o '/tmp/%s.jsonl' \"${%s}\"\n", name, variable)
+				script += fmt.Sprintf("seed '%s' '/tmp/%s.jsonl'\n", collection.Name, name)
+			}
+		}
+	}
+
+	var initContainers []corev1.Container
+	if len(env) > 0 {
+		initContainers = append(initContainers, corev1.Container{
+			Name:         "download",
+			Image:        image,
+			Command:      []string{"/bin/sh", "-c", downloadScript},
+			Env:          env,
+			VolumeMounts: volumeMounts[:1:1],
+		})
+	}
+
+	return &batchv1.Job{
+		ObjectMeta: getObjectMeta(ts, &key.Name, nil),
+		Spec: batchv1.JobSpec{
+			BackoffLimit:            ptr.To[int32](3),
+			TTLSecondsAfterFinished: ptr.To[int32](86400),
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: getApiClientLabels(ts)},
+				Spec: corev1.PodSpec{
+					RestartPolicy:  corev1.RestartPolicyNever,
+					InitContainers: initContainers,
+					Containers: []corev1.Container{
+						{
+							Name:    "seed",
+							Image:   image,
+							Command: []string{"/bin/sh", "-c", script},
+							Env: append([]corev1.EnvVar{
+								{
+									Name: "TYPESENSE_API_KEY",
+									ValueFrom: &corev1.EnvVarSource{
+										SecretKeyRef: &corev1.SecretKeySelector{
+											Key: ts.Spec.GetAdminApiKeySecretKey(),
+											LocalObjectReference: corev1.LocalObjectReference{
+												Name: adminApiKeyObjectKey(ts).Name,
+											},
+										},
+									},
+								},
+								{
+									Name:  "TYPESENSE_HOST",
+									Value: fmt.Sprintf(ClusterRestService, ts.Name),
+								},
+								{
+									Name:  "TYPESENSE_PORT",
+									Value: strconv.Itoa(ts.Spec.ApiPort),
+								},
+								{
+									Name:  "TYPESENSE_PROTOCOL",
+									Value: getApiProtocol(ts),
+								},
+								{
+									Name:  "BATCH_SIZE",
+									Value: strconv.Itoa(batchSize),
+								},
+								{
+									Name:  "MAX_RETRIES",
+									Value: strconv.Itoa(seed.MaxRetries),
+								},
+							}, getTLSClientEnv(ts)...),
+							Resources: corev1.ResourceRequirements{
+								Limits: corev1.ResourceList{
+									corev1.ResourceCPU:    resource.MustParse("1000m"),
+									corev1.ResourceMemory: resource.MustParse("512Mi"),
+								},
+								Requests: corev1.ResourceList{
+									corev1.ResourceCPU:    resource.MustParse("100m"),
+									corev1.ResourceMemory: resource.MustParse("128Mi"),
+								},
+							},
+							VolumeMounts: append(volumeMounts, getTLSVolumeMounts(ts)...),
+						},
+					},
+					Volumes: append(volumes, getTLSVolumes(ts, false)...),
+				},
+			},
+		},
+	}
+}
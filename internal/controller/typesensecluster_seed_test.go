@@ -0,0 +1,187 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	tsv1alpha1 "github.com/akyriako/typesense-operator/api/v1alpha1"
+)
+
+var _ = Describe("TypesenseCluster Seed", func() {
+	Context("When seeding a cluster", func() {
+		ctx := context.Background()
+
+		var (
+			typesense   *httptest.Server
+			collection  *typesenseCollection
+			collections int
+		)
+
+		newCluster := func() *tsv1alpha1.TypesenseCluster {
+			return &tsv1alpha1.TypesenseCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster-1", Namespace: "default"},
+				Spec: tsv1alpha1.TypesenseClusterSpec{
+					Seed: &tsv1alpha1.SeedSpec{
+						BatchSize:  2,
+						MaxRetries: 3,
+						Collections: []tsv1alpha1.SeedCollectionSpec{
+							{
+								Name: "products",
+								Fields: []tsv1alpha1.CollectionFieldSpec{
+									{Name: "title", Type: "string"},
+								},
+								Documents: []tsv1alpha1.SeedDocumentsSpec{
+									{Url: ptr.To("https://datasets.example.com/products.jsonl?token=it's")},
+								},
+							},
+						},
+					},
+				},
+			}
+		}
+
+		BeforeEach(func() {
+			collection, collections = nil, 0
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("/collections", func(w http.ResponseWriter, req *http.Request) {
+				Expect(req.Method).To(Equal(http.MethodPost))
+				collections++
+				collection = &typesenseCollection{}
+				Expect(json.NewDecoder(req.Body).Decode(collection)).To(Succeed())
+				_ = json.NewEncoder(w).Encode(collection)
+			})
+			mux.HandleFunc("/collections/products", func(w http.ResponseWriter, req *http.Request) {
+				if collection == nil {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				_ = json.NewEncoder(w).Encode(collection)
+			})
+
+			typesense = httptest.NewServer(mux)
+		})
+
+		AfterEach(func() {
+			typesense.Close()
+		})
+
+		It("should create only the collections that do not exist yet", func() {
+			reconciler := &TypesenseClusterReconciler{logger: log.Log}
+			tc := newTypesenseClient(typesense.URL, "admin")
+
+			Expect(reconciler.seedCollections(ctx, tc, newCluster())).To(Succeed())
+			Expect(collection).NotTo(BeNil())
+			Expect(collection.Fields).To(HaveLen(1))
+
+			collection.Fields = []typesenseCollectionField{{Name: ".*", Type: "auto"}}
+			Expect(reconciler.seedCollections(ctx, tc, newCluster())).To(Succeed())
+			Expect(collection.Fields[0].Type).To(Equal("auto"))
+			Expect(collections).To(Equal(1))
+		})
+
+		It("should import every source from the seed job", func() {
+			reconciler := &TypesenseClusterReconciler{logger: log.Log, JobImage: "typesense-operator:test"}
+
+			ts := newCluster()
+			ts.Spec.Seed.Collections[0].Documents = append(ts.Spec.Seed.Collections[0].Documents,
+				tsv1alpha1.SeedDocumentsSpec{ConfigMapRef: &corev1.ConfigMapKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "fixtures"},
+					Key:                  "products.jsonl",
+				}},
+				tsv1alpha1.SeedDocumentsSpec{PersistentVolumeClaim: &tsv1alpha1.SeedPersistentVolumeClaimSpec{ClaimName: "datasets", Path: "/products/part-1.jsonl"}},
+				tsv1alpha1.SeedDocumentsSpec{PersistentVolumeClaim: &tsv1alpha1.SeedPersistentVolumeClaimSpec{ClaimName: "datasets", Path: "products/part-2.jsonl"}},
+			)
+
+			job := reconciler.buildSeedJob(client.ObjectKey{Namespace: "default", Name: "cluster-1-seed"}, ts)
+			pod := job.Spec.Template.Spec
+			Expect(pod.Volumes).To(HaveLen(4))
+			Expect(pod.Volumes[1].ConfigMap.Items).To(Equal([]corev1.KeyToPath{{Key: "products.jsonl", Path: "products.jsonl"}}))
+			Expect(pod.Containers[0].VolumeMounts).To(HaveLen(4))
+			Expect(pod.Containers[0].Image).To(Equal("typesense-operator:test"))
+
+			Expect(pod.InitContainers).To(HaveLen(1))
+			Expect(pod.InitContainers[0].Env).To(Equal([]corev1.EnvVar{{Name: "SEED_0", Value: "https://datasets.example.com/products.jsonl?token=it's"}}))
+			Expect(pod.InitContainers[0].Command[2]).To(HaveSuffix("curl -sSfL -o '/tmp/seed-0.jsonl' \"${SEED_0}\"\n"))
+			Expect(pod.InitContainers[0].VolumeMounts).To(Equal([]corev1.VolumeMount{{Name: "scratch", MountPath: "/tmp"}}))
+
+			script := pod.Containers[0].Command[2]
+			Expect(script).To(HaveSuffix("seed 'products' '/tmp/seed-0.jsonl'\n" +
+				"seed 'products' '/seed-1/products.jsonl'\n" +
+				"seed 'products' '/seed-2/products/part-1.jsonl'\n" +
+				"seed 'products' '/seed-3/products/part-2.jsonl'\n"))
+			Expect(strings.Count(script, "seed '")).To(Equal(4))
+		})
+
+		It("should download the urls without the CA of the api certificate when TLS is enabled", func() {
+			reconciler := &TypesenseClusterReconciler{logger: log.Log, JobImage: "typesense-operator:test"}
+
+			ts := newCluster()
+			ts.Spec.TLS = &tsv1alpha1.TLSSpec{SecretName: ptr.To("api-tls")}
+
+			pod := reconciler.buildSeedJob(client.ObjectKey{Namespace: "default", Name: "cluster-1-seed"}, ts).Spec.Template.Spec
+			Expect(pod.Containers[0].Env).To(ContainElement(HaveField("Name", "CURL_CA_BUNDLE")))
+			Expect(pod.Containers[0].Env).NotTo(ContainElement(HaveField("Name", "SEED_0")))
+			Expect(pod.Containers[0].VolumeMounts).To(ContainElements(getTLSVolumeMounts(ts)))
+
+			Expect(pod.InitContainers).To(HaveLen(1))
+			Expect(pod.InitContainers[0].Env).NotTo(ContainElement(HaveField("Name", "CURL_CA_BUNDLE")))
+			Expect(pod.InitContainers[0].Env).NotTo(ContainElement(HaveField("Name", "SSL_CERT_FILE")))
+			Expect(pod.InitContainers[0].VolumeMounts).NotTo(ContainElements(getTLSVolumeMounts(ts)))
+		})
+
+		It("should seed only a cluster that was bootstrapped with a seed", func() {
+			s := runtime.NewScheme()
+			Expect(clientgoscheme.AddToScheme(s)).To(Succeed())
+			Expect(tsv1alpha1.AddToScheme(s)).To(Succeed())
+
+			// the seed was added to an existing cluster, so it was never armed
+			ts := newCluster()
+			reconciler := &TypesenseClusterReconciler{
+				Client: fake.NewClientBuilder().WithScheme(s).WithObjects(ts).WithStatusSubresource(ts).Build(),
+				Scheme: s,
+				logger: log.Log,
+			}
+
+			Expect(reconciler.ReconcileSeed(ctx, ts)).To(Succeed())
+			err := reconciler.Get(ctx, client.ObjectKey{Namespace: "default", Name: "cluster-1-seed"}, &batchv1.Job{})
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+
+			// a seed armed at bootstrap needs the admin api key to reach the cluster
+			ts.Status.Seed = &tsv1alpha1.SeedStatus{Phase: SeedPhasePending}
+			Expect(apierrors.IsNotFound(reconciler.ReconcileSeed(ctx, ts))).To(BeTrue())
+		})
+	})
+})
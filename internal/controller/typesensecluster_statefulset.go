@@ -13,8 +13,10 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/utils/ptr"
+	"path"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -27,6 +29,7 @@ const (
 	hashAnnotationKey                  = "ts.opentelekomcloud.com/pod-template-hash"
 	readLagAnnotationKey               = "ts.opentelekomcloud.com/read-lag-threshold"
 	writeLagAnnotationKey              = "ts.opentelekomcloud.com/write-lag-threshold"
+	tmpVolumeName                      = "tmp"
 )
 
 func (r *TypesenseClusterReconciler) ReconcileStatefulSet(ctx context.Context, ts *tsv1alpha1.TypesenseCluster) (*appsv1.StatefulSet, error) {
@@ -80,6 +83,7 @@ func (r *TypesenseClusterReconciler) ReconcileStatefulSet(ctx context.Context, t
 			desiredSts, err := r.buildStatefulSet(ctx, stsObjectKey, ts)
 			if err != nil {
 				r.logger.Error(err, "building statefulset failed", "sts", stsObjectKey.Name)
+				return nil, err
 			}
 
 			if r.shouldUpdateStatefulSet(sts, desiredSts, ts) {
@@ -172,6 +176,11 @@ func (r *TypesenseClusterReconciler) buildStatefulSet(ctx context.Context, key c
 	lagThresholdAnnotations[readLagAnnotationKey] = strconv.Itoa(readLagThreshold)
 	lagThresholdAnnotations[writeLagAnnotationKey] = strconv.Itoa(writeLagThreshold)
 
+	analyticsEnv, err := r.getAnalyticsEnv(ctx, ts)
+	if err != nil {
+		return nil, err
+	}
+
 	clusterName := ts.Name
 	sts := &appsv1.StatefulSet{
 		TypeMeta:   metav1.TypeMeta{},
@@ -186,11 +195,7 @@ func (r *TypesenseClusterReconciler) buildStatefulSet(ctx context.Context, key c
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: getObjectMeta(ts, &key.Name, lagThresholdAnnotations),
 				Spec: corev1.PodSpec{
-					SecurityContext: &corev1.PodSecurityContext{
-						RunAsUser:    ptr.To[int64](10000),
-						FSGroup:      ptr.To[int64](2000),
-						RunAsGroup:   ptr.To[int64](3000),
-						RunAsNonRoot: ptr.To[bool](true)},
+					SecurityContext:               ts.Spec.GetPodSecurityContext(),
 					TerminationGracePeriodSeconds: ptr.To[int64](5),
 					ReadinessGates: []corev1.PodReadinessGate{
 						{
@@ -208,12 +213,12 @@ func (r *TypesenseClusterReconciler) buildStatefulSet(ctx context.Context, key c
 									ContainerPort: int32(ts.Spec.ApiPort),
 								},
 							},
-							Env: []corev1.EnvVar{
+							Env: append([]corev1.EnvVar{
 								{
 									Name: "TYPESENSE_API_KEY",
 									ValueFrom: &corev1.EnvVarSource{
 										SecretKeyRef: &corev1.SecretKeySelector{
-											Key: ClusterAdminApiKeySecretKeyName,
+											Key: ts.Spec.GetAdminApiKeySecretKey(),
 											LocalObjectReference: corev1.LocalObjectReference{
 												Name: r.getAdminApiKeyObjectKey(ts).Name,
 											},
@@ -255,10 +260,11 @@ func (r *TypesenseClusterReconciler) buildStatefulSet(ctx context.Context, key c
 									Name:  "TYPESENSE_RESET_PEERS_ON_ERROR",
 									Value: strconv.FormatBool(ts.Spec.ResetPeersOnError),
 								},
-							},
-							EnvFrom:   ts.Spec.GetAdditionalServerConfiguration(),
-							Resources: ts.Spec.GetResources(),
-							VolumeMounts: []corev1.VolumeMount{
+							}, slices.Concat(analyticsEnv, getHealthyLagEnv(ts), r.getServerTLSEnv(ts))...),
+							EnvFrom:         ts.Spec.GetAdditionalServerConfiguration(),
+							Resources:       ts.Spec.GetResources(),
+							SecurityContext: ts.Spec.GetSecurityContext(),
+							VolumeMounts: append([]corev1.VolumeMount{
 								{
 									MountPath: "/usr/share/typesense",
 									Name:      "nodeslist",
@@ -267,7 +273,8 @@ func (r *TypesenseClusterReconciler) buildStatefulSet(ctx context.Context, key c
 									MountPath: "/usr/share/typesense/data",
 									Name:      "data",
 								},
-							},
+								getTmpVolumeMount("typesense"),
+							}, slices.Concat(getTLSVolumeMounts(ts), getAdminApiKeyVolumeMounts(ts))...),
 						},
 						{
 							Name:            "metrics-exporter",
@@ -279,12 +286,12 @@ func (r *TypesenseClusterReconciler) buildStatefulSet(ctx context.Context, key c
 									ContainerPort: metricsPort,
 								},
 							},
-							Env: []corev1.EnvVar{
+							Env: append([]corev1.EnvVar{
 								{
 									Name: "TYPESENSE_API_KEY",
 									ValueFrom: &corev1.EnvVarSource{
 										SecretKeyRef: &corev1.SecretKeySelector{
-											Key: ClusterAdminApiKeySecretKeyName,
+											Key: ts.Spec.GetAdminApiKeySecretKey(),
 											LocalObjectReference: corev1.LocalObjectReference{
 												Name: r.getAdminApiKeyObjectKey(ts).Name,
 											},
@@ -297,7 +304,7 @@ func (r *TypesenseClusterReconciler) buildStatefulSet(ctx context.Context, key c
 								},
 								{
 									Name:  "TYPESENSE_PROTOCOL",
-									Value: "http",
+									Value: getApiProtocol(ts),
 								},
 								{
 									Name:  "TYPESENSE_HOST",
@@ -315,8 +322,10 @@ func (r *TypesenseClusterReconciler) buildStatefulSet(ctx context.Context, key c
 									Name:  "TYPESENSE_CLUSTER",
 									Value: ts.Name,
 								},
-							},
-							Resources: ts.Spec.GetMetricsExporterResources(),
+							}, getTLSClientEnv(ts)...),
+							Resources:       ts.Spec.GetMetricsExporterResources(),
+							SecurityContext: ts.Spec.GetMetricsExporterSecurityContext(),
+							VolumeMounts:    append([]corev1.VolumeMount{getTmpVolumeMount("metrics-exporter")}, getTLSVolumeMounts(ts)...),
 						},
 						{
 							Name:            "healthcheck",
@@ -328,12 +337,12 @@ func (r *TypesenseClusterReconciler) buildStatefulSet(ctx context.Context, key c
 									ContainerPort: 8808,
 								},
 							},
-							Env: []corev1.EnvVar{
+							Env: append([]corev1.EnvVar{
 								{
 									Name: "TYPESENSE_API_KEY",
 									ValueFrom: &corev1.EnvVarSource{
 										SecretKeyRef: &corev1.SecretKeySelector{
-											Key: ClusterAdminApiKeySecretKeyName,
+											Key: ts.Spec.GetAdminApiKeySecretKey(),
 											LocalObjectReference: corev1.LocalObjectReference{
 												Name: r.getAdminApiKeyObjectKey(ts).Name,
 											},
@@ -346,7 +355,7 @@ func (r *TypesenseClusterReconciler) buildStatefulSet(ctx context.Context, key c
 								},
 								{
 									Name:  "TYPESENSE_PROTOCOL",
-									Value: "http",
+									Value: getApiProtocol(ts),
 								},
 								{
 									Name:  "TYPESENSE_API_PORT",
@@ -368,22 +377,24 @@ func (r *TypesenseClusterReconciler) buildStatefulSet(ctx context.Context, key c
 									Name:  "CLUSTER_NAMESPACE",
 									Value: ts.Namespace,
 								},
-							},
-							Resources: ts.Spec.GetHealthCheckSidecarResources(),
-							VolumeMounts: []corev1.VolumeMount{
+							}, getTLSClientEnv(ts)...),
+							Resources:       ts.Spec.GetHealthCheckSidecarResources(),
+							SecurityContext: ts.Spec.GetHealthCheckSidecarSecurityContext(),
+							VolumeMounts: append([]corev1.VolumeMount{
 								{
 									MountPath: "/usr/share/typesense",
 									Name:      "nodeslist",
 									ReadOnly:  true,
 								},
-							},
+								getTmpVolumeMount("healthcheck"),
+							}, getTLSVolumeMounts(ts)...),
 						},
 					},
 					Affinity:                  ts.Spec.Affinity,
 					NodeSelector:              ts.Spec.NodeSelector,
 					Tolerations:               ts.Spec.Tolerations,
 					TopologySpreadConstraints: ts.Spec.GetTopologySpreadConstraints(getLabels(ts)),
-					Volumes: []corev1.Volume{
+					Volumes: append([]corev1.Volume{
 						{
 							Name: "nodeslist",
 							VolumeSource: corev1.VolumeSource{
@@ -402,7 +413,13 @@ func (r *TypesenseClusterReconciler) buildStatefulSet(ctx context.Context, key c
 								},
 							},
 						},
-					},
+						{
+							Name: tmpVolumeName,
+							VolumeSource: corev1.VolumeSource{
+								EmptyDir: &corev1.EmptyDirVolumeSource{},
+							},
+						},
+					}, slices.Concat(getTLSVolumes(ts, true), getAdminApiKeyVolumes(ts))...),
 				},
 			},
 			VolumeClaimTemplates: []corev1.PersistentVolumeClaim{
@@ -466,6 +483,27 @@ func (r *TypesenseClusterReconciler) buildStatefulSet(ctx context.Context, key c
 		}
 	}
 
+	// a rotated admin api key rolls the pods, even when it is kept in the same Secret
+	if ts.Status.AdminApiKey != nil && ts.Status.AdminApiKey.LastRotationTime != nil {
+		specsHash = fmt.Sprintf("%s%s", specsHash, ts.Status.AdminApiKey.Fingerprint)
+	}
+
+	// a renewed certificate or CA rolls the pods, so that typesense serves it and the sidecars trust it right away
+	if ts.Spec.IsTLSEnabled() {
+		secret, err := getTLSSecret(ctx, r.Client, ts)
+		if err != nil {
+			return nil, err
+		}
+
+		certificate := []string{string(secret.Data[corev1.TLSCertKey]), string(secret.Data[ts.Spec.TLS.GetCAKey()])}
+		certificateHash, err := hashstructure.Hash(certificate, hashstructure.FormatV2, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		specsHash = fmt.Sprintf("%s%d", specsHash, certificateHash)
+	}
+
 	base16Hash := fmt.Sprintf("%x", sha256.Sum256([]byte(specsHash)))
 	r.logger.V(debugLevel).Info("calculated hash", "hash", base16Hash)
 
@@ -477,6 +515,32 @@ func (r *TypesenseClusterReconciler) buildStatefulSet(ctx context.Context, key c
 	return sts, nil
 }
 
+func (r *TypesenseClusterReconciler) getServerTLSEnv(ts *tsv1alpha1.TypesenseCluster) []corev1.EnvVar {
+	if !ts.Spec.IsTLSEnabled() {
+		return nil
+	}
+
+	return []corev1.EnvVar{
+		{
+			Name:  "TYPESENSE_SSL_CERTIFICATE",
+			Value: path.Join(tlsMountPath, corev1.TLSCertKey),
+		},
+		{
+			Name:  "TYPESENSE_SSL_CERTIFICATE_KEY",
+			Value: path.Join(tlsMountPath, corev1.TLSPrivateKeyKey),
+		},
+	}
+}
+
+// getTmpVolumeMount gives every container a writable /tmp of its own, as their root filesystem is read-only by default
+func getTmpVolumeMount(container string) corev1.VolumeMount {
+	return corev1.VolumeMount{
+		MountPath: "/tmp",
+		Name:      tmpVolumeName,
+		SubPath:   container,
+	}
+}
+
 func (r *TypesenseClusterReconciler) shouldUpdateStatefulSet(sts *appsv1.StatefulSet, desired *appsv1.StatefulSet, ts *tsv1alpha1.TypesenseCluster) bool {
 	//return false
 
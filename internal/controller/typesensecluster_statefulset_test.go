@@ -0,0 +1,125 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	tsv1alpha1 "github.com/akyriako/typesense-operator/api/v1alpha1"
+)
+
+var _ = Describe("TypesenseCluster StatefulSet", func() {
+	Context("When building the statefulset", func() {
+		ctx := context.Background()
+		key := client.ObjectKey{Namespace: "search", Name: "cluster-1-sts"}
+
+		var reconciler *TypesenseClusterReconciler
+		var cluster *tsv1alpha1.TypesenseCluster
+
+		BeforeEach(func() {
+			s := runtime.NewScheme()
+			Expect(clientgoscheme.AddToScheme(s)).To(Succeed())
+			Expect(tsv1alpha1.AddToScheme(s)).To(Succeed())
+
+			cluster = &tsv1alpha1.TypesenseCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster-1", Namespace: "search"},
+				Spec: tsv1alpha1.TypesenseClusterSpec{
+					Image:       "typesense/typesense:27.1",
+					Replicas:    3,
+					ApiPort:     8108,
+					PeeringPort: 8107,
+					Storage:     &tsv1alpha1.StorageSpec{StorageClassName: "standard"},
+				},
+			}
+
+			reconciler = &TypesenseClusterReconciler{
+				Client: fake.NewClientBuilder().WithScheme(s).Build(),
+				Scheme: s,
+				logger: log.Log,
+			}
+		})
+
+		It("should satisfy the restricted pod security standard by default", func() {
+			sts, err := reconciler.buildStatefulSet(ctx, key, cluster)
+			Expect(err).NotTo(HaveOccurred())
+
+			podSecurityContext := sts.Spec.Template.Spec.SecurityContext
+			Expect(podSecurityContext.RunAsUser).To(Equal(ptr.To[int64](10000)))
+			Expect(podSecurityContext.RunAsNonRoot).To(Equal(ptr.To(true)))
+			Expect(podSecurityContext.SeccompProfile.Type).To(Equal(corev1.SeccompProfileTypeRuntimeDefault))
+
+			Expect(sts.Spec.Template.Spec.Containers).To(HaveLen(3))
+			for _, container := range sts.Spec.Template.Spec.Containers {
+				Expect(container.SecurityContext.AllowPrivilegeEscalation).To(Equal(ptr.To(false)), container.Name)
+				Expect(container.SecurityContext.ReadOnlyRootFilesystem).To(Equal(ptr.To(true)), container.Name)
+				Expect(container.SecurityContext.Capabilities.Drop).To(ConsistOf(corev1.Capability("ALL")), container.Name)
+				Expect(container.VolumeMounts).To(ContainElement(getTmpVolumeMount(container.Name)), container.Name)
+			}
+		})
+
+		It("should use the security contexts of the spec and roll the pods when they change", func() {
+			before, err := reconciler.buildStatefulSet(ctx, key, cluster)
+			Expect(err).NotTo(HaveOccurred())
+
+			cluster.Spec.PodSecurityContext = &corev1.PodSecurityContext{RunAsNonRoot: ptr.To(true)}
+			cluster.Spec.HealthCheck = &tsv1alpha1.HealthCheckSpec{
+				SecurityContext: &corev1.SecurityContext{ReadOnlyRootFilesystem: ptr.To(false)},
+			}
+
+			after, err := reconciler.buildStatefulSet(ctx, key, cluster)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(after.Spec.Template.Spec.SecurityContext).To(Equal(cluster.Spec.PodSecurityContext))
+			Expect(after.Spec.Template.Spec.Containers[0].SecurityContext.ReadOnlyRootFilesystem).To(Equal(ptr.To(true)))
+			Expect(after.Spec.Template.Spec.Containers[2].SecurityContext).To(Equal(cluster.Spec.HealthCheck.SecurityContext))
+			Expect(after.Spec.Template.Annotations[hashAnnotationKey]).NotTo(Equal(before.Spec.Template.Annotations[hashAnnotationKey]))
+		})
+
+		It("should read the admin api key from the configured Secret key and mount the csi volume", func() {
+			cluster.Spec.AdminApiKey = &tsv1alpha1.AdminApiKeySpec{
+				Name: "typesense-admin",
+				Key:  "api-key",
+				CSI: &tsv1alpha1.AdminApiKeyCSISpec{
+					VolumeAttributes: map[string]string{"secretProviderClass": "typesense"},
+				},
+			}
+
+			sts, err := reconciler.buildStatefulSet(ctx, key, cluster)
+			Expect(err).NotTo(HaveOccurred())
+
+			for _, container := range sts.Spec.Template.Spec.Containers {
+				Expect(container.Env).To(ContainElement(HaveField("ValueFrom.SecretKeyRef", &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "typesense-admin"},
+					Key:                  "api-key",
+				})), container.Name)
+			}
+
+			Expect(sts.Spec.Template.Spec.Volumes).To(ContainElement(HaveField("CSI.Driver", "secrets-store.csi.k8s.io")))
+			Expect(sts.Spec.Template.Spec.Containers[0].VolumeMounts).To(ContainElement(getAdminApiKeyVolumeMounts(cluster)[0]))
+		})
+	})
+})
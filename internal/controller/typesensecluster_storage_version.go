@@ -0,0 +1,38 @@
+package controller
+
+import (
+	"context"
+
+	tsv1alpha1 "github.com/akyriako/typesense-operator/api/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// MigrateTypesenseClusterStorageVersion rewrites every TypesenseCluster, so that the objects persisted before v1beta1
+// became the storage version are stored as v1beta1. An update without changes is still persisted when the stored
+// version differs; once every object is rewritten, v1alpha1 can be removed from status.storedVersions of the CRD.
+// Failures are only logged, the migration runs again on the next start of the operator.
+func MigrateTypesenseClusterStorageVersion(ctx context.Context, c client.Client) {
+	logger := log.FromContext(ctx).WithName("storage-version-migration")
+
+	var clusters tsv1alpha1.TypesenseClusterList
+	if err := c.List(ctx, &clusters); err != nil {
+		logger.Error(err, "listing clusters failed")
+		return
+	}
+
+	migrated := 0
+	for i := range clusters.Items {
+		ts := &clusters.Items[i]
+
+		// a conflict means the cluster was written meanwhile, which migrates it as well
+		if err := c.Update(ctx, ts); err != nil && !apierrors.IsConflict(err) && !apierrors.IsNotFound(err) {
+			logger.Error(err, "migrating cluster failed", "cluster", client.ObjectKeyFromObject(ts))
+			continue
+		}
+		migrated++
+	}
+
+	logger.Info("migrated storage version of clusters", "clusters", migrated)
+}
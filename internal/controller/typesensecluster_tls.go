@@ -0,0 +1,359 @@
+package controller
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"path"
+
+	tsv1alpha1 "github.com/akyriako/typesense-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	certManagerApiGroup = "cert-manager.io"
+
+	tlsVolumeName      = "tls"
+	tlsMountPath       = "/etc/typesense/tls"
+	tlsCAFile          = "ca.crt"
+	tlsCABundleVolume  = "ca-bundle"
+	tlsCABundleMount   = "/etc/typesense/ca-bundle"
+	tlsCABundleFile    = "ca-bundle.crt"
+	systemCABundleFile = "/etc/ssl/certs/ca-certificates.crt"
+)
+
+var certificateGVK = schema.GroupVersionKind{Group: certManagerApiGroup, Version: "v1", Kind: "Certificate"}
+
+// certificateSpecFields are the fields of the Certificate spec owned by the operator, anything else
+// (e.g. defaults of cert-manager) is left untouched on update.
+var certificateSpecFields = []string{"secretName", "dnsNames", "ipAddresses", "issuerRef", "duration", "renewBefore"}
+
+func (r *TypesenseClusterReconciler) ReconcileCertificate(ctx context.Context, ts tsv1alpha1.TypesenseCluster) error {
+	r.logger.V(debugLevel).Info("reconciling certificate")
+
+	certificateName := fmt.Sprintf(ClusterCertificate, ts.Name)
+	certificateObjectKey := client.ObjectKey{Namespace: ts.Namespace, Name: certificateName}
+
+	if ts.Spec.TLS == nil {
+		return r.deleteCertificate(ctx, certificateObjectKey, &ts)
+	}
+
+	if err := validateTLSSpec(ts.Spec.TLS); err != nil {
+		return err
+	}
+
+	if ts.Spec.TLS.CertManager == nil {
+		if err := r.deleteCertificate(ctx, certificateObjectKey, &ts); err != nil {
+			return err
+		}
+	} else {
+		if deployed, err := r.IsCertManagerDeployed(); err != nil || !deployed {
+			if err == nil {
+				err = fmt.Errorf("cert-manager api group %s was not found in cluster", certManagerApiGroup)
+			}
+			return err
+		}
+
+		desired := buildCertificate(certificateObjectKey, &ts)
+		if err := ctrl.SetControllerReference(&ts, desired, r.Scheme); err != nil {
+			return err
+		}
+
+		certificate := &unstructured.Unstructured{}
+		certificate.SetGroupVersionKind(certificateGVK)
+		if err := r.Get(ctx, certificateObjectKey, certificate); err != nil {
+			if !apierrors.IsNotFound(err) {
+				r.logger.Error(err, fmt.Sprintf("unable to fetch certificate: %s", certificateName))
+				return err
+			}
+
+			r.logger.V(debugLevel).Info("creating certificate", "certificate", certificateName)
+			if err := r.Create(ctx, desired); err != nil {
+				r.logger.Error(err, "creating certificate failed", "certificate", certificateName)
+				return err
+			}
+		} else if updateCertificateSpec(certificate, desired) {
+			r.logger.V(debugLevel).Info("updating certificate", "certificate", certificateName)
+			if err := r.Update(ctx, certificate); err != nil {
+				r.logger.Error(err, "updating certificate failed", "certificate", certificateName)
+				return err
+			}
+		}
+	}
+
+	if _, err := getTLSSecret(ctx, r.Client, &ts); err != nil {
+		if apierrors.IsNotFound(err) {
+			return fmt.Errorf("waiting for tls secret %s", getTLSSecretName(&ts))
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (r *TypesenseClusterReconciler) deleteCertificate(ctx context.Context, key client.ObjectKey, ts *tsv1alpha1.TypesenseCluster) error {
+	return r.deleteUnstructured(ctx, certificateGVK, key, ts)
+}
+
+func (r *TypesenseClusterReconciler) IsCertManagerDeployed() (bool, error) {
+	apiGroupList, err := r.DiscoveryClient.ServerGroups()
+	if err != nil {
+		return false, err
+	}
+
+	for _, apiGroup := range apiGroupList.Groups {
+		if apiGroup.Name == certManagerApiGroup {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func validateTLSSpec(spec *tsv1alpha1.TLSSpec) error {
+	if (spec.CertManager == nil) == (spec.SecretName == nil) {
+		return fmt.Errorf("tls must set exactly one of certManager or secretName")
+	}
+
+	return nil
+}
+
+// buildCertificate returns a cert-manager Certificate covering every name the api of the cluster is reached by:
+// the resolver service, the headless service and its pods, and localhost for the sidecars.
+func buildCertificate(key client.ObjectKey, ts *tsv1alpha1.TypesenseCluster) *unstructured.Unstructured {
+	dnsNames := make([]any, 0)
+	for _, service := range []string{fmt.Sprintf(ClusterRestService, ts.Name), fmt.Sprintf(ClusterHeadlessService, ts.Name)} {
+		dnsNames = append(dnsNames,
+			service,
+			fmt.Sprintf("%s.%s", service, ts.Namespace),
+			fmt.Sprintf("%s.%s.svc", service, ts.Namespace),
+			fmt.Sprintf("%s.%s.svc.cluster.local", service, ts.Namespace),
+		)
+	}
+	dnsNames = append(dnsNames,
+		fmt.Sprintf("*.%s.%s.svc.cluster.local", fmt.Sprintf(ClusterHeadlessService, ts.Name), ts.Namespace),
+		"localhost",
+	)
+
+	issuer := ts.Spec.TLS.CertManager.IssuerRef
+	issuerRef := map[string]any{
+		"name":  issuer.Name,
+		"kind":  issuer.Kind,
+		"group": issuer.Group,
+	}
+	if issuer.Kind == "" {
+		issuerRef["kind"] = "Issuer"
+	}
+	if issuer.Group == "" {
+		issuerRef["group"] = certManagerApiGroup
+	}
+
+	spec := map[string]any{
+		"secretName":  getTLSSecretName(ts),
+		"dnsNames":    dnsNames,
+		"ipAddresses": []any{"127.0.0.1"},
+		"issuerRef":   issuerRef,
+	}
+	if ts.Spec.TLS.CertManager.Duration != nil {
+		spec["duration"] = ts.Spec.TLS.CertManager.Duration.Duration.String()
+	}
+	if ts.Spec.TLS.CertManager.RenewBefore != nil {
+		spec["renewBefore"] = ts.Spec.TLS.CertManager.RenewBefore.Duration.String()
+	}
+
+	certificate := &unstructured.Unstructured{}
+	certificate.SetGroupVersionKind(certificateGVK)
+	certificate.SetName(key.Name)
+	certificate.SetNamespace(key.Namespace)
+	certificate.SetLabels(getLabels(ts))
+	certificate.Object["spec"] = spec
+
+	return certificate
+}
+
+// updateCertificateSpec copies the fields owned by the operator from desired to certificate,
+// and reports whether any of them changed.
+func updateCertificateSpec(certificate *unstructured.Unstructured, desired *unstructured.Unstructured) bool {
+	return updateUnstructuredSpec(certificate, desired, certificateSpecFields)
+}
+
+// updateUnstructuredSpec copies the given spec fields from desired to object, and reports whether any of them changed.
+func updateUnstructuredSpec(object *unstructured.Unstructured, desired *unstructured.Unstructured, fields []string) bool {
+	spec, _, _ := unstructured.NestedMap(object.Object, "spec")
+	if spec == nil {
+		spec = map[string]any{}
+	}
+	desiredSpec, _, _ := unstructured.NestedMap(desired.Object, "spec")
+
+	changed := false
+	for _, field := range fields {
+		value, ok := desiredSpec[field]
+		if equality.Semantic.DeepEqual(spec[field], value) {
+			continue
+		}
+
+		changed = true
+		if ok {
+			spec[field] = value
+		} else {
+			delete(spec, field)
+		}
+	}
+
+	if changed {
+		object.Object["spec"] = spec
+	}
+
+	return changed
+}
+
+func getTLSSecretName(ts *tsv1alpha1.TypesenseCluster) string {
+	if ts.Spec.TLS != nil && ts.Spec.TLS.SecretName != nil {
+		return *ts.Spec.TLS.SecretName
+	}
+
+	return fmt.Sprintf(ClusterCertificateSecret, ts.Name)
+}
+
+// getTLSSecret returns the Secret of the api certificate, after checking it holds a key pair and the CA.
+func getTLSSecret(ctx context.Context, c client.Client, ts *tsv1alpha1.TypesenseCluster) (*corev1.Secret, error) {
+	secret := &corev1.Secret{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: ts.Namespace, Name: getTLSSecretName(ts)}, secret); err != nil {
+		return nil, err
+	}
+
+	for _, key := range []string{corev1.TLSCertKey, corev1.TLSPrivateKeyKey, ts.Spec.TLS.GetCAKey()} {
+		if len(secret.Data[key]) == 0 {
+			return nil, fmt.Errorf("secret %s does not contain key %s", secret.Name, key)
+		}
+	}
+
+	return secret, nil
+}
+
+func getApiProtocol(ts *tsv1alpha1.TypesenseCluster) string {
+	if ts.Spec.IsTLSEnabled() {
+		return "https"
+	}
+
+	return "http"
+}
+
+// newTLSTransport returns a transport verifying the api of the cluster against the CA of its certificate,
+// or nil when tls is disabled. serverName overrides the name that is verified, for when nodes are reached by ip.
+func newTLSTransport(ctx context.Context, c client.Client, ts *tsv1alpha1.TypesenseCluster, serverName string) (*http.Transport, error) {
+	if !ts.Spec.IsTLSEnabled() {
+		return nil, nil
+	}
+
+	secret, err := getTLSSecret(ctx, c, ts)
+	if err != nil {
+		return nil, err
+	}
+
+	caKey := ts.Spec.TLS.GetCAKey()
+	rootCAs := x509.NewCertPool()
+	if !rootCAs.AppendCertsFromPEM(secret.Data[caKey]) {
+		return nil, fmt.Errorf("secret %s does not contain a valid CA in key %s", secret.Name, caKey)
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{
+		RootCAs:    rootCAs,
+		ServerName: serverName,
+		MinVersion: tls.VersionTLS12,
+	}
+
+	return transport, nil
+}
+
+// getTLSVolumes returns the volume of the api certificate. Only the typesense container needs the key pair,
+// clients get the CA alone.
+func getTLSVolumes(ts *tsv1alpha1.TypesenseCluster, withKeyPair bool) []corev1.Volume {
+	if !ts.Spec.IsTLSEnabled() {
+		return nil
+	}
+
+	items := []corev1.KeyToPath{{Key: ts.Spec.TLS.GetCAKey(), Path: tlsCAFile}}
+	if withKeyPair {
+		items = append(items,
+			corev1.KeyToPath{Key: corev1.TLSCertKey, Path: corev1.TLSCertKey},
+			corev1.KeyToPath{Key: corev1.TLSPrivateKeyKey, Path: corev1.TLSPrivateKeyKey},
+		)
+	}
+
+	return []corev1.Volume{
+		{
+			Name: tlsVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: getTLSSecretName(ts),
+					Items:      items,
+				},
+			},
+		},
+	}
+}
+
+func getTLSVolumeMounts(ts *tsv1alpha1.TypesenseCluster) []corev1.VolumeMount {
+	if !ts.Spec.IsTLSEnabled() {
+		return nil
+	}
+
+	return []corev1.VolumeMount{
+		{
+			Name:      tlsVolumeName,
+			MountPath: tlsMountPath,
+			ReadOnly:  true,
+		},
+	}
+}
+
+// getTLSClientEnv points curl and Go clients to the CA of the api certificate. It replaces the system CAs,
+// so it is only given to containers that talk to Typesense alone.
+func getTLSClientEnv(ts *tsv1alpha1.TypesenseCluster) []corev1.EnvVar {
+	if !ts.Spec.IsTLSEnabled() {
+		return nil
+	}
+
+	caFile := path.Join(tlsMountPath, tlsCAFile)
+	return []corev1.EnvVar{
+		{
+			Name:  "CURL_CA_BUNDLE",
+			Value: caFile,
+		},
+		{
+			Name:  "SSL_CERT_FILE",
+			Value: caFile,
+		},
+	}
+}
+
+// getTLSCABundleInitContainer appends the CA of the api certificate to the system CAs, for clients like the
+// scraper that talk to Typesense and to public sites alike.
+func getTLSCABundleInitContainer(ts *tsv1alpha1.TypesenseCluster, image string) []corev1.Container {
+	if !ts.Spec.IsTLSEnabled() {
+		return nil
+	}
+
+	return []corev1.Container{
+		{
+			Name:  tlsCABundleVolume,
+			Image: image,
+			Command: []string{"/bin/sh", "-c", fmt.Sprintf("cat %s %s > %s",
+				systemCABundleFile, path.Join(tlsMountPath, tlsCAFile), path.Join(tlsCABundleMount, tlsCABundleFile))},
+			VolumeMounts: append(getTLSVolumeMounts(ts), corev1.VolumeMount{
+				Name:      tlsCABundleVolume,
+				MountPath: tlsCABundleMount,
+			}),
+		},
+	}
+}
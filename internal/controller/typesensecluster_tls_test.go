@@ -0,0 +1,176 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	tsv1alpha1 "github.com/akyriako/typesense-operator/api/v1alpha1"
+)
+
+var _ = Describe("TypesenseCluster TLS", func() {
+	Context("When tls is enabled", func() {
+		ctx := context.Background()
+
+		newCluster := func(tls *tsv1alpha1.TLSSpec) *tsv1alpha1.TypesenseCluster {
+			return &tsv1alpha1.TypesenseCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster-1", Namespace: "search"},
+				Spec: tsv1alpha1.TypesenseClusterSpec{
+					ApiPort: 8108,
+					TLS:     tls,
+				},
+			}
+		}
+
+		It("should request a certificate covering every name of the api", func() {
+			ts := newCluster(&tsv1alpha1.TLSSpec{
+				CertManager: &tsv1alpha1.TLSCertManagerSpec{
+					IssuerRef:   tsv1alpha1.TLSIssuerReference{Name: "internal-ca"},
+					RenewBefore: &metav1.Duration{Duration: 24 * time.Hour},
+				},
+			})
+
+			certificate := buildCertificate(client.ObjectKey{Namespace: "search", Name: "cluster-1-certificate"}, ts)
+			Expect(certificate.GetKind()).To(Equal("Certificate"))
+
+			spec := certificate.Object["spec"].(map[string]any)
+			Expect(spec["secretName"]).To(Equal("cluster-1-certificate-tls"))
+			Expect(spec["renewBefore"]).To(Equal("24h0m0s"))
+			Expect(spec).NotTo(HaveKey("duration"))
+			Expect(spec["issuerRef"]).To(Equal(map[string]any{"name": "internal-ca", "kind": "Issuer", "group": "cert-manager.io"}))
+			Expect(spec["dnsNames"]).To(ContainElements(
+				"cluster-1-svc",
+				"cluster-1-svc.search.svc.cluster.local",
+				"cluster-1-sts-svc.search.svc.cluster.local",
+				"*.cluster-1-sts-svc.search.svc.cluster.local",
+				"localhost",
+			))
+		})
+
+		It("should only update the fields of the certificate owned by the operator", func() {
+			ts := newCluster(&tsv1alpha1.TLSSpec{
+				CertManager: &tsv1alpha1.TLSCertManagerSpec{
+					IssuerRef: tsv1alpha1.TLSIssuerReference{Name: "internal-ca", Kind: "ClusterIssuer"},
+				},
+			})
+			desired := buildCertificate(client.ObjectKey{Namespace: "search", Name: "cluster-1-certificate"}, ts)
+
+			live := desired.DeepCopy()
+			Expect(unstructured.SetNestedField(live.Object, "Always", "spec", "privateKey", "rotationPolicy")).To(Succeed())
+			Expect(updateCertificateSpec(live, desired)).To(BeFalse())
+
+			Expect(unstructured.SetNestedField(live.Object, "2160h0m0s", "spec", "duration")).To(Succeed())
+			Expect(updateCertificateSpec(live, desired)).To(BeTrue())
+			Expect(live.Object["spec"]).NotTo(HaveKey("duration"))
+
+			rotationPolicy, _, _ := unstructured.NestedString(live.Object, "spec", "privateKey", "rotationPolicy")
+			Expect(rotationPolicy).To(Equal("Always"))
+		})
+
+		It("should require exactly one source of the certificate", func() {
+			Expect(validateTLSSpec(&tsv1alpha1.TLSSpec{})).NotTo(Succeed())
+			Expect(validateTLSSpec(&tsv1alpha1.TLSSpec{
+				SecretName:  ptr.To("api-tls"),
+				CertManager: &tsv1alpha1.TLSCertManagerSpec{},
+			})).NotTo(Succeed())
+			Expect(validateTLSSpec(&tsv1alpha1.TLSSpec{SecretName: ptr.To("api-tls")})).To(Succeed())
+		})
+
+		It("should watch the secrets of the admin api key and of the certificate", func() {
+			ts := newCluster(nil)
+			Expect(isClusterSecret(ts, "cluster-1-admin-key")).To(BeTrue())
+			Expect(isClusterSecret(ts, "cluster-1-certificate-tls")).To(BeFalse())
+
+			ts.Spec.TLS = &tsv1alpha1.TLSSpec{CertManager: &tsv1alpha1.TLSCertManagerSpec{}}
+			Expect(isClusterSecret(ts, getTLSSecretName(ts))).To(BeTrue())
+
+			ts.Spec.TLS = &tsv1alpha1.TLSSpec{SecretName: ptr.To("api-tls")}
+			Expect(isClusterSecret(ts, "api-tls")).To(BeTrue())
+			Expect(isClusterSecret(ts, "other-tls")).To(BeFalse())
+		})
+
+		It("should verify the api against the CA of the certificate secret", func() {
+			server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				_, _ = w.Write([]byte("{\"ok\": true}"))
+			}))
+			defer server.Close()
+
+			ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "api-tls", Namespace: "search"},
+				Data: map[string][]byte{
+					corev1.TLSCertKey:       ca,
+					corev1.TLSPrivateKeyKey: []byte("key"),
+					"root.pem":              ca,
+				},
+			}
+			c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(secret).Build()
+
+			ts := newCluster(&tsv1alpha1.TLSSpec{SecretName: ptr.To("api-tls"), CAKey: "root.pem"})
+			tc := newTypesenseClient(server.URL, "admin")
+			Expect(setTypesenseClientTLS(ctx, c, ts, tc)).To(Succeed())
+
+			var health map[string]bool
+			Expect(tc.get(ctx, "/health", &health)).To(Succeed())
+			Expect(health["ok"]).To(BeTrue())
+
+			untrusted := newTypesenseClient(server.URL, "admin")
+			Expect(untrusted.get(ctx, "/health", &health)).NotTo(Succeed())
+
+			secret.Data = map[string][]byte{corev1.TLSCertKey: ca, corev1.TLSPrivateKeyKey: []byte("key")}
+			Expect(c.Update(ctx, secret)).To(Succeed())
+			Expect(setTypesenseClientTLS(ctx, c, ts, tc)).To(MatchError(ContainSubstring("does not contain key root.pem")))
+		})
+
+		It("should proxy the ingress over https", func() {
+			reconciler := &TypesenseClusterReconciler{logger: log.Log}
+			ts := newCluster(&tsv1alpha1.TLSSpec{SecretName: ptr.To("api-tls")})
+			ts.Spec.Ingress = &tsv1alpha1.IngressSpec{Host: "search.example.com"}
+
+			conf, err := reconciler.getIngressNginxConf(ts)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(conf).To(ContainSubstring("proxy_pass https://cluster-1-svc:8108/;"))
+			Expect(conf).To(ContainSubstring("proxy_ssl_trusted_certificate /etc/typesense/tls/ca.crt;"))
+
+			volumes := reconciler.getDefaultReverseProxyVolumes(ts)
+			Expect(volumes).To(HaveLen(2))
+			Expect(volumes[1].Secret.SecretName).To(Equal("api-tls"))
+			Expect(volumes[1].Secret.Items).To(Equal([]corev1.KeyToPath{{Key: "ca.crt", Path: "ca.crt"}}))
+
+			ts.Spec.TLS = nil
+			conf, err = reconciler.getIngressNginxConf(ts)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(conf).To(ContainSubstring("proxy_pass http://cluster-1-svc:8108/;"))
+			Expect(conf).NotTo(ContainSubstring("proxy_ssl_verify"))
+		})
+	})
+})
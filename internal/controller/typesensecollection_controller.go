@@ -0,0 +1,469 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-logr/logr"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"net/url"
+	"reflect"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"strconv"
+	"strings"
+
+	tsv1alpha1 "github.com/akyriako/typesense-operator/api/v1alpha1"
+)
+
+const (
+	ConditionReasonBreakingChange  = "BreakingChange"
+	ConditionReasonMigrating       = "Migrating"
+	ConditionReasonMigrationFailed = "MigrationFailed"
+	CollectionDeletionPolicyRetain = "Retain"
+	CollectionDeletionPolicyDelete = "Delete"
+	CollectionPhysicalNameFormat   = "%s_v%d"
+	CollectionMigrationJob         = "%s-migrate-v%d"
+)
+
+// TypesenseCollectionReconciler reconciles a TypesenseCollection object
+type TypesenseCollectionReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	logger   logr.Logger
+	Recorder record.EventRecorder
+
+	// JobImage runs the Jobs and CronJobs that do not set an image of their own, it provides sh, curl and jq
+	JobImage string
+}
+
+// +kubebuilder:rbac:groups=ts.opentelekomcloud.com,resources=typesensecollections,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=ts.opentelekomcloud.com,resources=typesensecollections/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=ts.opentelekomcloud.com,resources=typesensecollections/finalizers,verbs=update
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;update;patch;delete
+
+// Reconcile keeps the alias of a TypesenseCollection pointing to a physical collection with the desired schema.
+// Non-breaking changes are patched in place, a revision bump migrates the documents to a new physical collection.
+func (r *TypesenseCollectionReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	r.logger = log.Log.WithValues("namespace", req.Namespace, "collection", req.Name)
+	r.logger.Info("reconciling collection")
+
+	var col tsv1alpha1.TypesenseCollection
+	if err := r.Get(ctx, req.NamespacedName, &col); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	ts, tc, reason, err := getTargetCluster(ctx, r.Client, col.Namespace, col.Spec.ClusterRef)
+	if err != nil {
+		if !col.DeletionTimestamp.IsZero() && reason == ConditionReasonClusterNotFound {
+			return ctrl.Result{}, removeDataPlaneFinalizer(ctx, r.Client, &col)
+		}
+
+		r.logger.Error(err, "resolving target cluster failed")
+		return ctrl.Result{RequeueAfter: dataPlaneRequeueAfter}, r.setCollectionCondition(ctx, &col, reason, err)
+	}
+
+	alias := getCollectionAlias(&col)
+
+	if !col.DeletionTimestamp.IsZero() {
+		if controllerutil.ContainsFinalizer(&col, dataPlaneFinalizer) && col.Spec.DeletionPolicy == CollectionDeletionPolicyDelete {
+			if err := r.deleteCollection(ctx, tc, alias, &col); err != nil {
+				r.logger.Error(err, "deleting collection failed")
+				return ctrl.Result{}, err
+			}
+		}
+
+		return ctrl.Result{}, removeDataPlaneFinalizer(ctx, r.Client, &col)
+	}
+
+	if err := addDataPlaneFinalizer(ctx, r.Client, &col); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if migration := col.Status.Migration; migration != nil && migration.Phase != tsv1alpha1.CollectionMigrationPhaseCompleted {
+		return r.ReconcileMigration(ctx, ts, tc, &col)
+	}
+
+	current, err := tc.getAlias(ctx, alias)
+	if err != nil && !isTypesenseNotFound(err) {
+		return ctrl.Result{RequeueAfter: dataPlaneRequeueAfter}, r.setCollectionCondition(ctx, &col, ConditionReasonSyncFailed, err)
+	}
+
+	// a collection that is named after the alias was created outside of the operator, it is adopted in place
+	if current == nil {
+		if _, err := tc.getCollection(ctx, alias); err == nil {
+			current = &typesenseAlias{Name: alias, CollectionName: alias}
+		} else if !isTypesenseNotFound(err) {
+			return ctrl.Result{RequeueAfter: dataPlaneRequeueAfter}, r.setCollectionCondition(ctx, &col, ConditionReasonSyncFailed, err)
+		}
+	}
+
+	diff, err := r.diffCollection(ctx, tc, alias, current, &col)
+	if err != nil {
+		r.logger.Error(err, "reading live collection failed")
+		return ctrl.Result{RequeueAfter: dataPlaneRequeueAfter}, r.setCollectionCondition(ctx, &col, ConditionReasonSyncFailed, err)
+	}
+
+	drifted, apply := evaluateDrift(col.Spec.DriftDetection, col.Status.Conditions, col.Generation, diff)
+	if drifted {
+		r.logger.Info("collection drifted from the spec", "differences", len(diff), "reconcile", apply)
+		recordDriftEvent(r.Recorder, &col, apply, diff)
+	}
+
+	requeueAfter := getDriftRequeueAfter(col.Spec.DriftDetection)
+	if !apply {
+		return ctrl.Result{RequeueAfter: requeueAfter}, r.setCollectionSynced(ctx, &col, col.Status.Collection, col.Status.Revision, drifted, apply, diff)
+	}
+
+	// the alias is pointed back to the physical collection that was synced last, recreating it if it was dropped
+	if physical := col.Status.Collection; drifted && (current == nil || current.CollectionName != physical || !r.collectionExists(ctx, tc, physical)) {
+		if err := r.createCollection(ctx, tc, physical, &col); err != nil {
+			return ctrl.Result{RequeueAfter: dataPlaneRequeueAfter}, r.setCollectionCondition(ctx, &col, ConditionReasonSyncFailed, err)
+		}
+
+		r.logger.V(debugLevel).Info("restoring collection alias", "collection", physical)
+		if err := tc.upsertAlias(ctx, alias, physical); err != nil {
+			return ctrl.Result{RequeueAfter: dataPlaneRequeueAfter}, r.setCollectionCondition(ctx, &col, ConditionReasonSyncFailed, err)
+		}
+
+		current = &typesenseAlias{Name: alias, CollectionName: physical}
+	}
+
+	revision := getCollectionRevision(&col)
+
+	if current == nil {
+		physical := fmt.Sprintf(CollectionPhysicalNameFormat, alias, revision)
+		if err := r.createCollection(ctx, tc, physical, &col); err != nil {
+			r.logger.Error(err, "creating collection failed", "collection", physical)
+			r.Recorder.Event(&col, "Warning", ConditionReasonSyncFailed, err.Error())
+
+			return ctrl.Result{RequeueAfter: dataPlaneRequeueAfter}, r.setCollectionCondition(ctx, &col, ConditionReasonSyncFailed, err)
+		}
+
+		if err := tc.upsertAlias(ctx, alias, physical); err != nil {
+			return ctrl.Result{RequeueAfter: dataPlaneRequeueAfter}, r.setCollectionCondition(ctx, &col, ConditionReasonSyncFailed, err)
+		}
+
+		return ctrl.Result{RequeueAfter: requeueAfter}, r.setCollectionSynced(ctx, &col, physical, revision, drifted, apply, diff)
+	}
+
+	currentRevision := getCurrentCollectionRevision(&col, current.CollectionName)
+	if revision < currentRevision {
+		err := fmt.Errorf("revision cannot be decreased from %d to %d", currentRevision, revision)
+		return ctrl.Result{}, r.setCollectionCondition(ctx, &col, ConditionReasonInvalidSpec, err)
+	}
+
+	if revision > currentRevision {
+		r.logger.Info("starting collection migration", "source", current.CollectionName, "revision", revision)
+		r.Recorder.Eventf(&col, "Normal", ConditionReasonMigrating, "Migrating %s to revision %d", current.CollectionName, revision)
+
+		err := patchObjectStatus(ctx, r.Client, &col, func() {
+			col.Status.Migration = &tsv1alpha1.CollectionMigrationStatus{
+				Phase:              tsv1alpha1.CollectionMigrationPhaseCreating,
+				Source:             current.CollectionName,
+				Target:             fmt.Sprintf(CollectionPhysicalNameFormat, alias, revision),
+				Revision:           revision,
+				ObservedGeneration: col.Generation,
+				StartTime:          ptr.To(metav1.Now()),
+			}
+		})
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
+		return r.ReconcileMigration(ctx, ts, tc, &col)
+	}
+
+	if reason, err := r.patchCollection(ctx, tc, current.CollectionName, &col); err != nil {
+		r.logger.Error(err, "updating collection schema failed", "collection", current.CollectionName)
+		r.Recorder.Event(&col, "Warning", reason, err.Error())
+
+		return ctrl.Result{RequeueAfter: dataPlaneRequeueAfter}, r.setCollectionCondition(ctx, &col, reason, err)
+	}
+
+	return ctrl.Result{RequeueAfter: requeueAfter}, r.setCollectionSynced(ctx, &col, current.CollectionName, currentRevision, drifted, apply, diff)
+}
+
+// diffCollection compares the alias and the fields of the physical collection that was synced last with the spec.
+// Field definitions that changed are not drift, Typesense only allows adding and dropping fields in place.
+func (r *TypesenseCollectionReconciler) diffCollection(ctx context.Context, tc *typesenseClient, alias string, current *typesenseAlias, col *tsv1alpha1.TypesenseCollection) ([]string, error) {
+	physical := col.Status.Collection
+	if physical == "" {
+		return nil, nil
+	}
+
+	var diff []string
+	if current == nil {
+		diff = append(diff, fmt.Sprintf("alias %s is missing", alias))
+	} else if current.CollectionName != physical {
+		diff = append(diff, fmt.Sprintf("alias %s points to %s, expected %s", alias, current.CollectionName, physical))
+	}
+
+	live, err := tc.getCollection(ctx, physical)
+	if err != nil {
+		if isTypesenseNotFound(err) {
+			return append(diff, fmt.Sprintf("collection %s is missing", physical)), nil
+		}
+		return nil, err
+	}
+
+	changes, _ := diffCollectionFields(live.Fields, buildTypesenseCollection(physical, col).Fields)
+	for _, field := range changes {
+		if ptr.Deref(field.Drop, false) {
+			diff = append(diff, fmt.Sprintf("collection %s field %s is not in the spec", physical, field.Name))
+		} else {
+			diff = append(diff, fmt.Sprintf("collection %s field %s is missing", physical, field.Name))
+		}
+	}
+
+	return diff, nil
+}
+
+func (r *TypesenseCollectionReconciler) collectionExists(ctx context.Context, tc *typesenseClient, name string) bool {
+	_, err := tc.getCollection(ctx, name)
+	return err == nil
+}
+
+func (r *TypesenseCollectionReconciler) createCollection(ctx context.Context, tc *typesenseClient, name string, col *tsv1alpha1.TypesenseCollection) error {
+	_, err := tc.getCollection(ctx, name)
+	if err == nil || !isTypesenseNotFound(err) {
+		return err
+	}
+
+	r.logger.V(debugLevel).Info("creating collection", "collection", name)
+	return tc.post(ctx, "/collections", buildTypesenseCollection(name, col), nil)
+}
+
+// patchCollection applies the non-breaking part of a schema change, adding and dropping fields.
+// Fields whose definition changed can only be applied by bumping the revision.
+func (r *TypesenseCollectionReconciler) patchCollection(ctx context.Context, tc *typesenseClient, name string, col *tsv1alpha1.TypesenseCollection) (string, error) {
+	live, err := tc.getCollection(ctx, name)
+	if err != nil {
+		if isTypesenseNotFound(err) {
+			return ConditionReasonCollectionNotFound, fmt.Errorf("collection %s was not found", name)
+		}
+		return ConditionReasonSyncFailed, err
+	}
+
+	changes, breaking := diffCollectionFields(live.Fields, buildTypesenseCollection(name, col).Fields)
+	if len(breaking) > 0 {
+		return ConditionReasonBreakingChange, fmt.Errorf("fields %s changed in a breaking way, bump the revision to migrate the collection", strings.Join(breaking, ", "))
+	}
+
+	if len(changes) == 0 {
+		return "", nil
+	}
+
+	r.logger.V(debugLevel).Info("patching collection schema", "collection", name, "changes", len(changes))
+	if err := tc.patch(ctx, fmt.Sprintf("/collections/%s", url.PathEscape(name)), map[string]any{"fields": changes}, nil); err != nil {
+		return ConditionReasonSyncFailed, err
+	}
+
+	return "", nil
+}
+
+// diffCollectionFields returns the fields to add or drop to go from live to desired,
+// as well as the names of the fields whose definition changed.
+func diffCollectionFields(live []typesenseCollectionField, desired []typesenseCollectionField) ([]typesenseCollectionField, []string) {
+	liveFields := make(map[string]typesenseCollectionField, len(live))
+	for _, field := range live {
+		liveFields[field.Name] = field
+	}
+
+	var changes []typesenseCollectionField
+	var breaking []string
+	desiredFields := make(map[string]bool, len(desired))
+
+	for _, field := range desired {
+		desiredFields[field.Name] = true
+
+		current, ok := liveFields[field.Name]
+		if !ok {
+			changes = append(changes, field)
+			continue
+		}
+
+		if !isSameCollectionField(current, field) {
+			breaking = append(breaking, field.Name)
+		}
+	}
+
+	for _, field := range live {
+		if !desiredFields[field.Name] {
+			changes = append(changes, typesenseCollectionField{Name: field.Name, Drop: ptr.To(true)})
+		}
+	}
+
+	return changes, breaking
+}
+
+// isSameCollectionField compares the attributes set in the desired field,
+// attributes left out are defaulted by Typesense and are not compared.
+func isSameCollectionField(live typesenseCollectionField, desired typesenseCollectionField) bool {
+	if live.Type != desired.Type {
+		return false
+	}
+
+	same := func(l *bool, d *bool) bool {
+		return d == nil || ptr.Deref(l, false) == *d
+	}
+
+	return same(live.Facet, desired.Facet) &&
+		same(live.Optional, desired.Optional) &&
+		same(live.Index, desired.Index) &&
+		same(live.Sort, desired.Sort) &&
+		same(live.Infix, desired.Infix) &&
+		same(live.Stem, desired.Stem) &&
+		(desired.Locale == "" || live.Locale == desired.Locale) &&
+		(desired.StemDictionary == "" || live.StemDictionary == desired.StemDictionary) &&
+		(desired.Reference == "" || live.Reference == desired.Reference) &&
+		(desired.NumDim == nil || reflect.DeepEqual(live.NumDim, desired.NumDim))
+}
+
+func (r *TypesenseCollectionReconciler) deleteCollection(ctx context.Context, tc *typesenseClient, alias string, col *tsv1alpha1.TypesenseCollection) error {
+	current, err := tc.getAlias(ctx, alias)
+	if err != nil {
+		if isTypesenseNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	r.logger.V(debugLevel).Info("deleting alias and collection", "alias", alias, "collection", current.CollectionName)
+	if err := tc.delete(ctx, fmt.Sprintf("/aliases/%s", url.PathEscape(alias))); err != nil && !isTypesenseNotFound(err) {
+		return err
+	}
+
+	if err := tc.delete(ctx, fmt.Sprintf("/collections/%s", url.PathEscape(current.CollectionName))); err != nil && !isTypesenseNotFound(err) {
+		return err
+	}
+
+	return nil
+}
+
+func buildTypesenseCollection(name string, col *tsv1alpha1.TypesenseCollection) typesenseCollection {
+	collection := typesenseCollection{
+		Name:                name,
+		DefaultSortingField: ptr.Deref(col.Spec.DefaultSortingField, ""),
+		TokenSeparators:     col.Spec.TokenSeparators,
+		SymbolsToIndex:      col.Spec.SymbolsToIndex,
+		EnableNestedFields:  col.Spec.EnableNestedFields,
+		Fields:              buildTypesenseCollectionFields(col.Spec.Fields),
+	}
+
+	return collection
+}
+
+func buildTypesenseCollectionFields(specs []tsv1alpha1.CollectionFieldSpec) []typesenseCollectionField {
+	fields := make([]typesenseCollectionField, 0, len(specs))
+	for _, field := range specs {
+		fields = append(fields, typesenseCollectionField{
+			Name:           field.Name,
+			Type:           field.Type,
+			Facet:          field.Facet,
+			Optional:       field.Optional,
+			Index:          field.Index,
+			Sort:           field.Sort,
+			Infix:          field.Infix,
+			Locale:         ptr.Deref(field.Locale, ""),
+			Stem:           field.Stem,
+			StemDictionary: ptr.Deref(field.StemDictionary, ""),
+			Reference:      ptr.Deref(field.Reference, ""),
+			NumDim:         field.NumDim,
+		})
+	}
+
+	return fields
+}
+
+func getCollectionAlias(col *tsv1alpha1.TypesenseCollection) string {
+	return ptr.Deref(col.Spec.Name, col.Name)
+}
+
+func getCollectionRevision(col *tsv1alpha1.TypesenseCollection) int {
+	if col.Spec.Revision < 1 {
+		return 1
+	}
+
+	return col.Spec.Revision
+}
+
+// getCurrentCollectionRevision returns the revision of the physical collection the alias points to. The revision is
+// kept in the status along with the physical collection, names are not parsed as a collection such as events_v2024
+// may as well be made by hand. Collections that were not created by the operator are adopted as the first revision,
+// or as the desired one when they are named after it.
+func getCurrentCollectionRevision(col *tsv1alpha1.TypesenseCollection, physical string) int {
+	revision := getCollectionRevision(col)
+
+	switch physical {
+	case col.Status.Collection:
+		return max(col.Status.Revision, 1)
+	case fmt.Sprintf(CollectionPhysicalNameFormat, getCollectionAlias(col), revision):
+		return revision
+	default:
+		return 1
+	}
+}
+
+// getPhysicalCollectionRevision parses the revision out of an <alias>_v<revision> collection name, names that do not
+// follow it exactly, including revisions with leading zeros or signs, are considered revision 0.
+func getPhysicalCollectionRevision(alias string, name string) int {
+	suffix, found := strings.CutPrefix(name, alias+"_v")
+	if !found {
+		return 0
+	}
+
+	revision, err := strconv.Atoi(suffix)
+	if err != nil || revision < 1 || strconv.Itoa(revision) != suffix {
+		return 0
+	}
+
+	return revision
+}
+
+func (r *TypesenseCollectionReconciler) setCollectionSynced(ctx context.Context, col *tsv1alpha1.TypesenseCollection, physical string, revision int, drifted bool, applied bool, diff []string) error {
+	r.logger.Info("reconciling collection completed", "physical", physical, "revision", revision)
+
+	return patchObjectStatus(ctx, r.Client, col, func() {
+		setDataPlaneCondition(&col.Status.Conditions, col.Generation, ConditionReasonSynced, nil)
+		setDriftCondition(&col.Status.Conditions, col.Generation, col.Spec.DriftDetection, drifted, applied, diff)
+		col.Status.Collection = physical
+		col.Status.Revision = revision
+		col.Status.LastSyncTime = ptr.To(metav1.Now())
+	})
+}
+
+func (r *TypesenseCollectionReconciler) setCollectionCondition(ctx context.Context, col *tsv1alpha1.TypesenseCollection, reason string, err error) error {
+	return patchObjectStatus(ctx, r.Client, col, func() {
+		setDataPlaneCondition(&col.Status.Conditions, col.Generation, reason, err)
+	})
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *TypesenseCollectionReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&tsv1alpha1.TypesenseCollection{}, eventFilters).
+		Owns(&batchv1.Job{}).
+		Complete(r)
+}
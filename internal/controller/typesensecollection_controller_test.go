@@ -0,0 +1,115 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	tsv1alpha1 "github.com/akyriako/typesense-operator/api/v1alpha1"
+)
+
+var _ = Describe("TypesenseCollection Controller", func() {
+	Context("When diffing collection schemas", func() {
+		live := []typesenseCollectionField{
+			{Name: "name", Type: "string", Facet: ptr.To(false)},
+			{Name: "price", Type: "float", Facet: ptr.To(true)},
+			{Name: "legacy", Type: "string"},
+		}
+
+		It("should add and drop fields in place", func() {
+			desired := []typesenseCollectionField{
+				{Name: "name", Type: "string"},
+				{Name: "price", Type: "float", Facet: ptr.To(true)},
+				{Name: "brand", Type: "string", Facet: ptr.To(true)},
+			}
+
+			changes, breaking := diffCollectionFields(live, desired)
+			Expect(breaking).To(BeEmpty())
+			Expect(changes).To(ConsistOf(
+				typesenseCollectionField{Name: "brand", Type: "string", Facet: ptr.To(true)},
+				typesenseCollectionField{Name: "legacy", Drop: ptr.To(true)},
+			))
+		})
+
+		It("should report changed field definitions as breaking", func() {
+			desired := []typesenseCollectionField{
+				{Name: "name", Type: "string", Facet: ptr.To(true)},
+				{Name: "price", Type: "int64"},
+				{Name: "legacy", Type: "string"},
+			}
+
+			_, breaking := diffCollectionFields(live, desired)
+			Expect(breaking).To(ConsistOf("name", "price"))
+		})
+
+		It("should take the revision of the current collection from the status", func() {
+			col := &tsv1alpha1.TypesenseCollection{
+				ObjectMeta: metav1.ObjectMeta{Name: "events"},
+				Spec:       tsv1alpha1.TypesenseCollectionSpec{Revision: 2},
+				Status:     tsv1alpha1.TypesenseCollectionStatus{Collection: "events_v1", Revision: 1},
+			}
+
+			Expect(getCurrentCollectionRevision(col, "events_v1")).To(Equal(1))
+			Expect(getCurrentCollectionRevision(col, "events_v2")).To(Equal(2))
+			Expect(getCurrentCollectionRevision(col, "events_v2024")).To(Equal(1))
+		})
+
+		It("should parse the revision of physical collections", func() {
+			Expect(getPhysicalCollectionRevision("products", "products_v3")).To(Equal(3))
+			Expect(getPhysicalCollectionRevision("products", "products")).To(Equal(0))
+			Expect(getPhysicalCollectionRevision("my", "my_vintage")).To(Equal(0))
+			Expect(getPhysicalCollectionRevision("events", "events_v03")).To(Equal(0))
+			Expect(getPhysicalCollectionRevision("events", "events_v2024_v2")).To(Equal(0))
+			Expect(getPhysicalCollectionRevision("events_v2024", "events_v2024_v2")).To(Equal(2))
+		})
+	})
+
+	Context("When building the migration job", func() {
+		It("should copy from the source to the target collection through the transform", func() {
+			ts := &tsv1alpha1.TypesenseCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster-1", Namespace: "default"},
+				Spec:       tsv1alpha1.TypesenseClusterSpec{ApiPort: 8108},
+			}
+			col := &tsv1alpha1.TypesenseCollection{
+				ObjectMeta: metav1.ObjectMeta{Name: "products", Namespace: "default"},
+				Spec: tsv1alpha1.TypesenseCollectionSpec{
+					ClusterRef: corev1.LocalObjectReference{Name: "cluster-1"},
+					Revision:   2,
+					Migration:  &tsv1alpha1.CollectionMigrationSpec{Transform: ptr.To(".price |= tonumber")},
+				},
+			}
+			migration := &tsv1alpha1.CollectionMigrationStatus{Source: "products_v1", Target: "products_v2", Revision: 2}
+
+			reconciler := &TypesenseCollectionReconciler{JobImage: "typesense-operator:test"}
+			job := reconciler.buildMigrationJob(client.ObjectKey{Namespace: "default", Name: "products-migrate-v2"}, ts, col, migration)
+
+			container := job.Spec.Template.Spec.Containers[0]
+			Expect(container.Image).To(Equal("typesense-operator:test"))
+			Expect(container.Env).To(ContainElements(
+				corev1.EnvVar{Name: "SOURCE_COLLECTION", Value: "products_v1"},
+				corev1.EnvVar{Name: "TARGET_COLLECTION", Value: "products_v2"},
+				corev1.EnvVar{Name: "TRANSFORM", Value: ".price |= tonumber"},
+				corev1.EnvVar{Name: "TYPESENSE_HOST", Value: "cluster-1-svc"},
+			))
+		})
+	})
+})
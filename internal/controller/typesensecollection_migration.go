@@ -0,0 +1,368 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	tsv1alpha1 "github.com/akyriako/typesense-operator/api/v1alpha1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"net/url"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"strconv"
+	"time"
+)
+
+const (
+	collectionMigrationPollInterval = 10 * time.Second
+
+	// collectionMigrationScript streams the export of the source collection through the jq transform into the import
+	// of the target collection, no document is held on disk or in memory; any line of the import that did not succeed
+	// fails the Job. The import upserts, so a retried Job copies the collection again without duplicates.
+	collectionMigrationScript = `set -euo pipefail
+base="${TYPESENSE_PROTOCOL}://${TYPESENSE_HOST}:${TYPESENSE_PORT}"
+curl -sSfN -H "X-TYPESENSE-API-KEY: ${TYPESENSE_API_KEY}" "${base}/collections/${SOURCE_COLLECTION}/documents/export" \
+  | jq -c "${TRANSFORM}" \
+  | curl -sSfN -H "X-TYPESENSE-API-KEY: ${TYPESENSE_API_KEY}" -H "Content-Type: text/plain" -X POST -T - \
+    "${base}/collections/${TARGET_COLLECTION}/documents/import?action=upsert&batch_size=${BATCH_SIZE}" \
+  | awk -v source="${SOURCE_COLLECTION}" -v target="${TARGET_COLLECTION}" '
+    { total++ }
+    !/"success": *true/ { failed++; if (failed <= 10) print }
+    END {
+      if (failed > 0) { printf "importing %d of %d documents in %s failed\n", failed, total, target; exit 1 }
+      printf "copied %d documents from %s to %s\n", total, source, target
+    }'
+`
+)
+
+// ReconcileMigration drives a migration through its phases. Every phase is recorded in the status before
+// moving on, so a migration interrupted by an operator restart resumes where it left off.
+func (r *TypesenseCollectionReconciler) ReconcileMigration(ctx context.Context, ts *tsv1alpha1.TypesenseCluster, tc *typesenseClient, col *tsv1alpha1.TypesenseCollection) (ctrl.Result, error) {
+	migration := col.Status.Migration
+	r.logger.V(debugLevel).Info("reconciling collection migration", "phase", migration.Phase, "source", migration.Source, "target", migration.Target)
+
+	if getCollectionRevision(col) != migration.Revision && migration.Phase != tsv1alpha1.CollectionMigrationPhaseSwapping && migration.Phase != tsv1alpha1.CollectionMigrationPhaseRetiring {
+		return ctrl.Result{Requeue: true}, r.abortMigration(ctx, tc, col)
+	}
+
+	if migration.Phase == tsv1alpha1.CollectionMigrationPhaseFailed {
+		if migration.ObservedGeneration == col.Generation {
+			return ctrl.Result{}, nil
+		}
+
+		r.logger.Info("retrying failed collection migration", "target", migration.Target)
+		if err := r.deleteMigrationJob(ctx, col, migration); err != nil {
+			return ctrl.Result{}, err
+		}
+
+		if err := r.setMigrationPhase(ctx, col, tsv1alpha1.CollectionMigrationPhaseCreating, ""); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	for {
+		var (
+			next    tsv1alpha1.CollectionMigrationPhase
+			message string
+			err     error
+		)
+
+		switch migration.Phase {
+		case tsv1alpha1.CollectionMigrationPhaseCreating:
+			err = r.createCollection(ctx, tc, migration.Target, col)
+			next = tsv1alpha1.CollectionMigrationPhaseCopying
+
+		case tsv1alpha1.CollectionMigrationPhaseCopying:
+			var done bool
+			done, message, err = r.reconcileMigrationJob(ctx, ts, col, migration)
+			if err == nil && !done && message == "" {
+				r.recordMigrationProgress(ctx, tc, col)
+				return ctrl.Result{RequeueAfter: collectionMigrationPollInterval}, r.setMigrationCondition(ctx, col)
+			}
+			next = tsv1alpha1.CollectionMigrationPhaseVerifying
+
+		case tsv1alpha1.CollectionMigrationPhaseVerifying:
+			message, err = r.verifyMigration(ctx, tc, col, migration)
+			next = tsv1alpha1.CollectionMigrationPhaseSwapping
+
+		case tsv1alpha1.CollectionMigrationPhaseSwapping:
+			err = tc.upsertAlias(ctx, getCollectionAlias(col), migration.Target)
+			next = tsv1alpha1.CollectionMigrationPhaseRetiring
+
+		case tsv1alpha1.CollectionMigrationPhaseRetiring:
+			if col.Spec.Migration == nil || !col.Spec.Migration.RetainPrevious {
+				err = tc.delete(ctx, fmt.Sprintf("/collections/%s", url.PathEscape(migration.Source)))
+				if isTypesenseNotFound(err) {
+					err = nil
+				}
+			}
+			next = tsv1alpha1.CollectionMigrationPhaseCompleted
+
+		default:
+			return ctrl.Result{}, nil
+		}
+
+		if err != nil {
+			r.logger.Error(err, "collection migration failed", "phase", migration.Phase)
+			return ctrl.Result{RequeueAfter: dataPlaneRequeueAfter}, r.setCollectionCondition(ctx, col, ConditionReasonMigrationFailed, err)
+		}
+
+		if message != "" {
+			r.logger.Info("collection migration failed", "phase", migration.Phase, "reason", message)
+			r.Recorder.Event(col, "Warning", ConditionReasonMigrationFailed, message)
+
+			if err := r.setMigrationPhase(ctx, col, tsv1alpha1.CollectionMigrationPhaseFailed, message); err != nil {
+				return ctrl.Result{}, err
+			}
+			return ctrl.Result{}, r.setCollectionCondition(ctx, col, ConditionReasonMigrationFailed, fmt.Errorf("%s", message))
+		}
+
+		if next == tsv1alpha1.CollectionMigrationPhaseCompleted {
+			r.Recorder.Eventf(col, "Normal", ConditionReasonMigrating, "Migrated %s to %s", migration.Source, migration.Target)
+
+			err := patchObjectStatus(ctx, r.Client, col, func() {
+				col.Status.Migration.Phase = tsv1alpha1.CollectionMigrationPhaseCompleted
+				col.Status.Migration.Message = ""
+				col.Status.Migration.CompletionTime = ptr.To(metav1.Now())
+			})
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+
+			return ctrl.Result{}, r.setCollectionSynced(ctx, col, migration.Target, migration.Revision, false, true, nil)
+		}
+
+		if err := r.setMigrationPhase(ctx, col, next, ""); err != nil {
+			return ctrl.Result{}, err
+		}
+		migration = col.Status.Migration
+	}
+}
+
+// reconcileMigrationJob creates the copy Job if it does not exist yet and reports whether it completed,
+// or why it failed.
+func (r *TypesenseCollectionReconciler) reconcileMigrationJob(ctx context.Context, ts *tsv1alpha1.TypesenseCluster, col *tsv1alpha1.TypesenseCollection, migration *tsv1alpha1.CollectionMigrationStatus) (bool, string, error) {
+	key := client.ObjectKey{Namespace: col.Namespace, Name: fmt.Sprintf(CollectionMigrationJob, col.Name, migration.Revision)}
+
+	job := &batchv1.Job{}
+	if err := r.Get(ctx, key, job); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return false, "", err
+		}
+
+		r.logger.V(debugLevel).Info("creating collection migration job", "job", key.Name)
+		job = r.buildMigrationJob(key, ts, col, migration)
+		if err := ctrl.SetControllerReference(col, job, r.Scheme); err != nil {
+			return false, "", err
+		}
+
+		if err := r.Create(ctx, job); err != nil {
+			return false, "", err
+		}
+
+		return false, "", patchObjectStatus(ctx, r.Client, col, func() {
+			col.Status.Migration.Job = key.Name
+		})
+	}
+
+	for _, condition := range job.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+
+		switch condition.Type {
+		case batchv1.JobComplete:
+			return true, "", nil
+		case batchv1.JobFailed:
+			return false, fmt.Sprintf("copy job %s failed: %s", job.Name, condition.Message), nil
+		}
+	}
+
+	return false, "", nil
+}
+
+func (r *TypesenseCollectionReconciler) buildMigrationJob(key client.ObjectKey, ts *tsv1alpha1.TypesenseCluster, col *tsv1alpha1.TypesenseCollection, migration *tsv1alpha1.CollectionMigrationStatus) *batchv1.Job {
+	image, transform, batchSize := r.JobImage, ".", 1000
+	if spec := col.Spec.Migration; spec != nil {
+		if spec.Image != "" {
+			image = spec.Image
+		}
+		if spec.BatchSize > 0 {
+			batchSize = spec.BatchSize
+		}
+		transform = ptr.Deref(spec.Transform, transform)
+	}
+
+	return &batchv1.Job{
+		ObjectMeta: getObjectMeta(ts, &key.Name, nil),
+		Spec: batchv1.JobSpec{
+			BackoffLimit:            ptr.To[int32](3),
+			TTLSecondsAfterFinished: ptr.To[int32](86400),
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: getApiClientLabels(ts)},
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					Containers: []corev1.Container{
+						{
+							Name:    "migrate",
+							Image:   image,
+							Command: []string{"/bin/sh", "-c", collectionMigrationScript},
+							Env: append([]corev1.EnvVar{
+								{
+									Name: "TYPESENSE_API_KEY",
+									ValueFrom: &corev1.EnvVarSource{
+										SecretKeyRef: &corev1.SecretKeySelector{
+											Key: ts.Spec.GetAdminApiKeySecretKey(),
+											LocalObjectReference: corev1.LocalObjectReference{
+												Name: adminApiKeyObjectKey(ts).Name,
+											},
+										},
+									},
+								},
+								{
+									Name:  "TYPESENSE_HOST",
+									Value: fmt.Sprintf(ClusterRestService, ts.Name),
+								},
+								{
+									Name:  "TYPESENSE_PORT",
+									Value: strconv.Itoa(ts.Spec.ApiPort),
+								},
+								{
+									Name:  "TYPESENSE_PROTOCOL",
+									Value: getApiProtocol(ts),
+								},
+								{
+									Name:  "SOURCE_COLLECTION",
+									Value: migration.Source,
+								},
+								{
+									Name:  "TARGET_COLLECTION",
+									Value: migration.Target,
+								},
+								{
+									Name:  "TRANSFORM",
+									Value: transform,
+								},
+								{
+									Name:  "BATCH_SIZE",
+									Value: strconv.Itoa(batchSize),
+								},
+							}, getTLSClientEnv(ts)...),
+							Resources: corev1.ResourceRequirements{
+								Limits: corev1.ResourceList{
+									corev1.ResourceCPU:    resource.MustParse("1000m"),
+									corev1.ResourceMemory: resource.MustParse("512Mi"),
+								},
+								Requests: corev1.ResourceList{
+									corev1.ResourceCPU:    resource.MustParse("100m"),
+									corev1.ResourceMemory: resource.MustParse("128Mi"),
+								},
+							},
+							VolumeMounts: getTLSVolumeMounts(ts),
+						},
+					},
+					Volumes: getTLSVolumes(ts, false),
+				},
+			},
+		},
+	}
+}
+
+// recordMigrationProgress reports the documents copied so far in the status while the copy Job runs. Progress is
+// informational only, a failure to read it is logged and the migration carries on.
+func (r *TypesenseCollectionReconciler) recordMigrationProgress(ctx context.Context, tc *typesenseClient, col *tsv1alpha1.TypesenseCollection) {
+	migration := col.Status.Migration
+
+	source, err := tc.getCollection(ctx, migration.Source)
+	if err == nil {
+		var target *typesenseCollection
+		if target, err = tc.getCollection(ctx, migration.Target); err == nil {
+			err = patchObjectStatus(ctx, r.Client, col, func() {
+				col.Status.Migration.SourceDocuments = source.NumDocuments
+				col.Status.Migration.TargetDocuments = target.NumDocuments
+			})
+		}
+	}
+
+	if err != nil {
+		r.logger.Error(err, "recording collection migration progress failed")
+	}
+}
+
+// verifyMigration compares the document counts of both collections. Writes that hit the alias
+// while the documents were copied end up only in the source, in which case the migration fails and can be retried.
+func (r *TypesenseCollectionReconciler) verifyMigration(ctx context.Context, tc *typesenseClient, col *tsv1alpha1.TypesenseCollection, migration *tsv1alpha1.CollectionMigrationStatus) (string, error) {
+	source, err := tc.getCollection(ctx, migration.Source)
+	if err != nil {
+		return "", err
+	}
+
+	target, err := tc.getCollection(ctx, migration.Target)
+	if err != nil {
+		return "", err
+	}
+
+	err = patchObjectStatus(ctx, r.Client, col, func() {
+		col.Status.Migration.SourceDocuments = source.NumDocuments
+		col.Status.Migration.TargetDocuments = target.NumDocuments
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if source.NumDocuments != target.NumDocuments {
+		return fmt.Sprintf("document counts do not match, %s has %d and %s has %d", source.Name, source.NumDocuments, target.Name, target.NumDocuments), nil
+	}
+
+	return "", nil
+}
+
+// abortMigration drops the half-migrated target collection when the revision is rolled back before the alias swap.
+func (r *TypesenseCollectionReconciler) abortMigration(ctx context.Context, tc *typesenseClient, col *tsv1alpha1.TypesenseCollection) error {
+	migration := col.Status.Migration
+	r.logger.Info("aborting collection migration", "target", migration.Target)
+
+	if err := r.deleteMigrationJob(ctx, col, migration); err != nil {
+		return err
+	}
+
+	if err := tc.delete(ctx, fmt.Sprintf("/collections/%s", url.PathEscape(migration.Target))); err != nil && !isTypesenseNotFound(err) {
+		return err
+	}
+
+	return patchObjectStatus(ctx, r.Client, col, func() {
+		col.Status.Migration = nil
+	})
+}
+
+func (r *TypesenseCollectionReconciler) deleteMigrationJob(ctx context.Context, col *tsv1alpha1.TypesenseCollection, migration *tsv1alpha1.CollectionMigrationStatus) error {
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: col.Namespace,
+			Name:      fmt.Sprintf(CollectionMigrationJob, col.Name, migration.Revision),
+		},
+	}
+
+	err := r.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground))
+	return client.IgnoreNotFound(err)
+}
+
+func (r *TypesenseCollectionReconciler) setMigrationPhase(ctx context.Context, col *tsv1alpha1.TypesenseCollection, phase tsv1alpha1.CollectionMigrationPhase, message string) error {
+	return patchObjectStatus(ctx, r.Client, col, func() {
+		col.Status.Migration.Phase = phase
+		col.Status.Migration.Message = message
+		col.Status.Migration.ObservedGeneration = col.Generation
+	})
+}
+
+func (r *TypesenseCollectionReconciler) setMigrationCondition(ctx context.Context, col *tsv1alpha1.TypesenseCollection) error {
+	migration := col.Status.Migration
+
+	return patchObjectStatus(ctx, r.Client, col, func() {
+		setDataPlaneCondition(&col.Status.Conditions, col.Generation, ConditionReasonMigrating, fmt.Errorf("migrating %s to %s: %s", migration.Source, migration.Target, migration.Phase))
+	})
+}
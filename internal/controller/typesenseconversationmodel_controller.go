@@ -0,0 +1,284 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"github.com/go-logr/logr"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"net/url"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"strings"
+
+	tsv1alpha1 "github.com/akyriako/typesense-operator/api/v1alpha1"
+)
+
+const ConditionReasonApiKeyNotFound = "ApiKeyNotFound"
+
+// TypesenseConversationModelReconciler reconciles a TypesenseConversationModel object
+type TypesenseConversationModelReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	logger   logr.Logger
+	Recorder record.EventRecorder
+}
+
+type typesenseConversationModel struct {
+	ID                string  `json:"id"`
+	ModelName         string  `json:"model_name"`
+	ApiKey            string  `json:"api_key,omitempty"`
+	HistoryCollection string  `json:"history_collection"`
+	SystemPrompt      *string `json:"system_prompt,omitempty"`
+	MaxBytes          int     `json:"max_bytes"`
+	Ttl               *int    `json:"ttl,omitempty"`
+	VllmUrl           *string `json:"vllm_url,omitempty"`
+	OpenaiUrl         *string `json:"openai_url,omitempty"`
+}
+
+// +kubebuilder:rbac:groups=ts.opentelekomcloud.com,resources=typesenseconversationmodels,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=ts.opentelekomcloud.com,resources=typesenseconversationmodels/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=ts.opentelekomcloud.com,resources=typesenseconversationmodels/finalizers,verbs=update
+
+// Reconcile makes sure the history collection of a TypesenseConversationModel exists and registers the model,
+// registering it again whenever its configuration or the API key in the referenced Secret changes.
+func (r *TypesenseConversationModelReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	r.logger = log.Log.WithValues("namespace", req.Namespace, "conversationmodel", req.Name)
+	r.logger.Info("reconciling conversation model")
+
+	var model tsv1alpha1.TypesenseConversationModel
+	if err := r.Get(ctx, req.NamespacedName, &model); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	_, tc, reason, err := getTargetCluster(ctx, r.Client, model.Namespace, model.Spec.ClusterRef)
+	if err != nil {
+		if !model.DeletionTimestamp.IsZero() && reason == ConditionReasonClusterNotFound {
+			return ctrl.Result{}, removeDataPlaneFinalizer(ctx, r.Client, &model)
+		}
+
+		r.logger.Error(err, "resolving target cluster failed")
+		return ctrl.Result{RequeueAfter: dataPlaneRequeueAfter}, r.setModelCondition(ctx, &model, reason, err)
+	}
+
+	if !model.DeletionTimestamp.IsZero() {
+		if controllerutil.ContainsFinalizer(&model, dataPlaneFinalizer) {
+			if err := tc.delete(ctx, r.getModelPath(r.getModelID(&model))); err != nil && !isTypesenseNotFound(err) {
+				r.logger.Error(err, "deleting conversation model failed")
+				return ctrl.Result{}, err
+			}
+		}
+
+		return ctrl.Result{}, removeDataPlaneFinalizer(ctx, r.Client, &model)
+	}
+
+	if err := addDataPlaneFinalizer(ctx, r.Client, &model); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	desired, err := r.buildConversationModel(ctx, &model)
+	if err != nil {
+		r.logger.Error(err, "reading llm api key failed")
+		r.Recorder.Event(&model, "Warning", ConditionReasonApiKeyNotFound, err.Error())
+
+		return ctrl.Result{RequeueAfter: dataPlaneRequeueAfter}, r.setModelCondition(ctx, &model, ConditionReasonApiKeyNotFound, err)
+	}
+
+	if err := r.ensureHistoryCollection(ctx, tc, model.Spec.HistoryCollection); err != nil {
+		r.logger.Error(err, "creating history collection failed")
+		r.Recorder.Event(&model, "Warning", ConditionReasonSyncFailed, err.Error())
+
+		return ctrl.Result{RequeueAfter: dataPlaneRequeueAfter}, r.setModelCondition(ctx, &model, ConditionReasonSyncFailed, err)
+	}
+
+	diff, err := r.diffConversationModel(ctx, tc, desired)
+	if err != nil {
+		r.logger.Error(err, "reading conversation model failed")
+		return ctrl.Result{RequeueAfter: dataPlaneRequeueAfter}, r.setModelCondition(ctx, &model, ConditionReasonSyncFailed, err)
+	}
+
+	drifted, apply := evaluateDrift(model.Spec.DriftDetection, model.Status.Conditions, model.Generation, diff)
+	if drifted {
+		r.logger.Info("conversation model drifted from the spec", "differences", len(diff), "reconcile", apply)
+		recordDriftEvent(r.Recorder, &model, apply, diff)
+	}
+
+	// a rotated api key does not show up in the diff, so registration still runs when nothing drifted
+	hash := model.Status.ConfigurationHash
+	if apply || !drifted {
+		hash, err = r.registerConversationModel(ctx, tc, &model, desired, drifted)
+		if err != nil {
+			r.logger.Error(err, "registering conversation model failed")
+			r.Recorder.Event(&model, "Warning", ConditionReasonSyncFailed, err.Error())
+
+			return ctrl.Result{RequeueAfter: dataPlaneRequeueAfter}, r.setModelCondition(ctx, &model, ConditionReasonSyncFailed, err)
+		}
+	}
+
+	err = patchObjectStatus(ctx, r.Client, &model, func() {
+		setDataPlaneCondition(&model.Status.Conditions, model.Generation, ConditionReasonSynced, nil)
+		setDriftCondition(&model.Status.Conditions, model.Generation, model.Spec.DriftDetection, drifted, apply, diff)
+		model.Status.ModelID = desired.ID
+		model.Status.ConfigurationHash = hash
+		model.Status.LastSyncTime = ptr.To(metav1.Now())
+	})
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	r.logger.Info("reconciling conversation model completed", "model", desired.ID)
+	return ctrl.Result{RequeueAfter: getDriftRequeueAfter(model.Spec.DriftDetection)}, nil
+}
+
+// diffConversationModel compares the live model with the desired one. Typesense never returns
+// the api key in full, so it is left out of the comparison.
+func (r *TypesenseConversationModelReconciler) diffConversationModel(ctx context.Context, tc *typesenseClient, desired *typesenseConversationModel) ([]string, error) {
+	live := map[string]any{}
+
+	current := typesenseConversationModel{}
+	if err := tc.get(ctx, r.getModelPath(desired.ID), &current); err != nil {
+		if !isTypesenseNotFound(err) {
+			return nil, err
+		}
+	} else {
+		current.ApiKey = ""
+		live[desired.ID] = current
+	}
+
+	expected := *desired
+	expected.ApiKey = ""
+
+	return diffServerObjects("conversation model", map[string]any{desired.ID: expected}, live)
+}
+
+func (r *TypesenseConversationModelReconciler) buildConversationModel(ctx context.Context, model *tsv1alpha1.TypesenseConversationModel) (*typesenseConversationModel, error) {
+	desired := &typesenseConversationModel{
+		ID:                r.getModelID(model),
+		ModelName:         model.Spec.ModelName,
+		HistoryCollection: model.Spec.HistoryCollection,
+		SystemPrompt:      model.Spec.SystemPrompt,
+		MaxBytes:          model.Spec.MaxBytes,
+		Ttl:               model.Spec.Ttl,
+	}
+
+	if model.Spec.BaseUrl != nil {
+		if strings.HasPrefix(model.Spec.ModelName, "vllm/") {
+			desired.VllmUrl = model.Spec.BaseUrl
+		} else {
+			desired.OpenaiUrl = model.Spec.BaseUrl
+		}
+	}
+
+	if model.Spec.ApiKeySecretRef != nil {
+		apiKey, err := getSecretKey(ctx, r.Client, model.Namespace, model.Spec.ApiKeySecretRef)
+		if err != nil {
+			return nil, err
+		}
+		desired.ApiKey = apiKey
+	}
+
+	return desired, nil
+}
+
+// registerConversationModel creates or updates the model when its configuration hash differs from the one
+// last applied, when the model is missing from the server or when forced to revert drift, and returns the
+// hash of the applied configuration.
+func (r *TypesenseConversationModelReconciler) registerConversationModel(ctx context.Context, tc *typesenseClient, model *tsv1alpha1.TypesenseConversationModel, desired *typesenseConversationModel, force bool) (string, error) {
+	payload, err := json.Marshal(desired)
+	if err != nil {
+		return "", err
+	}
+	hash := fmt.Sprintf("%x", sha256.Sum256(payload))
+
+	exists := true
+	if err := tc.get(ctx, r.getModelPath(desired.ID), nil); err != nil {
+		if !isTypesenseNotFound(err) {
+			return "", err
+		}
+		exists = false
+	}
+
+	if exists && !force && model.Status.ConfigurationHash == hash {
+		r.logger.V(debugLevel).Info("conversation model is up to date", "model", desired.ID)
+		return hash, nil
+	}
+
+	if !exists {
+		r.logger.V(debugLevel).Info("creating conversation model", "model", desired.ID)
+		return hash, tc.post(ctx, "/conversations/models", desired, nil)
+	}
+
+	r.logger.V(debugLevel).Info("updating conversation model", "model", desired.ID)
+	return hash, tc.put(ctx, r.getModelPath(desired.ID), desired, nil)
+}
+
+// ensureHistoryCollection creates the collection conversations are stored in, with the schema Typesense expects.
+func (r *TypesenseConversationModelReconciler) ensureHistoryCollection(ctx context.Context, tc *typesenseClient, name string) error {
+	_, err := tc.getCollection(ctx, name)
+	if err == nil || !isTypesenseNotFound(err) {
+		return err
+	}
+
+	r.logger.V(debugLevel).Info("creating history collection", "collection", name)
+
+	schema := typesenseCollection{
+		Name: name,
+		Fields: []typesenseCollectionField{
+			{Name: "conversation_id", Type: "string"},
+			{Name: "model_id", Type: "string"},
+			{Name: "timestamp", Type: "int32"},
+			{Name: "role", Type: "string", Index: ptr.To(false)},
+			{Name: "message", Type: "string", Index: ptr.To(false)},
+		},
+	}
+
+	return tc.post(ctx, "/collections", schema, nil)
+}
+
+func (r *TypesenseConversationModelReconciler) getModelID(model *tsv1alpha1.TypesenseConversationModel) string {
+	return ptr.Deref(model.Spec.Name, model.Name)
+}
+
+func (r *TypesenseConversationModelReconciler) getModelPath(id string) string {
+	return fmt.Sprintf("/conversations/models/%s", url.PathEscape(id))
+}
+
+func (r *TypesenseConversationModelReconciler) setModelCondition(ctx context.Context, model *tsv1alpha1.TypesenseConversationModel, reason string, err error) error {
+	return patchObjectStatus(ctx, r.Client, model, func() {
+		setDataPlaneCondition(&model.Status.Conditions, model.Generation, reason, err)
+	})
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *TypesenseConversationModelReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&tsv1alpha1.TypesenseConversationModel{}, eventFilters).
+		Watches(&v1.Secret{}, enqueueReferencingObjects(mgr.GetClient(), &tsv1alpha1.TypesenseConversationModelList{}, func(obj client.Object, name string) bool {
+			ref := obj.(*tsv1alpha1.TypesenseConversationModel).Spec.ApiKeySecretRef
+			return ref != nil && ref.Name == name
+		})).
+		Complete(r)
+}
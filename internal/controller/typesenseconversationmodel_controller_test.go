@@ -0,0 +1,170 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	tsv1alpha1 "github.com/akyriako/typesense-operator/api/v1alpha1"
+)
+
+// newOpenAIStub returns an OpenAI compatible chat completions endpoint that records
+// the bearer tokens it has been called with.
+func newOpenAIStub(tokens *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/v1/chat/completions" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		*tokens = append(*tokens, strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer "))
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"object":  "chat.completion",
+			"choices": []map[string]any{{"index": 0, "message": map[string]string{"role": "assistant", "content": "ok"}}},
+		})
+	}))
+}
+
+// newConversationsStub mimics the Typesense conversation endpoints; like Typesense, it validates
+// a model against its LLM before accepting it.
+func newConversationsStub(collections map[string]bool, models map[string]typesenseConversationModel) *httptest.Server {
+	validate := func(model typesenseConversationModel) int {
+		req, _ := http.NewRequest(http.MethodPost, ptr.Deref(model.OpenaiUrl, "")+"/v1/chat/completions", strings.NewReader(`{}`))
+		req.Header.Set("Authorization", "Bearer "+model.ApiKey)
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return http.StatusBadGateway
+		}
+		defer resp.Body.Close()
+
+		return resp.StatusCode
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch {
+		case req.Method == http.MethodPost && req.URL.Path == "/collections":
+			var collection typesenseCollection
+			_ = json.NewDecoder(req.Body).Decode(&collection)
+			collections[collection.Name] = true
+		case strings.HasPrefix(req.URL.Path, "/collections/"):
+			if !collections[strings.TrimPrefix(req.URL.Path, "/collections/")] {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write([]byte(`{}`))
+		case req.Method == http.MethodGet && strings.HasPrefix(req.URL.Path, "/conversations/models/"):
+			if _, ok := models[strings.TrimPrefix(req.URL.Path, "/conversations/models/")]; !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write([]byte(`{}`))
+		case req.Method == http.MethodPost || req.Method == http.MethodPut:
+			var model typesenseConversationModel
+			_ = json.NewDecoder(req.Body).Decode(&model)
+			if status := validate(model); status != http.StatusOK {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			models[model.ID] = model
+		}
+	}))
+}
+
+var _ = Describe("TypesenseConversationModel Controller", func() {
+	Context("When registering a conversation model", func() {
+		ctx := context.Background()
+
+		var (
+			llm         *httptest.Server
+			server      *httptest.Server
+			tokens      []string
+			collections map[string]bool
+			models      map[string]typesenseConversationModel
+		)
+
+		model := &tsv1alpha1.TypesenseConversationModel{
+			ObjectMeta: metav1.ObjectMeta{Name: "assistant", Namespace: "default"},
+			Spec: tsv1alpha1.TypesenseConversationModelSpec{
+				ClusterRef:        corev1.LocalObjectReference{Name: "cluster-1"},
+				ModelName:         "openai/gpt-4o-mini",
+				MaxBytes:          16384,
+				HistoryCollection: "conversation_store",
+			},
+		}
+
+		BeforeEach(func() {
+			tokens = nil
+			collections = map[string]bool{}
+			models = map[string]typesenseConversationModel{}
+
+			llm = newOpenAIStub(&tokens)
+			server = newConversationsStub(collections, models)
+		})
+
+		AfterEach(func() {
+			server.Close()
+			llm.Close()
+		})
+
+		It("should create the history collection and rotate the api key", func() {
+			reconciler := &TypesenseConversationModelReconciler{logger: log.Log}
+			tc := newTypesenseClient(server.URL, "admin")
+
+			Expect(reconciler.ensureHistoryCollection(ctx, tc, model.Spec.HistoryCollection)).To(Succeed())
+			Expect(collections).To(HaveKey("conversation_store"))
+
+			m := model.DeepCopy()
+			desired := &typesenseConversationModel{
+				ID:                "assistant",
+				ModelName:         m.Spec.ModelName,
+				ApiKey:            "sk-first",
+				HistoryCollection: m.Spec.HistoryCollection,
+				MaxBytes:          m.Spec.MaxBytes,
+				OpenaiUrl:         ptr.To(llm.URL),
+			}
+
+			hash, err := reconciler.registerConversationModel(ctx, tc, m, desired, false)
+			Expect(err).NotTo(HaveOccurred())
+			m.Status.ConfigurationHash = hash
+
+			By("skipping registration when nothing changed")
+			_, err = reconciler.registerConversationModel(ctx, tc, m, desired, false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tokens).To(Equal([]string{"sk-first"}))
+
+			By("registering the model again when the key is rotated")
+			desired.ApiKey = "sk-second"
+			rotated, err := reconciler.registerConversationModel(ctx, tc, m, desired, false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rotated).NotTo(Equal(hash))
+			Expect(tokens).To(Equal([]string{"sk-first", "sk-second"}))
+			Expect(models["assistant"].ApiKey).To(Equal("sk-second"))
+		})
+	})
+})
@@ -0,0 +1,299 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"net/url"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sort"
+
+	tsv1alpha1 "github.com/akyriako/typesense-operator/api/v1alpha1"
+)
+
+// TypesenseCurationReconciler reconciles a TypesenseCuration object
+type TypesenseCurationReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	logger   logr.Logger
+	Recorder record.EventRecorder
+}
+
+type typesenseOverrideRule struct {
+	Query    string   `json:"query,omitempty"`
+	Match    string   `json:"match,omitempty"`
+	FilterBy string   `json:"filter_by,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+}
+
+type typesenseOverrideInclude struct {
+	ID       string `json:"id"`
+	Position int    `json:"position"`
+}
+
+type typesenseOverrideExclude struct {
+	ID string `json:"id"`
+}
+
+type typesenseOverride struct {
+	ID                  string                     `json:"id,omitempty"`
+	Rule                typesenseOverrideRule      `json:"rule"`
+	Includes            []typesenseOverrideInclude `json:"includes,omitempty"`
+	Excludes            []typesenseOverrideExclude `json:"excludes,omitempty"`
+	FilterBy            *string                    `json:"filter_by,omitempty"`
+	SortBy              *string                    `json:"sort_by,omitempty"`
+	ReplaceQuery        *string                    `json:"replace_query,omitempty"`
+	RemoveMatchedTokens *bool                      `json:"remove_matched_tokens,omitempty"`
+	FilterCuratedHits   *bool                      `json:"filter_curated_hits,omitempty"`
+	StopProcessing      *bool                      `json:"stop_processing,omitempty"`
+	EffectiveFromTs     *int64                     `json:"effective_from_ts,omitempty"`
+	EffectiveToTs       *int64                     `json:"effective_to_ts,omitempty"`
+}
+
+type typesenseOverrideList struct {
+	Overrides []typesenseOverride `json:"overrides"`
+}
+
+// +kubebuilder:rbac:groups=ts.opentelekomcloud.com,resources=typesensecurations,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=ts.opentelekomcloud.com,resources=typesensecurations/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=ts.opentelekomcloud.com,resources=typesensecurations/finalizers,verbs=update
+
+// Reconcile applies the curation rules of a TypesenseCuration to the overrides of its collection
+// and removes the overrides it synced before that no longer appear in the spec. Overrides created by
+// hand or by other curations of the same collection are left alone.
+func (r *TypesenseCurationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	r.logger = log.Log.WithValues("namespace", req.Namespace, "curation", req.Name)
+	r.logger.Info("reconciling curation")
+
+	var curation tsv1alpha1.TypesenseCuration
+	if err := r.Get(ctx, req.NamespacedName, &curation); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	_, tc, reason, err := getTargetCluster(ctx, r.Client, curation.Namespace, curation.Spec.ClusterRef)
+	if err != nil {
+		if !curation.DeletionTimestamp.IsZero() && reason == ConditionReasonClusterNotFound {
+			return ctrl.Result{}, removeDataPlaneFinalizer(ctx, r.Client, &curation)
+		}
+
+		r.logger.Error(err, "resolving target cluster failed")
+		return ctrl.Result{RequeueAfter: dataPlaneRequeueAfter}, r.setCurationCondition(ctx, &curation, reason, err)
+	}
+
+	if !curation.DeletionTimestamp.IsZero() {
+		if controllerutil.ContainsFinalizer(&curation, dataPlaneFinalizer) {
+			for name := range r.getOwnedOverrides(&curation) {
+				if err := tc.delete(ctx, r.getOverridePath(&curation, name)); err != nil && !isTypesenseNotFound(err) {
+					r.logger.Error(err, "deleting override failed", "override", name)
+					return ctrl.Result{}, err
+				}
+			}
+		}
+
+		return ctrl.Result{}, removeDataPlaneFinalizer(ctx, r.Client, &curation)
+	}
+
+	if err := addDataPlaneFinalizer(ctx, r.Client, &curation); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	live, reason, err := r.getOverrides(ctx, tc, &curation)
+	if err != nil {
+		r.logger.Error(err, "reading overrides failed")
+		r.Recorder.Event(&curation, "Warning", reason, err.Error())
+
+		return ctrl.Result{RequeueAfter: dataPlaneRequeueAfter}, r.setCurationCondition(ctx, &curation, reason, err)
+	}
+
+	diff, err := r.diffOverrides(&curation, live)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	drifted, apply := evaluateDrift(curation.Spec.DriftDetection, curation.Status.Conditions, curation.Generation, diff)
+	if drifted {
+		r.logger.Info("overrides drifted from the spec", "differences", len(diff), "reconcile", apply)
+		recordDriftEvent(r.Recorder, &curation, apply, diff)
+	}
+
+	synced := curation.Status.Rules
+	if apply {
+		synced, reason, err = r.syncOverrides(ctx, tc, &curation, live)
+		if err != nil {
+			r.logger.Error(err, "syncing overrides failed")
+			r.Recorder.Event(&curation, "Warning", reason, err.Error())
+
+			return ctrl.Result{RequeueAfter: dataPlaneRequeueAfter}, r.setCurationCondition(ctx, &curation, reason, err)
+		}
+	}
+
+	err = patchObjectStatus(ctx, r.Client, &curation, func() {
+		setDataPlaneCondition(&curation.Status.Conditions, curation.Generation, ConditionReasonSynced, nil)
+		setDriftCondition(&curation.Status.Conditions, curation.Generation, curation.Spec.DriftDetection, drifted, apply, diff)
+		curation.Status.Rules = synced
+		curation.Status.LastSyncTime = ptr.To(metav1.Now())
+	})
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	r.logger.Info("reconciling curation completed", "rules", len(synced))
+	return ctrl.Result{RequeueAfter: getDriftRequeueAfter(curation.Spec.DriftDetection)}, nil
+}
+
+func (r *TypesenseCurationReconciler) getOverrides(ctx context.Context, tc *typesenseClient, curation *tsv1alpha1.TypesenseCuration) ([]typesenseOverride, string, error) {
+	var live typesenseOverrideList
+	if err := tc.get(ctx, fmt.Sprintf("/collections/%s/overrides", url.PathEscape(curation.Spec.Collection)), &live); err != nil {
+		if isTypesenseNotFound(err) {
+			return nil, ConditionReasonCollectionNotFound, fmt.Errorf("collection %s was not found", curation.Spec.Collection)
+		}
+		return nil, ConditionReasonSyncFailed, err
+	}
+
+	return live.Overrides, "", nil
+}
+
+func (r *TypesenseCurationReconciler) diffOverrides(curation *tsv1alpha1.TypesenseCuration, live []typesenseOverride) ([]string, error) {
+	desired := make(map[string]any, len(curation.Spec.Rules))
+	for _, rule := range curation.Spec.Rules {
+		desired[rule.Name] = buildTypesenseOverride(rule)
+	}
+
+	owned := r.getOwnedOverrides(curation)
+	current := make(map[string]any, len(live))
+	for _, override := range live {
+		if r.managesOverride(curation, owned, override.ID) {
+			current[override.ID] = override
+		}
+	}
+
+	return diffServerObjects("override", desired, current)
+}
+
+func (r *TypesenseCurationReconciler) syncOverrides(ctx context.Context, tc *typesenseClient, curation *tsv1alpha1.TypesenseCuration, live []typesenseOverride) ([]string, string, error) {
+	desired := make(map[string]bool, len(curation.Spec.Rules))
+	synced := make([]string, 0, len(curation.Spec.Rules))
+	owned := r.getOwnedOverrides(curation)
+
+	for _, rule := range curation.Spec.Rules {
+		r.logger.V(debugLevel).Info("upserting override", "override", rule.Name)
+
+		if err := tc.put(ctx, r.getOverridePath(curation, rule.Name), buildTypesenseOverride(rule), nil); err != nil {
+			return synced, ConditionReasonSyncFailed, fmt.Errorf("upserting override %s failed: %w", rule.Name, err)
+		}
+
+		desired[rule.Name] = true
+		synced = append(synced, rule.Name)
+	}
+
+	for _, override := range live {
+		if desired[override.ID] || !r.managesOverride(curation, owned, override.ID) {
+			continue
+		}
+
+		r.logger.V(debugLevel).Info("deleting override", "override", override.ID)
+		if err := tc.delete(ctx, r.getOverridePath(curation, override.ID)); err != nil && !isTypesenseNotFound(err) {
+			return synced, ConditionReasonSyncFailed, fmt.Errorf("deleting override %s failed: %w", override.ID, err)
+		}
+	}
+
+	sort.Strings(synced)
+	return synced, ConditionReasonSynced, nil
+}
+
+// getOwnedOverrides returns the overrides the curation is responsible for, the rules of its spec and the ones recorded
+// in status.rules by the previous sync
+func (r *TypesenseCurationReconciler) getOwnedOverrides(curation *tsv1alpha1.TypesenseCuration) map[string]bool {
+	owned := make(map[string]bool, len(curation.Spec.Rules)+len(curation.Status.Rules))
+	for _, rule := range curation.Spec.Rules {
+		owned[rule.Name] = true
+	}
+	for _, name := range curation.Status.Rules {
+		owned[name] = true
+	}
+
+	return owned
+}
+
+// managesOverride reports whether the curation syncs an override of its collection: every one of them, unless
+// spec.keepUnmanagedRules restricts it to the ones it owns
+func (r *TypesenseCurationReconciler) managesOverride(curation *tsv1alpha1.TypesenseCuration, owned map[string]bool, name string) bool {
+	return !curation.Spec.KeepUnmanagedRules || owned[name]
+}
+
+func buildTypesenseOverride(rule tsv1alpha1.CurationRuleSpec) typesenseOverride {
+	override := typesenseOverride{
+		Rule: typesenseOverrideRule{
+			Query:    ptr.Deref(rule.Rule.Query, ""),
+			Match:    ptr.Deref(rule.Rule.Match, ""),
+			FilterBy: ptr.Deref(rule.Rule.FilterBy, ""),
+			Tags:     rule.Rule.Tags,
+		},
+		FilterBy:            rule.FilterBy,
+		SortBy:              rule.SortBy,
+		ReplaceQuery:        rule.ReplaceQuery,
+		RemoveMatchedTokens: rule.RemoveMatchedTokens,
+		FilterCuratedHits:   rule.FilterCuratedHits,
+		StopProcessing:      rule.StopProcessing,
+	}
+
+	for _, include := range rule.Includes {
+		override.Includes = append(override.Includes, typesenseOverrideInclude{ID: include.ID, Position: include.Position})
+	}
+
+	for _, exclude := range rule.Excludes {
+		override.Excludes = append(override.Excludes, typesenseOverrideExclude{ID: exclude.ID})
+	}
+
+	if rule.EffectiveFrom != nil {
+		ts := rule.EffectiveFrom.Unix()
+		override.EffectiveFromTs = &ts
+	}
+
+	if rule.EffectiveTo != nil {
+		ts := rule.EffectiveTo.Unix()
+		override.EffectiveToTs = &ts
+	}
+
+	return override
+}
+
+func (r *TypesenseCurationReconciler) getOverridePath(curation *tsv1alpha1.TypesenseCuration, name string) string {
+	return fmt.Sprintf("/collections/%s/overrides/%s", url.PathEscape(curation.Spec.Collection), url.PathEscape(name))
+}
+
+func (r *TypesenseCurationReconciler) setCurationCondition(ctx context.Context, curation *tsv1alpha1.TypesenseCuration, reason string, err error) error {
+	return patchObjectStatus(ctx, r.Client, curation, func() {
+		setDataPlaneCondition(&curation.Status.Conditions, curation.Generation, reason, err)
+	})
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *TypesenseCurationReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&tsv1alpha1.TypesenseCuration{}, eventFilters).
+		Complete(r)
+}
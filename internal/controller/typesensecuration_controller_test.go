@@ -0,0 +1,164 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	tsv1alpha1 "github.com/akyriako/typesense-operator/api/v1alpha1"
+)
+
+var _ = Describe("TypesenseCuration Controller", func() {
+	Context("When syncing overrides", func() {
+		ctx := context.Background()
+
+		var (
+			server    *httptest.Server
+			mu        sync.Mutex
+			overrides map[string]typesenseOverride
+		)
+
+		curation := &tsv1alpha1.TypesenseCuration{
+			ObjectMeta: metav1.ObjectMeta{Name: "products-curation", Namespace: "default"},
+			Spec: tsv1alpha1.TypesenseCurationSpec{
+				ClusterRef: corev1.LocalObjectReference{Name: "cluster-1"},
+				Collection: "products",
+				Rules: []tsv1alpha1.CurationRuleSpec{
+					{
+						Name:     "pin-apple",
+						Rule:     tsv1alpha1.CurationRuleMatchSpec{Query: ptr.To("apple"), Match: ptr.To("exact")},
+						Includes: []tsv1alpha1.CurationIncludeSpec{{ID: "422", Position: 1}},
+					},
+				},
+			},
+		}
+
+		BeforeEach(func() {
+			overrides = map[string]typesenseOverride{
+				"stale":  {ID: "stale", Rule: typesenseOverrideRule{Query: "pear", Match: "exact"}},
+				"manual": {ID: "manual", Rule: typesenseOverrideRule{Query: "plum", Match: "exact"}},
+			}
+
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				mu.Lock()
+				defer mu.Unlock()
+
+				if !strings.HasPrefix(req.URL.Path, "/collections/products/overrides") {
+					w.WriteHeader(http.StatusNotFound)
+					_, _ = w.Write([]byte(`{"message": "Not Found"}`))
+					return
+				}
+
+				id := strings.TrimPrefix(strings.TrimPrefix(req.URL.Path, "/collections/products/overrides"), "/")
+				switch req.Method {
+				case http.MethodGet:
+					list := typesenseOverrideList{}
+					for _, o := range overrides {
+						list.Overrides = append(list.Overrides, o)
+					}
+					_ = json.NewEncoder(w).Encode(list)
+				case http.MethodPut:
+					var o typesenseOverride
+					_ = json.NewDecoder(req.Body).Decode(&o)
+					o.ID = id
+					overrides[id] = o
+					_ = json.NewEncoder(w).Encode(o)
+				case http.MethodDelete:
+					delete(overrides, id)
+					_, _ = w.Write([]byte(`{}`))
+				}
+			}))
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		It("should upsert the spec rules and delete every override that is not in the spec", func() {
+			reconciler := &TypesenseCurationReconciler{logger: log.Log}
+			tc := newTypesenseClient(server.URL, "admin")
+
+			synced := curation.DeepCopy()
+			synced.Status.Rules = []string{"pin-apple", "stale"}
+
+			live, _, err := reconciler.getOverrides(ctx, tc, synced)
+			Expect(err).NotTo(HaveOccurred())
+
+			diff, err := reconciler.diffOverrides(synced, live)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(diff).To(ContainElements(ContainSubstring("stale"), ContainSubstring("manual")))
+
+			rules, reason, err := reconciler.syncOverrides(ctx, tc, synced, live)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reason).To(Equal(ConditionReasonSynced))
+			Expect(rules).To(Equal([]string{"pin-apple"}))
+
+			Expect(overrides).To(HaveKey("pin-apple"))
+			Expect(overrides).NotTo(HaveKey("stale"))
+			Expect(overrides).NotTo(HaveKey("manual"))
+			Expect(overrides["pin-apple"].Includes).To(Equal([]typesenseOverrideInclude{{ID: "422", Position: 1}}))
+		})
+
+		It("should neither delete nor report as drift the overrides it does not own when keeping unmanaged rules", func() {
+			reconciler := &TypesenseCurationReconciler{logger: log.Log}
+			tc := newTypesenseClient(server.URL, "admin")
+
+			keeping := curation.DeepCopy()
+			keeping.Spec.KeepUnmanagedRules = true
+			keeping.Status.Rules = []string{"pin-apple", "stale"}
+
+			live, _, err := reconciler.getOverrides(ctx, tc, keeping)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, _, err = reconciler.syncOverrides(ctx, tc, keeping, live)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(overrides).NotTo(HaveKey("stale"))
+			Expect(overrides).To(HaveKey("manual"))
+
+			live, _, err = reconciler.getOverrides(ctx, tc, keeping)
+			Expect(err).NotTo(HaveOccurred())
+
+			diff, err := reconciler.diffOverrides(keeping, live)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(diff).To(BeEmpty())
+		})
+
+		It("should report a missing collection", func() {
+			reconciler := &TypesenseCurationReconciler{logger: log.Log}
+			tc := newTypesenseClient(server.URL, "admin")
+
+			missing := curation.DeepCopy()
+			missing.Spec.Collection = "articles"
+
+			_, reason, err := reconciler.getOverrides(ctx, tc, missing)
+			Expect(err).To(HaveOccurred())
+			Expect(reason).To(Equal(ConditionReasonCollectionNotFound))
+		})
+	})
+})
@@ -0,0 +1,680 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/go-logr/logr"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"reflect"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"slices"
+	"strconv"
+	"strings"
+
+	tsv1alpha1 "github.com/akyriako/typesense-operator/api/v1alpha1"
+)
+
+const (
+	IngestionCronJob          = "%s-ingestion"
+	IngestionKeySecret        = "%s-ingestion-key"
+	IngestionLabel            = "ts.opentelekomcloud.com/ingestion"
+	IngestionDefaultPgImage   = "postgres:16-alpine"
+	ingestionRunHistoryLimit  = 5
+	ingestionDefaultBatchSize = 1000
+
+	ConditionReasonIngestionCompleted = "IngestionCompleted"
+	ConditionReasonIngestionFailed    = "IngestionFailed"
+
+	// ingestionExtractPostgresScript runs the query through psql, which quotes the cursor variable as a literal.
+	ingestionExtractPostgresScript = `set -eu
+printf '%s\n' "${QUERY}" | psql "${PGURI}" -v ON_ERROR_STOP=1 -v cursor="${CURSOR}" -X -q -A -t -o /data/source
+`
+
+	ingestionExtractUrlScript = `set -eu
+curl -sSfL -o /data/source "${SOURCE_URL}"
+`
+
+	// ingestionScript converts the source to JSONL, maps it to documents and upserts them. CSV files are parsed by
+	// Miller, which honours quoted separators and line breaks, and the documents are streamed from the scratch volume
+	// rather than read into memory by curl. The cursor only moves forward when every document was imported, so
+	// failed documents are read again by the next run.
+	ingestionScript = `set -eu
+base="${TYPESENSE_PROTOCOL}://${TYPESENSE_HOST}:${TYPESENSE_PORT}"
+if [ "${SOURCE_FORMAT}" = "CSV" ]; then
+  mlr --icsv --ojsonl --infer-none cat "${SOURCE_FILE}" > /data/documents.jsonl
+else
+  grep -v '^[[:space:]]*$' "${SOURCE_FILE}" > /data/documents.jsonl || true
+fi
+jq -c "${MAPPING}" /data/documents.jsonl > /data/mapped.jsonl
+
+total=$(($(wc -l < /data/mapped.jsonl)))
+imported=0
+failed=0
+cursor="${CURSOR}"
+if [ "${total}" -gt 0 ]; then
+  curl -sSf -X POST -H "X-TYPESENSE-API-KEY: ${TYPESENSE_API_KEY}" -H "Content-Type: text/plain" -T /data/mapped.jsonl \
+    "${base}/collections/${COLLECTION}/documents/import?action=upsert&batch_size=${BATCH_SIZE}" > /data/results.jsonl
+  imported=$(($(grep -c '"success":true' /data/results.jsonl || true)))
+  failed=$((total - imported))
+  if [ -n "${CURSOR_COLUMN}" ] && [ "${failed}" -eq 0 ]; then
+    cursor="$(tail -n 1 /data/documents.jsonl | jq -r --arg column "${CURSOR_COLUMN}" '.[$column] | tostring')"
+  fi
+fi
+
+jq -n -c --argjson read "${total}" --argjson imported "${imported}" --argjson failed "${failed}" --arg cursor "${cursor}" \
+  '{read: $read, imported: $imported, failed: $failed, cursor: $cursor}' | tee /dev/termination-log
+if [ "${failed}" -gt 0 ]; then
+  grep -v '"success":true' /data/results.jsonl | head -n 1
+  exit 1
+fi
+`
+)
+
+// TypesenseIngestionReconciler reconciles a TypesenseIngestion object
+type TypesenseIngestionReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	logger   logr.Logger
+	Recorder record.EventRecorder
+
+	// JobImage runs the Jobs and CronJobs that do not set an image of their own, it provides sh, curl, jq and mlr
+	JobImage string
+}
+
+type ingestionRunResult struct {
+	Read     int64  `json:"read"`
+	Imported int64  `json:"imported"`
+	Failed   int64  `json:"failed"`
+	Cursor   string `json:"cursor"`
+}
+
+// +kubebuilder:rbac:groups=ts.opentelekomcloud.com,resources=typesenseingestions,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=ts.opentelekomcloud.com,resources=typesenseingestions/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=ts.opentelekomcloud.com,resources=typesenseingestions/finalizers,verbs=update
+// +kubebuilder:rbac:groups=batch,resources=cronjobs,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch;delete
+
+// Reconcile schedules a CronJob importing the documents of a TypesenseIngestion source into its collection,
+// with an api key that may only import into that collection, and records the cursor and metrics of every run.
+func (r *TypesenseIngestionReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	r.logger = log.Log.WithValues("namespace", req.Namespace, "ingestion", req.Name)
+	r.logger.Info("reconciling ingestion")
+
+	var ingestion tsv1alpha1.TypesenseIngestion
+	if err := r.Get(ctx, req.NamespacedName, &ingestion); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	ts, tc, reason, err := getTargetCluster(ctx, r.Client, ingestion.Namespace, ingestion.Spec.ClusterRef)
+	if err != nil {
+		if !ingestion.DeletionTimestamp.IsZero() && reason == ConditionReasonClusterNotFound {
+			return ctrl.Result{}, removeDataPlaneFinalizer(ctx, r.Client, &ingestion)
+		}
+
+		r.logger.Error(err, "resolving target cluster failed")
+		return ctrl.Result{RequeueAfter: dataPlaneRequeueAfter}, r.setIngestionCondition(ctx, &ingestion, reason, err)
+	}
+
+	if !ingestion.DeletionTimestamp.IsZero() {
+		if controllerutil.ContainsFinalizer(&ingestion, dataPlaneFinalizer) && ingestion.Status.KeyID != nil {
+			if err := tc.delete(ctx, fmt.Sprintf("/keys/%d", *ingestion.Status.KeyID)); err != nil && !isTypesenseNotFound(err) {
+				r.logger.Error(err, "deleting ingestion api key failed")
+				return ctrl.Result{}, err
+			}
+		}
+
+		return ctrl.Result{}, removeDataPlaneFinalizer(ctx, r.Client, &ingestion)
+	}
+
+	if err := addDataPlaneFinalizer(ctx, r.Client, &ingestion); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := validateIngestionSource(&ingestion.Spec.Source); err != nil {
+		r.Recorder.Event(&ingestion, "Warning", ConditionReasonInvalidSpec, err.Error())
+		return ctrl.Result{}, r.setIngestionCondition(ctx, &ingestion, ConditionReasonInvalidSpec, err)
+	}
+
+	collections, reason, err := r.getIngestionCollections(ctx, tc, &ingestion)
+	if err != nil {
+		r.logger.Error(err, "resolving target collection failed")
+		return ctrl.Result{RequeueAfter: dataPlaneRequeueAfter}, r.setIngestionCondition(ctx, &ingestion, reason, err)
+	}
+
+	if err := r.ensureIngestionKey(ctx, tc, &ingestion, collections); err != nil {
+		r.logger.Error(err, "creating ingestion api key failed")
+		r.Recorder.Event(&ingestion, "Warning", ConditionReasonSyncFailed, err.Error())
+
+		return ctrl.Result{RequeueAfter: dataPlaneRequeueAfter}, r.setIngestionCondition(ctx, &ingestion, ConditionReasonSyncFailed, err)
+	}
+
+	runs, err := r.getFinishedRuns(ctx, &ingestion)
+	if err != nil {
+		r.logger.Error(err, "listing ingestion runs failed")
+		return ctrl.Result{RequeueAfter: dataPlaneRequeueAfter}, r.setIngestionCondition(ctx, &ingestion, ConditionReasonSyncFailed, err)
+	}
+
+	cursor := ingestion.Status.Cursor
+	for _, run := range runs {
+		if run.status.Succeeded {
+			r.Recorder.Eventf(&ingestion, "Normal", ConditionReasonIngestionCompleted, "Imported %d of %d documents into %s", run.status.Imported, run.status.Read, ingestion.Spec.Collection)
+			if run.cursor != "" {
+				cursor = run.cursor
+			}
+		} else {
+			r.Recorder.Eventf(&ingestion, "Warning", ConditionReasonIngestionFailed, "Ingestion job %s failed: %s", run.status.JobName, run.status.Message)
+		}
+	}
+
+	// the cursor is handed to the next run through the cronjob, so it is updated as soon as a run moves it
+	key := client.ObjectKey{Namespace: ingestion.Namespace, Name: fmt.Sprintf(IngestionCronJob, ingestion.Name)}
+	if err := r.reconcileIngestionCronJob(ctx, key, ts, &ingestion, cursor); err != nil {
+		r.logger.Error(err, "reconciling ingestion cronjob failed", "cronjob", key.Name)
+		r.Recorder.Event(&ingestion, "Warning", ConditionReasonSyncFailed, err.Error())
+
+		return ctrl.Result{RequeueAfter: dataPlaneRequeueAfter}, r.setIngestionCondition(ctx, &ingestion, ConditionReasonSyncFailed, err)
+	}
+
+	err = patchObjectStatus(ctx, r.Client, &ingestion, func() {
+		setDataPlaneCondition(&ingestion.Status.Conditions, ingestion.Generation, ConditionReasonSynced, nil)
+		ingestion.Status.CronJobName = key.Name
+		ingestion.Status.Cursor = cursor
+		for _, run := range runs {
+			ingestion.Status.TotalImported += run.status.Imported
+			ingestion.Status.Runs = append([]tsv1alpha1.IngestionRunStatus{run.status}, ingestion.Status.Runs...)
+		}
+		if len(ingestion.Status.Runs) > ingestionRunHistoryLimit {
+			ingestion.Status.Runs = ingestion.Status.Runs[:ingestionRunHistoryLimit]
+		}
+	})
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	r.logger.Info("reconciling ingestion completed", "runs", len(runs), "cursor", cursor)
+	return ctrl.Result{}, nil
+}
+
+func validateIngestionSource(source *tsv1alpha1.IngestionSourceSpec) error {
+	if (source.Postgres != nil) == (source.File != nil) {
+		return fmt.Errorf("exactly one of postgres or file must be set as source")
+	}
+
+	if file := source.File; file != nil && (file.PersistentVolumeClaim != nil) == (file.Url != nil) {
+		return fmt.Errorf("exactly one of persistentVolumeClaim or url must be set as file source")
+	}
+
+	return nil
+}
+
+// getIngestionCollections returns the collections the api key has to be granted, the target collection
+// and, when the target is an alias, the physical collection it points to.
+func (r *TypesenseIngestionReconciler) getIngestionCollections(ctx context.Context, tc *typesenseClient, ingestion *tsv1alpha1.TypesenseIngestion) ([]string, string, error) {
+	collections := []string{ingestion.Spec.Collection}
+
+	alias, err := tc.getAlias(ctx, ingestion.Spec.Collection)
+	if err == nil {
+		collections = append(collections, alias.CollectionName)
+	} else if !isTypesenseNotFound(err) {
+		return nil, ConditionReasonSyncFailed, err
+	}
+
+	if _, err := tc.getCollection(ctx, ingestion.Spec.Collection); err != nil {
+		if isTypesenseNotFound(err) {
+			return nil, ConditionReasonCollectionNotFound, fmt.Errorf("collection %s was not found", ingestion.Spec.Collection)
+		}
+		return nil, ConditionReasonSyncFailed, err
+	}
+
+	slices.Sort(collections)
+	return slices.Compact(collections), "", nil
+}
+
+// ensureIngestionKey makes sure an api key that may only import into the given collections exists and is published
+// in the Secret the CronJob reads it from. Typesense returns the value of a key only once, so a key whose Secret
+// was lost, or whose collections changed, is replaced by a new one.
+func (r *TypesenseIngestionReconciler) ensureIngestionKey(ctx context.Context, tc *typesenseClient, ingestion *tsv1alpha1.TypesenseIngestion, collections []string) error {
+	secret := &corev1.Secret{}
+	secretExists := true
+	if err := r.Get(ctx, client.ObjectKey{Namespace: ingestion.Namespace, Name: fmt.Sprintf(IngestionKeySecret, ingestion.Name)}, secret); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		secretExists = false
+	}
+
+	previous := ingestion.Status.KeyID
+	if previous != nil && secretExists && len(secret.Data[ScopedKeySecretKeyName]) > 0 {
+		live := typesenseApiKey{}
+		err := tc.get(ctx, fmt.Sprintf("/keys/%d", *previous), &live)
+		if err == nil {
+			slices.Sort(live.Collections)
+			if slices.Equal(live.Collections, collections) {
+				return nil
+			}
+		} else if !isTypesenseNotFound(err) {
+			return err
+		}
+	}
+
+	r.logger.V(debugLevel).Info("creating ingestion api key", "collections", collections)
+	created := typesenseApiKey{}
+	payload := map[string]any{
+		"description": fmt.Sprintf("typesense-operator ingestion %s/%s", ingestion.Namespace, ingestion.Name),
+		"actions":     []string{"documents:import"},
+		"collections": collections,
+	}
+	if err := tc.post(ctx, "/keys", payload, &created); err != nil {
+		return err
+	}
+
+	// the value of the new key is published before it is recorded, and the previous key is deleted last, so
+	// that the CronJob never reads a Secret holding a key that was already deleted
+	var err error
+	secret.Data = map[string][]byte{ScopedKeySecretKeyName: []byte(created.Value)}
+	if secretExists {
+		err = r.Update(ctx, secret)
+	} else {
+		secret.ObjectMeta = metav1.ObjectMeta{
+			Name:      fmt.Sprintf(IngestionKeySecret, ingestion.Name),
+			Namespace: ingestion.Namespace,
+			Labels:    map[string]string{IngestionLabel: ingestion.Name},
+		}
+		if err := ctrl.SetControllerReference(ingestion, secret, r.Scheme); err != nil {
+			return err
+		}
+		err = r.Create(ctx, secret)
+	}
+	if err != nil {
+		return err
+	}
+
+	err = patchObjectStatus(ctx, r.Client, ingestion, func() {
+		ingestion.Status.KeyID = ptr.To(created.ID)
+	})
+	if err != nil {
+		return err
+	}
+
+	if previous != nil {
+		if err := tc.delete(ctx, fmt.Sprintf("/keys/%d", *previous)); err != nil && !isTypesenseNotFound(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *TypesenseIngestionReconciler) reconcileIngestionCronJob(ctx context.Context, key client.ObjectKey, ts *tsv1alpha1.TypesenseCluster, ingestion *tsv1alpha1.TypesenseIngestion, cursor string) error {
+	desired := r.buildIngestionCronJob(key, ts, ingestion, cursor)
+	if err := ctrl.SetControllerReference(ingestion, desired, r.Scheme); err != nil {
+		return err
+	}
+
+	cronJob := &batchv1.CronJob{}
+	if err := r.Get(ctx, key, cronJob); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+
+		r.logger.V(debugLevel).Info("creating ingestion cronjob", "cronjob", key.Name)
+		return r.Create(ctx, desired)
+	}
+
+	current, wanted := cronJob.Spec.JobTemplate.Spec.Template.Spec, desired.Spec.JobTemplate.Spec.Template.Spec
+	if cronJob.Spec.Schedule == desired.Spec.Schedule &&
+		ptr.Deref(cronJob.Spec.Suspend, false) == ptr.Deref(desired.Spec.Suspend, false) &&
+		reflect.DeepEqual(current.InitContainers, wanted.InitContainers) &&
+		reflect.DeepEqual(current.Containers, wanted.Containers) &&
+		reflect.DeepEqual(current.Volumes, wanted.Volumes) &&
+		reflect.DeepEqual(cronJob.Spec.JobTemplate.Spec.Template.Labels, desired.Spec.JobTemplate.Spec.Template.Labels) {
+		return nil
+	}
+
+	r.logger.V(debugLevel).Info("updating ingestion cronjob", "cronjob", key.Name)
+	cronJob.Spec = desired.Spec
+	return r.Update(ctx, cronJob)
+}
+
+func (r *TypesenseIngestionReconciler) buildIngestionCronJob(key client.ObjectKey, ts *tsv1alpha1.TypesenseCluster, ingestion *tsv1alpha1.TypesenseIngestion, cursor string) *batchv1.CronJob {
+	spec := ingestion.Spec
+	image := spec.Image
+	if image == "" {
+		image = r.JobImage
+	}
+
+	batchSize := spec.BatchSize
+	if batchSize < 1 {
+		batchSize = ingestionDefaultBatchSize
+	}
+
+	format, cursorColumn, sourceFile := tsv1alpha1.IngestionFileFormatJsonl, "", "/data/source"
+	volumes := []corev1.Volume{
+		{
+			Name: "data",
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{},
+			},
+		},
+	}
+	volumeMounts := []corev1.VolumeMount{
+		{
+			Name:      "data",
+			MountPath: "/data",
+		},
+	}
+
+	var initContainers []corev1.Container
+	switch source := spec.Source; {
+	case source.Postgres != nil:
+		cursorColumn = source.Postgres.CursorColumn
+		if cursor == "" {
+			cursor = ptr.Deref(source.Postgres.InitialCursor, "")
+		}
+
+		pgImage := source.Postgres.Image
+		if pgImage == "" {
+			pgImage = IngestionDefaultPgImage
+		}
+
+		initContainers = append(initContainers, corev1.Container{
+			Name:    "extract",
+			Image:   pgImage,
+			Command: []string{"/bin/sh", "-c", ingestionExtractPostgresScript},
+			Env: []corev1.EnvVar{
+				{
+					Name: "PGURI",
+					ValueFrom: &corev1.EnvVarSource{
+						SecretKeyRef: source.Postgres.ConnectionSecretRef.DeepCopy(),
+					},
+				},
+				{
+					Name:  "QUERY",
+					Value: buildIngestionQuery(source.Postgres, cursor != ""),
+				},
+				{
+					Name:  "CURSOR",
+					Value: cursor,
+				},
+			},
+			VolumeMounts: volumeMounts,
+		})
+
+	case source.File != nil && source.File.Url != nil:
+		format = source.File.Format
+		initContainers = append(initContainers, corev1.Container{
+			Name:    "extract",
+			Image:   image,
+			Command: []string{"/bin/sh", "-c", ingestionExtractUrlScript},
+			Env: []corev1.EnvVar{
+				{
+					Name:  "SOURCE_URL",
+					Value: *source.File.Url,
+				},
+			},
+			VolumeMounts: volumeMounts,
+		})
+
+	case source.File != nil && source.File.PersistentVolumeClaim != nil:
+		format = source.File.Format
+		sourceFile = "/source/" + strings.TrimPrefix(source.File.PersistentVolumeClaim.Path, "/")
+		volumes = append(volumes, corev1.Volume{
+			Name: "source",
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: source.File.PersistentVolumeClaim.ClaimName,
+					ReadOnly:  true,
+				},
+			},
+		})
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      "source",
+			MountPath: "/source",
+			ReadOnly:  true,
+		})
+	}
+
+	if format == "" {
+		format = tsv1alpha1.IngestionFileFormatJsonl
+	}
+
+	labels := map[string]string{IngestionLabel: ingestion.Name}
+
+	return &batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      key.Name,
+			Namespace: key.Namespace,
+			Labels:    labels,
+		},
+		Spec: batchv1.CronJobSpec{
+			ConcurrencyPolicy:          batchv1.ForbidConcurrent,
+			SuccessfulJobsHistoryLimit: ptr.To[int32](1),
+			FailedJobsHistoryLimit:     ptr.To[int32](1),
+			Schedule:                   spec.Schedule,
+			Suspend:                    spec.Suspend,
+			JobTemplate: batchv1.JobTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+				},
+				Spec: batchv1.JobSpec{
+					BackoffLimit: ptr.To[int32](0),
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{Labels: getApiClientLabels(ts)},
+						Spec: corev1.PodSpec{
+							RestartPolicy:  corev1.RestartPolicyNever,
+							InitContainers: initContainers,
+							Containers: []corev1.Container{
+								{
+									Name:                     "ingest",
+									Image:                    image,
+									Command:                  []string{"/bin/sh", "-c", ingestionScript},
+									TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+									Env: append([]corev1.EnvVar{
+										{
+											Name: "TYPESENSE_API_KEY",
+											ValueFrom: &corev1.EnvVarSource{
+												SecretKeyRef: &corev1.SecretKeySelector{
+													Key: ScopedKeySecretKeyName,
+													LocalObjectReference: corev1.LocalObjectReference{
+														Name: fmt.Sprintf(IngestionKeySecret, ingestion.Name),
+													},
+												},
+											},
+										},
+										{
+											Name:  "TYPESENSE_HOST",
+											Value: fmt.Sprintf(ClusterRestService, ts.Name),
+										},
+										{
+											Name:  "TYPESENSE_PORT",
+											Value: strconv.Itoa(ts.Spec.ApiPort),
+										},
+										{
+											Name:  "TYPESENSE_PROTOCOL",
+											Value: getApiProtocol(ts),
+										},
+										{
+											Name:  "COLLECTION",
+											Value: spec.Collection,
+										},
+										{
+											Name:  "SOURCE_FILE",
+											Value: sourceFile,
+										},
+										{
+											Name:  "SOURCE_FORMAT",
+											Value: string(format),
+										},
+										{
+											Name:  "MAPPING",
+											Value: buildIngestionMapping(spec.FieldMapping),
+										},
+										{
+											Name:  "CURSOR",
+											Value: cursor,
+										},
+										{
+											Name:  "CURSOR_COLUMN",
+											Value: cursorColumn,
+										},
+										{
+											Name:  "BATCH_SIZE",
+											Value: strconv.Itoa(batchSize),
+										},
+									}, getTLSClientEnv(ts)...),
+									Resources: corev1.ResourceRequirements{
+										Limits: corev1.ResourceList{
+											corev1.ResourceCPU:    resource.MustParse("1000m"),
+											corev1.ResourceMemory: resource.MustParse("512Mi"),
+										},
+										Requests: corev1.ResourceList{
+											corev1.ResourceCPU:    resource.MustParse("100m"),
+											corev1.ResourceMemory: resource.MustParse("128Mi"),
+										},
+									},
+									VolumeMounts: append(slices.Clone(volumeMounts), getTLSVolumeMounts(ts)...),
+								},
+							},
+							Volumes: append(volumes, getTLSVolumes(ts, false)...),
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// buildIngestionQuery wraps the query of the source so every row is returned as a JSON object, ordered by the cursor
+// column; the cursor itself is passed as a psql variable rather than formatted into the query.
+func buildIngestionQuery(source *tsv1alpha1.PostgresSourceSpec, hasCursor bool) string {
+	column := fmt.Sprintf("source.\"%s\"", strings.ReplaceAll(source.CursorColumn, "\"", "\"\""))
+	query := strings.TrimRight(strings.TrimSpace(source.Query), ";")
+
+	where := ""
+	if hasCursor {
+		where = fmt.Sprintf(" WHERE %s > :'cursor'", column)
+	}
+
+	return fmt.Sprintf("SELECT row_to_json(source) FROM (\n%s\n) AS source%s ORDER BY %s;", query, where, column)
+}
+
+// buildIngestionMapping returns the jq program turning a source row into a document,
+// Typesense only accepts string ids so the id is converted whatever its source type.
+func buildIngestionMapping(mapping []tsv1alpha1.IngestionFieldMappingSpec) string {
+	program := "."
+	if len(mapping) > 0 {
+		fields := make([]string, 0, len(mapping))
+		for _, field := range mapping {
+			name, _ := json.Marshal(field.Field)
+			source, _ := json.Marshal(field.Source)
+			fields = append(fields, fmt.Sprintf("%s: .[%s]", name, source))
+		}
+		program = "{" + strings.Join(fields, ", ") + "}"
+	}
+
+	return program + ` | if .id != null then .id |= tostring else del(.id) end`
+}
+
+type ingestionRun struct {
+	status tsv1alpha1.IngestionRunStatus
+	cursor string
+}
+
+// getFinishedRuns returns the runs that finished since the latest one recorded in the status, oldest first.
+func (r *TypesenseIngestionReconciler) getFinishedRuns(ctx context.Context, ingestion *tsv1alpha1.TypesenseIngestion) ([]ingestionRun, error) {
+	var since *metav1.Time
+	if len(ingestion.Status.Runs) > 0 {
+		since = ingestion.Status.Runs[0].StartTime
+	}
+
+	jobRuns, err := listFinishedJobRuns(ctx, r.Client, ingestion.Namespace, map[string]string{IngestionLabel: ingestion.Name}, since)
+	if err != nil {
+		return nil, err
+	}
+
+	runs := make([]ingestionRun, 0, len(jobRuns))
+	for _, run := range jobRuns {
+		runs = append(runs, getIngestionRun(run))
+	}
+
+	return runs, nil
+}
+
+// getIngestionRun reads the metrics and the cursor reached from the termination message of a run,
+// a run that failed to import some documents still reports how many it imported.
+func getIngestionRun(run jobRun) ingestionRun {
+	result := ingestionRun{
+		status: tsv1alpha1.IngestionRunStatus{
+			JobName:        run.Name,
+			StartTime:      run.StartTime,
+			CompletionTime: run.CompletionTime,
+			Succeeded:      run.Succeeded,
+			Message:        run.Message,
+		},
+	}
+
+	metrics := ingestionRunResult{}
+	if err := json.Unmarshal([]byte(run.TerminationMessage), &metrics); err != nil {
+		if !run.Succeeded && run.TerminationMessage != "" {
+			result.status.Message = run.TerminationMessage
+		} else if run.Succeeded {
+			result.status.Message = fmt.Sprintf("reading the result of the run failed: %s", err)
+		}
+		return result
+	}
+
+	result.status.Read, result.status.Imported, result.status.Failed = metrics.Read, metrics.Imported, metrics.Failed
+	if !run.Succeeded && metrics.Failed > 0 {
+		result.status.Message = fmt.Sprintf("%d documents failed to import", metrics.Failed)
+	}
+	if run.Succeeded {
+		result.cursor = metrics.Cursor
+	}
+
+	return result
+}
+
+func (r *TypesenseIngestionReconciler) setIngestionCondition(ctx context.Context, ingestion *tsv1alpha1.TypesenseIngestion, reason string, err error) error {
+	return patchObjectStatus(ctx, r.Client, ingestion, func() {
+		setDataPlaneCondition(&ingestion.Status.Conditions, ingestion.Generation, reason, err)
+	})
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *TypesenseIngestionReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&tsv1alpha1.TypesenseIngestion{}, eventFilters).
+		Owns(&batchv1.CronJob{}).
+		Owns(&corev1.Secret{}).
+		Watches(&batchv1.Job{}, enqueueJobOwner(IngestionLabel)).
+		Complete(r)
+}
@@ -0,0 +1,278 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	tsv1alpha1 "github.com/akyriako/typesense-operator/api/v1alpha1"
+)
+
+var _ = Describe("TypesenseIngestion Controller", func() {
+	Context("When ingesting documents from a source", func() {
+		ctx := context.Background()
+
+		ts := &tsv1alpha1.TypesenseCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-1", Namespace: "default"},
+			Spec:       tsv1alpha1.TypesenseClusterSpec{ApiPort: 8108},
+		}
+
+		newIngestion := func(source tsv1alpha1.IngestionSourceSpec) *tsv1alpha1.TypesenseIngestion {
+			return &tsv1alpha1.TypesenseIngestion{
+				ObjectMeta: metav1.ObjectMeta{Name: "products", Namespace: "default"},
+				Spec: tsv1alpha1.TypesenseIngestionSpec{
+					ClusterRef: corev1.LocalObjectReference{Name: "cluster-1"},
+					Source:     source,
+					Collection: "products",
+					FieldMapping: []tsv1alpha1.IngestionFieldMappingSpec{
+						{Field: "id", Source: "product_id"},
+						{Field: "title", Source: "name"},
+					},
+					Schedule: "*/5 * * * *",
+				},
+			}
+		}
+
+		getEnv := func(container corev1.Container, name string) string {
+			for _, env := range container.Env {
+				if env.Name == name {
+					return env.Value
+				}
+			}
+			return ""
+		}
+
+		It("should query postgres past the cursor with a restricted api key", func() {
+			reconciler := &TypesenseIngestionReconciler{logger: log.Log}
+			ingestion := newIngestion(tsv1alpha1.IngestionSourceSpec{
+				Postgres: &tsv1alpha1.PostgresSourceSpec{
+					ConnectionSecretRef: corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "pg"}, Key: "uri"},
+					Query:               "SELECT product_id, name, updated_at FROM products;",
+					CursorColumn:        "updated_at",
+				},
+			})
+			Expect(validateIngestionSource(&ingestion.Spec.Source)).To(Succeed())
+
+			cronJob := reconciler.buildIngestionCronJob(client.ObjectKey{Namespace: "default", Name: "products-ingestion"}, ts, ingestion, "2024-01-01T00:00:00")
+			pod := cronJob.Spec.JobTemplate.Spec.Template.Spec
+			Expect(pod.InitContainers).To(HaveLen(1))
+			Expect(getEnv(pod.InitContainers[0], "QUERY")).To(Equal("SELECT row_to_json(source) FROM (\nSELECT product_id, name, updated_at FROM products\n) AS source WHERE source.\"updated_at\" > :'cursor' ORDER BY source.\"updated_at\";"))
+			Expect(getEnv(pod.InitContainers[0], "CURSOR")).To(Equal("2024-01-01T00:00:00"))
+
+			ingest := pod.Containers[0]
+			Expect(getEnv(ingest, "MAPPING")).To(Equal(`{"id": .["product_id"], "title": .["name"]} | if .id != null then .id |= tostring else del(.id) end`))
+			Expect(getEnv(ingest, "CURSOR_COLUMN")).To(Equal("updated_at"))
+			Expect(ingest.Env[0].ValueFrom.SecretKeyRef.Name).To(Equal("products-ingestion-key"))
+
+			Expect(buildIngestionQuery(ingestion.Spec.Source.Postgres, false)).NotTo(ContainSubstring("WHERE"))
+		})
+
+		It("should mount the persistent volume claim of a file source", func() {
+			reconciler := &TypesenseIngestionReconciler{logger: log.Log}
+			ingestion := newIngestion(tsv1alpha1.IngestionSourceSpec{
+				File: &tsv1alpha1.FileSourceSpec{
+					Format:                tsv1alpha1.IngestionFileFormatCsv,
+					PersistentVolumeClaim: &tsv1alpha1.SeedPersistentVolumeClaimSpec{ClaimName: "datasets", Path: "/exports/products.csv"},
+				},
+			})
+			Expect(validateIngestionSource(&ingestion.Spec.Source)).To(Succeed())
+
+			cronJob := reconciler.buildIngestionCronJob(client.ObjectKey{Namespace: "default", Name: "products-ingestion"}, ts, ingestion, "")
+			pod := cronJob.Spec.JobTemplate.Spec.Template.Spec
+			Expect(pod.InitContainers).To(BeEmpty())
+			Expect(pod.Volumes).To(HaveLen(2))
+			Expect(getEnv(pod.Containers[0], "SOURCE_FILE")).To(Equal("/source/exports/products.csv"))
+			Expect(getEnv(pod.Containers[0], "SOURCE_FORMAT")).To(Equal("CSV"))
+
+			ingestion.Spec.Source.File.Url = ptr.To("https://example.com/products.csv")
+			Expect(validateIngestionSource(&ingestion.Spec.Source)).To(MatchError(ContainSubstring("exactly one of persistentVolumeClaim or url")))
+		})
+
+		It("should only move the cursor forward on successful runs", func() {
+			run := getIngestionRun(jobRun{Name: "products-ingestion-1", Succeeded: true, TerminationMessage: `{"read": 3, "imported": 3, "failed": 0, "cursor": "2024-01-02T00:00:00"}`})
+			Expect(run.status.Imported).To(Equal(int64(3)))
+			Expect(run.cursor).To(Equal("2024-01-02T00:00:00"))
+
+			run = getIngestionRun(jobRun{Name: "products-ingestion-2", Message: "BackoffLimitExceeded", TerminationMessage: `{"read": 3, "imported": 2, "failed": 1, "cursor": "2024-01-02T00:00:00"}`})
+			Expect(run.status.Succeeded).To(BeFalse())
+			Expect(run.status.Failed).To(Equal(int64(1)))
+			Expect(run.status.Message).To(Equal("1 documents failed to import"))
+			Expect(run.cursor).To(BeEmpty())
+		})
+
+		It("should grant the api key the target collection and the one it aliases", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				switch {
+				case req.URL.Path == "/aliases/products":
+					_, _ = w.Write([]byte(`{"name": "products", "collection_name": "products_v2"}`))
+				case req.URL.Path == "/collections/products":
+					_, _ = w.Write([]byte(`{"name": "products_v2", "fields": []}`))
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer server.Close()
+
+			reconciler := &TypesenseIngestionReconciler{logger: log.Log}
+			tc := newTypesenseClient(server.URL, "admin")
+
+			collections, _, err := reconciler.getIngestionCollections(ctx, tc, newIngestion(tsv1alpha1.IngestionSourceSpec{}))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(collections).To(Equal([]string{"products", "products_v2"}))
+		})
+
+		It("should publish a new api key before recording it and delete the previous one last", func() {
+			s := runtime.NewScheme()
+			Expect(clientgoscheme.AddToScheme(s)).To(Succeed())
+			Expect(tsv1alpha1.AddToScheme(s)).To(Succeed())
+
+			ingestion := newIngestion(tsv1alpha1.IngestionSourceSpec{})
+			ingestion.Status.KeyID = ptr.To[int64](1)
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "products-ingestion-key", Namespace: "default"},
+				Data:       map[string][]byte{ScopedKeySecretKeyName: []byte("key-1")},
+			}
+			reconciler := &TypesenseIngestionReconciler{
+				Client: fake.NewClientBuilder().WithScheme(s).WithObjects(ingestion, secret).WithStatusSubresource(ingestion).Build(),
+				Scheme: s,
+				logger: log.Log,
+			}
+
+			deleted := false
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				defer GinkgoRecover()
+
+				switch {
+				case req.Method == http.MethodGet && req.URL.Path == "/keys/1":
+					_, _ = w.Write([]byte(`{"id": 1, "collections": ["products"]}`))
+				case req.Method == http.MethodPost && req.URL.Path == "/keys":
+					_, _ = w.Write([]byte(`{"id": 2, "value": "key-2", "collections": ["products", "products_v2"]}`))
+				case req.Method == http.MethodDelete && req.URL.Path == "/keys/1":
+					published := &corev1.Secret{}
+					Expect(reconciler.Get(ctx, client.ObjectKeyFromObject(secret), published)).To(Succeed())
+					Expect(string(published.Data[ScopedKeySecretKeyName])).To(Equal("key-2"))
+
+					recorded := &tsv1alpha1.TypesenseIngestion{}
+					Expect(reconciler.Get(ctx, client.ObjectKeyFromObject(ingestion), recorded)).To(Succeed())
+					Expect(recorded.Status.KeyID).To(Equal(ptr.To[int64](2)))
+
+					deleted = true
+					_, _ = w.Write([]byte(`{"id": 1}`))
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer server.Close()
+
+			tc := newTypesenseClient(server.URL, "admin")
+			Expect(reconciler.ensureIngestionKey(ctx, tc, ingestion, []string{"products", "products_v2"})).To(Succeed())
+			Expect(deleted).To(BeTrue())
+		})
+
+		It("should pass the cursor to psql as a variable and only advance it when every document was imported", func() {
+			if _, err := exec.LookPath("jq"); err != nil {
+				Skip("jq is not installed")
+			}
+
+			// psql and curl are stubbed, the scripts run against a scratch directory instead of /data
+			dir := GinkgoT().TempDir()
+			writeStub := func(name string, script string) {
+				Expect(os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"+script), 0o755)).To(Succeed())
+			}
+			writeStub("psql", `printf '%s\n' "$@" > "${STUB_DIR}/psql.args"
+cat > "${STUB_DIR}/query.sql"
+while [ $# -gt 0 ]; do if [ "$1" = "-o" ]; then out="$2"; fi; shift; done
+printf '%s\n' '{"product_id": 7, "name": "seven", "updated_at": "2024-01-02T00:00:00"}' '{"product_id": 8, "name": "eight", "updated_at": "2024-01-03T00:00:00"}' > "${out}"
+`)
+			writeStub("curl", `while [ $# -gt 0 ]; do if [ "$1" = "-T" ]; then in="$2"; fi; shift; done
+awk -v fail="${FAIL_FIRST:-}" '{ if (NR == 1 && fail != "") print "{\"success\":false,\"error\":\"rejected\"}"; else print "{\"success\":true}" }' "${in}"
+`)
+
+			cursor := "2024-01-01 00:00:00'; DROP TABLE products; --"
+			reconciler := &TypesenseIngestionReconciler{logger: log.Log, JobImage: "typesense-operator:test"}
+			ingestion := newIngestion(tsv1alpha1.IngestionSourceSpec{
+				Postgres: &tsv1alpha1.PostgresSourceSpec{
+					ConnectionSecretRef: corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "pg"}, Key: "uri"},
+					Query:               "SELECT product_id, name, updated_at FROM products",
+					CursorColumn:        "updated_at",
+				},
+			})
+			cronJob := reconciler.buildIngestionCronJob(client.ObjectKey{Namespace: "default", Name: "products-ingestion"}, ts, ingestion, cursor)
+			pod := cronJob.Spec.JobTemplate.Spec.Template.Spec
+
+			run := func(container corev1.Container, extraEnv ...string) (map[string]any, error) {
+				scratch := strings.NewReplacer("/data/", dir+"/", "/dev/termination-log", dir+"/termination-log")
+				cmd := exec.Command("/bin/sh", "-c", scratch.Replace(container.Command[2]))
+				cmd.Env = append([]string{"PATH=" + dir + ":" + os.Getenv("PATH"), "STUB_DIR=" + dir, "PGURI=postgresql://pg", "TYPESENSE_API_KEY=key"}, extraEnv...)
+				for _, env := range container.Env {
+					if env.ValueFrom == nil {
+						cmd.Env = append(cmd.Env, env.Name+"="+scratch.Replace(env.Value))
+					}
+				}
+				output, err := cmd.CombinedOutput()
+				GinkgoWriter.Println(string(output))
+
+				result := map[string]any{}
+				if message, rerr := os.ReadFile(filepath.Join(dir, "termination-log")); rerr == nil {
+					Expect(json.Unmarshal(message, &result)).To(Succeed())
+				}
+				return result, err
+			}
+
+			_, err := run(pod.InitContainers[0])
+			Expect(err).NotTo(HaveOccurred())
+
+			args, err := os.ReadFile(filepath.Join(dir, "psql.args"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(strings.Split(string(args), "\n")).To(ContainElement("cursor=" + cursor))
+			query, err := os.ReadFile(filepath.Join(dir, "query.sql"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(query)).To(ContainSubstring(`WHERE source."updated_at" > :'cursor' ORDER BY`))
+			Expect(string(query)).NotTo(ContainSubstring("DROP TABLE"))
+
+			result, err := run(pod.Containers[0])
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(map[string]any{"read": 2.0, "imported": 2.0, "failed": 0.0, "cursor": "2024-01-03T00:00:00"}))
+
+			mapped, err := os.ReadFile(filepath.Join(dir, "mapped.jsonl"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(mapped)).To(HavePrefix(`{"id":"7","title":"seven"}`))
+
+			result, err = run(pod.Containers[0], "FAIL_FIRST=1")
+			Expect(err).To(HaveOccurred())
+			Expect(result).To(Equal(map[string]any{"read": 2.0, "imported": 1.0, "failed": 1.0, "cursor": cursor}))
+		})
+	})
+})
@@ -0,0 +1,358 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"net/url"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"strings"
+
+	tsv1alpha1 "github.com/akyriako/typesense-operator/api/v1alpha1"
+)
+
+// TypesensePresetReconciler reconciles a TypesensePreset object
+type TypesensePresetReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	logger   logr.Logger
+	Recorder record.EventRecorder
+}
+
+// +kubebuilder:rbac:groups=ts.opentelekomcloud.com,resources=typesensepresets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=ts.opentelekomcloud.com,resources=typesensepresets/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=ts.opentelekomcloud.com,resources=typesensepresets/finalizers,verbs=update
+// +kubebuilder:rbac:groups=ts.opentelekomcloud.com,resources=typesensecollections,verbs=get;list;watch
+
+// Reconcile validates the searches of a TypesensePreset against the schemas of the collections
+// they target and upserts the preset.
+func (r *TypesensePresetReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	r.logger = log.Log.WithValues("namespace", req.Namespace, "preset", req.Name)
+	r.logger.Info("reconciling preset")
+
+	var preset tsv1alpha1.TypesensePreset
+	if err := r.Get(ctx, req.NamespacedName, &preset); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	_, tc, reason, err := getTargetCluster(ctx, r.Client, preset.Namespace, preset.Spec.ClusterRef)
+	if err != nil {
+		if !preset.DeletionTimestamp.IsZero() && reason == ConditionReasonClusterNotFound {
+			return ctrl.Result{}, removeDataPlaneFinalizer(ctx, r.Client, &preset)
+		}
+
+		r.logger.Error(err, "resolving target cluster failed")
+		return ctrl.Result{RequeueAfter: dataPlaneRequeueAfter}, r.setPresetCondition(ctx, &preset, reason, err)
+	}
+
+	if !preset.DeletionTimestamp.IsZero() {
+		if controllerutil.ContainsFinalizer(&preset, dataPlaneFinalizer) {
+			name := preset.Status.PresetName
+			if name == "" {
+				name = r.getPresetName(&preset)
+			}
+
+			if err := tc.delete(ctx, r.getPresetPath(name)); err != nil && !isTypesenseNotFound(err) {
+				r.logger.Error(err, "deleting preset failed")
+				return ctrl.Result{}, err
+			}
+		}
+
+		return ctrl.Result{}, removeDataPlaneFinalizer(ctx, r.Client, &preset)
+	}
+
+	if err := addDataPlaneFinalizer(ctx, r.Client, &preset); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	value, reason, err := r.buildPresetValue(ctx, tc, &preset)
+	if err != nil {
+		r.logger.Error(err, "validating preset failed")
+		r.Recorder.Event(&preset, "Warning", reason, err.Error())
+
+		return ctrl.Result{RequeueAfter: dataPlaneRequeueAfter}, r.setPresetCondition(ctx, &preset, reason, err)
+	}
+
+	name := r.getPresetName(&preset)
+	if preset.Status.PresetName != "" && preset.Status.PresetName != name {
+		r.logger.V(debugLevel).Info("deleting renamed preset", "preset", preset.Status.PresetName)
+		if err := tc.delete(ctx, r.getPresetPath(preset.Status.PresetName)); err != nil && !isTypesenseNotFound(err) {
+			return ctrl.Result{RequeueAfter: dataPlaneRequeueAfter}, r.setPresetCondition(ctx, &preset, ConditionReasonSyncFailed, err)
+		}
+	}
+
+	diff, err := r.diffPreset(ctx, tc, name, value)
+	if err != nil {
+		r.logger.Error(err, "reading preset failed")
+		return ctrl.Result{RequeueAfter: dataPlaneRequeueAfter}, r.setPresetCondition(ctx, &preset, ConditionReasonSyncFailed, err)
+	}
+
+	drifted, apply := evaluateDrift(preset.Spec.DriftDetection, preset.Status.Conditions, preset.Generation, diff)
+	if drifted {
+		r.logger.Info("preset drifted from the spec", "differences", len(diff), "reconcile", apply)
+		recordDriftEvent(r.Recorder, &preset, apply, diff)
+	}
+
+	if apply {
+		r.logger.V(debugLevel).Info("upserting preset", "preset", name)
+		if err := tc.put(ctx, r.getPresetPath(name), map[string]any{"value": value}, nil); err != nil {
+			r.logger.Error(err, "upserting preset failed")
+			r.Recorder.Event(&preset, "Warning", ConditionReasonSyncFailed, err.Error())
+
+			return ctrl.Result{RequeueAfter: dataPlaneRequeueAfter}, r.setPresetCondition(ctx, &preset, ConditionReasonSyncFailed, err)
+		}
+	}
+
+	err = patchObjectStatus(ctx, r.Client, &preset, func() {
+		setDataPlaneCondition(&preset.Status.Conditions, preset.Generation, ConditionReasonSynced, nil)
+		setDriftCondition(&preset.Status.Conditions, preset.Generation, preset.Spec.DriftDetection, drifted, apply, diff)
+		preset.Status.PresetName = name
+		preset.Status.LastSyncTime = ptr.To(metav1.Now())
+	})
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	r.logger.Info("reconciling preset completed", "preset", name)
+	return ctrl.Result{RequeueAfter: getDriftRequeueAfter(preset.Spec.DriftDetection)}, nil
+}
+
+func (r *TypesensePresetReconciler) diffPreset(ctx context.Context, tc *typesenseClient, name string, value map[string]any) ([]string, error) {
+	live := map[string]any{}
+
+	current := typesensePreset{}
+	if err := tc.get(ctx, r.getPresetPath(name), &current); err != nil {
+		if !isTypesenseNotFound(err) {
+			return nil, err
+		}
+	} else {
+		live[name] = current.Value
+	}
+
+	return diffServerObjects("preset", map[string]any{name: value}, live)
+}
+
+// buildPresetValue returns the value of the preset, either the parameters of a single search
+// or a multi_search body. Searches are validated against the schema of the TypesenseCollection
+// they target, or against the live schema for collections created outside the operator.
+func (r *TypesensePresetReconciler) buildPresetValue(ctx context.Context, tc *typesenseClient, preset *tsv1alpha1.TypesensePreset) (map[string]any, string, error) {
+	if (preset.Spec.Search == nil) == (len(preset.Spec.MultiSearch) == 0) {
+		return nil, ConditionReasonInvalidSpec, fmt.Errorf("exactly one of search or multiSearch must be set")
+	}
+
+	searches := preset.Spec.MultiSearch
+	if preset.Spec.Search != nil {
+		searches = []tsv1alpha1.PresetSearchSpec{*preset.Spec.Search}
+	}
+
+	values := make([]any, 0, len(searches))
+	for i, search := range searches {
+		value := make(map[string]any, len(search.Parameters)+1)
+		for k, v := range search.Parameters {
+			value[k] = v
+		}
+
+		if search.Collection != nil {
+			value["collection"] = *search.Collection
+
+			collection, err := r.getPresetCollection(ctx, tc, preset, *search.Collection)
+			if err != nil {
+				return nil, ConditionReasonSyncFailed, err
+			}
+
+			// collections that are neither declared nor there yet, nothing to validate against
+			if collection != nil {
+				if errs := validatePresetSearch(search.Parameters, collection); len(errs) > 0 {
+					return nil, ConditionReasonInvalidSpec, fmt.Errorf("search %d is not valid: %w", i, errors.Join(errs...))
+				}
+			}
+		}
+
+		values = append(values, value)
+	}
+
+	if preset.Spec.Search != nil {
+		return values[0].(map[string]any), "", nil
+	}
+
+	return map[string]any{"searches": values}, "", nil
+}
+
+// getPresetCollection returns the schema of the TypesenseCollection of the cluster whose alias is name, the spec is
+// ahead of the server while a collection is created or migrated. It falls back to the live collection, which is nil
+// if it does not exist.
+func (r *TypesensePresetReconciler) getPresetCollection(ctx context.Context, tc *typesenseClient, preset *tsv1alpha1.TypesensePreset, name string) (*typesenseCollection, error) {
+	var collections tsv1alpha1.TypesenseCollectionList
+	if err := r.List(ctx, &collections, client.InNamespace(preset.Namespace)); err != nil {
+		return nil, err
+	}
+
+	for i := range collections.Items {
+		col := &collections.Items[i]
+		if col.Spec.ClusterRef.Name == preset.Spec.ClusterRef.Name && getCollectionAlias(col) == name && col.DeletionTimestamp.IsZero() {
+			collection := buildTypesenseCollection(name, col)
+			return &collection, nil
+		}
+	}
+
+	collection, err := tc.getCollection(ctx, name)
+	if err != nil && !isTypesenseNotFound(err) {
+		return nil, err
+	}
+
+	return collection, nil
+}
+
+// validatePresetSearch checks that the fields referenced by the search parameters exist in the collection,
+// and that the ones used for faceting and grouping are facets.
+func validatePresetSearch(parameters map[string]string, collection *typesenseCollection) []error {
+	var errs []error
+
+	check := func(parameter string, facet bool) {
+		value, ok := parameters[parameter]
+		if !ok {
+			return
+		}
+
+		for _, entry := range splitParameterList(value) {
+			name := entry
+			if parameter == "sort_by" {
+				name, _, _ = strings.Cut(name, ":")
+			}
+			name, _, _ = strings.Cut(name, "(")
+			name = strings.TrimSpace(name)
+
+			// special fields (_text_match, _eval etc.), wildcards and joined collections are resolved by Typesense
+			if name == "" || strings.HasPrefix(name, "_") || strings.HasPrefix(name, "$") || strings.Contains(name, "*") {
+				continue
+			}
+
+			field, found := collection.getField(name)
+			if !found {
+				errs = append(errs, fmt.Errorf("%s field %s does not exist in collection %s", parameter, name, collection.Name))
+				continue
+			}
+
+			if facet && !field.isFacet() {
+				errs = append(errs, fmt.Errorf("%s field %s is not a facet in collection %s", parameter, name, collection.Name))
+			}
+		}
+	}
+
+	check("query_by", false)
+	check("sort_by", false)
+	check("facet_by", true)
+	check("group_by", true)
+	check("include_fields", false)
+	check("exclude_fields", false)
+	check("highlight_fields", false)
+
+	return errs
+}
+
+// splitParameterList splits a comma separated parameter, ignoring commas nested in
+// parentheses or brackets such as facet ranges or geo points.
+func splitParameterList(value string) []string {
+	var (
+		entries []string
+		depth   int
+		start   int
+	)
+
+	for i, c := range value {
+		switch c {
+		case '(', '[':
+			depth++
+		case ')', ']':
+			depth--
+		case ',':
+			if depth == 0 {
+				entries = append(entries, strings.TrimSpace(value[start:i]))
+				start = i + 1
+			}
+		}
+	}
+
+	return append(entries, strings.TrimSpace(value[start:]))
+}
+
+func (r *TypesensePresetReconciler) getPresetName(preset *tsv1alpha1.TypesensePreset) string {
+	return ptr.Deref(preset.Spec.Name, preset.Name)
+}
+
+func (r *TypesensePresetReconciler) getPresetPath(name string) string {
+	return fmt.Sprintf("/presets/%s", url.PathEscape(name))
+}
+
+func (r *TypesensePresetReconciler) setPresetCondition(ctx context.Context, preset *tsv1alpha1.TypesensePreset, reason string, err error) error {
+	return patchObjectStatus(ctx, r.Client, preset, func() {
+		setDataPlaneCondition(&preset.Status.Conditions, preset.Generation, reason, err)
+	})
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *TypesensePresetReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&tsv1alpha1.TypesensePreset{}, eventFilters).
+		Watches(&tsv1alpha1.TypesenseCollection{}, handler.EnqueueRequestsFromMapFunc(r.getPresetsForCollection)).
+		Complete(r)
+}
+
+// getPresetsForCollection revalidates the presets of the cluster that search the collection whenever its schema changes
+func (r *TypesensePresetReconciler) getPresetsForCollection(ctx context.Context, obj client.Object) []reconcile.Request {
+	col := obj.(*tsv1alpha1.TypesenseCollection)
+
+	var presets tsv1alpha1.TypesensePresetList
+	if err := r.List(ctx, &presets, client.InNamespace(col.Namespace)); err != nil {
+		return nil
+	}
+
+	alias := getCollectionAlias(col)
+
+	var requests []reconcile.Request
+	for _, preset := range presets.Items {
+		if preset.Spec.ClusterRef.Name != col.Spec.ClusterRef.Name {
+			continue
+		}
+
+		searches := preset.Spec.MultiSearch
+		if preset.Spec.Search != nil {
+			searches = append(searches, *preset.Spec.Search)
+		}
+
+		for _, search := range searches {
+			if ptr.Deref(search.Collection, "") == alias {
+				requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&preset)})
+				break
+			}
+		}
+	}
+
+	return requests
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	tsv1alpha1 "github.com/akyriako/typesense-operator/api/v1alpha1"
+)
+
+var _ = Describe("TypesensePreset Controller", func() {
+	Context("When validating a preset against a collection schema", func() {
+		collection := &typesenseCollection{
+			Name: "products",
+			Fields: []typesenseCollectionField{
+				{Name: "name", Type: "string"},
+				{Name: "description", Type: "string"},
+				{Name: "brand", Type: "string", Facet: ptr.To(true)},
+				{Name: "price", Type: "float", Facet: ptr.To(true)},
+				{Name: "location", Type: "geopoint"},
+				{Name: "attributes", Type: "object"},
+				{Name: "num_.*", Type: "int32"},
+			},
+		}
+
+		It("should accept fields, facet ranges, geo sorting and special fields", func() {
+			errs := validatePresetSearch(map[string]string{
+				"query_by":       "name, description, attributes.color",
+				"sort_by":        "_text_match:desc,location(48.85, 2.34):asc,num_sold:desc",
+				"facet_by":       "brand,price(cheap:[0, 100], expensive:[100, ])",
+				"include_fields": "name,$brands(*)",
+			}, collection)
+
+			Expect(errs).To(BeEmpty())
+		})
+
+		It("should reject unknown fields and facets that are not facetable", func() {
+			errs := validatePresetSearch(map[string]string{
+				"query_by": "title",
+				"facet_by": "name",
+			}, collection)
+
+			Expect(errs).To(HaveLen(2))
+		})
+	})
+
+	Context("When validating a preset against the TypesenseCollections", func() {
+		ctx := context.Background()
+
+		var (
+			server     *httptest.Server
+			reconciler *TypesensePresetReconciler
+		)
+
+		preset := func(queryBy string) *tsv1alpha1.TypesensePreset {
+			return &tsv1alpha1.TypesensePreset{
+				ObjectMeta: metav1.ObjectMeta{Name: "listing", Namespace: "default"},
+				Spec: tsv1alpha1.TypesensePresetSpec{
+					ClusterRef: corev1.LocalObjectReference{Name: "cluster-1"},
+					Search: &tsv1alpha1.PresetSearchSpec{
+						Collection: ptr.To("products"),
+						Parameters: map[string]string{"query_by": queryBy},
+					},
+				},
+			}
+		}
+
+		BeforeEach(func() {
+			// the collection is declared but not created on the server yet
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"message": "Not Found"}`))
+			}))
+
+			s := runtime.NewScheme()
+			Expect(tsv1alpha1.AddToScheme(s)).To(Succeed())
+
+			col := &tsv1alpha1.TypesenseCollection{
+				ObjectMeta: metav1.ObjectMeta{Name: "products-collection", Namespace: "default"},
+				Spec: tsv1alpha1.TypesenseCollectionSpec{
+					ClusterRef: corev1.LocalObjectReference{Name: "cluster-1"},
+					Name:       ptr.To("products"),
+					Fields:     []tsv1alpha1.CollectionFieldSpec{{Name: "name", Type: "string"}},
+				},
+			}
+
+			reconciler = &TypesensePresetReconciler{
+				Client: fake.NewClientBuilder().WithScheme(s).WithObjects(col).Build(),
+				logger: log.Log,
+			}
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		It("should validate the search against the declared schema", func() {
+			tc := newTypesenseClient(server.URL, "admin")
+
+			_, _, err := reconciler.buildPresetValue(ctx, tc, preset("name"))
+			Expect(err).NotTo(HaveOccurred())
+
+			_, reason, err := reconciler.buildPresetValue(ctx, tc, preset("title"))
+			Expect(err).To(HaveOccurred())
+			Expect(reason).To(Equal(ConditionReasonInvalidSpec))
+		})
+
+		It("should enqueue the presets searching a collection", func() {
+			p := preset("name")
+			Expect(reconciler.Create(ctx, p)).To(Succeed())
+
+			var col tsv1alpha1.TypesenseCollection
+			Expect(reconciler.Get(ctx, client.ObjectKey{Name: "products-collection", Namespace: "default"}, &col)).To(Succeed())
+			Expect(reconciler.getPresetsForCollection(ctx, &col)).To(HaveLen(1))
+		})
+	})
+})
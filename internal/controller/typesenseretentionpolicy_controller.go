@@ -0,0 +1,391 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/go-logr/logr"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"reflect"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"strconv"
+
+	tsv1alpha1 "github.com/akyriako/typesense-operator/api/v1alpha1"
+)
+
+const (
+	RetentionPolicyCronJob = "%s-retention"
+	RetentionPolicyLabel   = "ts.opentelekomcloud.com/retention-policy"
+
+	ConditionReasonRetentionCompleted = "RetentionCompleted"
+	ConditionReasonRetentionFailed    = "RetentionFailed"
+
+	// retentionScript deletes every document whose timestamp field is older than MAX_AGE_SECONDS and writes
+	// the outcome to the termination log, where the operator picks it up to update the status of the policy.
+	// A response without a numeric num_deleted fails the run instead of reporting a bogus count.
+	retentionScript = `set -euo pipefail
+base="${TYPESENSE_PROTOCOL}://${TYPESENSE_HOST}:${TYPESENSE_PORT}"
+collection="$(jq -rn --arg collection "${COLLECTION}" '$collection | @uri')"
+cutoff=$(($(date +%s) - MAX_AGE_SECONDS))
+if [ "${TIMESTAMP_UNIT}" = "Milliseconds" ]; then
+  cutoff=$((cutoff * 1000))
+fi
+if ! deleted="$(curl -sSf -G -X DELETE -H "X-TYPESENSE-API-KEY: ${TYPESENSE_API_KEY}" \
+  --data-urlencode "filter_by=${TIMESTAMP_FIELD}:<${cutoff}" --data-urlencode "batch_size=${BATCH_SIZE}" \
+  "${base}/collections/${collection}/documents" | jq -e '.num_deleted | numbers')"; then
+  echo "the response of ${COLLECTION} lacks the number of deleted documents" | tee /dev/termination-log
+  exit 1
+fi
+echo "{\"deleted\": ${deleted}, \"cutoff\": ${cutoff}}" | tee /dev/termination-log
+`
+)
+
+// TypesenseRetentionPolicyReconciler reconciles a TypesenseRetentionPolicy object
+type TypesenseRetentionPolicyReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	logger   logr.Logger
+	Recorder record.EventRecorder
+
+	// JobImage runs the Jobs and CronJobs that do not set an image of their own, it provides sh, curl and jq
+	JobImage string
+}
+
+type retentionRunResult struct {
+	Deleted *int64 `json:"deleted"`
+	Cutoff  int64  `json:"cutoff"`
+}
+
+// +kubebuilder:rbac:groups=ts.opentelekomcloud.com,resources=typesenseretentionpolicies,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=ts.opentelekomcloud.com,resources=typesenseretentionpolicies/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=ts.opentelekomcloud.com,resources=typesenseretentionpolicies/finalizers,verbs=update
+// +kubebuilder:rbac:groups=batch,resources=cronjobs,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+
+// Reconcile schedules a CronJob deleting the documents of a collection that are older than the max age
+// of a TypesenseRetentionPolicy, and records the outcome of every finished run in its status.
+func (r *TypesenseRetentionPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	r.logger = log.Log.WithValues("namespace", req.Namespace, "retentionpolicy", req.Name)
+	r.logger.Info("reconciling retention policy")
+
+	var policy tsv1alpha1.TypesenseRetentionPolicy
+	if err := r.Get(ctx, req.NamespacedName, &policy); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	// the cronjob is owned by the policy and garbage collected along with it
+	if !policy.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	ts, tc, reason, err := getTargetCluster(ctx, r.Client, policy.Namespace, policy.Spec.ClusterRef)
+	if err != nil {
+		r.logger.Error(err, "resolving target cluster failed")
+		return ctrl.Result{RequeueAfter: dataPlaneRequeueAfter}, r.setRetentionCondition(ctx, &policy, reason, err)
+	}
+
+	if reason, err := r.checkTimestampField(ctx, tc, &policy); err != nil {
+		r.logger.Error(err, "validating retention policy failed")
+		r.Recorder.Event(&policy, "Warning", reason, err.Error())
+
+		return ctrl.Result{RequeueAfter: dataPlaneRequeueAfter}, r.setRetentionCondition(ctx, &policy, reason, err)
+	}
+
+	key := client.ObjectKey{Namespace: policy.Namespace, Name: fmt.Sprintf(RetentionPolicyCronJob, policy.Name)}
+	if err := r.reconcileRetentionCronJob(ctx, key, ts, &policy); err != nil {
+		r.logger.Error(err, "reconciling retention cronjob failed", "cronjob", key.Name)
+		r.Recorder.Event(&policy, "Warning", ConditionReasonSyncFailed, err.Error())
+
+		return ctrl.Result{RequeueAfter: dataPlaneRequeueAfter}, r.setRetentionCondition(ctx, &policy, ConditionReasonSyncFailed, err)
+	}
+
+	runs, err := r.getFinishedRuns(ctx, &policy)
+	if err != nil {
+		r.logger.Error(err, "listing retention runs failed")
+		return ctrl.Result{RequeueAfter: dataPlaneRequeueAfter}, r.setRetentionCondition(ctx, &policy, ConditionReasonSyncFailed, err)
+	}
+
+	for _, run := range runs {
+		if run.Succeeded {
+			r.Recorder.Eventf(&policy, "Normal", ConditionReasonRetentionCompleted, "Deleted %d documents older than %d from %s", run.Deleted, run.Cutoff, policy.Spec.Collection)
+		} else {
+			r.Recorder.Eventf(&policy, "Warning", ConditionReasonRetentionFailed, "Retention job %s failed: %s", run.JobName, run.Message)
+		}
+	}
+
+	err = patchObjectStatus(ctx, r.Client, &policy, func() {
+		setDataPlaneCondition(&policy.Status.Conditions, policy.Generation, ConditionReasonSynced, nil)
+		policy.Status.CronJobName = key.Name
+		for _, run := range runs {
+			policy.Status.TotalDeleted += run.Deleted
+			policy.Status.LastRun = run.DeepCopy()
+		}
+	})
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	r.logger.Info("reconciling retention policy completed", "runs", len(runs), "deleted", policy.Status.TotalDeleted)
+	return ctrl.Result{}, nil
+}
+
+// checkTimestampField verifies that the collection exists and that the timestamp field can be filtered numerically.
+func (r *TypesenseRetentionPolicyReconciler) checkTimestampField(ctx context.Context, tc *typesenseClient, policy *tsv1alpha1.TypesenseRetentionPolicy) (string, error) {
+	collection, err := tc.getCollection(ctx, policy.Spec.Collection)
+	if err != nil {
+		if isTypesenseNotFound(err) {
+			return ConditionReasonCollectionNotFound, fmt.Errorf("collection %s was not found", policy.Spec.Collection)
+		}
+		return ConditionReasonSyncFailed, err
+	}
+
+	field, ok := collection.getField(policy.Spec.TimestampField)
+	if !ok {
+		return ConditionReasonInvalidSpec, fmt.Errorf("timestamp field %s does not exist in collection %s", policy.Spec.TimestampField, policy.Spec.Collection)
+	}
+
+	if field.Type != "int64" && field.Type != "int32" && field.Type != "float" {
+		return ConditionReasonInvalidSpec, fmt.Errorf("timestamp field %s is of type %s, expected a numeric type", field.Name, field.Type)
+	}
+
+	return "", nil
+}
+
+func (r *TypesenseRetentionPolicyReconciler) reconcileRetentionCronJob(ctx context.Context, key client.ObjectKey, ts *tsv1alpha1.TypesenseCluster, policy *tsv1alpha1.TypesenseRetentionPolicy) error {
+	desired := r.buildRetentionCronJob(key, ts, policy)
+	if err := ctrl.SetControllerReference(policy, desired, r.Scheme); err != nil {
+		return err
+	}
+
+	cronJob := &batchv1.CronJob{}
+	if err := r.Get(ctx, key, cronJob); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+
+		r.logger.V(debugLevel).Info("creating retention cronjob", "cronjob", key.Name)
+		return r.Create(ctx, desired)
+	}
+
+	container := cronJob.Spec.JobTemplate.Spec.Template.Spec.Containers[0]
+	wanted := desired.Spec.JobTemplate.Spec.Template.Spec.Containers[0]
+	if cronJob.Spec.Schedule == desired.Spec.Schedule &&
+		ptr.Deref(cronJob.Spec.Suspend, false) == ptr.Deref(desired.Spec.Suspend, false) &&
+		container.Image == wanted.Image &&
+		reflect.DeepEqual(container.Env, wanted.Env) &&
+		reflect.DeepEqual(cronJob.Spec.JobTemplate.Spec.Template.Labels, desired.Spec.JobTemplate.Spec.Template.Labels) {
+		return nil
+	}
+
+	r.logger.V(debugLevel).Info("updating retention cronjob", "cronjob", key.Name)
+	cronJob.Spec = desired.Spec
+	return r.Update(ctx, cronJob)
+}
+
+func (r *TypesenseRetentionPolicyReconciler) buildRetentionCronJob(key client.ObjectKey, ts *tsv1alpha1.TypesenseCluster, policy *tsv1alpha1.TypesenseRetentionPolicy) *batchv1.CronJob {
+	image := policy.Spec.Image
+	if image == "" {
+		image = r.JobImage
+	}
+
+	batchSize := policy.Spec.BatchSize
+	if batchSize < 1 {
+		batchSize = 1000
+	}
+
+	unit := policy.Spec.TimestampUnit
+	if unit == "" {
+		unit = tsv1alpha1.RetentionTimestampUnitSeconds
+	}
+
+	labels := map[string]string{RetentionPolicyLabel: policy.Name}
+
+	return &batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      key.Name,
+			Namespace: key.Namespace,
+			Labels:    labels,
+		},
+		Spec: batchv1.CronJobSpec{
+			ConcurrencyPolicy:          batchv1.ForbidConcurrent,
+			SuccessfulJobsHistoryLimit: ptr.To[int32](1),
+			FailedJobsHistoryLimit:     ptr.To[int32](1),
+			Schedule:                   policy.Spec.Schedule,
+			Suspend:                    policy.Spec.Suspend,
+			JobTemplate: batchv1.JobTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+				},
+				Spec: batchv1.JobSpec{
+					BackoffLimit: ptr.To[int32](0),
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{Labels: getApiClientLabels(ts)},
+						Spec: corev1.PodSpec{
+							RestartPolicy: corev1.RestartPolicyNever,
+							Containers: []corev1.Container{
+								{
+									Name:                     "retention",
+									Image:                    image,
+									Command:                  []string{"/bin/sh", "-c", retentionScript},
+									TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+									Env: append([]corev1.EnvVar{
+										{
+											Name: "TYPESENSE_API_KEY",
+											ValueFrom: &corev1.EnvVarSource{
+												SecretKeyRef: &corev1.SecretKeySelector{
+													Key: ts.Spec.GetAdminApiKeySecretKey(),
+													LocalObjectReference: corev1.LocalObjectReference{
+														Name: adminApiKeyObjectKey(ts).Name,
+													},
+												},
+											},
+										},
+										{
+											Name:  "TYPESENSE_HOST",
+											Value: fmt.Sprintf(ClusterRestService, ts.Name),
+										},
+										{
+											Name:  "TYPESENSE_PORT",
+											Value: strconv.Itoa(ts.Spec.ApiPort),
+										},
+										{
+											Name:  "TYPESENSE_PROTOCOL",
+											Value: getApiProtocol(ts),
+										},
+										{
+											Name:  "COLLECTION",
+											Value: policy.Spec.Collection,
+										},
+										{
+											Name:  "TIMESTAMP_FIELD",
+											Value: policy.Spec.TimestampField,
+										},
+										{
+											Name:  "TIMESTAMP_UNIT",
+											Value: string(unit),
+										},
+										{
+											Name:  "MAX_AGE_SECONDS",
+											Value: strconv.FormatInt(int64(policy.Spec.MaxAge.Seconds()), 10),
+										},
+										{
+											Name:  "BATCH_SIZE",
+											Value: strconv.Itoa(batchSize),
+										},
+									}, getTLSClientEnv(ts)...),
+									Resources: corev1.ResourceRequirements{
+										Limits: corev1.ResourceList{
+											corev1.ResourceCPU:    resource.MustParse("250m"),
+											corev1.ResourceMemory: resource.MustParse("64Mi"),
+										},
+										Requests: corev1.ResourceList{
+											corev1.ResourceCPU:    resource.MustParse("10m"),
+											corev1.ResourceMemory: resource.MustParse("16Mi"),
+										},
+									},
+									VolumeMounts: getTLSVolumeMounts(ts),
+								},
+							},
+							Volumes: getTLSVolumes(ts, false),
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// getFinishedRuns returns the runs that finished since the last one recorded in the status, oldest first.
+func (r *TypesenseRetentionPolicyReconciler) getFinishedRuns(ctx context.Context, policy *tsv1alpha1.TypesenseRetentionPolicy) ([]tsv1alpha1.RetentionRunStatus, error) {
+	var since *metav1.Time
+	if policy.Status.LastRun != nil {
+		since = policy.Status.LastRun.StartTime
+	}
+
+	jobRuns, err := listFinishedJobRuns(ctx, r.Client, policy.Namespace, map[string]string{RetentionPolicyLabel: policy.Name}, since)
+	if err != nil {
+		return nil, err
+	}
+
+	runs := make([]tsv1alpha1.RetentionRunStatus, 0, len(jobRuns))
+	for _, run := range jobRuns {
+		runs = append(runs, getRetentionRun(run))
+	}
+
+	return runs, nil
+}
+
+// getRetentionRun reads the number of deleted documents from the termination message of a run.
+func getRetentionRun(run jobRun) tsv1alpha1.RetentionRunStatus {
+	status := tsv1alpha1.RetentionRunStatus{
+		JobName:        run.Name,
+		StartTime:      run.StartTime,
+		CompletionTime: run.CompletionTime,
+		Succeeded:      run.Succeeded,
+		Message:        run.Message,
+	}
+
+	if !run.Succeeded {
+		if run.TerminationMessage != "" {
+			status.Message = run.TerminationMessage
+		}
+		return status
+	}
+
+	// a run whose result cannot be read is not reported as a success, as it may not have deleted anything
+	result := retentionRunResult{}
+	if err := json.Unmarshal([]byte(run.TerminationMessage), &result); err != nil {
+		status.Succeeded = false
+		status.Message = fmt.Sprintf("reading the result of the run failed: %s", err)
+		return status
+	}
+	if result.Deleted == nil {
+		status.Succeeded = false
+		status.Message = "reading the result of the run failed: it lacks the deleted documents"
+		return status
+	}
+
+	status.Deleted, status.Cutoff = *result.Deleted, result.Cutoff
+	return status
+}
+
+func (r *TypesenseRetentionPolicyReconciler) setRetentionCondition(ctx context.Context, policy *tsv1alpha1.TypesenseRetentionPolicy, reason string, err error) error {
+	return patchObjectStatus(ctx, r.Client, policy, func() {
+		setDataPlaneCondition(&policy.Status.Conditions, policy.Generation, reason, err)
+	})
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *TypesenseRetentionPolicyReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&tsv1alpha1.TypesenseRetentionPolicy{}, eventFilters).
+		Owns(&batchv1.CronJob{}).
+		Watches(&batchv1.Job{}, enqueueJobOwner(RetentionPolicyLabel)).
+		Complete(r)
+}
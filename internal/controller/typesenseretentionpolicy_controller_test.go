@@ -0,0 +1,169 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	tsv1alpha1 "github.com/akyriako/typesense-operator/api/v1alpha1"
+)
+
+var _ = Describe("TypesenseRetentionPolicy Controller", func() {
+	Context("When deleting aged documents", func() {
+		policy := &tsv1alpha1.TypesenseRetentionPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "events-retention", Namespace: "default"},
+			Spec: tsv1alpha1.TypesenseRetentionPolicySpec{
+				ClusterRef:     corev1.LocalObjectReference{Name: "cluster-1"},
+				Collection:     "events",
+				TimestampField: "created_at",
+				TimestampUnit:  tsv1alpha1.RetentionTimestampUnitMilliseconds,
+				MaxAge:         metav1.Duration{Duration: 30 * 24 * time.Hour},
+				Schedule:       "0 3 * * *",
+			},
+		}
+
+		ts := &tsv1alpha1.TypesenseCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-1", Namespace: "default"},
+			Spec:       tsv1alpha1.TypesenseClusterSpec{ApiPort: 8108},
+		}
+
+		newJob := func(condition batchv1.JobConditionType, message string) *batchv1.Job {
+			return &batchv1.Job{
+				ObjectMeta: metav1.ObjectMeta{Name: "events-retention-retention-28000000", Namespace: "default"},
+				Status: batchv1.JobStatus{
+					StartTime:      &metav1.Time{Time: time.Unix(1700000000, 0)},
+					CompletionTime: &metav1.Time{Time: time.Unix(1700000010, 0)},
+					Conditions: []batchv1.JobCondition{
+						{Type: condition, Status: corev1.ConditionTrue, Message: message},
+					},
+				},
+			}
+		}
+
+		newPod := func(message string) corev1.Pod {
+			return corev1.Pod{
+				Status: corev1.PodStatus{
+					ContainerStatuses: []corev1.ContainerStatus{
+						{State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Message: message}}},
+					},
+				},
+			}
+		}
+
+		It("should label the jobs of the cronjob after the policy", func() {
+			reconciler := &TypesenseRetentionPolicyReconciler{logger: log.Log, JobImage: "typesense-operator:test"}
+
+			cronJob := reconciler.buildRetentionCronJob(client.ObjectKey{Namespace: "default", Name: "events-retention-retention"}, ts, policy)
+			Expect(cronJob.Spec.Schedule).To(Equal("0 3 * * *"))
+			Expect(cronJob.Spec.ConcurrencyPolicy).To(Equal(batchv1.ForbidConcurrent))
+			Expect(cronJob.Spec.JobTemplate.Labels).To(HaveKeyWithValue(RetentionPolicyLabel, "events-retention"))
+
+			container := cronJob.Spec.JobTemplate.Spec.Template.Spec.Containers[0]
+			Expect(container.Image).To(Equal("typesense-operator:test"))
+			Expect(container.Env).To(ContainElements(
+				corev1.EnvVar{Name: "MAX_AGE_SECONDS", Value: "2592000"},
+				corev1.EnvVar{Name: "TIMESTAMP_UNIT", Value: "Milliseconds"},
+				corev1.EnvVar{Name: "BATCH_SIZE", Value: "1000"},
+			))
+		})
+
+		It("should fail a run whose response lacks the number of deleted documents", func() {
+			// the job image runs the script with busybox, which unlike dash knows pipefail
+			if _, err := exec.LookPath("jq"); err != nil {
+				Skip("jq is not installed")
+			}
+			if _, err := exec.LookPath("bash"); err != nil {
+				Skip("bash is not installed")
+			}
+
+			dir := GinkgoT().TempDir()
+			Expect(os.WriteFile(filepath.Join(dir, "curl"), []byte("#!/bin/sh\necho \"$@\" > \"${STUB_DIR}/args\"\necho \"${STUB_RESPONSE}\"\n"), 0o755)).To(Succeed())
+
+			reconciler := &TypesenseRetentionPolicyReconciler{logger: log.Log, JobImage: "typesense-operator:test"}
+			spaced := policy.DeepCopy()
+			spaced.Spec.Collection = "events 2024/q1"
+			container := reconciler.buildRetentionCronJob(client.ObjectKey{Namespace: "default", Name: "events-retention-retention"}, ts, spaced).Spec.JobTemplate.Spec.Template.Spec.Containers[0]
+
+			run := func(response string) (string, error) {
+				cmd := exec.Command("bash", "-c", strings.ReplaceAll(container.Command[2], "/dev/termination-log", filepath.Join(dir, "termination-log")))
+				cmd.Env = []string{"PATH=" + dir + ":" + os.Getenv("PATH"), "STUB_DIR=" + dir, "STUB_RESPONSE=" + response, "TYPESENSE_API_KEY=key"}
+				for _, env := range container.Env {
+					if env.ValueFrom == nil {
+						cmd.Env = append(cmd.Env, env.Name+"="+env.Value)
+					}
+				}
+				err := cmd.Run()
+				message, _ := os.ReadFile(filepath.Join(dir, "termination-log"))
+				return string(message), err
+			}
+
+			message, err := run(`{"num_deleted": 3}`)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(message).To(HavePrefix(`{"deleted": 3, "cutoff": `))
+			Expect(os.ReadFile(filepath.Join(dir, "args"))).To(ContainSubstring("/collections/events%202024%2Fq1/documents"))
+
+			for _, response := range []string{`{"message": "Not Found"}`, `{"num_deleted": "3"}`, "oops"} {
+				message, err = run(response)
+				Expect(err).To(HaveOccurred())
+				Expect(message).To(ContainSubstring("lacks the number of deleted documents"))
+			}
+		})
+
+		It("should read the deleted documents from the termination message", func() {
+			run, finished := getJobRun(newJob(batchv1.JobComplete, ""), []corev1.Pod{newPod("{\"deleted\": 42, \"cutoff\": 1697408000000}\n")})
+			Expect(finished).To(BeTrue())
+
+			status := getRetentionRun(run)
+			Expect(status.Succeeded).To(BeTrue())
+			Expect(status.Deleted).To(Equal(int64(42)))
+			Expect(status.Cutoff).To(Equal(int64(1697408000000)))
+
+			run, finished = getJobRun(newJob(batchv1.JobFailed, "BackoffLimitExceeded"), []corev1.Pod{newPod("curl: (22) The requested URL returned error: 404")})
+			Expect(finished).To(BeTrue())
+
+			status = getRetentionRun(run)
+			Expect(status.Succeeded).To(BeFalse())
+			Expect(status.Message).To(ContainSubstring("returned error: 404"))
+
+			_, finished = getJobRun(&batchv1.Job{}, nil)
+			Expect(finished).To(BeFalse())
+		})
+
+		It("should not report a run whose result cannot be read as a success", func() {
+			for _, message := range []string{"", "{\"deleted\": null, \"cutoff\": 1697408000000}", "{\"cutoff\": 1697408000000}"} {
+				run, finished := getJobRun(newJob(batchv1.JobComplete, ""), []corev1.Pod{newPod(message)})
+				Expect(finished).To(BeTrue())
+
+				status := getRetentionRun(run)
+				Expect(status.Succeeded).To(BeFalse())
+				Expect(status.Message).To(HavePrefix("reading the result of the run failed"))
+			}
+		})
+	})
+})
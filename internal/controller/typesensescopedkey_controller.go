@@ -0,0 +1,213 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/go-logr/logr"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"time"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	tsv1alpha1 "github.com/akyriako/typesense-operator/api/v1alpha1"
+	"github.com/akyriako/typesense-operator/internal/auth"
+)
+
+const (
+	ScopedKeySecretKeyName = "typesense-api-key"
+
+	ConditionReasonScopedKeyExpired = "Expired"
+)
+
+// TypesenseScopedKeyReconciler reconciles a TypesenseScopedKey object
+type TypesenseScopedKeyReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	logger   logr.Logger
+	Recorder record.EventRecorder
+}
+
+// +kubebuilder:rbac:groups=ts.opentelekomcloud.com,resources=typesensescopedkeys,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=ts.opentelekomcloud.com,resources=typesensescopedkeys/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=ts.opentelekomcloud.com,resources=typesensescopedkeys/finalizers,verbs=update
+
+// Reconcile derives a scoped search key from the parent key of a TypesenseScopedKey and publishes it in a Secret.
+// The key is computed locally, without calling the cluster, and regenerated whenever the parent key rotates.
+func (r *TypesenseScopedKeyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	r.logger = log.Log.WithValues("namespace", req.Namespace, "scopedkey", req.Name)
+	r.logger.Info("reconciling scoped key")
+
+	var key tsv1alpha1.TypesenseScopedKey
+	if err := r.Get(ctx, req.NamespacedName, &key); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if !key.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	parentKey, err := getSecretKey(ctx, r.Client, key.Namespace, &key.Spec.ParentKeySecretRef)
+	if err != nil {
+		r.logger.Error(err, "reading parent api key failed")
+		r.Recorder.Event(&key, "Warning", ConditionReasonApiKeyNotFound, err.Error())
+
+		return ctrl.Result{RequeueAfter: dataPlaneRequeueAfter}, r.setScopedKeyCondition(ctx, &key, ConditionReasonApiKeyNotFound, err)
+	}
+
+	scopedKey, err := auth.GenerateScopedSearchKey(parentKey, buildScopedKeyParameters(&key))
+	if err != nil {
+		return ctrl.Result{}, r.setScopedKeyCondition(ctx, &key, ConditionReasonInvalidSpec, err)
+	}
+
+	secretName := ptr.Deref(key.Spec.SecretName, key.Name)
+	generated, err := r.publishScopedKey(ctx, &key, secretName, scopedKey)
+	if err != nil {
+		r.logger.Error(err, "publishing scoped key failed")
+		r.Recorder.Event(&key, "Warning", ConditionReasonSyncFailed, err.Error())
+
+		return ctrl.Result{RequeueAfter: dataPlaneRequeueAfter}, r.setScopedKeyCondition(ctx, &key, ConditionReasonSyncFailed, err)
+	}
+
+	if previous := key.Status.SecretName; previous != "" && previous != secretName {
+		if err := r.deleteScopedKeySecret(ctx, &key, previous); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	reason, result := ConditionReasonSynced, ctrl.Result{}
+	var expired error
+	if expiresAt := key.Spec.ExpiresAt; expiresAt != nil {
+		if untilExpiration := time.Until(expiresAt.Time); untilExpiration > 0 {
+			result.RequeueAfter = untilExpiration
+		} else {
+			reason, expired = ConditionReasonScopedKeyExpired, fmt.Errorf("scoped key expired at %s", expiresAt.UTC().Format(time.RFC3339))
+		}
+	}
+
+	err = patchObjectStatus(ctx, r.Client, &key, func() {
+		setDataPlaneCondition(&key.Status.Conditions, key.Generation, reason, expired)
+		key.Status.SecretName = secretName
+		if generated || key.Status.LastGenerationTime == nil {
+			key.Status.LastGenerationTime = ptr.To(metav1.Now())
+		}
+	})
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if generated {
+		r.Recorder.Event(&key, "Normal", ConditionReasonSynced, fmt.Sprintf("Scoped key published to secret %s", secretName))
+	}
+
+	r.logger.Info("reconciling scoped key completed", "secret", secretName)
+	return result, nil
+}
+
+func buildScopedKeyParameters(key *tsv1alpha1.TypesenseScopedKey) auth.ScopedKeyParameters {
+	params := auth.ScopedKeyParameters{
+		FilterBy:           ptr.Deref(key.Spec.FilterBy, ""),
+		LimitMultiSearches: ptr.Deref(key.Spec.LimitMultiSearches, 0),
+	}
+
+	if key.Spec.ExpiresAt != nil {
+		params.ExpiresAt = key.Spec.ExpiresAt.Unix()
+	}
+
+	return params
+}
+
+// publishScopedKey creates or updates the Secret holding the scoped key and reports whether its content changed.
+func (r *TypesenseScopedKeyReconciler) publishScopedKey(ctx context.Context, key *tsv1alpha1.TypesenseScopedKey, name string, scopedKey string) (bool, error) {
+	secret := &v1.Secret{}
+	if err := r.Get(ctx, client.ObjectKey{Namespace: key.Namespace, Name: name}, secret); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return false, err
+		}
+
+		r.logger.V(debugLevel).Info("creating scoped key secret", "secret", name)
+		secret = &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: key.Namespace},
+			Type:       v1.SecretTypeOpaque,
+			Data: map[string][]byte{
+				ScopedKeySecretKeyName: []byte(scopedKey),
+			},
+		}
+
+		if err := ctrl.SetControllerReference(key, secret, r.Scheme); err != nil {
+			return false, err
+		}
+
+		return true, r.Create(ctx, secret)
+	}
+
+	if !metav1.IsControlledBy(secret, key) {
+		return false, fmt.Errorf("secret %s already exists and is not managed by %s", name, key.Name)
+	}
+
+	if bytes.Equal(secret.Data[ScopedKeySecretKeyName], []byte(scopedKey)) {
+		return false, nil
+	}
+
+	r.logger.V(debugLevel).Info("updating scoped key secret", "secret", name)
+	patch := client.MergeFrom(secret.DeepCopy())
+	secret.Data = map[string][]byte{
+		ScopedKeySecretKeyName: []byte(scopedKey),
+	}
+
+	return true, r.Patch(ctx, secret, patch)
+}
+
+func (r *TypesenseScopedKeyReconciler) deleteScopedKeySecret(ctx context.Context, key *tsv1alpha1.TypesenseScopedKey, name string) error {
+	secret := &v1.Secret{}
+	if err := r.Get(ctx, client.ObjectKey{Namespace: key.Namespace, Name: name}, secret); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+
+	if !metav1.IsControlledBy(secret, key) {
+		return nil
+	}
+
+	r.logger.V(debugLevel).Info("deleting previous scoped key secret", "secret", name)
+	return client.IgnoreNotFound(r.Delete(ctx, secret))
+}
+
+func (r *TypesenseScopedKeyReconciler) setScopedKeyCondition(ctx context.Context, key *tsv1alpha1.TypesenseScopedKey, reason string, err error) error {
+	return patchObjectStatus(ctx, r.Client, key, func() {
+		setDataPlaneCondition(&key.Status.Conditions, key.Generation, reason, err)
+	})
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *TypesenseScopedKeyReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&tsv1alpha1.TypesenseScopedKey{}, eventFilters).
+		Owns(&v1.Secret{}).
+		Watches(&v1.Secret{}, enqueueReferencingObjects(mgr.GetClient(), &tsv1alpha1.TypesenseScopedKeyList{}, func(obj client.Object, name string) bool {
+			return obj.(*tsv1alpha1.TypesenseScopedKey).Spec.ParentKeySecretRef.Name == name
+		})).
+		Complete(r)
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"encoding/base64"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	tsv1alpha1 "github.com/akyriako/typesense-operator/api/v1alpha1"
+	"github.com/akyriako/typesense-operator/internal/auth"
+)
+
+var _ = Describe("TypesenseScopedKey Controller", func() {
+	Context("When generating a scoped key", func() {
+		parentKey := "RN23GFr1s6jQ9kgSNg2O7fYcAUXU7127"
+
+		It("should match the keys generated by the official clients", func() {
+			scopedKey, err := auth.GenerateScopedSearchKey(parentKey, auth.ScopedKeyParameters{
+				FilterBy:  "company_id:124",
+				ExpiresAt: 1906054106,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(scopedKey).To(Equal("OW9DYWZGS1Q1RGdSbmo0S1QrOWxhbk9PL2kxbTU1eXA3bCthdmE5eXJKRT1STjIzeyJmaWx0ZXJfYnkiOiJjb21wYW55X2lkOjEyNCIsImV4cGlyZXNfYXQiOjE5MDYwNTQxMDZ9"))
+		})
+
+		It("should embed only the parameters that are set", func() {
+			key := &tsv1alpha1.TypesenseScopedKey{
+				ObjectMeta: metav1.ObjectMeta{Name: "tenant-a", Namespace: "default"},
+				Spec: tsv1alpha1.TypesenseScopedKeySpec{
+					ParentKeySecretRef: corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "search-key"},
+						Key:                  "api-key",
+					},
+					FilterBy:           ptr.To("tenant_id:=a"),
+					LimitMultiSearches: ptr.To(5),
+				},
+			}
+
+			scopedKey, err := auth.GenerateScopedSearchKey(parentKey, buildScopedKeyParameters(key))
+			Expect(err).NotTo(HaveOccurred())
+
+			raw, err := base64.StdEncoding.DecodeString(scopedKey)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(raw)).To(HaveSuffix(`RN23{"filter_by":"tenant_id:=a","limit_multi_searches":5}`))
+
+			key.Spec.ExpiresAt = &metav1.Time{Time: time.Unix(1906054106, 0)}
+			rotated, err := auth.GenerateScopedSearchKey(parentKey, buildScopedKeyParameters(key))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rotated).NotTo(Equal(scopedKey))
+		})
+
+		It("should change when the parent key rotates", func() {
+			params := auth.ScopedKeyParameters{FilterBy: "tenant_id:=a"}
+
+			before, err := auth.GenerateScopedSearchKey(parentKey, params)
+			Expect(err).NotTo(HaveOccurred())
+			after, err := auth.GenerateScopedSearchKey("9vGkbxYpZKUXSYkbDbSjlmYYHYN5Ttnw", params)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(after).NotTo(Equal(before))
+
+			_, err = auth.GenerateScopedSearchKey("abc", params)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,340 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/go-logr/logr"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"net/url"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"strings"
+
+	tsv1alpha1 "github.com/akyriako/typesense-operator/api/v1alpha1"
+)
+
+// TypesenseStemmingDictionaryReconciler reconciles a TypesenseStemmingDictionary object
+type TypesenseStemmingDictionaryReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	logger   logr.Logger
+	Recorder record.EventRecorder
+}
+
+type typesenseStemmingWord struct {
+	Word string `json:"word"`
+	Root string `json:"root"`
+}
+
+type typesenseStemmingDictionary struct {
+	ID    string                  `json:"id"`
+	Words []typesenseStemmingWord `json:"words"`
+}
+
+// +kubebuilder:rbac:groups=ts.opentelekomcloud.com,resources=typesensestemmingdictionaries,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=ts.opentelekomcloud.com,resources=typesensestemmingdictionaries/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=ts.opentelekomcloud.com,resources=typesensestemmingdictionaries/finalizers,verbs=update
+
+// Reconcile imports the stemming dictionary named after the TypesenseStemmingDictionary resource
+// and reports which collections stem their fields with it.
+func (r *TypesenseStemmingDictionaryReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	r.logger = log.Log.WithValues("namespace", req.Namespace, "dictionary", req.Name)
+	r.logger.Info("reconciling stemming dictionary")
+
+	var dictionary tsv1alpha1.TypesenseStemmingDictionary
+	if err := r.Get(ctx, req.NamespacedName, &dictionary); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	_, tc, reason, err := getTargetCluster(ctx, r.Client, dictionary.Namespace, dictionary.Spec.ClusterRef)
+	if err != nil {
+		if !dictionary.DeletionTimestamp.IsZero() && reason == ConditionReasonClusterNotFound {
+			return ctrl.Result{}, removeDataPlaneFinalizer(ctx, r.Client, &dictionary)
+		}
+
+		r.logger.Error(err, "resolving target cluster failed")
+		return ctrl.Result{RequeueAfter: dataPlaneRequeueAfter}, r.setDictionaryCondition(ctx, &dictionary, reason, err)
+	}
+
+	if !dictionary.DeletionTimestamp.IsZero() {
+		if controllerutil.ContainsFinalizer(&dictionary, dataPlaneFinalizer) {
+			if err := tc.delete(ctx, r.getDictionaryPath(&dictionary)); err != nil && !isTypesenseNotFound(err) {
+				r.logger.Error(err, "deleting stemming dictionary failed")
+				return ctrl.Result{}, err
+			}
+		}
+
+		return ctrl.Result{}, removeDataPlaneFinalizer(ctx, r.Client, &dictionary)
+	}
+
+	if err := addDataPlaneFinalizer(ctx, r.Client, &dictionary); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	words, err := r.getWords(ctx, &dictionary)
+	if err != nil {
+		r.logger.Error(err, "reading stemming dictionary failed")
+		r.Recorder.Event(&dictionary, "Warning", ConditionReasonInvalidSpec, err.Error())
+
+		return ctrl.Result{RequeueAfter: dataPlaneRequeueAfter}, r.setDictionaryCondition(ctx, &dictionary, ConditionReasonInvalidSpec, err)
+	}
+
+	diff, err := r.diffDictionary(ctx, tc, &dictionary, words)
+	if err != nil {
+		r.logger.Error(err, "reading live stemming dictionary failed")
+		return ctrl.Result{RequeueAfter: dataPlaneRequeueAfter}, r.setDictionaryCondition(ctx, &dictionary, ConditionReasonSyncFailed, err)
+	}
+
+	drifted, apply := evaluateDrift(dictionary.Spec.DriftDetection, dictionary.Status.Conditions, dictionary.Generation, diff)
+	if drifted {
+		r.logger.Info("stemming dictionary drifted from the spec", "differences", len(diff), "reconcile", apply)
+		recordDriftEvent(r.Recorder, &dictionary, apply, diff)
+	}
+
+	if apply {
+		if err := r.syncDictionary(ctx, tc, &dictionary, words); err != nil {
+			r.logger.Error(err, "syncing stemming dictionary failed")
+			r.Recorder.Event(&dictionary, "Warning", ConditionReasonSyncFailed, err.Error())
+
+			return ctrl.Result{RequeueAfter: dataPlaneRequeueAfter}, r.setDictionaryCondition(ctx, &dictionary, ConditionReasonSyncFailed, err)
+		}
+	}
+
+	collections, err := getStemmingDictionaryReferences(ctx, tc, dictionary.Name)
+	if err != nil {
+		r.logger.Error(err, "listing stemming dictionary references failed")
+		return ctrl.Result{RequeueAfter: dataPlaneRequeueAfter}, r.setDictionaryCondition(ctx, &dictionary, ConditionReasonSyncFailed, err)
+	}
+
+	err = patchObjectStatus(ctx, r.Client, &dictionary, func() {
+		setDataPlaneCondition(&dictionary.Status.Conditions, dictionary.Generation, ConditionReasonSynced, nil)
+		setDriftCondition(&dictionary.Status.Conditions, dictionary.Generation, dictionary.Spec.DriftDetection, drifted, apply, diff)
+		dictionary.Status.Count = len(words)
+		dictionary.Status.Collections = collections
+		dictionary.Status.LastSyncTime = ptr.To(metav1.Now())
+	})
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	r.logger.Info("reconciling stemming dictionary completed", "count", len(words), "collections", len(collections))
+
+	// references are owned by collection schemas, so they are refreshed periodically along with the drift check rather than on events
+	requeueAfter := getDriftRequeueAfter(dictionary.Spec.DriftDetection)
+	if requeueAfter == 0 {
+		requeueAfter = dataPlaneRequeueAfter
+	}
+
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// diffDictionary compares the live words of the dictionary, keyed by word, with the desired ones.
+func (r *TypesenseStemmingDictionaryReconciler) diffDictionary(ctx context.Context, tc *typesenseClient, dictionary *tsv1alpha1.TypesenseStemmingDictionary, words []typesenseStemmingWord) ([]string, error) {
+	desired := make(map[string]any, len(words))
+	for _, word := range words {
+		desired[word.Word] = word.Root
+	}
+
+	var live typesenseStemmingDictionary
+	if err := tc.get(ctx, r.getDictionaryPath(dictionary), &live); err != nil && !isTypesenseNotFound(err) {
+		return nil, err
+	}
+
+	current := make(map[string]any, len(live.Words))
+	for _, word := range live.Words {
+		current[word.Word] = word.Root
+	}
+
+	return diffServerObjects("stemming word", desired, current)
+}
+
+// syncDictionary imports the desired words. Imports only upsert, so a dictionary that holds words
+// which are no longer desired is dropped and imported from scratch.
+func (r *TypesenseStemmingDictionaryReconciler) syncDictionary(ctx context.Context, tc *typesenseClient, dictionary *tsv1alpha1.TypesenseStemmingDictionary, words []typesenseStemmingWord) error {
+	desired := make(map[string]string, len(words))
+	for _, word := range words {
+		desired[word.Word] = word.Root
+	}
+
+	var live typesenseStemmingDictionary
+	err := tc.get(ctx, r.getDictionaryPath(dictionary), &live)
+	if err != nil && !isTypesenseNotFound(err) {
+		return err
+	}
+
+	stale, missing := false, len(words) != len(live.Words)
+	for _, word := range live.Words {
+		if root, ok := desired[word.Word]; !ok || root != word.Root {
+			stale = true
+			break
+		}
+	}
+
+	if !stale && !missing {
+		r.logger.V(debugLevel).Info("stemming dictionary is up to date")
+		return nil
+	}
+
+	if stale {
+		r.logger.V(debugLevel).Info("dropping stale stemming dictionary")
+		if err := tc.delete(ctx, r.getDictionaryPath(dictionary)); err != nil && !isTypesenseNotFound(err) {
+			return err
+		}
+	}
+
+	var lines bytes.Buffer
+	encoder := json.NewEncoder(&lines)
+	for _, word := range words {
+		if err := encoder.Encode(word); err != nil {
+			return err
+		}
+	}
+
+	query := url.Values{"id": {dictionary.Name}}
+
+	r.logger.V(debugLevel).Info("importing stemming dictionary", "count", len(words))
+	response, err := tc.importJsonl(ctx, fmt.Sprintf("/stemming/dictionaries/import?%s", query.Encode()), lines.Bytes())
+	if err != nil {
+		return err
+	}
+
+	failures, err := getImportFailures(response)
+	if err != nil {
+		return err
+	}
+
+	if len(failures) > 0 {
+		return fmt.Errorf("importing %d of %d words failed: %s", len(failures), len(words), failures[0].Error)
+	}
+
+	return nil
+}
+
+func (r *TypesenseStemmingDictionaryReconciler) getWords(ctx context.Context, dictionary *tsv1alpha1.TypesenseStemmingDictionary) ([]typesenseStemmingWord, error) {
+	if len(dictionary.Spec.Words) > 0 && dictionary.Spec.ConfigMapRef != nil {
+		return nil, fmt.Errorf("words and configMapRef are mutually exclusive")
+	}
+
+	if dictionary.Spec.ConfigMapRef == nil {
+		if len(dictionary.Spec.Words) == 0 {
+			return nil, fmt.Errorf("one of words or configMapRef must be set")
+		}
+
+		words := make([]typesenseStemmingWord, 0, len(dictionary.Spec.Words))
+		for _, word := range dictionary.Spec.Words {
+			words = append(words, typesenseStemmingWord{Word: word.Word, Root: word.Root})
+		}
+		return normalizeStemmingWords(words), nil
+	}
+
+	content, err := getConfigMapKey(ctx, r.Client, dictionary.Namespace, dictionary.Spec.ConfigMapRef)
+	if err != nil {
+		return nil, err
+	}
+
+	words := make([]typesenseStemmingWord, 0)
+	for i, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		var word typesenseStemmingWord
+		if err := json.Unmarshal([]byte(line), &word); err != nil {
+			return nil, fmt.Errorf("line %d of configmap %s key %s is not valid: %w", i+1, dictionary.Spec.ConfigMapRef.Name, dictionary.Spec.ConfigMapRef.Key, err)
+		}
+		if word.Word == "" || word.Root == "" {
+			return nil, fmt.Errorf("line %d of configmap %s key %s requires both word and root", i+1, dictionary.Spec.ConfigMapRef.Name, dictionary.Spec.ConfigMapRef.Key)
+		}
+
+		words = append(words, word)
+	}
+
+	if len(words) == 0 {
+		return nil, fmt.Errorf("configmap %s key %s does not contain any words", dictionary.Spec.ConfigMapRef.Name, dictionary.Spec.ConfigMapRef.Key)
+	}
+
+	return normalizeStemmingWords(words), nil
+}
+
+// normalizeStemmingWords keeps the last root of every word, the one an import upserts, and sorts the words; the
+// dictionaries of Typesense hold every word once, so duplicates would never match the live dictionary
+func normalizeStemmingWords(words []typesenseStemmingWord) []typesenseStemmingWord {
+	roots := make(map[string]string, len(words))
+	set := make(map[string]bool, len(words))
+	for _, word := range words {
+		roots[word.Word] = word.Root
+		set[word.Word] = true
+	}
+
+	normalized := make([]typesenseStemmingWord, 0, len(roots))
+	for _, word := range sortedKeys(set) {
+		normalized = append(normalized, typesenseStemmingWord{Word: word, Root: roots[word]})
+	}
+
+	return normalized
+}
+
+// getStemmingDictionaryReferences returns the collections with at least one field stemmed with the given dictionary.
+func getStemmingDictionaryReferences(ctx context.Context, tc *typesenseClient, id string) ([]string, error) {
+	collections, err := tc.listCollections(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	referenced := map[string]bool{}
+	for _, collection := range collections {
+		for _, field := range collection.Fields {
+			if field.StemDictionary == id {
+				referenced[collection.Name] = true
+			}
+		}
+	}
+
+	return sortedKeys(referenced), nil
+}
+
+func (r *TypesenseStemmingDictionaryReconciler) getDictionaryPath(dictionary *tsv1alpha1.TypesenseStemmingDictionary) string {
+	return fmt.Sprintf("/stemming/dictionaries/%s", url.PathEscape(dictionary.Name))
+}
+
+func (r *TypesenseStemmingDictionaryReconciler) setDictionaryCondition(ctx context.Context, dictionary *tsv1alpha1.TypesenseStemmingDictionary, reason string, err error) error {
+	return patchObjectStatus(ctx, r.Client, dictionary, func() {
+		setDataPlaneCondition(&dictionary.Status.Conditions, dictionary.Generation, reason, err)
+	})
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *TypesenseStemmingDictionaryReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&tsv1alpha1.TypesenseStemmingDictionary{}, eventFilters).
+		Watches(&v1.ConfigMap{}, enqueueReferencingObjects(mgr.GetClient(), &tsv1alpha1.TypesenseStemmingDictionaryList{}, func(obj client.Object, name string) bool {
+			ref := obj.(*tsv1alpha1.TypesenseStemmingDictionary).Spec.ConfigMapRef
+			return ref != nil && ref.Name == name
+		})).
+		Complete(r)
+}
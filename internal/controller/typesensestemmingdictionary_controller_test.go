@@ -0,0 +1,160 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	tsv1alpha1 "github.com/akyriako/typesense-operator/api/v1alpha1"
+)
+
+var _ = Describe("TypesenseStemmingDictionary Controller", func() {
+	Context("When syncing a dictionary", func() {
+		ctx := context.Background()
+
+		var (
+			server   *httptest.Server
+			live     map[string]string
+			imported int
+			deleted  int
+			query    string
+		)
+
+		dictionary := &tsv1alpha1.TypesenseStemmingDictionary{
+			ObjectMeta: metav1.ObjectMeta{Name: "irregular-plurals", Namespace: "default"},
+			Spec: tsv1alpha1.TypesenseStemmingDictionarySpec{
+				ClusterRef: corev1.LocalObjectReference{Name: "cluster-1"},
+				Words: []tsv1alpha1.StemmingWordSpec{
+					{Word: "people", Root: "person"},
+					{Word: "children", Root: "child"},
+				},
+			},
+		}
+
+		BeforeEach(func() {
+			live, imported, deleted, query = nil, 0, 0, ""
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("/stemming/dictionaries/irregular-plurals", func(w http.ResponseWriter, req *http.Request) {
+				if live == nil {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+
+				if req.Method == http.MethodDelete {
+					live, deleted = nil, deleted+1
+					return
+				}
+
+				d := typesenseStemmingDictionary{ID: "irregular-plurals"}
+				for word, root := range live {
+					d.Words = append(d.Words, typesenseStemmingWord{Word: word, Root: root})
+				}
+				_ = json.NewEncoder(w).Encode(d)
+			})
+			mux.HandleFunc("/stemming/dictionaries/import", func(w http.ResponseWriter, req *http.Request) {
+				Expect(req.URL.Query().Get("id")).To(Equal("irregular-plurals"))
+				query = req.URL.RawQuery
+				if live == nil {
+					live = map[string]string{}
+				}
+
+				imported++
+				scanner := bufio.NewScanner(req.Body)
+				for scanner.Scan() {
+					var word typesenseStemmingWord
+					Expect(json.Unmarshal(scanner.Bytes(), &word)).To(Succeed())
+					live[word.Word] = word.Root
+					_, _ = w.Write([]byte("{\"success\": true}\n"))
+				}
+			})
+
+			server = httptest.NewServer(mux)
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		It("should import a missing dictionary only once", func() {
+			reconciler := &TypesenseStemmingDictionaryReconciler{logger: log.Log}
+			tc := newTypesenseClient(server.URL, "admin")
+
+			words, err := reconciler.getWords(ctx, dictionary)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(reconciler.syncDictionary(ctx, tc, dictionary, words)).To(Succeed())
+			Expect(reconciler.syncDictionary(ctx, tc, dictionary, words)).To(Succeed())
+
+			Expect(imported).To(Equal(1))
+			Expect(live).To(Equal(map[string]string{"people": "person", "children": "child"}))
+		})
+
+		It("should import duplicated words only once", func() {
+			reconciler := &TypesenseStemmingDictionaryReconciler{logger: log.Log}
+			tc := newTypesenseClient(server.URL, "admin")
+
+			duplicated := dictionary.DeepCopy()
+			duplicated.Spec.Words = append(duplicated.Spec.Words, tsv1alpha1.StemmingWordSpec{Word: "people", Root: "person"})
+
+			words, err := reconciler.getWords(ctx, duplicated)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(words).To(HaveLen(2))
+
+			Expect(reconciler.syncDictionary(ctx, tc, duplicated, words)).To(Succeed())
+			Expect(reconciler.syncDictionary(ctx, tc, duplicated, words)).To(Succeed())
+			Expect(imported).To(Equal(1))
+		})
+
+		It("should not send the locale of the dictionary", func() {
+			reconciler := &TypesenseStemmingDictionaryReconciler{logger: log.Log}
+			tc := newTypesenseClient(server.URL, "admin")
+
+			localized := dictionary.DeepCopy()
+			localized.Spec.Locale = ptr.To("en")
+
+			words, err := reconciler.getWords(ctx, localized)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reconciler.syncDictionary(ctx, tc, localized, words)).To(Succeed())
+			Expect(query).To(Equal("id=irregular-plurals"))
+		})
+
+		It("should drop words that are no longer desired", func() {
+			live = map[string]string{"people": "person", "mice": "mouse"}
+			reconciler := &TypesenseStemmingDictionaryReconciler{logger: log.Log}
+			tc := newTypesenseClient(server.URL, "admin")
+
+			words, err := reconciler.getWords(ctx, dictionary)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reconciler.syncDictionary(ctx, tc, dictionary, words)).To(Succeed())
+
+			Expect(deleted).To(Equal(1))
+			Expect(live).NotTo(HaveKey("mice"))
+		})
+	})
+})
@@ -0,0 +1,263 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-logr/logr"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"net/url"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"strings"
+
+	tsv1alpha1 "github.com/akyriako/typesense-operator/api/v1alpha1"
+)
+
+// TypesenseStopwordsReconciler reconciles a TypesenseStopwords object
+type TypesenseStopwordsReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	logger   logr.Logger
+	Recorder record.EventRecorder
+}
+
+type typesenseStopwordsSet struct {
+	ID        string   `json:"id,omitempty"`
+	Stopwords []string `json:"stopwords"`
+	Locale    string   `json:"locale,omitempty"`
+}
+
+// +kubebuilder:rbac:groups=ts.opentelekomcloud.com,resources=typesensestopwords,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=ts.opentelekomcloud.com,resources=typesensestopwords/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=ts.opentelekomcloud.com,resources=typesensestopwords/finalizers,verbs=update
+
+// Reconcile upserts the stopwords set named after the TypesenseStopwords resource
+// and reports which collections are searched with it.
+func (r *TypesenseStopwordsReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	r.logger = log.Log.WithValues("namespace", req.Namespace, "stopwords", req.Name)
+	r.logger.Info("reconciling stopwords")
+
+	var stopwords tsv1alpha1.TypesenseStopwords
+	if err := r.Get(ctx, req.NamespacedName, &stopwords); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	_, tc, reason, err := getTargetCluster(ctx, r.Client, stopwords.Namespace, stopwords.Spec.ClusterRef)
+	if err != nil {
+		if !stopwords.DeletionTimestamp.IsZero() && reason == ConditionReasonClusterNotFound {
+			return ctrl.Result{}, removeDataPlaneFinalizer(ctx, r.Client, &stopwords)
+		}
+
+		r.logger.Error(err, "resolving target cluster failed")
+		return ctrl.Result{RequeueAfter: dataPlaneRequeueAfter}, r.setStopwordsCondition(ctx, &stopwords, reason, err)
+	}
+
+	if !stopwords.DeletionTimestamp.IsZero() {
+		if controllerutil.ContainsFinalizer(&stopwords, dataPlaneFinalizer) {
+			if err := tc.delete(ctx, r.getStopwordsPath(&stopwords)); err != nil && !isTypesenseNotFound(err) {
+				r.logger.Error(err, "deleting stopwords set failed")
+				return ctrl.Result{}, err
+			}
+		}
+
+		return ctrl.Result{}, removeDataPlaneFinalizer(ctx, r.Client, &stopwords)
+	}
+
+	if err := addDataPlaneFinalizer(ctx, r.Client, &stopwords); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	words, err := r.getStopwords(ctx, &stopwords)
+	if err != nil {
+		r.logger.Error(err, "reading stopwords failed")
+		r.Recorder.Event(&stopwords, "Warning", ConditionReasonInvalidSpec, err.Error())
+
+		return ctrl.Result{RequeueAfter: dataPlaneRequeueAfter}, r.setStopwordsCondition(ctx, &stopwords, ConditionReasonInvalidSpec, err)
+	}
+
+	set := typesenseStopwordsSet{
+		Stopwords: normalizeStopwords(words),
+		Locale:    ptr.Deref(stopwords.Spec.Locale, ""),
+	}
+
+	diff, err := r.diffStopwords(ctx, tc, &stopwords, set)
+	if err != nil {
+		r.logger.Error(err, "reading stopwords set failed")
+		return ctrl.Result{RequeueAfter: dataPlaneRequeueAfter}, r.setStopwordsCondition(ctx, &stopwords, ConditionReasonSyncFailed, err)
+	}
+
+	drifted, apply := evaluateDrift(stopwords.Spec.DriftDetection, stopwords.Status.Conditions, stopwords.Generation, diff)
+	if drifted {
+		r.logger.Info("stopwords set drifted from the spec", "differences", len(diff), "reconcile", apply)
+		recordDriftEvent(r.Recorder, &stopwords, apply, diff)
+	}
+
+	if apply {
+		r.logger.V(debugLevel).Info("upserting stopwords set", "count", len(words))
+		if err := tc.put(ctx, r.getStopwordsPath(&stopwords), set, nil); err != nil {
+			r.logger.Error(err, "upserting stopwords set failed")
+			r.Recorder.Event(&stopwords, "Warning", ConditionReasonSyncFailed, err.Error())
+
+			return ctrl.Result{RequeueAfter: dataPlaneRequeueAfter}, r.setStopwordsCondition(ctx, &stopwords, ConditionReasonSyncFailed, err)
+		}
+	}
+
+	collections, err := getStopwordsReferences(ctx, tc, stopwords.Name)
+	if err != nil {
+		r.logger.Error(err, "listing stopwords references failed")
+		return ctrl.Result{RequeueAfter: dataPlaneRequeueAfter}, r.setStopwordsCondition(ctx, &stopwords, ConditionReasonSyncFailed, err)
+	}
+
+	err = patchObjectStatus(ctx, r.Client, &stopwords, func() {
+		setDataPlaneCondition(&stopwords.Status.Conditions, stopwords.Generation, ConditionReasonSynced, nil)
+		setDriftCondition(&stopwords.Status.Conditions, stopwords.Generation, stopwords.Spec.DriftDetection, drifted, apply, diff)
+		stopwords.Status.Count = len(set.Stopwords)
+		stopwords.Status.Collections = collections
+		stopwords.Status.LastSyncTime = ptr.To(metav1.Now())
+	})
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	r.logger.Info("reconciling stopwords completed", "count", len(set.Stopwords), "collections", len(collections))
+
+	// references are owned by presets, so they are refreshed periodically along with the drift check rather than on events
+	requeueAfter := getDriftRequeueAfter(stopwords.Spec.DriftDetection)
+	if requeueAfter == 0 {
+		requeueAfter = dataPlaneRequeueAfter
+	}
+
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// diffStopwords compares the live stopwords set with the desired one. Typesense keeps neither the order
+// nor the duplicates of the stopwords, so both lists are compared normalized.
+func (r *TypesenseStopwordsReconciler) diffStopwords(ctx context.Context, tc *typesenseClient, stopwords *tsv1alpha1.TypesenseStopwords, desired typesenseStopwordsSet) ([]string, error) {
+	normalize := func(set typesenseStopwordsSet) typesenseStopwordsSet {
+		return typesenseStopwordsSet{Stopwords: normalizeStopwords(set.Stopwords), Locale: set.Locale}
+	}
+
+	live := map[string]any{}
+
+	var current struct {
+		Stopwords typesenseStopwordsSet `json:"stopwords"`
+	}
+	if err := tc.get(ctx, r.getStopwordsPath(stopwords), &current); err != nil {
+		if !isTypesenseNotFound(err) {
+			return nil, err
+		}
+	} else {
+		live[stopwords.Name] = normalize(current.Stopwords)
+	}
+
+	return diffServerObjects("stopwords set", map[string]any{stopwords.Name: normalize(desired)}, live)
+}
+
+func (r *TypesenseStopwordsReconciler) getStopwords(ctx context.Context, stopwords *tsv1alpha1.TypesenseStopwords) ([]string, error) {
+	if len(stopwords.Spec.Stopwords) > 0 && stopwords.Spec.ConfigMapRef != nil {
+		return nil, fmt.Errorf("stopwords and configMapRef are mutually exclusive")
+	}
+
+	if stopwords.Spec.ConfigMapRef == nil {
+		if len(stopwords.Spec.Stopwords) == 0 {
+			return nil, fmt.Errorf("one of stopwords or configMapRef must be set")
+		}
+		return stopwords.Spec.Stopwords, nil
+	}
+
+	content, err := getConfigMapKey(ctx, r.Client, stopwords.Namespace, stopwords.Spec.ConfigMapRef)
+	if err != nil {
+		return nil, err
+	}
+
+	words := make([]string, 0)
+	for _, line := range strings.Split(content, "\n") {
+		word := strings.TrimSpace(line)
+		if word == "" || strings.HasPrefix(word, "#") {
+			continue
+		}
+		words = append(words, word)
+	}
+
+	if len(words) == 0 {
+		return nil, fmt.Errorf("configmap %s key %s does not contain any stopwords", stopwords.Spec.ConfigMapRef.Name, stopwords.Spec.ConfigMapRef.Key)
+	}
+
+	return words, nil
+}
+
+// normalizeStopwords lowercases, de-duplicates and sorts the stopwords the way Typesense stores them
+func normalizeStopwords(words []string) []string {
+	set := make(map[string]bool, len(words))
+	for _, word := range words {
+		if word = strings.ToLower(strings.TrimSpace(word)); word != "" {
+			set[word] = true
+		}
+	}
+
+	return sortedKeys(set)
+}
+
+// getStopwordsReferences returns the collections searched with the given stopwords set.
+// Collection schemas do not reference stopwords, they are a search parameter, so presets are inspected instead.
+func getStopwordsReferences(ctx context.Context, tc *typesenseClient, id string) ([]string, error) {
+	presets, err := tc.listPresets(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	referenced := map[string]bool{}
+	for _, preset := range presets {
+		for _, search := range getPresetSearches(preset) {
+			collection, _ := search["collection"].(string)
+			if set, _ := search["stopwords"].(string); set == id && collection != "" {
+				referenced[collection] = true
+			}
+		}
+	}
+
+	return sortedKeys(referenced), nil
+}
+
+func (r *TypesenseStopwordsReconciler) getStopwordsPath(stopwords *tsv1alpha1.TypesenseStopwords) string {
+	return fmt.Sprintf("/stopwords/%s", url.PathEscape(stopwords.Name))
+}
+
+func (r *TypesenseStopwordsReconciler) setStopwordsCondition(ctx context.Context, stopwords *tsv1alpha1.TypesenseStopwords, reason string, err error) error {
+	return patchObjectStatus(ctx, r.Client, stopwords, func() {
+		setDataPlaneCondition(&stopwords.Status.Conditions, stopwords.Generation, reason, err)
+	})
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *TypesenseStopwordsReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&tsv1alpha1.TypesenseStopwords{}, eventFilters).
+		Watches(&v1.ConfigMap{}, enqueueReferencingObjects(mgr.GetClient(), &tsv1alpha1.TypesenseStopwordsList{}, func(obj client.Object, name string) bool {
+			ref := obj.(*tsv1alpha1.TypesenseStopwords).Spec.ConfigMapRef
+			return ref != nil && ref.Name == name
+		})).
+		Complete(r)
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	tsv1alpha1 "github.com/akyriako/typesense-operator/api/v1alpha1"
+)
+
+var _ = Describe("TypesenseStopwords Controller", func() {
+	Context("When syncing a stopwords set", func() {
+		ctx := context.Background()
+
+		var (
+			server *httptest.Server
+			live   *typesenseStopwordsSet
+		)
+
+		stopwords := &tsv1alpha1.TypesenseStopwords{
+			ObjectMeta: metav1.ObjectMeta{Name: "common-words", Namespace: "default"},
+			Spec: tsv1alpha1.TypesenseStopwordsSpec{
+				ClusterRef: corev1.LocalObjectReference{Name: "cluster-1"},
+				Locale:     ptr.To("en"),
+				Stopwords:  []string{"the", "a", "The", "an", "a"},
+			},
+		}
+
+		BeforeEach(func() {
+			live = nil
+
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				Expect(req.URL.Path).To(Equal("/stopwords/common-words"))
+
+				switch req.Method {
+				case http.MethodGet:
+					if live == nil {
+						w.WriteHeader(http.StatusNotFound)
+						_, _ = w.Write([]byte(`{"message": "Not Found"}`))
+						return
+					}
+					_ = json.NewEncoder(w).Encode(map[string]any{"stopwords": live})
+				case http.MethodPut:
+					var set typesenseStopwordsSet
+					Expect(json.NewDecoder(req.Body).Decode(&set)).To(Succeed())
+
+					// Typesense keeps every stopword once, lowercased and in no particular order
+					words := map[string]bool{}
+					for _, word := range set.Stopwords {
+						words[strings.ToLower(word)] = true
+					}
+					set.ID, set.Stopwords = "common-words", nil
+					for word := range words {
+						set.Stopwords = append(set.Stopwords, word)
+					}
+					live = &set
+					_ = json.NewEncoder(w).Encode(set)
+				}
+			}))
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		It("should report a missing set as drift", func() {
+			reconciler := &TypesenseStopwordsReconciler{logger: log.Log}
+			tc := newTypesenseClient(server.URL, "admin")
+
+			diff, err := reconciler.diffStopwords(ctx, tc, stopwords, typesenseStopwordsSet{Stopwords: []string{"the"}})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(diff).To(HaveLen(1))
+		})
+
+		It("should not report duplicated stopwords as drift once they are upserted", func() {
+			reconciler := &TypesenseStopwordsReconciler{logger: log.Log}
+			tc := newTypesenseClient(server.URL, "admin")
+
+			words, err := reconciler.getStopwords(ctx, stopwords)
+			Expect(err).NotTo(HaveOccurred())
+
+			set := typesenseStopwordsSet{Stopwords: normalizeStopwords(words), Locale: "en"}
+			Expect(set.Stopwords).To(Equal([]string{"a", "an", "the"}))
+			Expect(tc.put(ctx, reconciler.getStopwordsPath(stopwords), set, nil)).To(Succeed())
+
+			diff, err := reconciler.diffStopwords(ctx, tc, stopwords, set)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(diff).To(BeEmpty())
+		})
+
+		It("should reject a spec with both stopwords and a configmap", func() {
+			reconciler := &TypesenseStopwordsReconciler{logger: log.Log}
+
+			invalid := stopwords.DeepCopy()
+			invalid.Spec.ConfigMapRef = &corev1.ConfigMapKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "stopwords"},
+				Key:                  "words",
+			}
+
+			_, err := reconciler.getStopwords(ctx, invalid)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -66,6 +66,13 @@ func getReverseProxyLabels(ts *tsv1alpha1.TypesenseCluster) map[string]string {
 	}
 }
 
+// getApiClientLabels marks the pods of the jobs that call the api of the cluster, so the network policy lets them in
+func getApiClientLabels(ts *tsv1alpha1.TypesenseCluster) map[string]string {
+	return map[string]string{
+		ClusterApiClientLabel: ts.Name,
+	}
+}
+
 func getReverseProxyObjectMeta(ts *tsv1alpha1.TypesenseCluster, name *string, annotations map[string]string) metav1.ObjectMeta {
 	if name == nil {
 		name = &ts.Name
@@ -136,6 +143,9 @@ func normalizeVolumes(vols []corev1.Volume) []corev1.Volume {
 		if cm := vcopy[i].VolumeSource.ConfigMap; cm != nil {
 			cm.DefaultMode = nil
 		}
+		if secret := vcopy[i].VolumeSource.Secret; secret != nil {
+			secret.DefaultMode = nil
+		}
 	}
 
 	sort.Slice(vcopy, func(i, j int) bool {